@@ -12,6 +12,7 @@ require (
 	go.opentelemetry.io/otel/metric v1.44.0
 	go.opentelemetry.io/otel/sdk v1.44.0
 	go.opentelemetry.io/otel/sdk/metric v1.44.0
+	gopkg.in/yaml.v3 v3.0.1
 )
 
 require (
@@ -0,0 +1,58 @@
+package artifacts
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/navikt/copilot/cli/nav-pilot/internal/domain"
+	"github.com/navikt/copilot/cli/nav-pilot/internal/source"
+)
+
+// BaseStoreDir is the directory next to the state file that holds the pristine
+// source content of each installed artifact, used as the merge base by sync.
+const BaseStoreDir = ".nav-pilot-base"
+
+// BaseRoot returns the base store directory for a scope.
+func BaseRoot(scope *domain.InstallScope) string {
+	return filepath.Join(filepath.Dir(scope.StatePath()), BaseStoreDir)
+}
+
+// BasePath returns where the pristine copy of a state-relative path is kept.
+// The scope's path prefix is dropped so repo-scope bases live at
+// .github/.nav-pilot-base/agents/... rather than repeating .github/.
+func BasePath(scope *domain.InstallScope, relPath string) string {
+	rel := strings.TrimPrefix(filepath.ToSlash(relPath), scope.PathPrefix)
+	return filepath.Join(BaseRoot(scope), filepath.FromSlash(rel))
+}
+
+// HasBase reports whether a pristine copy exists for relPath.
+func HasBase(scope *domain.InstallScope, relPath string) bool {
+	_, err := os.Lstat(BasePath(scope, relPath))
+	return err == nil
+}
+
+// SaveBase records srcPath as the pristine base for relPath.
+func SaveBase(scope *domain.InstallScope, relPath, srcPath string, isDir bool) error {
+	return source.CopyArtifact(srcPath, BasePath(scope, relPath), scope.RootDir, isDir)
+}
+
+// SaveBaseData records data as the pristine base for relPath. Used for
+// content that is rendered at install time, such as subproject instructions.
+func SaveBaseData(scope *domain.InstallScope, relPath string, data []byte) error {
+	return source.WriteFile(BasePath(scope, relPath), data, scope.RootDir)
+}
+
+// RemoveBase deletes the pristine copy for relPath, if any.
+func RemoveBase(scope *domain.InstallScope, relPath string) error {
+	err := os.RemoveAll(BasePath(scope, relPath))
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+// RemoveBaseStore deletes the whole base store for a scope (used on uninstall).
+func RemoveBaseStore(scope *domain.InstallScope) error {
+	return os.RemoveAll(BaseRoot(scope))
+}
@@ -14,12 +14,13 @@ import (
 
 // CmdExport dispatches to the appropriate export format.
 func CmdExport(format string, scope *domain.InstallScope, ref, sourceRepo, cliVersion string, dryRun, force, jsonOutput bool) error {
-	switch format {
-	case "opencode":
+	if format == "opencode" {
 		return ExportOpenCode(scope, ref, sourceRepo, cliVersion, dryRun, force, jsonOutput)
-	default:
-		return fmt.Errorf("unknown export format: %q\n\nSupported formats: opencode", format)
 	}
+	if f := ExportFormatByName(format); f != nil {
+		return ExportToFormat(f, scope, ref, sourceRepo, cliVersion, dryRun, force, jsonOutput)
+	}
+	return fmt.Errorf("unknown export format: %q\n\nSupported formats: %s", format, strings.Join(ExportFormatNames(), ", "))
 }
 
 // ExportOpenCode transforms Nav's .github/ artifacts into OpenCode-compatible .opencode/ format.
@@ -242,6 +243,14 @@ func exportAgents(sourceDir, outputDir string, dryRun bool) (int, error) {
 }
 
 func transformAgent(data []byte, name string) []byte {
+	return rewriteAgentFrontmatter(data, func(description string) []byte {
+		return source.BuildAgentFrontmatter(description, source.OpenCodeAgentMode(name))
+	})
+}
+
+// rewriteAgentFrontmatter replaces an agent's frontmatter with the one build
+// produces from its description. Files without frontmatter pass through.
+func rewriteAgentFrontmatter(data []byte, build func(description string) []byte) []byte {
 	fm, body, hasFM := source.SplitFrontmatter(data)
 	if !hasFM {
 		return data
@@ -252,13 +261,13 @@ func transformAgent(data []byte, name string) []byte {
 		description = "Nav agent"
 	}
 
-	newFM := source.BuildAgentFrontmatter(description, source.OpenCodeAgentMode(name))
-	return source.Reassemble(newFM, body)
+	return source.Reassemble(build(description), body)
 }
 
 // InstructionSection holds global instruction content to be inlined into AGENTS.md.
 type InstructionSection struct {
 	Name string
+	Slug string // file-name form of Name, e.g. "kotlin-ktor"
 	Body []byte
 }
 
@@ -271,17 +280,28 @@ type InstructionRef struct {
 }
 
 func collectInstructionData(sourceDir string) ([]InstructionSection, []InstructionRef, error) {
-	instrEntries := source.NewSourceResolver(sourceDir).List(source.KindInstruction)
+	return collectResolvedInstructionData(source.NewSourceResolver(sourceDir))
+}
+
+// collectResolvedInstructionData collects instructions across the resolver's
+// layers. copilot-instructions.md comes from the highest layer that has one.
+func collectResolvedInstructionData(resolver *source.SourceResolver) ([]InstructionSection, []InstructionRef, error) {
+	instrEntries := resolver.List(source.KindInstruction)
 
 	var globalSections []InstructionSection
-	globalInstr := filepath.Join(sourceDir, "copilot-instructions.md")
-	if data, err := os.ReadFile(globalInstr); err == nil {
+	var globalData []byte
+	layers := resolver.Layers()
+	for i := len(layers) - 1; i >= 0 && globalData == nil; i-- {
+		globalData, _ = os.ReadFile(filepath.Join(layers[i].Dir, "copilot-instructions.md"))
+	}
+	if data := globalData; data != nil {
 		_, body, hasFM := source.SplitFrontmatter(data)
 		if !hasFM {
 			body = data
 		}
 		globalSections = append(globalSections, InstructionSection{
 			Name: "Global Instructions",
+			Slug: "copilot-instructions",
 			Body: body,
 		})
 	}
@@ -307,6 +327,7 @@ func collectInstructionData(sourceDir string) ([]InstructionSection, []Instructi
 			sectionName := titleCase(strings.ReplaceAll(entry.Name, "-", " "))
 			globalSections = append(globalSections, InstructionSection{
 				Name: sectionName,
+				Slug: entry.Name,
 				Body: body,
 			})
 		} else {
@@ -369,8 +390,16 @@ func exportInstructions(sourceDir, outputDir string, dryRun bool) (int, error) {
 }
 
 func buildLeanAGENTSmd(globalSections []InstructionSection, refs []InstructionRef) []byte {
+	return buildInstructionIndex("opencode", globalSections, refs, func(name string) string {
+		return "@.opencode/instructions/" + name + ".md"
+	})
+}
+
+// buildInstructionIndex inlines the global sections and lists scoped
+// instructions by applyTo, pointing at refPath(name) for lazy loading.
+func buildInstructionIndex(format string, globalSections []InstructionSection, refs []InstructionRef, refPath func(name string) string) []byte {
 	var buf strings.Builder
-	buf.WriteString("<!-- Auto-generated by nav-pilot export opencode — do not edit manually -->\n\n")
+	buf.WriteString("<!-- Auto-generated by nav-pilot export " + format + " — do not edit manually -->\n\n")
 
 	for i, s := range globalSections {
 		if i > 0 {
@@ -390,7 +419,7 @@ func buildLeanAGENTSmd(globalSections []InstructionSection, refs []InstructionRe
 		buf.WriteString("Load instruction files on a **need-to-know basis** only — do not preemptively load all references.\n")
 		buf.WriteString("Use the Read tool to load the relevant file when about to write or review matching code:\n\n")
 		for _, ref := range refs {
-			buf.WriteString(fmt.Sprintf("- `%s` → @.opencode/instructions/%s.md\n", ref.ApplyTo, ref.Name))
+			buf.WriteString(fmt.Sprintf("- `%s` → %s\n", ref.ApplyTo, refPath(ref.Name)))
 		}
 		buf.WriteString("\n**CRITICAL**: Only load a file when it matches the current task. Do not load files for languages or frameworks not in use.\n")
 	}
@@ -0,0 +1,491 @@
+package artifacts
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/navikt/copilot/cli/nav-pilot/internal/domain"
+	"github.com/navikt/copilot/cli/nav-pilot/internal/source"
+)
+
+// ExportFormat is an export target for another agent tool. Unlike opencode,
+// which has its own launch-time materializer, these formats all go through
+// SyncExport: Render produces the files, SyncExport writes them with conflict
+// detection and records them in the format's own state file.
+type ExportFormat struct {
+	Name   string // CLI name, e.g. "claude"
+	Label  string // display name, e.g. "Claude Code"
+	Layout func(scope *domain.InstallScope) (ExportLayout, error)
+	Render func(resolver *source.SourceResolver, layout ExportLayout) ([]ExportFile, error)
+}
+
+// ExportLayout places a format's files for one scope.
+type ExportLayout struct {
+	Root string // directory exported paths are relative to
+	Dir  string // the tool's directory under Root, holding the state file; "" when Root is the tool dir
+}
+
+// ExportFile is one rendered output.
+type ExportFile struct {
+	Path   string // relative to ExportLayout.Root, slash-separated; trailing "/" for a directory
+	Data   []byte // file content
+	SrcDir string // directory copied as-is (skills)
+}
+
+var exportFormats = []*ExportFormat{
+	{Name: "claude", Label: "Claude Code", Layout: claudeLayout, Render: renderClaude},
+	{Name: "cursor", Label: "Cursor", Layout: cursorLayout, Render: renderCursor},
+	{Name: "codex", Label: "Codex", Layout: codexLayout, Render: renderCodex},
+}
+
+// ExportFormats returns the state-tracked export formats.
+func ExportFormats() []*ExportFormat {
+	return exportFormats
+}
+
+// ExportFormatByName returns the format called name, or nil.
+func ExportFormatByName(name string) *ExportFormat {
+	for _, f := range exportFormats {
+		if f.Name == name {
+			return f
+		}
+	}
+	return nil
+}
+
+// ExportFormatNames lists every export format, opencode included.
+func ExportFormatNames() []string {
+	names := []string{"opencode"}
+	for _, f := range exportFormats {
+		names = append(names, f.Name)
+	}
+	return names
+}
+
+// rel joins parts under the tool directory.
+func (l ExportLayout) rel(parts ...string) string {
+	return path.Join(append([]string{l.Dir}, parts...)...)
+}
+
+// ref is how generated docs point at another exported file: relative to the
+// project for repo exports, absolute for user-level ones.
+func (l ExportLayout) ref(parts ...string) string {
+	if l.Dir == "" {
+		return filepath.ToSlash(filepath.Join(append([]string{l.Root}, parts...)...))
+	}
+	return l.rel(parts...)
+}
+
+// StatePath returns the format's state file.
+func (l ExportLayout) StatePath() string {
+	return filepath.Join(l.Root, l.Dir, openCodeStateFileName)
+}
+
+// validStatePath checks that a state entry stays inside the layout: under the
+// tool directory, or a top-level doc file such as CLAUDE.md.
+func (l ExportLayout) validStatePath(p string) error {
+	if filepath.IsAbs(p) {
+		return fmt.Errorf("absolute path not allowed: %s", p)
+	}
+	if strings.Contains(p, "..") {
+		return fmt.Errorf("path traversal not allowed: %s", p)
+	}
+	normalized := filepath.ToSlash(p)
+	if l.Dir == "" || strings.HasPrefix(normalized, l.Dir+"/") {
+		return nil
+	}
+	if !strings.Contains(normalized, "/") && strings.HasSuffix(normalized, ".md") {
+		return nil
+	}
+	return fmt.Errorf("path outside %s export: %s", l.Dir, p)
+}
+
+// ReadExportState reads the format's state file; nil when it was never exported.
+func ReadExportState(f *ExportFormat, layout ExportLayout) (*domain.StateFile, error) {
+	s, err := ReadStateRaw(layout.StatePath())
+	if err != nil || s == nil {
+		return s, err
+	}
+	if s.Scope != f.Name {
+		return nil, fmt.Errorf("state file scope mismatch: expected %q, got %q", f.Name, s.Scope)
+	}
+	for _, file := range s.Files {
+		if err := layout.validStatePath(file.Path); err != nil {
+			return nil, fmt.Errorf("unsafe %s state file: %w", f.Name, err)
+		}
+	}
+	return s, nil
+}
+
+// ExportResult summarizes an export or sync of one format.
+type ExportResult struct {
+	Written   []string `json:"written"`
+	Unchanged int      `json:"unchanged"`
+	Conflicts []string `json:"conflicts,omitempty"`
+	Removed   []string `json:"removed,omitempty"`
+}
+
+// SyncExport renders f from all of the resolver's layers, the same artifacts
+// install and sync use, and brings layout up to date. A file
+// is a conflict, and left alone, when it was changed locally since nav-pilot
+// wrote it or exists without nav-pilot having written it; force overwrites
+// both. Files the source no longer produces are removed unless modified.
+// With dryRun nothing is written, but the result reports what would be.
+func SyncExport(f *ExportFormat, layout ExportLayout, resolver *source.SourceResolver, sourceVersion, sourceSHA, sourceRepo string, dryRun, force bool) (*ExportResult, error) {
+	state, err := ReadExportState(f, layout)
+	if err != nil {
+		return nil, err
+	}
+	stored := map[string]domain.InstalledFile{}
+	if state != nil {
+		for _, file := range state.Files {
+			stored[file.Path] = file
+		}
+	}
+
+	files, err := f.Render(resolver, layout)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &ExportResult{}
+	var tracked []domain.InstalledFile
+	produced := make(map[string]bool, len(files))
+	for _, ef := range files {
+		produced[ef.Path] = true
+		isDir := strings.HasSuffix(ef.Path, "/")
+		dst := filepath.Join(layout.Root, filepath.FromSlash(ef.Path))
+		want, err := ef.hash()
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", ef.Path, err)
+		}
+
+		current, curErr := source.RawArtifactHash(dst, isDir)
+		prev, wasTracked := stored[ef.Path]
+		switch {
+		case curErr == nil && current == want:
+			result.Unchanged++
+			tracked = append(tracked, domain.InstalledFile{Path: ef.Path, Hash: want})
+			continue
+		case curErr == nil && !force && (!wasTracked || current != prev.Hash):
+			result.Conflicts = append(result.Conflicts, ef.Path)
+			if wasTracked {
+				tracked = append(tracked, domain.InstalledFile{Path: ef.Path, Hash: prev.Hash, Status: domain.FileStatusConflict})
+			}
+			continue
+		}
+
+		if !dryRun {
+			if err := writeExportFile(layout.Root, dst, ef); err != nil {
+				return nil, fmt.Errorf("%s: %w", ef.Path, err)
+			}
+		}
+		result.Written = append(result.Written, ef.Path)
+		tracked = append(tracked, domain.InstalledFile{Path: ef.Path, Hash: want})
+	}
+
+	var stale []string
+	for p := range stored {
+		if !produced[p] {
+			stale = append(stale, p)
+		}
+	}
+	sort.Strings(stale)
+	for _, p := range stale {
+		isDir := strings.HasSuffix(p, "/")
+		dst := filepath.Join(layout.Root, filepath.FromSlash(p))
+		if current, err := source.RawArtifactHash(dst, isDir); err == nil && current != stored[p].Hash && !force {
+			result.Conflicts = append(result.Conflicts, p)
+			continue
+		}
+		if !dryRun {
+			if err := source.CheckSymlink(dst, layout.Root); err != nil {
+				return nil, fmt.Errorf("%s: %w", p, err)
+			}
+			os.RemoveAll(dst)
+		}
+		result.Removed = append(result.Removed, p)
+	}
+
+	if dryRun {
+		return result, nil
+	}
+	newState := &domain.StateFile{
+		Collection:  f.Name + "-export",
+		Version:     sourceVersion,
+		Scope:       f.Name,
+		SourceRepo:  sourceRepo,
+		SourceSHA:   sourceSHA,
+		InstalledAt: time.Now().UTC().Format("2006-01-02T15:04:05Z07:00"),
+		Files:       tracked,
+	}
+	if err := WriteStateAt(layout.StatePath(), layout.Root, newState); err != nil {
+		return result, fmt.Errorf("writing %s state: %w", f.Name, err)
+	}
+	return result, nil
+}
+
+func (ef ExportFile) hash() (string, error) {
+	if ef.SrcDir != "" {
+		return source.DirHash(ef.SrcDir)
+	}
+	sum := sha256.Sum256(ef.Data)
+	return hex.EncodeToString(sum[:])[:16], nil
+}
+
+func writeExportFile(root, dst string, ef ExportFile) error {
+	if err := source.CheckSymlink(dst, root); err != nil {
+		return err
+	}
+	if ef.SrcDir != "" {
+		// Replace the whole directory so files dropped upstream don't linger.
+		if err := os.RemoveAll(dst); err != nil {
+			return err
+		}
+		return copyDirSimple(ef.SrcDir, dst)
+	}
+	return writeFile(dst, ef.Data)
+}
+
+// ExportToFormat runs `nav-pilot export <format>`.
+func ExportToFormat(f *ExportFormat, scope *domain.InstallScope, ref, sourceRepo, cliVersion string, dryRun, force, jsonOutput bool) error {
+	layout, err := f.Layout(scope)
+	if err != nil {
+		return err
+	}
+	src, err := source.ResolveSource(ref, sourceRepo, cliVersion)
+	if err != nil {
+		return err
+	}
+	defer src.Cleanup()
+
+	if !jsonOutput {
+		if dryRun {
+			fmt.Printf("%s Export %s to %s\n\n", domain.Dim("→"), f.Label, domain.Dim(layout.Root))
+		} else {
+			fmt.Printf("Exporting %s to %s\n\n", f.Label, domain.Bold(layout.Root))
+		}
+	}
+
+	result, err := SyncExport(f, layout, src.Resolver(), src.Version, src.SHA, src.Repo, dryRun, force)
+	if err != nil {
+		return err
+	}
+
+	if jsonOutput {
+		return outputJSON(map[string]interface{}{
+			"command":    "export",
+			"format":     f.Name,
+			"output_dir": layout.Root,
+			"written":    result.Written,
+			"unchanged":  result.Unchanged,
+			"conflicts":  result.Conflicts,
+			"removed":    result.Removed,
+			"dry_run":    dryRun,
+		})
+	}
+
+	PrintExportResult(result, dryRun)
+
+	action := "Exported"
+	if dryRun {
+		action = "Would export"
+	}
+	fmt.Printf("\n%s %s %d file(s) for %s (%d unchanged).\n",
+		domain.Green("✓"), action, len(result.Written), f.Label, result.Unchanged)
+	if len(result.Conflicts) > 0 {
+		fmt.Printf("%s %d file(s) skipped due to conflicts. Use %s to overwrite.\n",
+			domain.Yellow("⚠"), len(result.Conflicts), domain.Bold("--force"))
+	}
+	return nil
+}
+
+// PrintExportResult prints one line per written, removed or conflicting path.
+func PrintExportResult(result *ExportResult, dryRun bool) {
+	for _, p := range result.Written {
+		if dryRun {
+			fmt.Printf("  %s %s\n", domain.Dim("→"), p)
+		} else {
+			fmt.Printf("  %s %s\n", domain.Green("✓"), p)
+		}
+	}
+	for _, p := range result.Removed {
+		if dryRun {
+			fmt.Printf("  %s %s\n", domain.Dim("×"), p)
+		} else {
+			fmt.Printf("  %s %s\n", domain.Red("×"), p)
+		}
+	}
+	for _, p := range result.Conflicts {
+		fmt.Printf("  %s %s (exists, differs — not overwritten)\n", domain.Yellow("⊘"), p)
+	}
+}
+
+// ─── Claude Code ─────────────────────────────────────────────────────────────
+
+// claudeLayout: <repo>/.claude/ + <repo>/CLAUDE.md, or ~/.claude/ for user scope.
+func claudeLayout(scope *domain.InstallScope) (ExportLayout, error) {
+	if scope.IsUser() {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return ExportLayout{}, err
+		}
+		return ExportLayout{Root: filepath.Join(home, ".claude")}, nil
+	}
+	return ExportLayout{Root: scope.RootDir, Dir: ".claude"}, nil
+}
+
+// renderClaude maps agents to subagents, skills as-is, prompts to slash
+// commands, and instructions to CLAUDE.md plus lazily loaded scoped files.
+func renderClaude(resolver *source.SourceResolver, l ExportLayout) ([]ExportFile, error) {
+	var files []ExportFile
+
+	for _, entry := range resolver.List(source.KindAgent) {
+		data, err := os.ReadFile(entry.AbsPath)
+		if err != nil {
+			return nil, fmt.Errorf("reading agent %s: %w", entry.Name, err)
+		}
+		name := entry.Name
+		files = append(files, ExportFile{
+			Path: l.rel("agents", name+".md"),
+			Data: rewriteAgentFrontmatter(data, func(description string) []byte {
+				return source.BuildClaudeAgentFrontmatter(name, description)
+			}),
+		})
+	}
+
+	for _, skill := range resolver.List(source.KindSkill) {
+		files = append(files, ExportFile{Path: l.rel("skills", skill.Name) + "/", SrcDir: skill.AbsPath})
+	}
+
+	for _, entry := range resolver.List(source.KindPrompt) {
+		if entry.IsDir {
+			continue
+		}
+		data, err := os.ReadFile(entry.AbsPath)
+		if err != nil {
+			return nil, fmt.Errorf("reading prompt %s: %w", entry.Name, err)
+		}
+		files = append(files, ExportFile{Path: l.rel("commands", entry.Name+".md"), Data: transformPrompt(data)})
+	}
+
+	globalSections, scopedRefs, err := collectResolvedInstructionData(resolver)
+	if err != nil {
+		return nil, err
+	}
+	if len(globalSections) > 0 || len(scopedRefs) > 0 {
+		for _, ref := range scopedRefs {
+			files = append(files, ExportFile{Path: l.rel("instructions", ref.Name+".md"), Data: ref.Body})
+		}
+		files = append(files, ExportFile{
+			Path: "CLAUDE.md",
+			Data: buildInstructionIndex("claude", globalSections, scopedRefs, func(name string) string {
+				return "`" + l.ref("instructions", name+".md") + "`"
+			}),
+		})
+	}
+	return files, nil
+}
+
+// ─── Cursor ──────────────────────────────────────────────────────────────────
+
+// cursorLayout: <repo>/.cursor/rules/. Cursor keeps user rules in its
+// settings, not on disk, so only repo scope is supported.
+func cursorLayout(scope *domain.InstallScope) (ExportLayout, error) {
+	if scope.IsUser() {
+		return ExportLayout{}, fmt.Errorf("cursor export supports repo scope only (Cursor user rules live in its settings)")
+	}
+	return ExportLayout{Root: scope.RootDir, Dir: ".cursor"}, nil
+}
+
+// renderCursor writes one .mdc rule per instruction: global ones always
+// apply, scoped ones attach by the globs in their applyTo.
+func renderCursor(resolver *source.SourceResolver, l ExportLayout) ([]ExportFile, error) {
+	globalSections, scopedRefs, err := collectResolvedInstructionData(resolver)
+	if err != nil {
+		return nil, err
+	}
+	var files []ExportFile
+	for _, s := range globalSections {
+		files = append(files, ExportFile{
+			Path: l.rel("rules", s.Slug+".mdc"),
+			Data: buildCursorRule(s.Name, "", true, s.Body),
+		})
+	}
+	for _, ref := range scopedRefs {
+		files = append(files, ExportFile{
+			Path: l.rel("rules", ref.Name+".mdc"),
+			Data: buildCursorRule(titleCase(strings.ReplaceAll(ref.Name, "-", " ")), ref.ApplyTo, false, ref.Body),
+		})
+	}
+	return files, nil
+}
+
+// buildCursorRule renders an .mdc rule. Cursor reads globs as a raw
+// comma-separated list, the same shape as applyTo, so it is not quoted.
+func buildCursorRule(description, globs string, alwaysApply bool, body []byte) []byte {
+	var buf strings.Builder
+	buf.WriteString("---\n")
+	buf.WriteString("description: " + description + "\n")
+	buf.WriteString("globs: " + globs + "\n")
+	buf.WriteString(fmt.Sprintf("alwaysApply: %t\n", alwaysApply))
+	buf.WriteString("---\n\n")
+	buf.WriteString("<!-- Auto-generated by nav-pilot export cursor — do not edit manually -->\n\n")
+	buf.WriteString(strings.TrimSpace(string(body)))
+	buf.WriteByte('\n')
+	return []byte(buf.String())
+}
+
+// ─── Codex ───────────────────────────────────────────────────────────────────
+
+// codexLayout: <repo>/AGENTS.md with state in <repo>/.codex/, or ~/.codex/.
+func codexLayout(scope *domain.InstallScope) (ExportLayout, error) {
+	if scope.IsUser() {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return ExportLayout{}, err
+		}
+		return ExportLayout{Root: filepath.Join(home, ".codex")}, nil
+	}
+	return ExportLayout{Root: scope.RootDir, Dir: ".codex"}, nil
+}
+
+// renderCodex inlines every instruction into AGENTS.md. Codex has no
+// per-file instruction mechanism, so scoped ones carry their applyTo.
+func renderCodex(resolver *source.SourceResolver, _ ExportLayout) ([]ExportFile, error) {
+	globalSections, scopedRefs, err := collectResolvedInstructionData(resolver)
+	if err != nil {
+		return nil, err
+	}
+	if len(globalSections) == 0 && len(scopedRefs) == 0 {
+		return nil, nil
+	}
+
+	var buf strings.Builder
+	buf.WriteString("<!-- Auto-generated by nav-pilot export codex — do not edit manually -->\n\n")
+	for i, s := range globalSections {
+		if i > 0 {
+			buf.WriteString("\n---\n\n")
+		}
+		buf.WriteString("## " + s.Name + "\n\n")
+		buf.WriteString(strings.TrimSpace(string(s.Body)))
+		buf.WriteByte('\n')
+	}
+	for i, ref := range scopedRefs {
+		if i > 0 || len(globalSections) > 0 {
+			buf.WriteString("\n---\n\n")
+		}
+		buf.WriteString("## " + titleCase(strings.ReplaceAll(ref.Name, "-", " ")) + "\n\n")
+		buf.WriteString(fmt.Sprintf("_Applies to files matching `%s`._\n\n", ref.ApplyTo))
+		buf.WriteString(strings.TrimSpace(string(ref.Body)))
+		buf.WriteByte('\n')
+	}
+	return []ExportFile{{Path: "AGENTS.md", Data: []byte(buf.String())}}, nil
+}
@@ -0,0 +1,174 @@
+package artifacts
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/navikt/copilot/cli/nav-pilot/internal/domain"
+	"github.com/navikt/copilot/cli/nav-pilot/internal/source"
+)
+
+func readFile(t *testing.T, path string) string {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("reading %s: %v", path, err)
+	}
+	return string(data)
+}
+
+func syncFormat(t *testing.T, name, sourceDir string, scope *domain.InstallScope, dryRun, force bool) (*ExportResult, ExportLayout) {
+	t.Helper()
+	f := ExportFormatByName(name)
+	layout, err := f.Layout(scope)
+	if err != nil {
+		t.Fatalf("layout: %v", err)
+	}
+	result, err := SyncExport(f, layout, source.NewSourceResolver(sourceDir), "v1", "abc1234", "navikt/copilot", dryRun, force)
+	if err != nil {
+		t.Fatalf("SyncExport(%s): %v", name, err)
+	}
+	return result, layout
+}
+
+func TestExportClaude_Repo(t *testing.T) {
+	sourceDir := setupTestSource(t)
+	repo := t.TempDir()
+	result, layout := syncFormat(t, "claude", sourceDir, domain.ScopeRepo(repo), false, false)
+
+	agent := readFile(t, filepath.Join(repo, ".claude", "agents", "auth.md"))
+	if !strings.HasPrefix(agent, "---\nname: auth\ndescription: Authentication and authorization expert\n---\n") {
+		t.Errorf("agent frontmatter not converted:\n%s", agent)
+	}
+	if strings.Contains(agent, "tools:") || !strings.Contains(agent, "You handle auth flows") {
+		t.Errorf("agent body/tools wrong:\n%s", agent)
+	}
+	readFile(t, filepath.Join(repo, ".claude", "skills", "security-review", "checklist.md"))
+	readFile(t, filepath.Join(repo, ".claude", "commands", "aksel-component.md"))
+	readFile(t, filepath.Join(repo, ".claude", "instructions", "database.md"))
+
+	claudeMD := readFile(t, filepath.Join(repo, "CLAUDE.md"))
+	for _, want := range []string{"export claude", "Global Nav Standards", "`**/*.tsx` → `.claude/instructions/accessibility.md`"} {
+		if !strings.Contains(claudeMD, want) {
+			t.Errorf("CLAUDE.md missing %q:\n%s", want, claudeMD)
+		}
+	}
+
+	state, err := ReadExportState(ExportFormatByName("claude"), layout)
+	if err != nil || state == nil || state.Scope != "claude" || len(state.Files) != len(result.Written) {
+		t.Fatalf("state = %+v, %v (written %d)", state, err, len(result.Written))
+	}
+	if _, err := os.Stat(filepath.Join(repo, ".claude", ".nav-pilot-state.json")); err != nil {
+		t.Errorf("state file not in .claude/: %v", err)
+	}
+}
+
+func TestExportClaude_UserUsesAbsoluteRefs(t *testing.T) {
+	sourceDir := setupTestSource(t)
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("USERPROFILE", home)
+	scope := &domain.InstallScope{Name: "user", RootDir: filepath.Join(home, ".copilot")}
+
+	syncFormat(t, "claude", sourceDir, scope, false, false)
+	claudeMD := readFile(t, filepath.Join(home, ".claude", "CLAUDE.md"))
+	want := filepath.ToSlash(filepath.Join(home, ".claude", "instructions", "database.md"))
+	if !strings.Contains(claudeMD, want) {
+		t.Errorf("CLAUDE.md should reference %s:\n%s", want, claudeMD)
+	}
+	readFile(t, filepath.Join(home, ".claude", "agents", "nav-pilot.md"))
+}
+
+func TestExportCursor(t *testing.T) {
+	sourceDir := setupTestSource(t)
+	repo := t.TempDir()
+	syncFormat(t, "cursor", sourceDir, domain.ScopeRepo(repo), false, false)
+
+	scoped := readFile(t, filepath.Join(repo, ".cursor", "rules", "database.mdc"))
+	if !strings.HasPrefix(scoped, "---\ndescription: Database\nglobs: **/db/migration/**/*.sql\nalwaysApply: false\n---\n") {
+		t.Errorf("scoped rule:\n%s", scoped)
+	}
+	global := readFile(t, filepath.Join(repo, ".cursor", "rules", "copilot-instructions.mdc"))
+	if !strings.Contains(global, "alwaysApply: true") || !strings.Contains(global, "Global Nav Standards") {
+		t.Errorf("global rule:\n%s", global)
+	}
+
+	if _, err := ExportFormatByName("cursor").Layout(&domain.InstallScope{Name: "user"}); err == nil {
+		t.Error("cursor should reject user scope")
+	}
+}
+
+func TestExportCodex(t *testing.T) {
+	sourceDir := setupTestSource(t)
+	repo := t.TempDir()
+	syncFormat(t, "codex", sourceDir, domain.ScopeRepo(repo), false, false)
+
+	agentsMD := readFile(t, filepath.Join(repo, "AGENTS.md"))
+	for _, want := range []string{"export codex", "## Global Instructions", "## Accessibility", "_Applies to files matching `**/*.tsx`._", "Always use semantic HTML"} {
+		if !strings.Contains(agentsMD, want) {
+			t.Errorf("AGENTS.md missing %q:\n%s", want, agentsMD)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(repo, ".codex", ".nav-pilot-state.json")); err != nil {
+		t.Errorf("codex state missing: %v", err)
+	}
+}
+
+func TestSyncExport_ConflictsAndRemovals(t *testing.T) {
+	sourceDir := setupTestSource(t)
+	repo := t.TempDir()
+	scope := domain.ScopeRepo(repo)
+
+	// An AGENTS.md nav-pilot didn't write is never overwritten without force.
+	mustWrite(t, filepath.Join(repo, "AGENTS.md"), "# Team notes\n")
+	result, _ := syncFormat(t, "codex", sourceDir, scope, false, false)
+	if len(result.Conflicts) != 1 || readFile(t, filepath.Join(repo, "AGENTS.md")) != "# Team notes\n" {
+		t.Fatalf("untracked file: result = %+v", result)
+	}
+	syncFormat(t, "codex", sourceDir, scope, false, true)
+
+	// Upstream change applies; a local edit turns the next change into a conflict.
+	mustWrite(t, filepath.Join(sourceDir, "instructions", "database.instructions.md"), "---\napplyTo: \"**/*.sql\"\n---\n\nv2\n")
+	if result, _ = syncFormat(t, "codex", sourceDir, scope, true, false); len(result.Written) != 1 {
+		t.Fatalf("dry run should report the update: %+v", result)
+	}
+	syncFormat(t, "codex", sourceDir, scope, false, false)
+	if !strings.Contains(readFile(t, filepath.Join(repo, "AGENTS.md")), "v2") {
+		t.Error("upstream change not applied")
+	}
+	mustWrite(t, filepath.Join(repo, "AGENTS.md"), "# edited\n")
+	mustWrite(t, filepath.Join(sourceDir, "instructions", "database.instructions.md"), "---\napplyTo: \"**/*.sql\"\n---\n\nv3\n")
+	if result, _ = syncFormat(t, "codex", sourceDir, scope, false, false); len(result.Conflicts) != 1 {
+		t.Fatalf("local edit should conflict: %+v", result)
+	}
+	if readFile(t, filepath.Join(repo, "AGENTS.md")) != "# edited\n" {
+		t.Error("locally edited file was overwritten")
+	}
+
+	// Artifacts removed upstream are removed from the export.
+	syncFormat(t, "claude", sourceDir, scope, false, false)
+	os.Remove(filepath.Join(sourceDir, "agents", "auth.agent.md"))
+	result, _ = syncFormat(t, "claude", sourceDir, scope, false, false)
+	if len(result.Removed) != 1 || result.Removed[0] != ".claude/agents/auth.md" {
+		t.Fatalf("removed = %v", result.Removed)
+	}
+	if _, err := os.Stat(filepath.Join(repo, ".claude", "agents", "auth.md")); !os.IsNotExist(err) {
+		t.Errorf("stale agent still present: %v", err)
+	}
+}
+
+func TestExportLayout_ValidStatePath(t *testing.T) {
+	l := ExportLayout{Root: "/repo", Dir: ".claude"}
+	for _, p := range []string{".claude/agents/x.md", "CLAUDE.md"} {
+		if err := l.validStatePath(p); err != nil {
+			t.Errorf("%s: %v", p, err)
+		}
+	}
+	for _, p := range []string{"../x.md", "/etc/passwd", "src/main.go", ".github/agents/x.md"} {
+		if err := l.validStatePath(p); err == nil {
+			t.Errorf("%s should be rejected", p)
+		}
+	}
+}
@@ -0,0 +1,274 @@
+package artifacts
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"syscall"
+	"time"
+
+	"github.com/navikt/copilot/cli/nav-pilot/internal/domain"
+	"github.com/navikt/copilot/cli/nav-pilot/internal/source"
+)
+
+// JournalDir is the directory next to the state file that holds one journal
+// per mutating operation: the paths it touched and their previous content.
+const JournalDir = ".nav-pilot-journal"
+
+// JournalKeep is how many finished journals are kept per scope.
+const JournalKeep = 10
+
+// Journal statuses. A journal is pending from the first write until the
+// operation finishes; a pending journal found later means it was interrupted.
+const (
+	JournalPending    = "pending"
+	JournalCommitted  = "committed"
+	JournalRolledBack = "rolled_back"
+)
+
+const journalFile = "journal.json"
+
+// JournalEntry is one path touched by an operation. Existed is false when the
+// operation created it, so rolling back removes it.
+type JournalEntry struct {
+	Path    string `json:"path"`
+	IsDir   bool   `json:"is_dir,omitempty"`
+	Existed bool   `json:"existed"`
+}
+
+// Journal records the pre-images of everything one operation changes, so the
+// operation can be undone as a whole.
+type Journal struct {
+	ID        string         `json:"id"`
+	Command   string         `json:"command"`
+	Scope     string         `json:"scope"`
+	StartedAt string         `json:"started_at"`
+	PID       int            `json:"pid,omitempty"` // process that wrote it
+	Status    string         `json:"status"`
+	Entries   []JournalEntry `json:"entries"`
+
+	dir      string
+	rootDir  string
+	tracked  map[string]bool
+	recorded bool
+}
+
+// JournalRoot returns the journal directory for a scope.
+func JournalRoot(scope *domain.InstallScope) string {
+	return filepath.Join(filepath.Dir(scope.StatePath()), JournalDir)
+}
+
+// BeginJournal starts a pending journal for command. Nothing is written until
+// the first Track, so operations that change nothing leave no journal.
+func BeginJournal(scope *domain.InstallScope, command string, now time.Time) *Journal {
+	root := JournalRoot(scope)
+	id := now.UTC().Format("20060102T150405.000000000Z")
+	for i := 1; ; i++ {
+		if _, err := os.Lstat(filepath.Join(root, id)); os.IsNotExist(err) {
+			break
+		}
+		id = fmt.Sprintf("%s-%d", now.UTC().Format("20060102T150405.000000000Z"), i)
+	}
+	return &Journal{
+		ID:        id,
+		Command:   command,
+		Scope:     scope.Name,
+		StartedAt: now.UTC().Format(time.RFC3339),
+		PID:       os.Getpid(),
+		Status:    JournalPending,
+		dir:       filepath.Join(root, id),
+		rootDir:   scope.RootDir,
+		tracked:   make(map[string]bool),
+	}
+}
+
+// Track backs up the current content of relPath (relative to the scope root)
+// and records it in the journal before the caller changes it. Only the first
+// call per path counts: later calls would capture the operation's own writes.
+func (j *Journal) Track(relPath string) error {
+	rel := filepath.ToSlash(filepath.Clean(filepath.FromSlash(relPath)))
+	if j.tracked[rel] {
+		return nil
+	}
+	target := filepath.Join(j.rootDir, filepath.FromSlash(rel))
+	if err := source.CheckSymlink(target, j.rootDir); err != nil {
+		return err
+	}
+
+	entry := JournalEntry{Path: rel}
+	info, err := os.Lstat(target)
+	switch {
+	case err == nil:
+		entry.Existed = true
+		entry.IsDir = info.IsDir()
+		if err := source.CopyArtifact(target, j.backupPath(rel), j.rootDir, entry.IsDir); err != nil {
+			return fmt.Errorf("backing up %s: %w", rel, err)
+		}
+	case !os.IsNotExist(err):
+		return err
+	}
+
+	j.Entries = append(j.Entries, entry)
+	if err := j.save(); err != nil {
+		j.Entries = j.Entries[:len(j.Entries)-1]
+		return err
+	}
+	j.tracked[rel] = true
+	return nil
+}
+
+// Commit marks the operation as finished. A journal that tracked nothing is
+// discarded. Older journals beyond keep are pruned.
+func (j *Journal) Commit(scope *domain.InstallScope, keep int) error {
+	if !j.recorded {
+		return nil
+	}
+	j.Status = JournalCommitted
+	if err := j.save(); err != nil {
+		return err
+	}
+	return PruneJournals(scope, keep)
+}
+
+// Restore puts every tracked path back the way it was, newest entry first,
+// and marks the journal rolled back. It is safe to run again after an
+// interrupted restore.
+func (j *Journal) Restore() error {
+	for i := len(j.Entries) - 1; i >= 0; i-- {
+		e := j.Entries[i]
+		target := filepath.Join(j.rootDir, filepath.FromSlash(e.Path))
+		if err := source.CheckSymlink(target, j.rootDir); err != nil {
+			return err
+		}
+		if err := os.RemoveAll(target); err != nil {
+			return fmt.Errorf("restoring %s: %w", e.Path, err)
+		}
+		if !e.Existed {
+			continue
+		}
+		if err := source.CopyArtifact(j.backupPath(e.Path), target, j.rootDir, e.IsDir); err != nil {
+			return fmt.Errorf("restoring %s: %w", e.Path, err)
+		}
+	}
+	if !j.recorded {
+		return nil
+	}
+	j.Status = JournalRolledBack
+	return j.save()
+}
+
+// OwnerRunning reports whether the journal belongs to another process that
+// is still running: a pending journal is then in progress, not interrupted.
+// Journals written before the PID was recorded have no owner.
+func (j *Journal) OwnerRunning() bool {
+	if j.PID <= 0 || j.PID == os.Getpid() {
+		return false
+	}
+	// Signal 0 checks that the process exists; EPERM means it does but
+	// belongs to another user.
+	err := syscall.Kill(j.PID, 0)
+	return err == nil || errors.Is(err, syscall.EPERM)
+}
+
+// Dir returns the directory holding this journal.
+func (j *Journal) Dir() string {
+	return j.dir
+}
+
+func (j *Journal) backupPath(rel string) string {
+	return filepath.Join(j.dir, "files", filepath.FromSlash(rel))
+}
+
+// save writes journal.json. The first save also drops a .gitignore in the
+// journal root so repo-scope journals are never committed.
+func (j *Journal) save() error {
+	if !j.recorded {
+		root := filepath.Dir(j.dir)
+		if err := os.MkdirAll(root, 0o755); err != nil {
+			return err
+		}
+		ignore := filepath.Join(root, ".gitignore")
+		if _, err := os.Lstat(ignore); os.IsNotExist(err) {
+			if err := os.WriteFile(ignore, []byte("*\n"), 0o644); err != nil {
+				return err
+			}
+		}
+		j.recorded = true
+	}
+	return writeJSONAt(filepath.Join(j.dir, journalFile), j.rootDir, ".journal-*", j)
+}
+
+// ListJournals returns the recorded journals for a scope, oldest first.
+// Unreadable journal directories are skipped.
+func ListJournals(scope *domain.InstallScope) ([]*Journal, error) {
+	root := JournalRoot(scope)
+	entries, err := os.ReadDir(root)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var journals []*Journal
+	for _, e := range entries {
+		if !e.IsDir() {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(root, e.Name(), journalFile))
+		if err != nil {
+			continue
+		}
+		var j Journal
+		if err := json.Unmarshal(data, &j); err != nil || j.ID != e.Name() {
+			continue
+		}
+		if !validJournal(scope, &j) {
+			continue
+		}
+		j.dir = filepath.Join(root, e.Name())
+		j.rootDir = scope.RootDir
+		j.recorded = true
+		journals = append(journals, &j)
+	}
+	sort.SliceStable(journals, func(a, b int) bool { return journals[a].ID < journals[b].ID })
+	return journals, nil
+}
+
+// validJournal rejects journals from another scope or with paths that escape
+// the scope root, since Restore deletes and rewrites every entry.
+func validJournal(scope *domain.InstallScope, j *Journal) bool {
+	if j.Scope != scope.Name {
+		return false
+	}
+	for _, e := range j.Entries {
+		if !filepath.IsLocal(filepath.FromSlash(e.Path)) {
+			return false
+		}
+	}
+	return true
+}
+
+// PruneJournals deletes the oldest finished journals beyond keep. Pending
+// journals are never pruned: they still need to be recovered.
+func PruneJournals(scope *domain.InstallScope, keep int) error {
+	journals, err := ListJournals(scope)
+	if err != nil {
+		return err
+	}
+	var finished []*Journal
+	for _, j := range journals {
+		if j.Status != JournalPending {
+			finished = append(finished, j)
+		}
+	}
+	for len(finished) > keep {
+		if err := os.RemoveAll(finished[0].dir); err != nil {
+			return err
+		}
+		finished = finished[1:]
+	}
+	return nil
+}
@@ -0,0 +1,128 @@
+package artifacts
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/navikt/copilot/cli/nav-pilot/internal/domain"
+)
+
+func TestJournal_TrackAndRestore(t *testing.T) {
+	dir := t.TempDir()
+	scope := domain.ScopeRepo(dir)
+	agent := filepath.Join(dir, ".github", "agents", "nais.agent.md")
+	skill := filepath.Join(dir, ".github", "skills", "api")
+	mustWrite(t, agent, "old agent")
+	mustWrite(t, filepath.Join(skill, "SKILL.md"), "old skill")
+	mustWrite(t, filepath.Join(skill, "references", "a.md"), "ref")
+
+	j := BeginJournal(scope, "sync", time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC))
+	for _, rel := range []string{".github/agents/nais.agent.md", ".github/skills/api/", ".github/agents/new.agent.md"} {
+		if err := j.Track(rel); err != nil {
+			t.Fatalf("Track(%s): %v", rel, err)
+		}
+	}
+	// Second track of the same path must not overwrite the pre-image.
+	mustWrite(t, agent, "new agent")
+	if err := j.Track(".github/agents/nais.agent.md"); err != nil {
+		t.Fatal(err)
+	}
+	if len(j.Entries) != 3 {
+		t.Fatalf("entries = %+v", j.Entries)
+	}
+	if j.Entries[1].Path != ".github/skills/api" || !j.Entries[1].IsDir {
+		t.Errorf("dir entry = %+v", j.Entries[1])
+	}
+
+	os.RemoveAll(filepath.Join(skill, "references"))
+	mustWrite(t, filepath.Join(skill, "SKILL.md"), "new skill")
+	mustWrite(t, filepath.Join(dir, ".github", "agents", "new.agent.md"), "created")
+
+	journals, err := ListJournals(scope)
+	if err != nil || len(journals) != 1 || journals[0].Status != JournalPending {
+		t.Fatalf("ListJournals = %+v, %v", journals, err)
+	}
+	if err := journals[0].Restore(); err != nil {
+		t.Fatal(err)
+	}
+	for path, want := range map[string]string{
+		agent:                            "old agent",
+		filepath.Join(skill, "SKILL.md"): "old skill",
+		filepath.Join(skill, "references", "a.md"): "ref",
+	} {
+		if got, _ := os.ReadFile(path); string(got) != want {
+			t.Errorf("%s = %q, want %q", path, got, want)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(dir, ".github", "agents", "new.agent.md")); !os.IsNotExist(err) {
+		t.Error("created file should be removed on restore")
+	}
+	journals, _ = ListJournals(scope)
+	if journals[0].Status != JournalRolledBack {
+		t.Errorf("status = %q", journals[0].Status)
+	}
+	if data, _ := os.ReadFile(filepath.Join(JournalRoot(scope), ".gitignore")); string(data) != "*\n" {
+		t.Errorf(".gitignore = %q", data)
+	}
+}
+
+func TestJournal_CommitDiscardsEmptyAndPrunes(t *testing.T) {
+	dir := t.TempDir()
+	scope := domain.ScopeRepo(dir)
+	start := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+
+	empty := BeginJournal(scope, "sync", start)
+	if err := empty.Commit(scope, 2); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(JournalRoot(scope)); !os.IsNotExist(err) {
+		t.Error("a journal that tracked nothing should leave nothing on disk")
+	}
+
+	pending := BeginJournal(scope, "install", start)
+	if err := pending.Track(".github/.nav-pilot-state.json"); err != nil {
+		t.Fatal(err)
+	}
+	for i := 1; i <= 4; i++ {
+		j := BeginJournal(scope, "sync", start.Add(time.Duration(i)*time.Minute))
+		if err := j.Track(".github/.nav-pilot-state.json"); err != nil {
+			t.Fatal(err)
+		}
+		if err := j.Commit(scope, 2); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	journals, err := ListJournals(scope)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(journals) != 3 {
+		t.Fatalf("kept %d journals, want 3 (pending + 2 committed)", len(journals))
+	}
+	if journals[0].ID != pending.ID || journals[0].Status != JournalPending {
+		t.Errorf("pending journal was pruned: %+v", journals[0])
+	}
+	if journals[2].StartedAt != "2026-10-18T12:04:00Z" {
+		t.Errorf("newest = %s", journals[2].StartedAt)
+	}
+}
+
+func TestListJournals_SkipsUnsafeEntries(t *testing.T) {
+	dir := t.TempDir()
+	scope := domain.ScopeRepo(dir)
+	mustWrite(t, filepath.Join(JournalRoot(scope), "x", journalFile),
+		`{"id":"x","command":"sync","scope":"repo","status":"committed","entries":[{"path":"../../etc/passwd","existed":false}]}`)
+	mustWrite(t, filepath.Join(JournalRoot(scope), "y", journalFile),
+		`{"id":"y","command":"sync","scope":"user","status":"committed","entries":[]}`)
+
+	journals, err := ListJournals(scope)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(journals) != 0 {
+		t.Errorf("journals = %+v, want none", journals)
+	}
+}
@@ -0,0 +1,55 @@
+package artifacts
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/navikt/copilot/cli/nav-pilot/internal/domain"
+)
+
+// LockFileName is the lockfile written next to the state file.
+const LockFileName = "nav-pilot.lock"
+
+// LockPath returns the lockfile location for a scope
+// (.github/nav-pilot.lock for repo scope).
+func LockPath(scope *domain.InstallScope) string {
+	return filepath.Join(filepath.Dir(scope.StatePath()), LockFileName)
+}
+
+// ReadLock reads the scope's lockfile. Returns nil, nil when there is none.
+// Artifact paths are validated like state paths.
+func ReadLock(scope *domain.InstallScope) (*domain.LockFile, error) {
+	data, err := os.ReadFile(LockPath(scope))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var lock domain.LockFile
+	if err := json.Unmarshal(data, &lock); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", LockFileName, err)
+	}
+	if lock.LockVersion > domain.LockFileVersion {
+		return nil, fmt.Errorf("%s has lock_version %d; upgrade nav-pilot to read it", LockFileName, lock.LockVersion)
+	}
+	if lock.Scope != "" && lock.Scope != scope.Name {
+		return nil, fmt.Errorf("%s scope mismatch: expected %q, got %q", LockFileName, scope.Name, lock.Scope)
+	}
+	if len(lock.Sources) == 0 {
+		return nil, fmt.Errorf("%s has no sources", LockFileName)
+	}
+	for _, a := range lock.Artifacts {
+		if err := scope.ValidateStatePath(a.Path); err != nil {
+			return nil, fmt.Errorf("unsafe %s: %w", LockFileName, err)
+		}
+	}
+	return &lock, nil
+}
+
+// WriteLock writes the scope's lockfile atomically.
+func WriteLock(scope *domain.InstallScope, lock *domain.LockFile) error {
+	return writeJSONAt(LockPath(scope), scope.RootDir, ".nav-pilot-lock-*", lock)
+}
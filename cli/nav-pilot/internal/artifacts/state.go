@@ -68,18 +68,24 @@ func WriteScopedState(scope *domain.InstallScope, state *domain.StateFile) error
 }
 
 func WriteStateAt(path, boundary string, state *domain.StateFile) error {
+	return writeJSONAt(path, boundary, ".nav-pilot-state-*", state)
+}
+
+// writeJSONAt writes v as indented JSON via a temp file and rename, refusing
+// to follow symlinks out of boundary.
+func writeJSONAt(path, boundary, tmpPattern string, v interface{}) error {
 	if err := source.CheckSymlink(path, boundary); err != nil {
 		return err
 	}
 	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
 		return err
 	}
-	data, err := json.MarshalIndent(state, "", "  ")
+	data, err := json.MarshalIndent(v, "", "  ")
 	if err != nil {
 		return err
 	}
 	data = append(data, '\n')
-	tmp, err := os.CreateTemp(filepath.Dir(path), ".nav-pilot-state-*")
+	tmp, err := os.CreateTemp(filepath.Dir(path), tmpPattern)
 	if err != nil {
 		return err
 	}
@@ -8,7 +8,15 @@ import (
 
 // cmdAdd installs a single agent, skill, instruction, or prompt from the source repo.
 // It appends to the existing state file if one exists.
-func cmdAdd(itemType, name string, scope *InstallScope, ref, sourceRepo string, dryRun, force bool, jsonOutput bool) error {
+func cmdAdd(itemType, name string, scope *InstallScope, ref, sourceRepo string, dryRun, force bool, jsonOutput bool) (err error) {
+	if !dryRun {
+		tx, txErr := beginJournal(scope, "add")
+		if txErr != nil {
+			return txErr
+		}
+		defer tx.finish(&err)
+	}
+
 	// Validate type
 	switch itemType {
 	case "agent", "skill", "instruction", "prompt":
@@ -40,11 +48,6 @@ func cmdAdd(itemType, name string, scope *InstallScope, ref, sourceRepo string,
 	}
 	defer src.Cleanup()
 
-	sourceLabel := "navikt/copilot"
-	if sourceRepo != "" {
-		sourceLabel = sourceRepo
-	}
-
 	result := &installResult{}
 
 	if !jsonOutput {
@@ -54,18 +57,21 @@ func cmdAdd(itemType, name string, scope *InstallScope, ref, sourceRepo string,
 		} else {
 			fmt.Println(bold(fmt.Sprintf("Adding %s: %s", itemType, name)))
 		}
-		fmt.Printf("%s %s\n", dim("Source:"), dim(fmt.Sprintf("%s@%s", sourceLabel, src.SHA)))
+		fmt.Printf("%s %s\n", dim("Source:"), dim(sourceLabel(src)))
 		fmt.Printf("%s %s\n", dim("Target:"), dim(scope.Label()))
 		fmt.Println()
 	}
 
 	// Dispatch to the appropriate installer
 	kind := kindByName[itemType]
-	resolver := NewSourceResolver(src.Dir)
+	resolver := src.Resolver()
 	installErr := installArtifact(resolver, scope, kind, name, dryRun, force, result)
 	if installErr != nil {
 		return installErr
 	}
+	if err := installDependencies(resolver, scope, Dependency{Kind: kind, Name: name}, dryRun, force, result); err != nil {
+		return err
+	}
 
 	if jsonOutput {
 		return outputJSON(map[string]interface{}{
@@ -130,6 +136,7 @@ func cmdAdd(itemType, name string, scope *InstallScope, ref, sourceRepo string,
 	if err := writeScopedState(scope, state); err != nil {
 		fmt.Fprintf(os.Stderr, "%s Could not write state file: %v\n", yellow("⚠"), err)
 	}
+	writeLockFromState(scope, src, state)
 
 	fmt.Printf("\n%s Added %s %q.\n", green("✓"), itemType, name)
 	return nil
@@ -1,6 +1,7 @@
 package cli
 
 import (
+	"encoding/json"
 	"os"
 	"path/filepath"
 	"strings"
@@ -328,12 +329,69 @@ func TestListAvailableItems(t *testing.T) {
 	os.WriteFile(filepath.Join(ghDir, "prompts", "test.prompt.md"), []byte("# Prompt"), 0o644)
 
 	// Should not panic/error
-	err := listAvailableItems(source)
+	err := listAvailableItems(NewSourceResolver(source))
 	if err != nil {
 		t.Fatalf("listAvailableItems: %v", err)
 	}
 }
 
+func TestCmdList_ItemsShowsInheritance(t *testing.T) {
+	srcDir := t.TempDir()
+	for name, manifest := range map[string]string{
+		"base": `{"name":"base","agents":["nav-pilot"],"skills":["kafka"]}`,
+		"team": `{"name":"team","extends":["base"],"agents":["team-agent"],"exclude":{"skills":["kafka"]}}`,
+	} {
+		os.MkdirAll(filepath.Join(srcDir, "collections", name), 0o755)
+		os.WriteFile(filepath.Join(srcDir, "collections", name, "manifest.json"), []byte(manifest), 0o644)
+	}
+	orig := resolveSource
+	t.Cleanup(func() { resolveSource = orig })
+	resolveSource = func(ref, sourceRepo string) (*Source, error) {
+		return &Source{Dir: srcDir, SHA: "abc1234"}, nil
+	}
+
+	out := captureStdout(func() {
+		if err := cmdList("", "", true, true); err != nil {
+			t.Fatalf("cmdList: %v", err)
+		}
+	})
+	var got struct {
+		Collections []struct {
+			Name     string   `json:"name"`
+			Items    int      `json:"items"`
+			Extends  []string `json:"extends"`
+			Contents []struct {
+				Kind, Name, From string
+			} `json:"contents"`
+		} `json:"collections"`
+	}
+	if err := json.Unmarshal([]byte(out), &got); err != nil {
+		t.Fatalf("parsing %q: %v", out, err)
+	}
+	if len(got.Collections) != 2 || got.Collections[1].Name != "team" {
+		t.Fatalf("collections = %+v", got.Collections)
+	}
+	team := got.Collections[1]
+	var contents []string
+	for _, c := range team.Contents {
+		contents = append(contents, c.Kind+":"+c.Name+"<"+c.From)
+	}
+	if team.Items != 2 || strings.Join(team.Extends, ",") != "base" || strings.Join(contents, "|") != "agent:nav-pilot<base|agent:team-agent<" {
+		t.Errorf("team = %+v", team)
+	}
+
+	out = captureStdout(func() {
+		if err := cmdList("", "", true, false); err != nil {
+			t.Fatalf("cmdList: %v", err)
+		}
+	})
+	for _, want := range []string{"extends: base", "agents: nav-pilot ← base, team-agent"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("list --items output missing %q:\n%s", want, out)
+		}
+	}
+}
+
 // ─── resolveSource (findGitRoot) tests ──────────────────────────────────────
 
 func TestFindGitRoot(t *testing.T) {
@@ -401,7 +459,7 @@ func TestCmdInstall_FullFlow(t *testing.T) {
 		t.Fatalf("loadManifest: %v", err)
 	}
 
-	result, err := installItems(source, ScopeRepo(target), manifest, false, false)
+	result, err := installItems(NewSourceResolver(source), ScopeRepo(target), manifest, false, false)
 	if err != nil {
 		t.Fatalf("installItems: %v", err)
 	}
@@ -433,7 +491,7 @@ func TestCmdInstall_DryRunNoSideEffects(t *testing.T) {
 		t.Fatalf("loadManifest: %v", err)
 	}
 
-	result, err := installItems(source, ScopeRepo(target), manifest, true, false)
+	result, err := installItems(NewSourceResolver(source), ScopeRepo(target), manifest, true, false)
 	if err != nil {
 		t.Fatalf("installItems dry-run: %v", err)
 	}
@@ -461,7 +519,7 @@ func TestInstallArtifact_ConflictStillTracked(t *testing.T) {
 	if err != nil {
 		t.Fatalf("loadManifest: %v", err)
 	}
-	result, err := installItems(source, ScopeRepo(target), manifest, false, false)
+	result, err := installItems(NewSourceResolver(source), ScopeRepo(target), manifest, false, false)
 	if err != nil {
 		t.Fatalf("first install: %v", err)
 	}
@@ -474,7 +532,7 @@ func TestInstallArtifact_ConflictStillTracked(t *testing.T) {
 	os.WriteFile(agentPath, []byte("# Modified locally"), 0o644)
 
 	// Second install WITHOUT force — should report conflict but still track it
-	result2, err := installItems(source, ScopeRepo(target), manifest, false, false)
+	result2, err := installItems(NewSourceResolver(source), ScopeRepo(target), manifest, false, false)
 	if err != nil {
 		t.Fatalf("second install: %v", err)
 	}
@@ -513,7 +571,7 @@ func TestCmdStatus_Integrity(t *testing.T) {
 	os.MkdirAll(filepath.Join(target, ".git"), 0o755)
 
 	manifest, _ := loadManifest(source, "test-collection")
-	result, _ := installItems(source, ScopeRepo(target), manifest, false, false)
+	result, _ := installItems(NewSourceResolver(source), ScopeRepo(target), manifest, false, false)
 
 	state := &StateFile{
 		Collection: "test-collection",
@@ -537,7 +595,7 @@ func TestCmdUninstall_RemovesFiles(t *testing.T) {
 	os.MkdirAll(filepath.Join(target, ".git"), 0o755)
 
 	manifest, _ := loadManifest(source, "test-collection")
-	result, _ := installItems(source, ScopeRepo(target), manifest, false, false)
+	result, _ := installItems(NewSourceResolver(source), ScopeRepo(target), manifest, false, false)
 
 	state := &StateFile{
 		Collection: "test-collection",
@@ -662,7 +720,7 @@ func TestCmdAdd_ClearsIgnoredStatus(t *testing.T) {
 	}
 
 	// Verify resolveSyncFiles includes the file again
-	files, _, err := resolveSyncFiles(scope, "", false)
+	files, _, err := resolveSyncFiles(scope, NewSourceResolver(""), false)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -16,18 +16,26 @@ import (
 
 // Type aliases (zero-cost compile-time redirections)
 type (
-	Config         = domain.Config
-	ResolvedConfig = domain.ResolvedConfig
-	CLIOverrides   = domain.CLIOverrides
-	InstallScope   = domain.InstallScope
-	StateFile      = domain.StateFile
-	InstalledFile  = domain.InstalledFile
+	Config             = domain.Config
+	Profile            = domain.Profile
+	RepoConfig         = domain.RepoConfig
+	ResolvedConfig     = domain.ResolvedConfig
+	CLIOverrides       = domain.CLIOverrides
+	InstallScope       = domain.InstallScope
+	StateFile          = domain.StateFile
+	InstalledFile      = domain.InstalledFile
+	InstalledMCPServer = domain.InstalledMCPServer
+	SourceLayer        = domain.SourceLayer
+	LockFile           = domain.LockFile
+	LockedSource       = domain.LockedSource
+	LockedArtifact     = domain.LockedArtifact
 )
 
 // Constant aliases
 const (
 	fileStatusIgnored  = domain.FileStatusIgnored
 	fileStatusConflict = domain.FileStatusConflict
+	lockFileVersion    = domain.LockFileVersion
 )
 
 // Function and slice aliases — var means they can be called/indexed identically
@@ -48,8 +56,9 @@ var (
 	validLogLevels       = domain.ValidLogLevels
 	validOtelLogLevels   = domain.ValidOtelLogLevels
 
-	ScopeRepo = domain.ScopeRepo
-	ScopeUser = domain.ScopeUser
+	ScopeRepo       = domain.ScopeRepo
+	ScopeSubproject = domain.ScopeSubproject
+	ScopeUser       = domain.ScopeUser
 )
 
 // ─── provider aliases ────────────────────────────────────────────────────────
@@ -63,7 +72,13 @@ type (
 var (
 	providerFor      = providerpkg.ProviderFor
 	allProviders     = providerpkg.AllProviders
+	builtinProviders = providerpkg.BuiltinProviders
 	validProviderIDs = providerpkg.ValidProviderIDs
+	providerIDs      = providerpkg.ProviderIDs
+	pluginPath       = providerpkg.PluginPath
+	pluginError      = providerpkg.PluginError
+
+	providerPluginPrefix = providerpkg.PluginPrefix
 
 	recordFreshness = providerpkg.RecordFreshness
 
@@ -75,6 +90,9 @@ var (
 	knownCopilotModelIDs  = providerpkg.KnownCopilotModelIDs
 	isKnownOpenCodeModel  = providerpkg.IsKnownOpenCodeModel
 	knownOpenCodeModelIDs = providerpkg.KnownOpenCodeModelIDs
+
+	openCodeOTelEnabled      = providerpkg.OpenCodeOTelEnabled
+	ensureOpenCodeOTelConfig = providerpkg.EnsureOpenCodeOTelConfig
 )
 
 // ─── source aliases ──────────────────────────────────────────────────────────
@@ -86,6 +104,14 @@ type (
 	Resolved       = source.Resolved
 	Manifest       = source.Manifest
 	SourceResolver = source.SourceResolver
+	Layer          = source.Layer
+	Dependency     = source.Dependency
+	DiffHunk       = source.DiffHunk
+	LintFinding    = source.LintFinding
+	InitStack      = source.InitStack
+	InitTemplates  = source.InitTemplates
+	SearchDoc      = source.SearchDoc
+	LogEntry       = source.LogEntry
 )
 
 // Var aliases for kind constants and maps
@@ -98,8 +124,13 @@ var (
 	kindByName      = source.KindByName
 )
 
-// Const alias
-const CollectionAll = source.CollectionAll
+// Const aliases
+const (
+	CollectionAll        = source.CollectionAll
+	sourceLayerSeparator = source.LayerSeparator
+	diffContext          = source.DiffContext
+	lintError            = source.LintError
+)
 
 // Function aliases — closures capture the package-level `Version` var at call time
 var (
@@ -123,6 +154,11 @@ var (
 	rawArtifactHash        = source.RawArtifactHash
 	comparableArtifactHash = source.ComparableArtifactHash
 	checkConflict          = source.CheckConflict
+	writeFileAtomic        = source.WriteFile
+	normalizedDataHash     = source.NormalizedDataHash
+
+	// frontmatter.go
+	scopeApplyTo = source.ScopeApplyTo
 
 	// manifest.go
 	validateName       = source.ValidateName
@@ -130,6 +166,33 @@ var (
 	loadManifest       = source.LoadManifest
 	listCollectionDirs = source.ListCollectionDirs
 	collectAllItems    = source.CollectAllItems
+
+	// deps.go
+	readDependencies = source.ReadDependencies
+
+	// inittemplates.go
+	loadInitTemplates = source.LoadInitTemplates
+	validateInitPath  = source.ValidateInitPath
+
+	// cache.go
+	openSourceCache = source.OpenCache
+	sourceCacheRoot = source.CacheRoot
+	setOffline      = func(offline bool) { source.Offline = offline }
+	isOffline       = func() bool { return source.Offline }
+
+	// merge.go
+	hasConflictMarkers = source.HasConflictMarkers
+	isBinaryContent    = source.IsBinary
+	merge3             = source.Merge3
+
+	// diff.go
+	diffLines         = source.Diff
+	diffStat          = source.DiffStat
+	normalizeMarkdown = source.NormalizeMarkdown
+
+	// lint.go
+	lintTree  = source.Lint
+	lintRules = source.LintRules
 )
 
 // ─── artifacts aliases ───────────────────────────────────────────────────────
@@ -148,13 +211,36 @@ const (
 	syncConfigPath     = artifacts.SyncConfigPath
 	openCodeCollection = artifacts.OpenCodeCollection
 	openCodeScopeName  = artifacts.OpenCodeScopeName
+	lockFileName       = artifacts.LockFileName
 )
 
 var (
-	readState        = artifacts.ReadState
-	readScopedState  = artifacts.ReadScopedState
-	writeState       = artifacts.WriteState
-	writeScopedState = artifacts.WriteScopedState
+	readState       = artifacts.ReadState
+	readScopedState = artifacts.ReadScopedState
+	writeState      = artifacts.WriteState
+	readLock        = artifacts.ReadLock
+	lockPath        = artifacts.LockPath
+)
+
+var (
+	baseStorePath = artifacts.BasePath
+	hasBase       = artifacts.HasBase
+	saveBaseData  = artifacts.SaveBaseData
+)
+
+// journal.go, rollback.go
+type Journal = artifacts.Journal
+
+const (
+	journalKeep       = artifacts.JournalKeep
+	journalPending    = artifacts.JournalPending
+	journalCommitted  = artifacts.JournalCommitted
+	journalRolledBack = artifacts.JournalRolledBack
+)
+
+var (
+	beginJournalAt = artifacts.BeginJournal
+	listJournals   = artifacts.ListJournals
 )
 
 var (
@@ -186,6 +272,19 @@ var cmdExport = func(format string, scope *InstallScope, ref, sourceRepo string,
 
 var writeOpenCodeState = artifacts.WriteOpenCodeState
 
+type (
+	ExportFormat = artifacts.ExportFormat
+	ExportLayout = artifacts.ExportLayout
+)
+
+var (
+	exportFormats     = artifacts.ExportFormats
+	exportFormatNames = artifacts.ExportFormatNames
+	readExportState   = artifacts.ReadExportState
+	syncExport        = artifacts.SyncExport
+	printExportResult = artifacts.PrintExportResult
+)
+
 // ─── telemetry aliases ───────────────────────────────────────────────────────
 
 // Type aliases
@@ -196,13 +295,17 @@ type (
 
 // Function aliases
 var (
-	initTelemetry       = telemetrypkg.InitTelemetry
-	telemetryEnabled    = telemetrypkg.TelemetryEnabled
-	lookupEnvValue      = telemetrypkg.LookupEnvValue
-	copilotDeviceID     = telemetrypkg.CopilotDeviceID
-	getOrCreateDeviceID = telemetrypkg.GetOrCreateDeviceID
-	debugLog            = telemetrypkg.DebugLog
-	getConfigDir        = telemetrypkg.GetConfigDir
+	initTelemetry             = telemetrypkg.InitTelemetry
+	telemetryEnabled          = telemetrypkg.TelemetryEnabled
+	lookupEnvValue            = telemetrypkg.LookupEnvValue
+	copilotDeviceID           = telemetrypkg.CopilotDeviceID
+	getOrCreateDeviceID       = telemetrypkg.GetOrCreateDeviceID
+	debugLog                  = telemetrypkg.DebugLog
+	getConfigDir              = telemetrypkg.GetConfigDir
+	readTelemetryBuffer       = telemetrypkg.ReadBufferStatus
+	discardTelemetryBuffer    = telemetrypkg.DiscardBuffer
+	newTelemetryInspector     = telemetrypkg.NewInspector
+	sortedTelemetryAttributes = telemetrypkg.SortedAttributes
 
 	_ = telemetryEnabled
 	_ = copilotDeviceID
@@ -0,0 +1,76 @@
+package cli
+
+import (
+	"fmt"
+)
+
+// cmdCache manages the persistent source cache under ~/.nav-pilot/cache.
+func cmdCache(args []string, all, dryRun, jsonOutput bool) error {
+	if len(args) == 0 {
+		return fmt.Errorf("cache requires a subcommand.\n\nUsage: nav-pilot cache <subcommand> [options]\n\nSubcommands:\n  prune     Remove cached snapshots no ref points to (--all removes everything)\n  path      Print the cache directory")
+	}
+	switch args[0] {
+	case "prune":
+		return cmdCachePrune(all, dryRun, jsonOutput)
+	case "path":
+		root, err := sourceCacheRoot()
+		if err != nil {
+			return err
+		}
+		fmt.Println(root)
+		return nil
+	default:
+		return fmt.Errorf("unknown cache subcommand: %q\n\nSubcommands: prune, path", args[0])
+	}
+}
+
+func cmdCachePrune(all, dryRun, jsonOutput bool) error {
+	cache, err := openSourceCache()
+	if err != nil {
+		return fmt.Errorf("opening source cache: %w", err)
+	}
+	result, err := cache.Prune(all, dryRun)
+	if err != nil {
+		return fmt.Errorf("pruning source cache: %w", err)
+	}
+
+	if jsonOutput {
+		return outputJSON(map[string]interface{}{
+			"command": "cache prune",
+			"all":     all,
+			"dry_run": dryRun,
+			"removed": result.Removed,
+			"bytes":   result.Bytes,
+		})
+	}
+
+	if len(result.Removed) == 0 {
+		fmt.Printf("%s Source cache is already clean.\n", green("✓"))
+		return nil
+	}
+	glyph := red("×")
+	if dryRun {
+		glyph = dim("×")
+	}
+	for _, p := range result.Removed {
+		fmt.Printf("  %s %s\n", glyph, p)
+	}
+	if dryRun {
+		fmt.Printf("\n%s Would free %s from %s.\n", dim("→"), formatSize(result.Bytes), cache.Root)
+		return nil
+	}
+	fmt.Printf("\n%s Freed %s from %s.\n", green("✓"), formatSize(result.Bytes), cache.Root)
+	return nil
+}
+
+// formatSize renders a byte count as B, KB or MB.
+func formatSize(n int64) string {
+	switch {
+	case n >= 1<<20:
+		return fmt.Sprintf("%.1f MB", float64(n)/(1<<20))
+	case n >= 1<<10:
+		return fmt.Sprintf("%.1f KB", float64(n)/(1<<10))
+	default:
+		return fmt.Sprintf("%d B", n)
+	}
+}
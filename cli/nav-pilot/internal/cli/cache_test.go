@@ -0,0 +1,53 @@
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestCachePrune_Command(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("USERPROFILE", home)
+	stale := filepath.Join(home, ".nav-pilot", "cache", "trees", strings.Repeat("a", 40))
+	os.MkdirAll(stale, 0o755)
+	os.WriteFile(filepath.Join(stale, "README.md"), []byte("stale\n"), 0o644)
+
+	out := captureStdout(func() {
+		if err := run([]string{"cache", "prune", "--dry-run"}); err != nil {
+			t.Errorf("cache prune --dry-run: %v", err)
+		}
+	})
+	if !strings.Contains(out, "Would free 6 B") {
+		t.Errorf("dry-run output = %q", out)
+	}
+	if _, err := os.Stat(stale); err != nil {
+		t.Fatalf("dry run removed the snapshot: %v", err)
+	}
+
+	if err := run([]string{"cache", "prune"}); err != nil {
+		t.Fatalf("cache prune: %v", err)
+	}
+	if _, err := os.Stat(stale); !os.IsNotExist(err) {
+		t.Errorf("stale snapshot should be removed, stat err = %v", err)
+	}
+}
+
+func TestCache_UnknownSubcommand(t *testing.T) {
+	if err := cmdCache(nil, false, false, false); err == nil {
+		t.Error("expected error without subcommand")
+	}
+	if err := cmdCache([]string{"purge"}, false, false, false); err == nil || !strings.Contains(err.Error(), "unknown cache subcommand") {
+		t.Errorf("err = %v", err)
+	}
+}
+
+func TestFormatSize(t *testing.T) {
+	for n, want := range map[int64]string{512: "512 B", 2048: "2.0 KB", 3 << 20: "3.0 MB"} {
+		if got := formatSize(n); got != want {
+			t.Errorf("formatSize(%d) = %q, want %q", n, got, want)
+		}
+	}
+}
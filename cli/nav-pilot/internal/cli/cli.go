@@ -20,6 +20,8 @@ type BuildInfo struct {
 	Version   string
 	Commit    string
 	BuildDate string
+	// ReleaseKey is the minisign public key releases are signed with.
+	ReleaseKey string
 }
 
 var (
@@ -57,9 +59,9 @@ func isKnownCommand(arg string) bool {
 		return true
 	}
 	switch arg {
-	case "install", "init", "export", "add", "ignore", "sync", "list", "doctor",
-		"uninstall", "upgrade", "update", "config", "env", "feedback", "models",
-		"version", "--version", "-v", "-h", "--help", "help":
+	case "install", "init", "export", "add", "ignore", "sync", "diff", "lint", "list", "search", "doctor",
+		"uninstall", "rollback", "upgrade", "update", "config", "cache", "telemetry", "workspace", "env", "feedback", "models",
+		"mcp", "mcp-serve", "version", "--version", "-v", "-h", "--help", "help":
 		return true
 	default:
 		return false
@@ -81,13 +83,24 @@ Commands:
   install --user --all    Install all agents, skills & instructions to ~/.copilot (user-wide)
   init                    Scaffold repo-local Copilot config files (AGENTS.md, instructions)
   sync (s)                Check for updates and optionally apply them
+  workspace sync [dir]    Check or apply updates in every nav-pilot repo under dir (--apply, --json)
+  diff [path...]          Show unified diffs between installed files and source (--stat, --json)
+  lint [dir]              Validate agents, skills, instructions and prompts (--json, --sarif)
   list (ls)               List available collections and items
   list --installed        Show what's currently installed
-  doctor                  Run system health checks and diagnostics
+  search <query>          Search agents, skills, instructions and prompts (--type, --collection)
+  mcp list|add|remove     Manage MCP servers from the Nav registry in VS Code, Copilot CLI and opencode (--user)
+  mcp-serve               Run nav-pilot as a stdio MCP server for coding agents (search, add, sync check, doctor)
+  doctor [--fix]          Run system health checks (--fix applies safe fixes)
   upgrade (up)            Update nav-pilot CLI to the latest version
-  uninstall (rm)          Remove installed collection files
+  upgrade --version <v>   Install and pin a specific release (upgrade --rollback restores the previous one)
+  uninstall (rm) [name]   Remove installed collection files, or a single installed item
+  rollback [n|list]       Undo the last n install/sync/uninstall operations (default 1)
   export <format>         Export Nav customizations to another tool's format
   config <subcommand>     Manage user-specific nav-pilot configuration (init, setup, show, get, set, validate)
+  cache prune             Evict stale source snapshots from ~/.nav-pilot/cache (--all clears it)
+  telemetry status        Show the telemetry endpoint and exports buffered while offline
+  telemetry inspect <cmd> Run a command and show every metric it records, without sending it
   env                     Print shell exports for Copilot CLI integration
   ignore <type> <name>    Suppress new-item reminders for a specific item (--user)
   feedback                Report a bug or request a feature
@@ -98,18 +111,30 @@ Flags:
   -f, --force             Overwrite files that differ from source
   -t, --target <dir>      Target repository (default: current directory)
   -r, --ref <ref>         Git branch or tag to install from
-  -s, --source <repo>     Source repository (default: navikt/copilot)
+  -s, --source <repo>     Source repository (default: navikt/copilot); repeat to layer sources
   -u, --user              Install to ~/.copilot — works across all repos (agents, skills & instructions only)
-  --type <type>           Artifact type for install (agent, skill, instruction, prompt)
+  --type <type>           Artifact type for install/uninstall/search (agent, skill, instruction, prompt)
+  --collection <name>     Only search items in this collection (search only)
+  --subproject <dir>      Scope instructions to a monorepo subproject (install, uninstall)
   --all                   Install everything (use with --user)
-  --apply                 Apply available updates (sync only)
+  --apply                 Apply available updates (sync, workspace sync)
+  --stat                  Show a per-file change summary instead of full diffs (diff only)
+  --locked                Install/sync exactly what nav-pilot.lock pins; fail on drift
+  --update-lock           Apply updates and move nav-pilot.lock forward (sync only)
+  --offline               Use the last fetched source from the cache; no network
   --sync                  Sync all scopes and launch Copilot (non-interactive)
   --json                  Output results as JSON
+  --profile <name>        Use a [profiles.<name>] config profile (launch, config; or NAV_PILOT_PROFILE)
+  --sarif                 Output lint findings as SARIF 2.1.0 (lint only)
+  --fix                   Apply safe fixes for failing checks (doctor only; --force skips the prompt)
+  --version <version>     Install and pin a specific nav-pilot release (upgrade only)
+  --rollback              Switch back to the previously installed nav-pilot (upgrade only)
+  --allow-unsigned        Install a release without a valid signature file (upgrade only)
   -F, --feature           Submit a feature request (feedback only)
 
 Exit Codes:
   0   Success
-  1   Error / Updates available (sync)
+  1   Error / Updates available (sync, diff) / Lint errors
   2   Sync failed
 
 Get started:
@@ -119,7 +144,9 @@ Get started:
   nav-pilot install --user --all         # Install everything to ~/.copilot (all repos)
   nav-pilot install security-champion    # Install a single agent
   nav-pilot sync                         # Check for updates
+  nav-pilot rollback                     # Undo the last install, sync or uninstall
   nav-pilot export opencode              # Export for OpenCode/oh-my-openagent
+  nav-pilot export claude                # Export for Claude Code (also cursor, codex)
 
 After installing, use @nav-pilot in GitHub Copilot Chat.
 `)
@@ -131,7 +158,11 @@ func run(args []string) error {
 	// Self-check: warn if nav-pilot binary is outdated (fast, cached)
 	assessment := assessStaleness(Version)
 	recordFreshness("cli", "none", assessment)
-	if Version != "dev" && assessment.LatestVersion != "" && versionNewer(assessment.LatestVersion, Version) {
+	// A pinned version (upgrade --version/--rollback) is deliberate: no nagging
+	// and no auto-update until the user runs `nav-pilot upgrade` again. The
+	// MCP server is left alone too: its stdout is the protocol.
+	mcpServe := len(args) > 0 && args[0] == "mcp-serve"
+	if Version != "dev" && !versionPinned() && !mcpServe && assessment.LatestVersion != "" && versionNewer(assessment.LatestVersion, Version) {
 		fileCfg, _ := readConfig()
 		autoUpdate := fileCfg != nil && fileCfg.AutoUpdate != nil && *fileCfg.AutoUpdate
 
@@ -142,7 +173,7 @@ func run(args []string) error {
 
 		if autoUpdate && !alreadyReexeced {
 			fmt.Fprintf(os.Stderr, "%s Auto-updating nav-pilot %s → %s...\n", yellow("ℹ"), Version, assessment.LatestVersion)
-			updated, err := doUpdate()
+			updated, err := doUpdate(false)
 			if err != nil {
 				return fmt.Errorf("auto-update failed: %w", err)
 			}
@@ -165,7 +196,7 @@ func run(args []string) error {
 				Run()
 
 			if err == nil && upgradeChoice {
-				updated, err := doUpdate()
+				updated, err := doUpdate(false)
 				if err != nil {
 					return fmt.Errorf("interactive upgrade failed: %w", err)
 				}
@@ -196,6 +227,12 @@ func run(args []string) error {
 		var cleanArgs []string
 		for i := 0; i < len(args); i++ {
 			switch args[i] {
+			case "--profile":
+				if i+1 >= len(args) {
+					return fmt.Errorf("--profile requires a value")
+				}
+				i++
+				cliOverrides.Profile = args[i]
 			case "--client":
 				if i+1 >= len(args) {
 					return fmt.Errorf("--client requires a value")
@@ -291,8 +328,8 @@ func run(args []string) error {
 		args = cleanArgs
 	}
 
-	if cliOverrides.Client != "" && !containsStr(validProviderIDs, cliOverrides.Client) {
-		return fmt.Errorf("--client %q is not valid (allowed: %s)", cliOverrides.Client, strings.Join(validProviderIDs, ", "))
+	if cliOverrides.Client != "" && !isValidClient(cliOverrides.Client) {
+		return fmt.Errorf("--client %q is not valid (allowed: %s)", cliOverrides.Client, strings.Join(providerIDs(), ", "))
 	}
 
 	if len(args) < 1 {
@@ -313,7 +350,7 @@ func run(args []string) error {
 			}
 		}
 		if err := runWithCommandTelemetry("auto_sync", "non_interactive", "auto", func() error {
-			return cmdSyncAuto(".", "", "", true, false)
+			return cmdSyncAuto(".", "", "", lockFollow, true, false)
 		}); err != nil && err != errUpdatesAvailable {
 			fmt.Fprintf(os.Stderr, "%s Sync failed: %v\n", yellow("⚠"), err)
 		}
@@ -335,8 +372,15 @@ func run(args []string) error {
 		command = canonical
 	}
 
+	// telemetry inspect runs another command: the flags after it are that
+	// command's, so it is dispatched before flag parsing.
+	if command == "telemetry" && len(rest) > 0 && rest[0] == "inspect" {
+		return cmdTelemetryInspect(rest[1:])
+	}
+
 	var dryRun, force, apply, jsonOutput, listItems, featureRequest, userScope, targetProvided, installAll, listInstalled bool
-	var targetDir, ref, sourceRepo, installType string
+	var locked, updateLock, offline, diffStatOnly, sarifOutput, doctorFix, rollback, allowUnsigned bool
+	var targetDir, ref, sourceRepo, installType, profile, pinVersion, subproject, collection string
 	var positional []string
 
 	targetDir = "."
@@ -349,8 +393,24 @@ func run(args []string) error {
 			force = true
 		case "--apply":
 			apply = true
+		case "--stat":
+			diffStatOnly = true
+		case "--locked":
+			locked = true
+		case "--update-lock":
+			updateLock = true
+		case "--offline":
+			offline = true
 		case "--json":
 			jsonOutput = true
+		case "--sarif":
+			sarifOutput = true
+		case "--fix":
+			doctorFix = true
+		case "--rollback":
+			rollback = true
+		case "--allow-unsigned":
+			allowUnsigned = true
 		case "--items":
 			listItems = true
 		case "--installed":
@@ -379,13 +439,41 @@ func run(args []string) error {
 				return fmt.Errorf("--source requires a value")
 			}
 			i++
-			sourceRepo = rest[i]
+			// Repeating --source layers sources: later ones take precedence.
+			if sourceRepo != "" {
+				sourceRepo += sourceLayerSeparator
+			}
+			sourceRepo += rest[i]
+		case "--profile":
+			if i+1 >= len(rest) {
+				return fmt.Errorf("--profile requires a value")
+			}
+			i++
+			profile = rest[i]
+		case "--version":
+			if i+1 >= len(rest) {
+				return fmt.Errorf("--version requires a value")
+			}
+			i++
+			pinVersion = rest[i]
 		case "--type":
 			if i+1 >= len(rest) {
 				return fmt.Errorf("--type requires a value")
 			}
 			i++
 			installType = rest[i]
+		case "--subproject":
+			if i+1 >= len(rest) {
+				return fmt.Errorf("--subproject requires a value")
+			}
+			i++
+			subproject = rest[i]
+		case "--collection":
+			if i+1 >= len(rest) {
+				return fmt.Errorf("--collection requires a value")
+			}
+			i++
+			collection = rest[i]
 		case "-h", "--help":
 			usage()
 			return nil
@@ -434,27 +522,91 @@ func run(args []string) error {
 		scope = ScopeRepo(targetDir)
 	}
 
+	if subproject != "" {
+		if command != "install" && command != "add" && command != "uninstall" {
+			return fmt.Errorf("--subproject is only supported for install and uninstall")
+		}
+		if userScope {
+			return fmt.Errorf("--subproject is not supported with --user")
+		}
+		sub, err := normalizeSubproject(targetDir, subproject)
+		if err != nil {
+			return err
+		}
+		scope = ScopeSubproject(targetDir, sub)
+	}
+
 	// Reject --user for commands that don't support scoped installs
 	if userScope {
 		switch command {
-		case "install", "add", "ignore", "sync", "doctor", "uninstall", "export", "list":
+		case "install", "add", "ignore", "sync", "diff", "lint", "doctor", "uninstall", "rollback", "export", "list", "search", "mcp":
 			// These commands support --user
 		default:
 			return fmt.Errorf("--user is not supported for %q", command)
 		}
 	}
 
-	// Validate --type is only used with install (or hidden add alias)
-	if installType != "" && command != "install" && command != "add" {
-		return fmt.Errorf("--type is only supported for the install command")
+	// Validate --type is only used with install/uninstall/search (or hidden add alias)
+	if installType != "" && command != "install" && command != "add" && command != "uninstall" && command != "search" {
+		return fmt.Errorf("--type is only supported for the install, uninstall and search commands")
+	}
+	if collection != "" && command != "search" {
+		return fmt.Errorf("--collection is only supported for search")
+	}
+
+	if diffStatOnly && command != "diff" {
+		return fmt.Errorf("--stat is only supported for diff")
+	}
+	if profile != "" && command != "config" {
+		return fmt.Errorf("--profile is only supported for config and when launching (nav-pilot --profile <name>)")
+	}
+	if sarifOutput && command != "lint" {
+		return fmt.Errorf("--sarif is only supported for lint")
+	}
+	if doctorFix && command != "doctor" {
+		return fmt.Errorf("--fix is only supported for doctor")
+	}
+	if (pinVersion != "" || rollback || allowUnsigned) && command != "upgrade" && command != "update" {
+		return fmt.Errorf("--version, --rollback and --allow-unsigned are only supported for upgrade")
+	}
+
+	if locked && command != "install" && command != "sync" {
+		return fmt.Errorf("--locked is only supported for install and sync")
+	}
+	if updateLock && command != "sync" {
+		return fmt.Errorf("--update-lock is only supported for sync")
+	}
+	if locked && updateLock {
+		return fmt.Errorf("--locked and --update-lock are mutually exclusive")
+	}
+	setOffline(offline)
+	lockMode := lockFollow
+	switch {
+	case locked:
+		lockMode = lockPinned
+	case updateLock:
+		lockMode = lockUpdate
 	}
 
 	switch command {
 	case "install":
 		return runWithCommandTelemetry("install", telemetryMode(), scope.Name, func() error {
+			if locked {
+				if len(positional) > 1 || installType != "" || installAll || subproject != "" {
+					return fmt.Errorf("install --locked takes at most the collection name")
+				}
+				name := ""
+				if len(positional) == 1 {
+					name = positional[0]
+				}
+				return cmdInstallLocked(name, scope, dryRun, force, jsonOutput)
+			}
 			if userScope && (len(positional) == 0 || installAll) {
 				return cmdInstallAll(scope, ref, sourceRepo, dryRun, force, jsonOutput)
 			}
+			if len(positional) == 0 && subproject != "" {
+				return fmt.Errorf("install --subproject requires a name")
+			}
 			if len(positional) == 0 {
 				// No args: launch interactive flow if in a terminal
 				if isInteractive() && !jsonOutput {
@@ -469,11 +621,11 @@ func run(args []string) error {
 		})
 	case "init":
 		return runWithCommandTelemetry("init", telemetryMode(), scope.Name, func() error {
-			return cmdInit(targetDir, dryRun, force)
+			return cmdInit(targetDir, ref, sourceRepo, dryRun, force)
 		})
 	case "export":
 		if len(positional) == 0 {
-			return fmt.Errorf("export requires a format.\n\nUsage: nav-pilot export <format>\n\nFormats: opencode")
+			return fmt.Errorf("export requires a format.\n\nUsage: nav-pilot export <format>\n\nFormats: %s", strings.Join(exportFormatNames(), ", "))
 		}
 		if positional[0] == "opencode" && userScope && !jsonOutput {
 			fmt.Fprintf(os.Stderr, "%s %s is deprecated. Personal opencode context is materialized automatically on launch and refreshed by %s.\n  Use %s to start opencode with Nav context, or %s to refresh it.\n  Use %s only to commit Nav context into a project repo.\n\n",
@@ -514,9 +666,41 @@ func run(args []string) error {
 		}
 		return runWithCommandTelemetry("sync", telemetryMode(), syncScope, func() error {
 			if userScope || targetProvided {
-				return cmdSync(scope, ref, sourceRepo, apply, jsonOutput)
+				return cmdSync(scope, ref, sourceRepo, lockMode, apply, jsonOutput)
+			}
+			return cmdSyncAuto(targetDir, ref, sourceRepo, lockMode, apply, jsonOutput)
+		})
+	case "workspace":
+		if targetProvided {
+			return fmt.Errorf("workspace takes the directory as an argument: nav-pilot workspace sync <dir>")
+		}
+		return runWithCommandTelemetry("workspace", telemetryMode(), "repo", func() error {
+			return cmdWorkspace(positional, ref, sourceRepo, apply, jsonOutput)
+		})
+	case "diff":
+		diffScope := "auto"
+		if userScope || targetProvided {
+			diffScope = scope.Name
+		}
+		return runWithCommandTelemetry("diff", telemetryMode(), diffScope, func() error {
+			if userScope || targetProvided {
+				return cmdDiff([]*InstallScope{scope}, positional, ref, sourceRepo, jsonOutput, diffStatOnly)
 			}
-			return cmdSyncAuto(targetDir, ref, sourceRepo, apply, jsonOutput)
+			return cmdDiffAuto(targetDir, positional, ref, sourceRepo, jsonOutput, diffStatOnly)
+		})
+	case "lint":
+		if len(positional) > 1 {
+			return fmt.Errorf("usage: nav-pilot lint [dir]")
+		}
+		lintRoot := scope.RootDir
+		if len(positional) == 1 {
+			if userScope || targetProvided {
+				return fmt.Errorf("lint takes either a directory or --user/--target, not both")
+			}
+			lintRoot = positional[0]
+		}
+		return runWithCommandTelemetry("lint", telemetryMode(), scope.Name, func() error {
+			return cmdLint(lintRoot, jsonOutput, sarifOutput)
 		})
 	case "list":
 		listScope := "none"
@@ -532,26 +716,77 @@ func run(args []string) error {
 			}
 			return cmdList(ref, sourceRepo, listItems, jsonOutput)
 		})
+	case "search":
+		// Results install where `install` would: the repo, or ~/.copilot
+		// outside a git repo.
+		if !userScope && !isGitRepo(targetDir) {
+			if s, err := ScopeUser(); err == nil {
+				scope = s
+			}
+		}
+		return runWithCommandTelemetry("search", telemetryMode(), "none", func() error {
+			return cmdSearch(strings.Join(positional, " "), installType, collection, scope, ref, sourceRepo, jsonOutput)
+		})
+	case "mcp":
+		// Outside a git repo there is no .vscode to write to: use the user's
+		// clients, like search does.
+		if !userScope && !targetProvided && !isGitRepo(targetDir) {
+			if s, err := ScopeUser(); err == nil {
+				scope = s
+			}
+		}
+		return runWithCommandTelemetry("mcp", telemetryMode(), scope.Name, func() error {
+			return cmdMCP(positional, scope, force, jsonOutput)
+		})
+	case "mcp-serve":
+		if len(positional) > 0 {
+			return fmt.Errorf("mcp-serve takes no arguments.\n\nUsage: nav-pilot mcp-serve [--target <dir>] [--ref <ref>] [--source <repo>]")
+		}
+		return runWithCommandTelemetry("mcp-serve", "non_interactive", "auto", func() error {
+			return cmdMCPServe(targetDir, ref, sourceRepo)
+		})
 	case "doctor":
 		return runWithCommandTelemetry("doctor", telemetryMode(), "none", func() error {
-			return cmdDoctor()
+			return cmdDoctor(jsonOutput, doctorFix, force)
 		})
 	case "uninstall":
 		return runWithCommandTelemetry("uninstall", telemetryMode(), scope.Name, func() error {
+			if len(positional) > 0 {
+				return cmdUninstallItem(scope, positional[0], installType, dryRun, force)
+			}
+			if subproject != "" {
+				return fmt.Errorf("uninstall --subproject requires a name")
+			}
 			return cmdUninstall(scope, dryRun)
 		})
+	case "rollback":
+		return runWithCommandTelemetry("rollback", telemetryMode(), scope.Name, func() error {
+			return cmdRollback(scope, positional, dryRun, jsonOutput)
+		})
 	case "upgrade":
-		return runWithCommandTelemetry("upgrade", telemetryMode(), "none", cmdUpdate)
+		return runWithCommandTelemetry("upgrade", telemetryMode(), "none", func() error {
+			return cmdUpdate(pinVersion, rollback, allowUnsigned)
+		})
 	case "update":
 		// Deprecated: hidden alias for backward compatibility
 		if !jsonOutput {
 			fmt.Fprintf(os.Stderr, "%s %s is deprecated. Use: %s\n\n",
 				yellow("⚠"), bold("nav-pilot update"), bold("nav-pilot upgrade"))
 		}
-		return runWithCommandTelemetry("update", telemetryMode(), "none", cmdUpdate)
+		return runWithCommandTelemetry("update", telemetryMode(), "none", func() error {
+			return cmdUpdate(pinVersion, rollback, allowUnsigned)
+		})
 	case "config":
 		return runWithCommandTelemetry("config", telemetryMode(), "none", func() error {
-			return cmdConfig(positional, force, jsonOutput)
+			return cmdConfig(positional, force, jsonOutput, profile)
+		})
+	case "cache":
+		return runWithCommandTelemetry("cache", telemetryMode(), "none", func() error {
+			return cmdCache(positional, installAll, dryRun, jsonOutput)
+		})
+	case "telemetry":
+		return runWithCommandTelemetry("telemetry", telemetryMode(), "none", func() error {
+			return cmdTelemetry(positional, jsonOutput)
 		})
 	case "env":
 		return runWithCommandTelemetry("env", telemetryMode(), "none", cmdEnv)
@@ -570,7 +805,7 @@ func run(args []string) error {
 		usage()
 		return nil
 	default:
-		knownCmds := []string{"install", "init", "export", "add", "ignore", "sync", "list", "doctor", "uninstall", "upgrade", "update", "config", "env", "feedback", "models", "version", "help"}
+		knownCmds := []string{"install", "init", "export", "add", "ignore", "sync", "diff", "lint", "list", "search", "doctor", "uninstall", "rollback", "upgrade", "update", "config", "cache", "telemetry", "workspace", "env", "feedback", "models", "mcp", "mcp-serve", "version", "help"}
 		if hint := suggest(command, knownCmds); hint != "" {
 			return fmt.Errorf("unknown command: %s. Did you mean %s?\nRun with --help for usage", command, hint)
 		}
@@ -609,6 +844,7 @@ func reexecSelf() error {
 func Main(info BuildInfo) {
 	Version = info.Version
 	buildInfo = info
+	releasePublicKey = info.ReleaseKey
 	providerpkg.SetVersion(info.Version)
 	providerpkg.FetchLatestVersion = func() (string, string, error) {
 		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
@@ -616,17 +852,18 @@ func Main(info BuildInfo) {
 		return fetchLatestVersion(ctx)
 	}
 
-	rtkStatus := "false"
-	if isRtkInstalled() {
-		rtkStatus = "true"
-	}
-	tel, err := initTelemetry(context.Background(), info.Version, rtkStatus)
-	if err != nil {
-		debugLog("telemetry disabled: %v", err)
+	if telemetryOptedIn() {
+		tel, err := initTelemetry(context.Background(), info.Version, rtkInstalledLabel())
+		if err != nil {
+			debugLog("telemetry disabled: %v", err)
+		}
+		telemetry = tel
+	} else if err := discardTelemetryBuffer(); err != nil {
+		debugLog("discarding telemetry buffer: %v", err)
 	}
-	telemetry = tel
-	providerpkg.SetTelemetry(tel)
-	for _, p := range allProviders() {
+	providerpkg.SetTelemetry(telemetry)
+	// Built-ins only: probing plugins would run an executable on every start.
+	for _, p := range builtinProviders() {
 		telemetry.RecordClientAvailable(p.ID(), p.Available())
 	}
 
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 
 	"github.com/BurntSushi/toml"
@@ -22,6 +23,13 @@ func validateModelForClient(model, client string) error {
 	return p.ValidateModel(model)
 }
 
+// isValidClient reports whether id names a built-in provider or a provider
+// plugin on PATH.
+func isValidClient(id string) bool {
+	_, err := providerFor(id)
+	return err == nil
+}
+
 // configPath returns the path to the user config file.
 // Honors NAV_PILOT_CONFIG env var if set.
 func configPath() string {
@@ -32,6 +40,93 @@ func configPath() string {
 	return filepath.Join(home, ".nav-pilot", "config.toml")
 }
 
+// profileEnv selects a config profile when --profile is not given.
+const profileEnv = "NAV_PILOT_PROFILE"
+
+// selectedProfile returns the profile chosen by --profile, falling back to
+// NAV_PILOT_PROFILE. Empty means no profile.
+func selectedProfile(cli CLIOverrides) string {
+	if cli.Profile != "" {
+		return cli.Profile
+	}
+	return strings.TrimSpace(os.Getenv(profileEnv))
+}
+
+// profileNames returns the names of the profiles defined in cfg, sorted.
+func profileNames(cfg *Config) []string {
+	if cfg == nil {
+		return nil
+	}
+	names := make([]string, 0, len(cfg.Profiles))
+	for name := range cfg.Profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// lookupProfile returns the named profile, or an error listing the profiles
+// that do exist.
+func lookupProfile(cfg *Config, name string) (*Profile, error) {
+	if cfg != nil {
+		if p, ok := cfg.Profiles[name]; ok {
+			if p == nil {
+				p = &Profile{}
+			}
+			return p, nil
+		}
+	}
+	available := "none defined"
+	if names := profileNames(cfg); len(names) > 0 {
+		available = "available: " + strings.Join(names, ", ")
+	}
+	return nil, fmt.Errorf("profile %q not found in %s (%s)\n\nAdd a [profiles.%s] table or run `nav-pilot config setup --profile %s`",
+		name, configPath(), available, name, name)
+}
+
+// applyProfile returns a copy of cfg with the profile's set fields laid over
+// the top-level values.
+func applyProfile(cfg *Config, p *Profile) *Config {
+	out := Config{Version: 1}
+	if cfg != nil {
+		out = *cfg
+	}
+	if p == nil {
+		return &out
+	}
+	if p.Client != nil {
+		out.Client = p.Client
+	}
+	if p.Model != nil {
+		out.Model = p.Model
+	}
+	if p.Mode != nil {
+		out.Mode = p.Mode
+	}
+	if p.ReasoningEffort != nil {
+		out.ReasoningEffort = p.ReasoningEffort
+	}
+	if p.ContextTier != nil {
+		out.ContextTier = p.ContextTier
+	}
+	if p.AllowAllTools != nil {
+		out.AllowAllTools = p.AllowAllTools
+	}
+	if p.AskUser != nil {
+		out.AskUser = p.AskUser
+	}
+	if p.AutoLaunch != nil {
+		out.AutoLaunch = p.AutoLaunch
+	}
+	if p.LogLevel != nil {
+		out.LogLevel = p.LogLevel
+	}
+	if p.OtelLogLevel != nil {
+		out.OtelLogLevel = p.OtelLogLevel
+	}
+	return &out
+}
+
 // readConfig reads and parses the config file at configPath().
 // Returns (nil, nil) if the file does not exist (fail-soft).
 // Returns an error if the file exists but cannot be parsed.
@@ -83,9 +178,9 @@ func validateConfigProblems(cfg *Config) []string {
 	if cfg.Version != 1 {
 		problems = append(problems, fmt.Sprintf("version must be 1 (got %d)", cfg.Version))
 	}
-	if cfg.Client != nil && !containsStr(validProviderIDs, *cfg.Client) {
+	if cfg.Client != nil && !isValidClient(*cfg.Client) {
 		problems = append(problems, fmt.Sprintf("client %q is not valid (allowed: %s)",
-			*cfg.Client, strings.Join(validProviderIDs, ", ")))
+			*cfg.Client, strings.Join(providerIDs(), ", ")))
 	}
 	if cfg.Model != nil {
 		client := ""
@@ -116,6 +211,22 @@ func validateConfigProblems(cfg *Config) []string {
 		problems = append(problems, fmt.Sprintf("otel_log_level %q is not valid (allowed: %s)",
 			*cfg.OtelLogLevel, strings.Join(validOtelLogLevels, ", ")))
 	}
+
+	// Profiles are checked with the same rules. A profile without its own
+	// client validates its model against the top-level client.
+	for _, name := range profileNames(cfg) {
+		p := cfg.Profiles[name]
+		if p == nil {
+			continue
+		}
+		pc := applyProfile(&Config{Version: 1}, p)
+		if p.Client == nil && p.Model != nil && cfg.Client != nil && isValidClient(*cfg.Client) {
+			pc.Client = cfg.Client
+		}
+		for _, problem := range validateConfigProblems(pc) {
+			problems = append(problems, fmt.Sprintf("profiles.%s: %s", name, problem))
+		}
+	}
 	return problems
 }
 
@@ -154,8 +265,8 @@ func configAdvisories(cfg *Config, meta toml.MetaData) []string {
 	return nil
 }
 
-// loadConfigForLaunch reads, validates, and resolves the user config ahead of a
-// launch. Hard validation errors (unknown keys, invalid enum values, wrong version,
+// loadConfigForLaunch reads, validates, and resolves the user config (and the
+// repo's .nav-pilot.toml, if any) ahead of a launch. Hard validation errors (unknown keys, invalid enum values, wrong version,
 // malformed model) cause it to refuse with an error so nav-pilot does not start
 // with a broken config. Non-fatal advisories (unrecognized model ids) are printed
 // to stderr but do not block the launch.
@@ -177,10 +288,25 @@ func loadConfigForLaunch(cli CLIOverrides) (ResolvedConfig, error) {
 		return ResolvedConfig{}, fmt.Errorf("config has unknown key(s): %s\n\nFix %s or run `nav-pilot config setup`",
 			strings.Join(keys, ", "), configPath())
 	}
-	for _, w := range configAdvisories(file, meta) {
+	effective := file
+	if name := selectedProfile(cli); name != "" {
+		p, err := lookupProfile(file, name)
+		if err != nil {
+			return ResolvedConfig{}, err
+		}
+		effective = applyProfile(file, p)
+	}
+	repo, _, err := readRepoConfig()
+	if err != nil {
+		return ResolvedConfig{}, fmt.Errorf("%w\n\nFix it or ask the repo owners; remove it to launch without repo defaults", err)
+	}
+	for _, w := range configAdvisories(effective, meta) {
+		fmt.Fprintf(os.Stderr, "%s %s\n", yellow("⚠"), w)
+	}
+	resolved := resolveLayers(repo, file, cli)
+	for _, w := range lockedOverrides(repo, cli, resolved) {
 		fmt.Fprintf(os.Stderr, "%s %s\n", yellow("⚠"), w)
 	}
-	resolved := resolve(file, cli)
 	telemetry.RecordConfig(
 		resolved.Client,
 		resolved.Mode,
@@ -194,90 +320,170 @@ func loadConfigForLaunch(cli CLIOverrides) (ResolvedConfig, error) {
 	return resolved, nil
 }
 
-// resolve builds a ResolvedConfig from file config and CLI overrides.
-// Precedence: CLI flag > file value > built-in default.
+// Layer names recorded in ResolvedConfig.Sources. A selected profile is
+// recorded as "profile <name>".
+const (
+	sourceDefault = "default"
+	sourceUnset   = "unset"
+	sourceRepo    = "repo"
+	sourceFile    = "file"
+	sourceFlag    = "flag"
+	sourceLocked  = "repo, locked"
+)
+
+// resolve builds a ResolvedConfig from file config and CLI overrides, without
+// a repo layer. See resolveLayers.
 func resolve(file *Config, cli CLIOverrides) ResolvedConfig {
+	return resolveLayers(nil, file, cli)
+}
+
+// resolveLayers builds a ResolvedConfig from every config layer.
+// Precedence: locked repo value > CLI flag > selected profile > file value >
+// repo value > built-in default. Sources records which layer won each key.
+// An unknown profile is ignored here; loadConfigForLaunch rejects it first.
+func resolveLayers(repo *RepoConfig, file *Config, cli CLIOverrides) ResolvedConfig {
 	r := ResolvedConfig{
 		Client:       "copilot",
 		Mode:         "default",
 		AskUser:      true,
+		Telemetry:    true,
 		OtelLogLevel: "none",
+		Sources: map[string]string{
+			"client":           sourceDefault,
+			"model":            sourceUnset,
+			"mode":             sourceDefault,
+			"reasoning_effort": sourceUnset,
+			"context_tier":     sourceUnset,
+			"allow_all_tools":  sourceDefault,
+			"ask_user":         sourceDefault,
+			"auto_launch":      sourceDefault,
+			"auto_update":      sourceDefault,
+			"telemetry":        sourceDefault,
+			"log_level":        sourceUnset,
+			"otel_log_level":   sourceDefault,
+		},
 	}
 
-	// Apply file values.
+	if repo != nil {
+		applyConfigLayer(&r, repoAsConfig(repo), sourceRepo)
+	}
 	if file != nil {
-		if file.Client != nil {
-			r.Client = *file.Client
-		}
-		if file.Model != nil {
-			r.Model = *file.Model
-		}
-		if file.Mode != nil {
-			r.Mode = *file.Mode
-		}
-		if file.ReasoningEffort != nil {
-			r.ReasoningEffort = *file.ReasoningEffort
-		}
-		if file.ContextTier != nil {
-			r.ContextTier = *file.ContextTier
-		}
-		if file.AllowAllTools != nil {
-			r.AllowAllTools = *file.AllowAllTools
-		}
-		if file.AskUser != nil {
-			r.AskUser = *file.AskUser
-		}
-		if file.AutoLaunch != nil {
-			r.AutoLaunch = *file.AutoLaunch
-		}
-		if file.AutoUpdate != nil {
-			r.AutoUpdate = *file.AutoUpdate
-		}
-		if file.LogLevel != nil {
-			r.LogLevel = *file.LogLevel
-		}
-		if file.OtelLogLevel != nil {
-			r.OtelLogLevel = *file.OtelLogLevel
-		}
-		if file.RtkPromptedClient != nil {
-			r.RtkPromptedClient = *file.RtkPromptedClient
-		}
-		if file.RtkPromptedAt != nil {
-			r.RtkPromptedAt = *file.RtkPromptedAt
+		applyConfigLayer(&r, file, sourceFile)
+		if name := selectedProfile(cli); name != "" {
+			if p, ok := file.Profiles[name]; ok {
+				applyConfigLayer(&r, applyProfile(&Config{}, p), "profile "+name)
+				r.Profile = name
+			}
 		}
 	}
 
 	// Apply CLI overrides (higher precedence than file).
+	flag := func(key string) { r.Sources[key] = sourceFlag }
 	if cli.Client != "" {
 		r.Client = cli.Client
+		flag("client")
 	}
 	if cli.Model != "" {
 		r.Model = cli.Model
+		flag("model")
 	}
 	if cli.Mode != "" {
 		r.Mode = cli.Mode
+		flag("mode")
 	}
 	if cli.ReasoningEffort != "" {
 		r.ReasoningEffort = cli.ReasoningEffort
+		flag("reasoning_effort")
 	}
 	if cli.ContextTier != "" {
 		r.ContextTier = cli.ContextTier
+		flag("context_tier")
 	}
 	if cli.AllowAllTools != nil {
 		r.AllowAllTools = *cli.AllowAllTools
+		flag("allow_all_tools")
 	}
 	if cli.AskUser != nil {
 		r.AskUser = *cli.AskUser
+		flag("ask_user")
 	}
 	if cli.AutoLaunch != nil {
 		r.AutoLaunch = *cli.AutoLaunch
+		flag("auto_launch")
 	}
 	if cli.LogLevel != "" {
 		r.LogLevel = cli.LogLevel
+		flag("log_level")
 	}
 	if cli.OtelLogLevel != "" {
 		r.OtelLogLevel = cli.OtelLogLevel
+		flag("otel_log_level")
 	}
 	r.ExtraArgs = cli.ExtraArgs
+
+	// Locked repo keys win over everything, CLI flags included.
+	if repo != nil {
+		applyConfigLayer(&r, repoLockedConfig(repo), sourceLocked)
+	}
 	return r
 }
+
+// applyConfigLayer copies every set field of c into r and records src as the
+// source of those keys.
+func applyConfigLayer(r *ResolvedConfig, c *Config, src string) {
+	set := func(key string) { r.Sources[key] = src }
+	if c.Client != nil {
+		r.Client = *c.Client
+		set("client")
+	}
+	if c.Model != nil {
+		r.Model = *c.Model
+		set("model")
+	}
+	if c.Mode != nil {
+		r.Mode = *c.Mode
+		set("mode")
+	}
+	if c.ReasoningEffort != nil {
+		r.ReasoningEffort = *c.ReasoningEffort
+		set("reasoning_effort")
+	}
+	if c.ContextTier != nil {
+		r.ContextTier = *c.ContextTier
+		set("context_tier")
+	}
+	if c.AllowAllTools != nil {
+		r.AllowAllTools = *c.AllowAllTools
+		set("allow_all_tools")
+	}
+	if c.AskUser != nil {
+		r.AskUser = *c.AskUser
+		set("ask_user")
+	}
+	if c.AutoLaunch != nil {
+		r.AutoLaunch = *c.AutoLaunch
+		set("auto_launch")
+	}
+	if c.AutoUpdate != nil {
+		r.AutoUpdate = *c.AutoUpdate
+		set("auto_update")
+	}
+	if c.Telemetry != nil {
+		r.Telemetry = *c.Telemetry
+		set("telemetry")
+	}
+	if c.LogLevel != nil {
+		r.LogLevel = *c.LogLevel
+		set("log_level")
+	}
+	if c.OtelLogLevel != nil {
+		r.OtelLogLevel = *c.OtelLogLevel
+		set("otel_log_level")
+	}
+	if c.RtkPromptedClient != nil {
+		r.RtkPromptedClient = *c.RtkPromptedClient
+	}
+	if c.RtkPromptedAt != nil {
+		r.RtkPromptedAt = *c.RtkPromptedAt
+	}
+}
@@ -42,7 +42,7 @@ var configKeyDefs = []configKeyDef{
 	{
 		name:        "client",
 		kind:        keyKindString,
-		description: "Coding-agent CLI to launch (copilot, opencode, pi).",
+		description: "Coding-agent CLI to launch (copilot, opencode, pi, or a provider plugin id).",
 		allowed:     validProviderIDs,
 		defaultVal:  "copilot",
 		flag:        "--client",
@@ -111,6 +111,14 @@ var configKeyDefs = []configKeyDef{
 		defaultVal:  "false",
 		flag:        "",
 	},
+	{
+		name:        "telemetry",
+		kind:        keyKindBool,
+		description: "Send anonymous usage metrics to Nav. Set to false to opt out permanently; see `nav-pilot telemetry inspect`.",
+		allowed:     nil,
+		defaultVal:  "true",
+		flag:        "",
+	},
 	{
 		name:        "log_level",
 		kind:        keyKindString,
@@ -222,6 +230,13 @@ version = 1
 # Default: false
 # auto_update = false
 
+# Send anonymous usage metrics (commands, installs, config choices) to Nav.
+# Set to false to opt out permanently. NAV_PILOT_TELEMETRY_ENABLED=0 opts out
+# for a single shell. Run "nav-pilot telemetry inspect <command>" to see
+# exactly what is recorded.
+# Default: true
+# telemetry = true
+
 # Log level for Copilot CLI output.
 # Allowed: none, error, warning, info, debug, all, default — Default: unset
 # Corresponds to Copilot CLI flag: --log-level
@@ -241,13 +256,29 @@ version = 1
 # Internal flag to track when the user was last prompted to set up rtk (RFC3339 timestamp).
 # Default: unset
 # rtk_prompted_at = ""
+
+# Profiles: named sets of launch settings, selected per run with
+# --profile <name> or NAV_PILOT_PROFILE=<name>. Keys set in a profile override
+# the values above; CLI flags still win. Allowed keys: client, model, mode,
+# reasoning_effort, context_tier, allow_all_tools, ask_user, auto_launch,
+# log_level, otel_log_level.
+#
+# [profiles.careful]
+# model = "claude-opus-4.8"
+# mode = "plan"
+# reasoning_effort = "high"
+#
+# [profiles.fast]
+# model = "claude-sonnet-4.6"
+# mode = "autopilot"
+# allow_all_tools = true
 `
 
 // ─── Subcommand dispatch ──────────────────────────────────────────────────────
 
-func cmdConfig(args []string, force bool, jsonOutput bool) error {
+func cmdConfig(args []string, force bool, jsonOutput bool, profile string) error {
 	if len(args) == 0 {
-		return fmt.Errorf("config requires a subcommand.\n\nUsage: nav-pilot config <subcommand> [options]\n\nSubcommands:\n  init      Create ~/.nav-pilot/config.toml with all options commented out\n  setup     Run the interactive first-run setup wizard\n  show      Print effective configuration (file values merged with defaults)\n  path      Print the config file path\n  get       Print one key value\n  set       Set a key value (creates file if missing)\n  validate  Validate config syntax, unknown keys, and values\n  explain   Describe configuration keys\n  sandbox   Interactively configure cplt sandbox profile")
+		return fmt.Errorf("config requires a subcommand.\n\nUsage: nav-pilot config <subcommand> [options]\n\nSubcommands:\n  init      Create ~/.nav-pilot/config.toml with all options commented out\n  setup     Run the interactive first-run setup wizard\n  show      Print effective configuration (file values merged with defaults)\n  path      Print the config file path\n  get       Print one key value\n  set       Set a key value (creates file if missing)\n  validate  Validate config syntax, unknown keys, and values\n  explain   Describe configuration keys\n  sandbox   Interactively configure cplt sandbox profile\n\nWith --profile <name>, setup writes [profiles.<name>] and show/get/explain use that profile.")
 	}
 
 	sub := args[0]
@@ -257,17 +288,20 @@ func cmdConfig(args []string, force bool, jsonOutput bool) error {
 	case "init":
 		return cmdConfigInit()
 	case "setup":
-		return cmdConfigSetup(force)
+		return cmdConfigSetup(force, profile)
 	case "show":
-		return cmdConfigShow(jsonOutput)
+		return cmdConfigShow(jsonOutput, profile)
 	case "path":
 		return cmdConfigPath()
 	case "get":
 		if len(rest) == 0 {
 			return fmt.Errorf("config get requires a key.\n\nUsage: nav-pilot config get <key>\n\nKnown keys: %s", knownKeyNames())
 		}
-		return cmdConfigGet(rest[0])
+		return cmdConfigGet(rest[0], profile)
 	case "set":
+		if profile != "" {
+			return fmt.Errorf("config set writes top-level keys only.\n\nEdit the [profiles.%s] table in %s, or run `nav-pilot config setup --profile %s`", profile, configPath(), profile)
+		}
 		if len(rest) < 2 {
 			return fmt.Errorf("config set requires a key and value.\n\nUsage: nav-pilot config set <key> <value>")
 		}
@@ -279,7 +313,7 @@ func cmdConfig(args []string, force bool, jsonOutput bool) error {
 		if len(rest) > 0 {
 			key = rest[0]
 		}
-		return cmdConfigExplain(key)
+		return cmdConfigExplain(key, profile)
 	case "sandbox":
 		return cmdConfigSandbox()
 	default:
@@ -313,15 +347,18 @@ func cmdConfigInit() error {
 
 // ─── config show ─────────────────────────────────────────────────────────────
 
-func cmdConfigShow(jsonOutput bool) error {
-	cfg, err := readConfig()
+func cmdConfigShow(jsonOutput bool, profile string) error {
+	cfg, repoPath, resolved, err := loadConfigLayers(profile)
 	if err != nil {
 		return err
 	}
-	resolved := resolve(cfg, CLIOverrides{})
 
 	if jsonOutput {
 		return outputJSON(map[string]interface{}{
+			"profile":          resolved.Profile,
+			"profiles":         profileNames(cfg),
+			"repo_config":      repoPath,
+			"sources":          resolved.Sources,
 			"client":           resolved.Client,
 			"model":            resolved.Model,
 			"mode":             resolved.Mode,
@@ -331,6 +368,7 @@ func cmdConfigShow(jsonOutput bool) error {
 			"ask_user":         resolved.AskUser,
 			"auto_launch":      resolved.AutoLaunch,
 			"auto_update":      resolved.AutoUpdate,
+			"telemetry":        resolved.Telemetry,
 			"log_level":        resolved.LogLevel,
 			"otel_log_level":   resolved.OtelLogLevel,
 		})
@@ -338,89 +376,63 @@ func cmdConfigShow(jsonOutput bool) error {
 
 	path := configPath()
 	if cfg == nil {
-		fmt.Printf("# Config file: %s %s\n\n", path, dim("(not found, using defaults)"))
+		fmt.Printf("# Config file: %s %s\n", path, dim("(not found, using defaults)"))
 	} else {
-		fmt.Printf("# Config file: %s\n\n", path)
-	}
-
-	printField := func(key, val, src string) {
-		if val == "" {
-			fmt.Printf("  %-20s = %-20s (%s)\n", key, "(unset)", src)
-		} else {
-			fmt.Printf("  %-20s = %-20s (%s)\n", key, val, src)
-		}
+		fmt.Printf("# Config file: %s\n", path)
 	}
-	printBoolField := func(key string, val bool, src string) {
-		fmt.Printf("  %-20s = %-20s (%s)\n", key, strconv.FormatBool(val), src)
+	if repoPath != "" {
+		fmt.Printf("# Repo config: %s\n", repoPath)
 	}
-
-	clientSrc := "default"
-	if cfg != nil && cfg.Client != nil {
-		clientSrc = "file"
-	}
-	printField("client", resolved.Client, clientSrc)
-
-	modelSrc := "unset"
-	if cfg != nil && cfg.Model != nil {
-		modelSrc = "file"
-	}
-	printField("model", resolved.Model, modelSrc)
-
-	modeSrc := "default"
-	if cfg != nil && cfg.Mode != nil {
-		modeSrc = "file"
-	}
-	printField("mode", resolved.Mode, modeSrc)
-
-	effortSrc := "unset"
-	if cfg != nil && cfg.ReasoningEffort != nil {
-		effortSrc = "file"
-	}
-	printField("reasoning_effort", resolved.ReasoningEffort, effortSrc)
-
-	tierSrc := "unset"
-	if cfg != nil && cfg.ContextTier != nil {
-		tierSrc = "file"
+	if resolved.Profile != "" {
+		via := "--profile"
+		if profile == "" {
+			via = profileEnv
+		}
+		fmt.Printf("# Profile:     %s %s\n", resolved.Profile, dim("(from "+via+")"))
 	}
-	printField("context_tier", resolved.ContextTier, tierSrc)
+	fmt.Println()
 
-	toolsSrc := "default"
-	if cfg != nil && cfg.AllowAllTools != nil {
-		toolsSrc = "file"
+	for _, key := range []string{
+		"client", "model", "mode", "reasoning_effort", "context_tier", "allow_all_tools",
+		"ask_user", "auto_launch", "auto_update", "telemetry", "log_level", "otel_log_level",
+	} {
+		val := resolvedFieldStr(resolved, key)
+		if val == "" {
+			val = "(unset)"
+		}
+		fmt.Printf("  %-20s = %-20s (%s)\n", key, val, resolved.Sources[key])
 	}
-	printBoolField("allow_all_tools", resolved.AllowAllTools, toolsSrc)
 
-	askSrc := "default"
-	if cfg != nil && cfg.AskUser != nil {
-		askSrc = "file"
+	if names := profileNames(cfg); len(names) > 0 {
+		fmt.Println()
+		fmt.Printf("# Profiles: %s %s\n", strings.Join(names, ", "), dim("(select with --profile <name> or "+profileEnv+")"))
 	}
-	printBoolField("ask_user", resolved.AskUser, askSrc)
+	return nil
+}
 
-	autoLaunchSrc := "default"
-	if cfg != nil && cfg.AutoLaunch != nil {
-		autoLaunchSrc = "file"
+// loadConfigLayers reads the user config and the repo's .nav-pilot.toml and
+// resolves them with the given (or NAV_PILOT_PROFILE) profile, for the config
+// subcommands. Returns the user config (nil if absent) and the repo config
+// path ("" if none). An unknown profile or invalid repo config is an error.
+func loadConfigLayers(profile string) (*Config, string, ResolvedConfig, error) {
+	cfg, err := readConfig()
+	if err != nil {
+		return nil, "", ResolvedConfig{}, err
 	}
-	printBoolField("auto_launch", resolved.AutoLaunch, autoLaunchSrc)
-
-	autoUpdateSrc := "default"
-	if cfg != nil && cfg.AutoUpdate != nil {
-		autoUpdateSrc = "file"
+	overrides := CLIOverrides{Profile: profile}
+	if name := selectedProfile(overrides); name != "" {
+		if _, err := lookupProfile(cfg, name); err != nil {
+			return nil, "", ResolvedConfig{}, err
+		}
 	}
-	printBoolField("auto_update", resolved.AutoUpdate, autoUpdateSrc)
-
-	logSrc := "unset"
-	if cfg != nil && cfg.LogLevel != nil {
-		logSrc = "file"
+	repo, repoPath, err := readRepoConfig()
+	if err != nil {
+		return nil, "", ResolvedConfig{}, err
 	}
-	printField("log_level", resolved.LogLevel, logSrc)
-
-	otelSrc := "default"
-	if cfg != nil && cfg.OtelLogLevel != nil {
-		otelSrc = "file"
+	if repo == nil {
+		repoPath = ""
 	}
-	printField("otel_log_level", resolved.OtelLogLevel, otelSrc)
-
-	return nil
+	return cfg, repoPath, resolveLayers(repo, cfg, overrides), nil
 }
 
 // ─── config path ─────────────────────────────────────────────────────────────
@@ -432,17 +444,16 @@ func cmdConfigPath() error {
 
 // ─── config get ──────────────────────────────────────────────────────────────
 
-func cmdConfigGet(key string) error {
+func cmdConfigGet(key, profile string) error {
 	kd := findKeyDef(key)
 	if kd == nil {
 		return fmt.Errorf("unknown key: %q\n\nKnown keys: %s", key, knownKeyNames())
 	}
 
-	cfg, err := readConfig()
+	_, _, resolved, err := loadConfigLayers(profile)
 	if err != nil {
 		return err
 	}
-	resolved := resolve(cfg, CLIOverrides{})
 
 	val := resolvedFieldStr(resolved, key)
 	fmt.Println(val)
@@ -472,6 +483,8 @@ func resolvedFieldStr(r ResolvedConfig, key string) string {
 		return strconv.FormatBool(r.AutoLaunch)
 	case "auto_update":
 		return strconv.FormatBool(r.AutoUpdate)
+	case "telemetry":
+		return strconv.FormatBool(r.Telemetry)
 	case "log_level":
 		return r.LogLevel
 	case "otel_log_level":
@@ -586,9 +599,13 @@ func validateKeyValue(kd *configKeyDef, value string) error {
 	}
 	// Allowlist check for string and int keys that have one.
 	if len(kd.allowed) > 0 && kd.kind != keyKindBool {
-		if !containsStr(kd.allowed, value) {
+		allowed := kd.allowed
+		if kd.name == "client" {
+			allowed = providerIDs() // includes provider plugins on PATH
+		}
+		if !containsStr(allowed, value) {
 			return fmt.Errorf("key %q value %q is not valid\n\nAllowed: %s",
-				kd.name, value, strings.Join(kd.allowed, ", "))
+				kd.name, value, strings.Join(allowed, ", "))
 		}
 	}
 	return nil
@@ -704,12 +721,16 @@ func configHints(cfg *Config) []string {
 
 // ─── config explain ──────────────────────────────────────────────────────────
 
-func cmdConfigExplain(key string) error {
-	cfg, err := readConfig()
+func cmdConfigExplain(key, profile string) error {
+	cfg, repoPath, resolved, err := loadConfigLayers(profile)
 	if err != nil {
 		return err
 	}
-	resolved := resolve(cfg, CLIOverrides{})
+
+	if key == "profiles" {
+		printProfilesExplain(cfg, resolved)
+		return nil
+	}
 
 	if key != "" {
 		kd := findKeyDef(key)
@@ -721,19 +742,64 @@ func cmdConfigExplain(key string) error {
 	}
 
 	// Print all keys.
+	printLayersExplain(repoPath)
 	for i := range configKeyDefs {
-		if i > 0 {
-			fmt.Println()
-		}
+		fmt.Println()
 		printKeyExplain(&configKeyDefs[i], resolved)
 	}
+	fmt.Println()
+	printProfilesExplain(cfg, resolved)
 	return nil
 }
 
+// printLayersExplain prints the config layers in precedence order, which is
+// what the per-key "Source:" lines refer to.
+func printLayersExplain(repoPath string) {
+	repo := repoConfigFileName + " at the git root"
+	if repoPath != "" {
+		repo = repoPath
+	}
+	fmt.Printf("  %s (highest first)\n", bold("Layers"))
+	fmt.Printf("    %-14s keys listed in locked = [...] in %s\n", sourceLocked, repoConfigFileName)
+	fmt.Printf("    %-14s per-run CLI flags\n", sourceFlag)
+	fmt.Printf("    %-14s [profiles.<name>] selected with --profile or %s\n", "profile <name>", profileEnv)
+	fmt.Printf("    %-14s %s\n", sourceFile, configPath())
+	fmt.Printf("    %-14s %s\n", sourceRepo, repo)
+	fmt.Printf("    %-14s built-in default (or unset)\n", sourceDefault)
+}
+
+// profileKeys are the keys a [profiles.<name>] table may set.
+var profileKeys = []string{
+	"client", "model", "mode", "reasoning_effort", "context_tier",
+	"allow_all_tools", "ask_user", "auto_launch", "log_level", "otel_log_level",
+}
+
+func printProfilesExplain(cfg *Config, resolved ResolvedConfig) {
+	fmt.Printf("  %s\n", bold("profiles"))
+	fmt.Printf("    Named sets of launch settings in [profiles.<name>] tables. A selected\n")
+	fmt.Printf("    profile overrides the top-level values; CLI flags still win.\n")
+	fmt.Printf("    Keys:     %s\n", strings.Join(profileKeys, ", "))
+	fmt.Printf("    Select:   --profile <name> or %s=<name>\n", profileEnv)
+	if names := profileNames(cfg); len(names) > 0 {
+		fmt.Printf("    Defined:  %s\n", strings.Join(names, ", "))
+	} else {
+		fmt.Printf("    Defined:  (none)\n")
+	}
+	if resolved.Profile != "" {
+		fmt.Printf("    Current:  %s\n", resolved.Profile)
+	} else {
+		fmt.Printf("    Current:  (none)\n")
+	}
+	fmt.Printf("    To add:   nav-pilot config setup --profile <name>\n")
+}
+
 func printKeyExplain(kd *configKeyDef, resolved ResolvedConfig) {
 	fmt.Printf("  %s\n", bold(kd.name))
 	fmt.Printf("    %s\n", kd.description)
-	if len(kd.allowed) > 0 {
+	if kd.name == "client" {
+		fmt.Printf("    Allowed:  %s\n", strings.Join(providerIDs(), ", "))
+		fmt.Printf("    Plugins:  %s<id> executables on PATH add client <id>\n", providerPluginPrefix)
+	} else if len(kd.allowed) > 0 {
 		fmt.Printf("    Allowed:  %s\n", strings.Join(kd.allowed, ", "))
 	} else if kd.kind == keyKindBool {
 		fmt.Printf("    Allowed:  true, false\n")
@@ -757,6 +823,12 @@ func printKeyExplain(kd *configKeyDef, resolved ResolvedConfig) {
 	if kd.flag != "" {
 		fmt.Printf("    CLI flag: %s\n", kd.flag)
 	}
+	if containsStr(profileKeys, kd.name) {
+		fmt.Printf("    Profile:  can be set per profile in [profiles.<name>]\n")
+	}
+	if containsStr(repoConfigKeys, kd.name) {
+		fmt.Printf("    Repo:     can be set (and locked) in %s\n", repoConfigFileName)
+	}
 
 	val := resolvedFieldStr(resolved, kd.name)
 	if val == "" {
@@ -764,5 +836,8 @@ func printKeyExplain(kd *configKeyDef, resolved ResolvedConfig) {
 	} else {
 		fmt.Printf("    Current:  %s\n", val)
 	}
+	if src := resolved.Sources[kd.name]; src != "" {
+		fmt.Printf("    Source:   %s\n", src)
+	}
 	fmt.Printf("    To set:   nav-pilot config set %s <value>\n", kd.name)
 }
@@ -20,6 +20,17 @@ func captureStdout(f func()) string {
 	return string(out)
 }
 
+func captureStderr(f func()) string {
+	old := os.Stderr
+	r, w, _ := os.Pipe()
+	os.Stderr = w
+	f()
+	w.Close()
+	os.Stderr = old
+	out, _ := io.ReadAll(r)
+	return string(out)
+}
+
 // ─── validateOptionalModel ────────────────────────────────────────────────────
 
 func TestValidateOptionalModel_Blank(t *testing.T) {
@@ -66,13 +77,13 @@ func TestValidateOptionalModel_InvalidIDs(t *testing.T) {
 // ─── cmdConfig router ─────────────────────────────────────────────────────────
 
 func TestCmdConfig_NoArgs(t *testing.T) {
-	if err := cmdConfig(nil, false, false); err == nil {
+	if err := cmdConfig(nil, false, false, ""); err == nil {
 		t.Error("expected error when no subcommand given")
 	}
 }
 
 func TestCmdConfig_EmptyArgs(t *testing.T) {
-	if err := cmdConfig([]string{}, false, false); err == nil {
+	if err := cmdConfig([]string{}, false, false, ""); err == nil {
 		t.Error("expected error for empty args slice")
 	}
 }
@@ -80,7 +91,7 @@ func TestCmdConfig_EmptyArgs(t *testing.T) {
 func TestCmdConfig_UnknownSubcommand(t *testing.T) {
 	dir := t.TempDir()
 	t.Setenv("NAV_PILOT_CONFIG", filepath.Join(dir, "config.toml"))
-	if err := cmdConfig([]string{"bogussubcmd"}, false, false); err == nil {
+	if err := cmdConfig([]string{"bogussubcmd"}, false, false, ""); err == nil {
 		t.Error("expected error for unknown subcommand")
 	}
 }
@@ -88,7 +99,7 @@ func TestCmdConfig_UnknownSubcommand(t *testing.T) {
 func TestCmdConfig_GetNoKey(t *testing.T) {
 	dir := t.TempDir()
 	t.Setenv("NAV_PILOT_CONFIG", filepath.Join(dir, "config.toml"))
-	if err := cmdConfig([]string{"get"}, false, false); err == nil {
+	if err := cmdConfig([]string{"get"}, false, false, ""); err == nil {
 		t.Error("expected error for 'get' with no key argument")
 	}
 }
@@ -96,7 +107,7 @@ func TestCmdConfig_GetNoKey(t *testing.T) {
 func TestCmdConfig_SetNoArgs(t *testing.T) {
 	dir := t.TempDir()
 	t.Setenv("NAV_PILOT_CONFIG", filepath.Join(dir, "config.toml"))
-	if err := cmdConfig([]string{"set"}, false, false); err == nil {
+	if err := cmdConfig([]string{"set"}, false, false, ""); err == nil {
 		t.Error("expected error for 'set' with no arguments")
 	}
 }
@@ -104,7 +115,7 @@ func TestCmdConfig_SetNoArgs(t *testing.T) {
 func TestCmdConfig_SetOneArg(t *testing.T) {
 	dir := t.TempDir()
 	t.Setenv("NAV_PILOT_CONFIG", filepath.Join(dir, "config.toml"))
-	if err := cmdConfig([]string{"set", "client"}, false, false); err == nil {
+	if err := cmdConfig([]string{"set", "client"}, false, false, ""); err == nil {
 		t.Error("expected error for 'set' with only one argument (missing value)")
 	}
 }
@@ -118,7 +129,7 @@ func TestCmdConfigShow_NoFile(t *testing.T) {
 	var out string
 	var showErr error
 	out = captureStdout(func() {
-		showErr = cmdConfigShow(false)
+		showErr = cmdConfigShow(false, "")
 	})
 
 	if showErr != nil {
@@ -139,7 +150,7 @@ func TestCmdConfigShow_WithFile(t *testing.T) {
 	var out string
 	var showErr error
 	out = captureStdout(func() {
-		showErr = cmdConfigShow(false)
+		showErr = cmdConfigShow(false, "")
 	})
 
 	if showErr != nil {
@@ -160,7 +171,7 @@ func TestCmdConfigShow_JSON(t *testing.T) {
 	var out string
 	var showErr error
 	out = captureStdout(func() {
-		showErr = cmdConfigShow(true)
+		showErr = cmdConfigShow(true, "")
 	})
 
 	if showErr != nil {
@@ -180,7 +191,7 @@ func TestCmdConfigShow_JSONDefaultValues(t *testing.T) {
 	var out string
 	var showErr error
 	out = captureStdout(func() {
-		showErr = cmdConfigShow(true)
+		showErr = cmdConfigShow(true, "")
 	})
 
 	if showErr != nil {
@@ -219,7 +230,7 @@ func TestCmdConfigExplain_AllKeys(t *testing.T) {
 	var out string
 	var explainErr error
 	out = captureStdout(func() {
-		explainErr = cmdConfigExplain("")
+		explainErr = cmdConfigExplain("", "")
 	})
 
 	if explainErr != nil {
@@ -239,7 +250,7 @@ func TestCmdConfigExplain_SingleKeyModel(t *testing.T) {
 	var out string
 	var explainErr error
 	out = captureStdout(func() {
-		explainErr = cmdConfigExplain("model")
+		explainErr = cmdConfigExplain("model", "")
 	})
 
 	if explainErr != nil {
@@ -259,7 +270,7 @@ func TestCmdConfigExplain_SingleKeyClient(t *testing.T) {
 	var out string
 	var explainErr error
 	out = captureStdout(func() {
-		explainErr = cmdConfigExplain("client")
+		explainErr = cmdConfigExplain("client", "")
 	})
 
 	if explainErr != nil {
@@ -274,7 +285,7 @@ func TestCmdConfigExplain_UnknownKey(t *testing.T) {
 	dir := t.TempDir()
 	t.Setenv("NAV_PILOT_CONFIG", filepath.Join(dir, "config.toml"))
 
-	if err := cmdConfigExplain("nosuchkey"); err == nil {
+	if err := cmdConfigExplain("nosuchkey", ""); err == nil {
 		t.Error("expected error for unknown key, got nil")
 	}
 }
@@ -285,7 +296,7 @@ func TestCmdConfigSetup_FileAlreadyExists(t *testing.T) {
 	path := writeTempConfig(t, "version = 1\n")
 	t.Setenv("NAV_PILOT_CONFIG", path)
 
-	err := cmdConfigSetup(false)
+	err := cmdConfigSetup(false, "")
 	if err == nil {
 		t.Fatal("expected error when config file already exists")
 	}
@@ -300,7 +311,7 @@ func TestCmdConfigSetup_NonInteractiveNoFile(t *testing.T) {
 	forceNonInteractive = true
 	defer func() { forceNonInteractive = false }()
 
-	err := cmdConfigSetup(false)
+	err := cmdConfigSetup(false, "")
 	if err == nil {
 		t.Fatal("expected error when non-interactive and no config file")
 	}
@@ -316,7 +327,7 @@ func TestCmdConfigShow_JSON_OtelLogLevel_DefaultNone(t *testing.T) {
 	t.Setenv("NAV_PILOT_CONFIG", filepath.Join(dir, "config.toml"))
 
 	out := captureStdout(func() {
-		if err := cmdConfigShow(true); err != nil {
+		if err := cmdConfigShow(true, ""); err != nil {
 			t.Fatalf("cmdConfigShow(true) returned unexpected error: %v", err)
 		}
 	})
@@ -333,7 +344,7 @@ func TestCmdConfigGet_OtelLogLevel_DefaultNone(t *testing.T) {
 	t.Setenv("NAV_PILOT_CONFIG", filepath.Join(dir, "config.toml"))
 
 	out := captureStdout(func() {
-		if err := cmdConfigGet("otel_log_level"); err != nil {
+		if err := cmdConfigGet("otel_log_level", ""); err != nil {
 			t.Fatalf("cmdConfigGet(otel_log_level) returned unexpected error: %v", err)
 		}
 	})
@@ -0,0 +1,147 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+)
+
+// repoConfigFileName is the team-shared config committed at the git root.
+const repoConfigFileName = ".nav-pilot.toml"
+
+// repoConfigKeys are the keys .nav-pilot.toml may set (and lock).
+var repoConfigKeys = []string{"client", "model", "mode", "reasoning_effort", "context_tier"}
+
+// repoConfigPath returns the .nav-pilot.toml path for the git repository
+// containing the working directory, or "" outside a repository.
+func repoConfigPath() string {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return ""
+	}
+	root := findGitRoot(cwd)
+	if root == "" {
+		return ""
+	}
+	return filepath.Join(root, repoConfigFileName)
+}
+
+// readRepoConfig reads and validates the repo config for the working
+// directory. Returns (nil, "", nil) when there is none. Unknown keys are an
+// error, as in the user config.
+func readRepoConfig() (*RepoConfig, string, error) {
+	path := repoConfigPath()
+	if path == "" {
+		return nil, "", nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, "", nil
+		}
+		return nil, path, fmt.Errorf("reading repo config %s: %w", path, err)
+	}
+	var rc RepoConfig
+	meta, err := toml.Decode(string(data), &rc)
+	if err != nil {
+		return nil, path, fmt.Errorf("parsing repo config %s: %w", path, err)
+	}
+	problems := validateRepoConfigProblems(&rc)
+	for _, k := range meta.Undecoded() {
+		problems = append(problems, fmt.Sprintf("unknown key: %s (allowed: %s, locked)", k.String(), strings.Join(repoConfigKeys, ", ")))
+	}
+	if len(problems) > 0 {
+		return nil, path, fmt.Errorf("repo config %s is invalid:\n  - %s", path, strings.Join(problems, "\n  - "))
+	}
+	return &rc, path, nil
+}
+
+// validateRepoConfigProblems checks a repo config with the user-config rules
+// plus: every locked key must be a repo key that the file also sets.
+func validateRepoConfigProblems(rc *RepoConfig) []string {
+	cfg := repoAsConfig(rc)
+	cfg.Version = rc.Version
+	problems := validateConfigProblems(cfg)
+	set := repoSetKeys(rc)
+	for _, key := range rc.Locked {
+		switch {
+		case !containsStr(repoConfigKeys, key):
+			problems = append(problems, fmt.Sprintf("locked key %q is not a repo config key (allowed: %s)", key, strings.Join(repoConfigKeys, ", ")))
+		case !set[key]:
+			problems = append(problems, fmt.Sprintf("locked key %q has no value in the repo config", key))
+		}
+	}
+	return problems
+}
+
+// repoAsConfig returns the repo values as a Config layer.
+func repoAsConfig(rc *RepoConfig) *Config {
+	return &Config{
+		Version:         1,
+		Client:          rc.Client,
+		Model:           rc.Model,
+		Mode:            rc.Mode,
+		ReasoningEffort: rc.ReasoningEffort,
+		ContextTier:     rc.ContextTier,
+	}
+}
+
+// repoLockedConfig returns a Config layer holding only the locked repo values.
+func repoLockedConfig(rc *RepoConfig) *Config {
+	all := repoAsConfig(rc)
+	out := &Config{Version: 1}
+	for _, key := range rc.Locked {
+		switch key {
+		case "client":
+			out.Client = all.Client
+		case "model":
+			out.Model = all.Model
+		case "mode":
+			out.Mode = all.Mode
+		case "reasoning_effort":
+			out.ReasoningEffort = all.ReasoningEffort
+		case "context_tier":
+			out.ContextTier = all.ContextTier
+		}
+	}
+	return out
+}
+
+// repoSetKeys returns the repo keys that have a value.
+func repoSetKeys(rc *RepoConfig) map[string]bool {
+	return map[string]bool{
+		"client":           rc.Client != nil,
+		"model":            rc.Model != nil,
+		"mode":             rc.Mode != nil,
+		"reasoning_effort": rc.ReasoningEffort != nil,
+		"context_tier":     rc.ContextTier != nil,
+	}
+}
+
+// lockedOverrides lists CLI flags that a locked repo key overrode, as
+// human-readable warnings.
+func lockedOverrides(rc *RepoConfig, cli CLIOverrides, resolved ResolvedConfig) []string {
+	if rc == nil {
+		return nil
+	}
+	flags := map[string][2]string{
+		"client":           {"--client", cli.Client},
+		"model":            {"--model", cli.Model},
+		"mode":             {"--mode", cli.Mode},
+		"reasoning_effort": {"--effort", cli.ReasoningEffort},
+		"context_tier":     {"--context", cli.ContextTier},
+	}
+	var warnings []string
+	for _, key := range rc.Locked {
+		f := flags[key]
+		if f[1] != "" && f[1] != resolvedFieldStr(resolved, key) {
+			warnings = append(warnings, fmt.Sprintf("%s %s ignored: %s is locked to %q by %s",
+				f[0], f[1], key, resolvedFieldStr(resolved, key), repoConfigFileName))
+		}
+	}
+	return warnings
+}
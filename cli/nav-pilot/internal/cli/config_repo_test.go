@@ -0,0 +1,92 @@
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// repoConfigFixture creates a git repo with the given .nav-pilot.toml, makes
+// it the working directory and points the user config at userTOML.
+func repoConfigFixture(t *testing.T, repoTOML, userTOML string) string {
+	t.Helper()
+	t.Setenv(profileEnv, "")
+	dir := t.TempDir()
+	os.MkdirAll(filepath.Join(dir, ".git"), 0o755)
+	os.MkdirAll(filepath.Join(dir, "sub"), 0o755)
+	if repoTOML != "" {
+		writeFileForTest(t, filepath.Join(dir, repoConfigFileName), repoTOML)
+	}
+	t.Setenv("NAV_PILOT_CONFIG", writeTempConfig(t, userTOML))
+	t.Chdir(filepath.Join(dir, "sub"))
+	return dir
+}
+
+func TestRepoConfig_LayersAndLocks(t *testing.T) {
+	repoConfigFixture(t,
+		"version = 1\nclient = \"opencode\"\nmode = \"plan\"\ncontext_tier = \"long_context\"\nmodel = \"github-copilot/claude-opus-4.8\"\nlocked = [\"model\"]\n",
+		"version = 1\nmode = \"autopilot\"\nmodel = \"gpt-5.5\"\n")
+
+	var r ResolvedConfig
+	var err error
+	stderr := captureStderr(func() {
+		r, err = loadConfigForLaunch(CLIOverrides{Model: "gpt-5-mini", ContextTier: "default"})
+	})
+	if err != nil {
+		t.Fatalf("loadConfigForLaunch() error: %v", err)
+	}
+	// Repo defaults sit below the user file; a locked key beats even CLI flags.
+	want := map[string][2]string{
+		"client":       {"opencode", sourceRepo},
+		"mode":         {"autopilot", sourceFile},
+		"context_tier": {"default", sourceFlag},
+		"model":        {"github-copilot/claude-opus-4.8", sourceLocked},
+		"ask_user":     {"true", sourceDefault},
+	}
+	for key, w := range want {
+		if got := resolvedFieldStr(r, key); got != w[0] || r.Sources[key] != w[1] {
+			t.Errorf("%s = %q (%s), want %q (%s)", key, got, r.Sources[key], w[0], w[1])
+		}
+	}
+	if !strings.Contains(stderr, "--model gpt-5-mini ignored") {
+		t.Errorf("expected locked-override warning, stderr: %q", stderr)
+	}
+}
+
+func TestRepoConfig_Invalid(t *testing.T) {
+	for name, tc := range map[string]struct{ toml, want string }{
+		"permission key": {"version = 1\nallow_all_tools = true\n", "unknown key: allow_all_tools"},
+		"bad value":      {"version = 1\nmode = \"turbo\"\n", `mode "turbo" is not valid`},
+		"lock unset key": {"version = 1\nmode = \"plan\"\nlocked = [\"model\"]\n", `locked key "model" has no value`},
+		"lock non-repo":  {"version = 1\nlocked = [\"ask_user\"]\n", `locked key "ask_user" is not a repo config key`},
+	} {
+		t.Run(name, func(t *testing.T) {
+			repoConfigFixture(t, tc.toml, "version = 1\n")
+			_, err := loadConfigForLaunch(CLIOverrides{})
+			if err == nil || !strings.Contains(err.Error(), tc.want) || !strings.Contains(err.Error(), repoConfigFileName) {
+				t.Errorf("err = %v, want %q", err, tc.want)
+			}
+		})
+	}
+}
+
+func TestCmdConfigExplain_ShowsSource(t *testing.T) {
+	dir := repoConfigFixture(t, "version = 1\nmode = \"plan\"\nlocked = [\"mode\"]\n", "version = 1\nmodel = \"gpt-5.5\"\n")
+
+	var err error
+	out := captureStdout(func() { err = cmdConfigExplain("", "") })
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"Layers", filepath.Join(dir, repoConfigFileName), "Source:   repo, locked", "Source:   file", "Source:   default"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("explain output missing %q", want)
+		}
+	}
+
+	out = captureStdout(func() { err = cmdConfigShow(false, "") })
+	if err != nil || !strings.Contains(out, "# Repo config:") || !strings.Contains(out, "(repo, locked)") {
+		t.Errorf("show err = %v, output:\n%s", err, out)
+	}
+}
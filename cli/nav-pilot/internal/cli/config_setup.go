@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"regexp"
 	"strings"
 
 	"github.com/BurntSushi/toml"
@@ -93,13 +94,94 @@ func writeSetupConfig(answers setupAnswers) error {
 	return os.Chmod(path, 0o600)
 }
 
-// runConfigSetup runs the interactive first-run wizard.
+// profileNamePattern restricts profile names to TOML bare keys so the
+// table header never needs quoting.
+var profileNamePattern = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+
+// writeSetupProfile writes wizard answers as a [profiles.<name>] table,
+// replacing an existing table of that name and keeping the rest of the file.
+// Settings the wizard leaves unset are omitted so they fall through to the
+// top-level values. Creates the file (with version = 1) when missing.
+func writeSetupProfile(name string, answers setupAnswers) error {
+	path := configPath()
+	var lines []string
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if !errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("reading config: %w", err)
+		}
+		lines = []string{"version = 1"}
+	} else {
+		lines = strings.Split(strings.TrimRight(string(data), "\n"), "\n")
+	}
+
+	header := "[profiles." + name + "]"
+	var kept []string
+	inSection := false
+	for _, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "[") {
+			inSection = trimmed == header
+		}
+		if !inSection {
+			kept = append(kept, line)
+		}
+	}
+	for len(kept) > 0 && strings.TrimSpace(kept[len(kept)-1]) == "" {
+		kept = kept[:len(kept)-1]
+	}
+
+	kept = append(kept, "", header)
+	for _, kv := range []struct{ key, val string }{
+		{"client", answers.Client},
+		{"mode", answers.Mode},
+		{"model", answers.Model},
+		{"reasoning_effort", answers.ReasoningEffort},
+		{"auto_launch", answers.AutoLaunch},
+	} {
+		if kv.val == "" {
+			continue
+		}
+		tomlVal, err := formatTOMLValue(findKeyDef(kv.key), kv.val)
+		if err != nil {
+			return err
+		}
+		kept = append(kept, kv.key+" = "+tomlVal)
+	}
+	content := strings.Join(kept, "\n") + "\n"
+
+	// Validate before writing.
+	var cfg Config
+	if _, err := toml.Decode(content, &cfg); err != nil {
+		return fmt.Errorf("updated config would not parse: %w", err)
+	}
+	if err := validateConfig(&cfg); err != nil {
+		return fmt.Errorf("updated config would be invalid: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return fmt.Errorf("creating config directory: %w", err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		return err
+	}
+	return os.Chmod(path, 0o600)
+}
+
+// runConfigSetup runs the interactive first-run wizard. With a profile name
+// the answers are saved as [profiles.<name>] instead of top-level settings,
+// and the global auto-update question is skipped.
 // Returns nil on user cancel (so callers never block).
 // Each huh prompt: on cancel/error, print a soft notice and return nil.
-func runConfigSetup() error {
+func runConfigSetup(profile string) error {
 	fmt.Println()
-	fmt.Printf("%s  First-run setup\n", bold("🧭 nav-pilot"))
-	fmt.Println(dim("  Set your preferences — change anytime with 'nav-pilot config set'."))
+	if profile != "" {
+		fmt.Printf("%s  Profile setup: %s\n", bold("🧭 nav-pilot"), bold(profile))
+		fmt.Println(dim("  Launch settings for --profile " + profile + " — unset answers fall back to the top-level config."))
+	} else {
+		fmt.Printf("%s  First-run setup\n", bold("🧭 nav-pilot"))
+		fmt.Println(dim("  Set your preferences — change anytime with 'nav-pilot config set'."))
+	}
 	fmt.Println()
 
 	answers := setupAnswers{
@@ -248,6 +330,15 @@ func runConfigSetup() error {
 		return errors.New("setup aborted by user")
 	}
 
+	if profile != "" {
+		if err := writeSetupProfile(profile, answers); err != nil {
+			return fmt.Errorf("saving profile: %w", err)
+		}
+		fmt.Printf("\n%s Saved profile %s in %s\n", green("✓"), bold(profile), configPath())
+		fmt.Printf("  Use it with %s or %s\n\n", bold("nav-pilot --profile "+profile), bold(profileEnv+"="+profile))
+		return nil
+	}
+
 	err = huh.NewSelect[string]().
 		Title("Auto-update nav-pilot").
 		Description("Automatically install new CLI versions in the background.").
@@ -301,12 +392,17 @@ func maybeRunFirstRunSetup() error {
 		// File exists (err == nil) or some other stat error — skip setup.
 		return nil
 	}
-	return runConfigSetup()
+	return runConfigSetup("")
 }
 
 // cmdConfigSetup implements the 'nav-pilot config setup' subcommand.
 // Refuses to clobber an existing config and directs the user to 'config set'.
-func cmdConfigSetup(force bool) error {
+// With a profile it adds (or with --force replaces) [profiles.<name>] in the
+// existing file instead.
+func cmdConfigSetup(force bool, profile string) error {
+	if profile != "" {
+		return cmdConfigSetupProfile(force, profile)
+	}
 	if _, err := os.Stat(configPath()); err == nil {
 		if force {
 			// Remove config to allow setup to overwrite cleanly
@@ -323,7 +419,7 @@ func cmdConfigSetup(force bool) error {
 	if !isInteractive() {
 		return fmt.Errorf("config setup requires an interactive terminal.\n\nUse 'nav-pilot config init' to create a template, then edit it directly")
 	}
-	if err := runConfigSetup(); err != nil {
+	if err := runConfigSetup(""); err != nil {
 		return err
 	}
 
@@ -332,3 +428,21 @@ func cmdConfigSetup(force bool) error {
 	}
 	return nil
 }
+
+func cmdConfigSetupProfile(force bool, profile string) error {
+	if !profileNamePattern.MatchString(profile) {
+		return fmt.Errorf("profile name %q is not valid (allowed: letters, digits, '_' and '-')", profile)
+	}
+	cfg, err := readConfig()
+	if err != nil {
+		return err
+	}
+	if cfg != nil && cfg.Profiles[profile] != nil && !force {
+		return fmt.Errorf("profile %q already exists in %s\n\nTo see it:           %s\nTo replace it:       re-run with --force",
+			profile, configPath(), bold("nav-pilot config show --profile "+profile))
+	}
+	if !isInteractive() {
+		return fmt.Errorf("config setup requires an interactive terminal.\n\nAdd a [profiles.%s] table to %s directly", profile, configPath())
+	}
+	return runConfigSetup(profile)
+}
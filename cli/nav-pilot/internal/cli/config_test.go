@@ -3,6 +3,7 @@ package cli
 import (
 	"os"
 	"path/filepath"
+	"runtime"
 	"strings"
 	"testing"
 
@@ -163,6 +164,32 @@ func TestValidateConfig_UnknownClient(t *testing.T) {
 	}
 }
 
+func TestValidateConfig_PluginClient(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("shell plugin fixture requires a POSIX shell")
+	}
+	bin := t.TempDir()
+	plugin := filepath.Join(bin, "nav-pilot-provider-acme")
+	script := "#!/bin/sh\ncat >/dev/null\necho '{\"id\":\"acme\",\"available\":true}'\n"
+	if err := os.WriteFile(plugin, []byte(script), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	client := "acme"
+	cfg := &Config{Version: 1, Client: &client}
+	if err := validateConfig(cfg); err == nil {
+		t.Fatal("expected error for client acme before the plugin is on PATH")
+	}
+
+	t.Setenv("PATH", bin+string(os.PathListSeparator)+os.Getenv("PATH"))
+	if err := validateConfig(cfg); err != nil {
+		t.Errorf("expected plugin client to be valid, got: %v", err)
+	}
+	kd := findKeyDef("client")
+	if err := validateKeyValue(kd, "acme"); err != nil {
+		t.Errorf("config set client acme: %v", err)
+	}
+}
+
 func TestValidateConfig_UnknownMode(t *testing.T) {
 	tests := []struct {
 		mode  string
@@ -863,7 +890,7 @@ func TestCmdConfigGet_KnownKey(t *testing.T) {
 
 	// Verify the key def lookup works for all known keys.
 	for _, kd := range configKeyDefs {
-		if err := cmdConfigGet(kd.name); err != nil {
+		if err := cmdConfigGet(kd.name, ""); err != nil {
 			t.Errorf("cmdConfigGet(%q) returned unexpected error: %v", kd.name, err)
 		}
 	}
@@ -873,7 +900,7 @@ func TestCmdConfigGet_UnknownKey(t *testing.T) {
 	dir := t.TempDir()
 	t.Setenv("NAV_PILOT_CONFIG", filepath.Join(dir, "config.toml"))
 
-	err := cmdConfigGet("does_not_exist")
+	err := cmdConfigGet("does_not_exist", "")
 	if err == nil {
 		t.Fatal("expected error for unknown key")
 	}
@@ -1410,3 +1437,117 @@ func TestValidateConfig_OtelLogLevel(t *testing.T) {
 		})
 	}
 }
+
+// ─── profiles ────────────────────────────────────────────────────────────────
+
+const profilesConfig = `version = 1
+client = "copilot"
+mode = "default"
+model = "claude-sonnet-4.6"
+
+[profiles.careful]
+mode = "plan"
+model = "claude-opus-4.8"
+reasoning_effort = "high"
+
+[profiles.fast]
+mode = "autopilot"
+allow_all_tools = true
+`
+
+func TestResolve_ProfilePrecedence(t *testing.T) {
+	t.Setenv(profileEnv, "")
+	path := writeTempConfig(t, profilesConfig)
+	t.Setenv("NAV_PILOT_CONFIG", path)
+
+	r, err := loadConfigForLaunch(CLIOverrides{Profile: "careful", Mode: "autopilot"})
+	if err != nil {
+		t.Fatalf("loadConfigForLaunch() error: %v", err)
+	}
+	// CLI flag > profile > file > default.
+	if r.Profile != "careful" || r.Mode != "autopilot" || r.Model != "claude-opus-4.8" ||
+		r.ReasoningEffort != "high" || r.Client != "copilot" || r.AllowAllTools {
+		t.Errorf("resolved = %+v", r)
+	}
+
+	// NAV_PILOT_PROFILE selects when --profile is absent; --profile wins over it.
+	t.Setenv(profileEnv, "fast")
+	r, err = loadConfigForLaunch(CLIOverrides{})
+	if err != nil {
+		t.Fatalf("loadConfigForLaunch() error: %v", err)
+	}
+	if r.Profile != "fast" || r.Mode != "autopilot" || !r.AllowAllTools || r.Model != "claude-sonnet-4.6" {
+		t.Errorf("env-selected profile = %+v", r)
+	}
+	if r, _ = loadConfigForLaunch(CLIOverrides{Profile: "careful"}); r.Profile != "careful" {
+		t.Errorf("--profile should win over %s, got %q", profileEnv, r.Profile)
+	}
+}
+
+func TestLoadConfigForLaunch_UnknownProfile(t *testing.T) {
+	t.Setenv(profileEnv, "")
+	t.Setenv("NAV_PILOT_CONFIG", writeTempConfig(t, profilesConfig))
+
+	_, err := loadConfigForLaunch(CLIOverrides{Profile: "nope"})
+	if err == nil || !strings.Contains(err.Error(), `profile "nope" not found`) || !strings.Contains(err.Error(), "careful, fast") {
+		t.Errorf("err = %v, want unknown-profile error listing profiles", err)
+	}
+}
+
+func TestValidateConfig_ProfileProblems(t *testing.T) {
+	t.Setenv("NAV_PILOT_CONFIG", writeTempConfig(t, "version = 1\n\n[profiles.bad]\nmode = \"turbo\"\nbogus = 1\n"))
+
+	cfg, meta, err := readConfigWithMeta()
+	if err != nil {
+		t.Fatal(err)
+	}
+	problems := validateConfigProblems(cfg)
+	if len(problems) != 1 || !strings.HasPrefix(problems[0], "profiles.bad: mode \"turbo\"") {
+		t.Errorf("problems = %v", problems)
+	}
+	if undecoded := meta.Undecoded(); len(undecoded) != 1 || undecoded[0].String() != "profiles.bad.bogus" {
+		t.Errorf("undecoded = %v, want profiles.bad.bogus", undecoded)
+	}
+}
+
+func TestCmdConfigShow_Profile(t *testing.T) {
+	t.Setenv(profileEnv, "")
+	t.Setenv("NAV_PILOT_CONFIG", writeTempConfig(t, profilesConfig))
+
+	var err error
+	out := captureStdout(func() { err = cmdConfigShow(false, "careful") })
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"Profile:     careful", "(profile careful)", "(file)", "# Profiles: careful, fast"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("show output missing %q:\n%s", want, out)
+		}
+	}
+	if err := cmdConfigShow(false, "nope"); err == nil {
+		t.Error("cmdConfigShow with unknown profile: expected error")
+	}
+}
+
+func TestWriteSetupProfile_ReplacesTable(t *testing.T) {
+	path := writeTempConfig(t, profilesConfig)
+	t.Setenv("NAV_PILOT_CONFIG", path)
+
+	if err := writeSetupProfile("careful", setupAnswers{Client: "opencode", Mode: "plan"}); err != nil {
+		t.Fatalf("writeSetupProfile() error: %v", err)
+	}
+	cfg, err := readConfig()
+	if err != nil {
+		t.Fatal(err)
+	}
+	p := cfg.Profiles["careful"]
+	if p == nil || p.Client == nil || *p.Client != "opencode" || p.Model != nil || p.ReasoningEffort != nil {
+		t.Errorf("careful profile = %+v, want replaced table", p)
+	}
+	if f := cfg.Profiles["fast"]; f == nil || f.AllowAllTools == nil || !*f.AllowAllTools {
+		t.Errorf("fast profile should be kept, got %+v", f)
+	}
+	if cfg.Model == nil || *cfg.Model != "claude-sonnet-4.6" {
+		t.Errorf("top-level model should be kept, got %v", cfg.Model)
+	}
+}
@@ -0,0 +1,255 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// manifestItems returns the manifest's items as dependency roots.
+func manifestItems(m *Manifest) []Dependency {
+	var items []Dependency
+	for _, group := range []struct {
+		names []string
+		kind  *ArtifactKind
+	}{
+		{m.Agents, KindAgent},
+		{m.Skills, KindSkill},
+		{m.Instructions, KindInstruction},
+		{m.Prompts, KindPrompt},
+	} {
+		for _, name := range group.names {
+			items = append(items, Dependency{Kind: group.kind, Name: name})
+		}
+	}
+	return items
+}
+
+// withDependencies returns a copy of manifest extended with the transitive
+// closure of the dependencies its items declare in frontmatter. Additions and
+// missing dependencies are printed so the install output explains them.
+func withDependencies(resolver *SourceResolver, manifest *Manifest) (*Manifest, error) {
+	deps, err := resolver.ResolveClosure(manifestItems(manifest))
+	if err != nil {
+		return nil, err
+	}
+	if len(deps) == 0 {
+		return manifest, nil
+	}
+
+	expanded := *manifest
+	expanded.Agents = append([]string(nil), manifest.Agents...)
+	expanded.Skills = append([]string(nil), manifest.Skills...)
+	expanded.Instructions = append([]string(nil), manifest.Instructions...)
+	expanded.Prompts = append([]string(nil), manifest.Prompts...)
+
+	fmt.Println(bold("Dependencies:"))
+	for _, d := range deps {
+		if !d.Found {
+			fmt.Printf("  %s %s %s not found (required by %s %s)\n",
+				yellow("⚠"), d.Kind.Name, d.Name, d.RequiredBy.Kind.Name, d.RequiredBy.Name)
+			continue
+		}
+		fmt.Printf("  %s %s %s %s\n", dim("+"), d.Kind.Name, d.Name,
+			dim(fmt.Sprintf("(required by %s %s)", d.RequiredBy.Kind.Name, d.RequiredBy.Name)))
+		switch d.Kind {
+		case KindAgent:
+			expanded.Agents = append(expanded.Agents, d.Name)
+		case KindSkill:
+			expanded.Skills = append(expanded.Skills, d.Name)
+		case KindInstruction:
+			expanded.Instructions = append(expanded.Instructions, d.Name)
+		case KindPrompt:
+			expanded.Prompts = append(expanded.Prompts, d.Name)
+		}
+	}
+	fmt.Println()
+	return &expanded, nil
+}
+
+// installDependencies installs the transitive dependencies of a single added
+// artifact into result. Kinds the scope cannot hold are skipped with a note.
+func installDependencies(resolver *SourceResolver, scope *InstallScope, root Dependency, dryRun, force bool, result *installResult) error {
+	deps, err := resolver.ResolveClosure([]Dependency{root})
+	if err != nil {
+		return err
+	}
+	for _, d := range deps {
+		switch {
+		case !d.Found:
+			fmt.Printf("  %s %s %s not found (required by %s %s)\n",
+				yellow("⚠"), d.Kind.Name, d.Name, d.RequiredBy.Kind.Name, d.RequiredBy.Name)
+		case !scope.SupportsType(d.Kind.Name):
+			fmt.Printf("  %s %s %s %s\n", dim("⊘"), d.Kind.Name, d.Name,
+				dim(fmt.Sprintf("(required by %s %s, not supported in %s scope)", d.RequiredBy.Kind.Name, d.RequiredBy.Name, scope.Name)))
+		default:
+			if err := installArtifact(resolver, scope, d.Kind, d.Name, dryRun, force, result); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// installedArtifact maps a state path back to the artifact it holds, e.g.
+// ".github/skills/api-design/" → skill api-design.
+func installedArtifact(f InstalledFile) (Dependency, bool) {
+	p := strings.TrimPrefix(unscopedPath(f.Path, f.Subproject), ".github/")
+	isDir := strings.HasSuffix(p, "/")
+	p = strings.TrimSuffix(p, "/")
+	for _, kind := range AllKinds {
+		rest, ok := strings.CutPrefix(p, kind.Dir+"/")
+		if !ok || strings.Contains(rest, "/") {
+			continue
+		}
+		if !isDir {
+			rest, ok = strings.CutSuffix(rest, kind.Suffix)
+			if !ok {
+				continue
+			}
+		}
+		return Dependency{Kind: kind, Name: rest}, true
+	}
+	return Dependency{}, false
+}
+
+// installedDependents returns the active installed artifacts whose installed
+// copy declares target as a dependency, as "kind name" labels.
+func installedDependents(scope *InstallScope, state *StateFile, target Dependency) []string {
+	var dependents []string
+	for _, f := range state.Files {
+		if f.Status == fileStatusIgnored {
+			continue
+		}
+		art, ok := installedArtifact(f)
+		if !ok || art == target {
+			continue
+		}
+		deps, err := readDependencies(art.Kind, art.Name, filepath.Join(scope.RootDir, f.Path), strings.HasSuffix(f.Path, "/"))
+		if err != nil {
+			continue
+		}
+		for _, d := range deps {
+			if d == target {
+				dependents = append(dependents, art.Kind.Name+" "+art.Name)
+				break
+			}
+		}
+	}
+	sort.Strings(dependents)
+	return dependents
+}
+
+// cmdUninstallItem removes a single installed artifact. It refuses when other
+// installed artifacts still require it, unless force is set.
+func cmdUninstallItem(scope *InstallScope, name, itemType string, dryRun, force bool) (err error) {
+	if !dryRun {
+		tx, txErr := beginJournal(scope, "uninstall")
+		if txErr != nil {
+			return txErr
+		}
+		defer tx.finish(&err)
+	}
+
+	state, err := readScopedState(scope)
+	if err != nil {
+		return fmt.Errorf("reading state: %w", err)
+	}
+	if state == nil {
+		fmt.Println("No nav-pilot collection installed. Nothing to uninstall.")
+		return nil
+	}
+
+	var matches []InstalledFile
+	var targets []Dependency
+	for _, f := range state.Files {
+		art, ok := installedArtifact(f)
+		if !ok || art.Name != name || (itemType != "" && art.Kind.Name != itemType) {
+			continue
+		}
+		// An instruction installed for a subproject is removed with the same
+		// --subproject; without it, only the repo-wide copy matches.
+		if art.Kind == KindInstruction && f.Subproject != scope.Subproject {
+			continue
+		}
+		matches = append(matches, f)
+		targets = append(targets, art)
+	}
+	switch {
+	case len(matches) == 0:
+		return fmt.Errorf("%q is not installed. Run 'nav-pilot list --installed' to see installed items", name)
+	case len(matches) > 1:
+		var kinds []string
+		for _, t := range targets {
+			kinds = append(kinds, t.Kind.Name)
+		}
+		return fmt.Errorf("%q matches multiple installed types: %s. Use --type to pick one", name, strings.Join(kinds, ", "))
+	}
+	f, target := matches[0], targets[0]
+
+	if dependents := installedDependents(scope, state, target); len(dependents) > 0 {
+		if !force {
+			return fmt.Errorf("cannot uninstall %s %s: required by %s. Use --force to remove it anyway",
+				target.Kind.Name, target.Name, strings.Join(dependents, ", "))
+		}
+		fmt.Fprintf(os.Stderr, "%s %s %s is still required by %s\n",
+			yellow("⚠"), target.Kind.Name, target.Name, strings.Join(dependents, ", "))
+	}
+
+	if dryRun {
+		fmt.Printf("  %s %s\n", dim("×"), f.Path)
+		fmt.Printf("\n%s Would remove %s %s.\n", dim("→"), target.Kind.Name, target.Name)
+		return nil
+	}
+
+	path := filepath.Join(scope.RootDir, f.Path)
+	if err := journalTrack(scope, path); err != nil {
+		return err
+	}
+	if strings.HasSuffix(f.Path, "/") {
+		err = os.RemoveAll(path)
+	} else {
+		err = os.Remove(path)
+	}
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("removing %s: %w", f.Path, err)
+	}
+	if err := removeBase(scope, f.Path); err != nil {
+		fmt.Fprintf(os.Stderr, "%s Could not remove merge base for %s: %v\n", yellow("⚠"), f.Path, err)
+	}
+	if err := removeFilesFromState(scope, []string{f.Path}); err != nil {
+		return fmt.Errorf("updating state: %w", err)
+	}
+	if err := dropFromLock(scope, f.Path); err != nil {
+		fmt.Fprintf(os.Stderr, "%s Could not update %s: %v\n", yellow("⚠"), lockFileName, err)
+	}
+	scope.CleanupDirs()
+
+	fmt.Printf("  %s %s\n", red("×"), f.Path)
+	fmt.Printf("\n%s Removed %s %s.\n", green("✓"), target.Kind.Name, target.Name)
+	return nil
+}
+
+// dropFromLock removes path from nav-pilot.lock, if there is one.
+func dropFromLock(scope *InstallScope, path string) error {
+	lock, err := readLock(scope)
+	if err != nil || lock == nil {
+		return err
+	}
+	var kept []LockedArtifact
+	for _, a := range lock.Artifacts {
+		if a.Path != path {
+			kept = append(kept, a)
+		}
+	}
+	if len(kept) == len(lock.Artifacts) {
+		return nil
+	}
+	if len(kept) == 0 {
+		return removeLock(scope)
+	}
+	lock.Artifacts = kept
+	return writeLock(scope, lock)
+}
@@ -0,0 +1,116 @@
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/navikt/copilot/cli/nav-pilot/internal/source"
+)
+
+// depsFixture builds a source where agent nais requires skill api-design,
+// which in turn requires instruction kotlin.
+func depsFixture(t *testing.T) (*InstallScope, *source.Source) {
+	t.Helper()
+	dir := t.TempDir()
+	os.MkdirAll(filepath.Join(dir, ".git"), 0o755)
+	srcDir := t.TempDir()
+
+	os.MkdirAll(filepath.Join(srcDir, "collections", "backend"), 0o755)
+	os.WriteFile(filepath.Join(srcDir, "collections", "backend", "manifest.json"),
+		[]byte(`{"name":"backend","agents":["nais"]}`), 0o644)
+	os.MkdirAll(filepath.Join(srcDir, "agents"), 0o755)
+	os.WriteFile(filepath.Join(srcDir, "agents", "nais.agent.md"),
+		[]byte("---\nname: nais\nrequires:\n  - skill:api-design\n---\n# Nais\n"), 0o644)
+	os.MkdirAll(filepath.Join(srcDir, "skills", "api-design"), 0o755)
+	os.WriteFile(filepath.Join(srcDir, "skills", "api-design", "SKILL.md"),
+		[]byte("---\nrequires: [instruction:kotlin]\n---\n# API\n"), 0o644)
+	os.MkdirAll(filepath.Join(srcDir, "instructions"), 0o755)
+	os.WriteFile(filepath.Join(srcDir, "instructions", "kotlin.instructions.md"), []byte("# Kotlin\n"), 0o644)
+
+	return ScopeRepo(dir), &source.Source{Dir: srcDir, SHA: "abc1234", Version: "dev"}
+}
+
+func assertInstalled(t *testing.T, scope *InstallScope, paths ...string) {
+	t.Helper()
+	for _, p := range paths {
+		if _, err := os.Stat(filepath.Join(scope.RootDir, p)); err != nil {
+			t.Errorf("%s not installed: %v", p, err)
+		}
+	}
+}
+
+func TestInstall_PullsInDependencies(t *testing.T) {
+	scope, src := depsFixture(t)
+	if err := cmdInstallFromSource("backend", src, scope, false, false, false); err != nil {
+		t.Fatalf("install: %v", err)
+	}
+	assertInstalled(t, scope,
+		".github/agents/nais.agent.md",
+		".github/skills/api-design/SKILL.md",
+		".github/instructions/kotlin.instructions.md")
+
+	state, _ := readScopedState(scope)
+	if state == nil || len(state.Files) != 3 {
+		t.Errorf("state files = %+v, want 3", state)
+	}
+}
+
+func TestAdd_PullsInDependencies(t *testing.T) {
+	scope, src := depsFixture(t)
+	if err := cmdAddFromSource("agent", "nais", src, scope, false, false, false); err != nil {
+		t.Fatalf("add: %v", err)
+	}
+	assertInstalled(t, scope,
+		".github/skills/api-design/SKILL.md",
+		".github/instructions/kotlin.instructions.md")
+}
+
+func TestUninstallItem_GuardsDependents(t *testing.T) {
+	scope, src := depsFixture(t)
+	if err := cmdInstallFromSource("backend", src, scope, false, false, false); err != nil {
+		t.Fatalf("install: %v", err)
+	}
+
+	err := cmdUninstallItem(scope, "api-design", "", false, false)
+	if err == nil || !strings.Contains(err.Error(), "required by agent nais") {
+		t.Fatalf("err = %v, want refusal naming agent nais", err)
+	}
+	assertInstalled(t, scope, ".github/skills/api-design/SKILL.md")
+
+	// Removing the dependent first frees the skill.
+	if err := cmdUninstallItem(scope, "nais", "agent", false, false); err != nil {
+		t.Fatalf("uninstall agent: %v", err)
+	}
+	if err := cmdUninstallItem(scope, "api-design", "", false, false); err != nil {
+		t.Fatalf("uninstall skill after agent: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(scope.RootDir, ".github", "skills", "api-design")); !os.IsNotExist(err) {
+		t.Errorf("skill dir should be removed, stat err = %v", err)
+	}
+	state, _ := readScopedState(scope)
+	if state == nil || len(state.Files) != 1 || state.Files[0].Path != ".github/instructions/kotlin.instructions.md" {
+		t.Errorf("state after removals = %+v", state)
+	}
+	lock, _ := readLock(scope)
+	if lock == nil || len(lock.Artifacts) != 1 {
+		t.Errorf("lock after removals = %+v", lock)
+	}
+}
+
+func TestUninstallItem_Force(t *testing.T) {
+	scope, src := depsFixture(t)
+	if err := cmdInstallFromSource("backend", src, scope, false, false, false); err != nil {
+		t.Fatalf("install: %v", err)
+	}
+	if err := cmdUninstallItem(scope, "kotlin", "", false, true); err != nil {
+		t.Fatalf("uninstall --force: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(scope.RootDir, ".github", "instructions", "kotlin.instructions.md")); !os.IsNotExist(err) {
+		t.Errorf("instruction should be removed, stat err = %v", err)
+	}
+	if err := cmdUninstallItem(scope, "kotlin", "", false, false); err == nil {
+		t.Error("expected error for an item that is not installed")
+	}
+}
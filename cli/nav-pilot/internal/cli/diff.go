@@ -0,0 +1,361 @@
+package cli
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// diffFile is one installed file that differs from the resolved source.
+// Status is "modified", "deleted" (gone from source; sync would remove it)
+// or "added" (new file inside a directory artifact).
+type diffFile struct {
+	Path    string     `json:"path"`
+	Status  string     `json:"status"`
+	Source  string     `json:"source,omitempty"`
+	Binary  bool       `json:"binary,omitempty"`
+	Added   int        `json:"added"`
+	Deleted int        `json:"deleted"`
+	Hunks   []DiffHunk `json:"hunks,omitempty"`
+}
+
+// diffScopeResult groups the differing files of one scope.
+type diffScopeResult struct {
+	Scope  string     `json:"scope"`
+	Source string     `json:"source"`
+	Files  []diffFile `json:"files"`
+}
+
+// cmdDiffAuto diffs every scope sync would touch: repo and user scope when
+// they have state, falling back to the auto-detected repo scope.
+func cmdDiffAuto(repoDir string, paths []string, ref, sourceRepo string, jsonOutput, stat bool) error {
+	var scopes []*InstallScope
+	repoScope := ScopeRepo(repoDir)
+	if state, _ := readScopedState(repoScope); state != nil {
+		scopes = append(scopes, repoScope)
+	}
+	if userScope, err := ScopeUser(); err == nil {
+		if state, _ := readScopedState(userScope); state != nil {
+			scopes = append(scopes, userScope)
+		}
+	}
+	if len(scopes) == 0 {
+		scopes = append(scopes, repoScope)
+	}
+	return cmdDiff(scopes, paths, ref, sourceRepo, jsonOutput, stat)
+}
+
+// cmdDiff prints unified diffs between installed files and the resolved
+// source — what `sync --apply` would change. It picks files exactly like
+// sync: ignored and conflicted files and copilot-sync.json overrides are
+// skipped. paths narrows the output to files at or under those paths.
+// Returns errUpdatesAvailable when anything differs, like sync in check mode.
+func cmdDiff(scopes []*InstallScope, paths []string, ref, sourceRepo string, jsonOutput, stat bool) error {
+	for i, p := range paths {
+		paths[i] = strings.TrimSuffix(filepath.ToSlash(filepath.Clean(p)), "/")
+	}
+	matched := make(map[string]bool)
+
+	var results []diffScopeResult
+	for _, scope := range scopes {
+		res, err := diffScope(scope, paths, matched, ref, sourceRepo)
+		if err != nil {
+			return err
+		}
+		results = append(results, *res)
+	}
+
+	var unmatched []string
+	for _, p := range paths {
+		if !matched[p] {
+			unmatched = append(unmatched, p)
+		}
+	}
+	if len(unmatched) > 0 {
+		return fmt.Errorf("no installed file matches %s", strings.Join(unmatched, ", "))
+	}
+
+	changed := false
+	for _, r := range results {
+		if len(r.Files) > 0 {
+			changed = true
+		}
+	}
+
+	if jsonOutput {
+		if stat {
+			for i := range results {
+				for j := range results[i].Files {
+					results[i].Files[j].Hunks = nil
+				}
+			}
+		}
+		if err := outputJSON(map[string]interface{}{"up_to_date": !changed, "scopes": results}); err != nil {
+			return err
+		}
+	} else {
+		for i, r := range results {
+			if len(results) > 1 {
+				if i > 0 {
+					fmt.Println()
+				}
+				fmt.Printf("%s %s scope (source: %s)\n", dim("→"), bold(r.Scope), r.Source)
+			}
+			if len(r.Files) == 0 {
+				fmt.Printf("%s No differences (source: %s)\n", green("✓"), r.Source)
+				continue
+			}
+			if stat {
+				printDiffStat(r.Files)
+			} else {
+				printUnifiedDiff(r.Files)
+			}
+		}
+	}
+
+	if changed {
+		return errUpdatesAvailable
+	}
+	return nil
+}
+
+// diffScope resolves the source for scope and diffs the files sync would check.
+func diffScope(scope *InstallScope, paths []string, matched map[string]bool, ref, sourceRepo string) (*diffScopeResult, error) {
+	if sourceRepo == "" {
+		if state, err := readScopedState(scope); err == nil && state != nil && state.SourceRepo != "" {
+			sourceRepo = state.SourceRepo
+		}
+	}
+	src, err := resolveSourceForSync(ref, sourceRepo)
+	if err != nil {
+		return nil, err
+	}
+	defer src.Cleanup()
+
+	files, _, err := resolveSyncFiles(scope, src.Resolver(), false)
+	if err != nil {
+		return nil, err
+	}
+	cfg, err := readSyncConfig(scope.RootDir)
+	if err != nil {
+		return nil, fmt.Errorf("reading %s: %w", syncConfigPath, err)
+	}
+	overrides := overrideSet(cfg)
+
+	result := &diffScopeResult{Scope: scope.Name, Source: src.SHA, Files: []diffFile{}}
+	for _, sf := range files {
+		if overrides[filepath.ToSlash(filepath.Clean(sf.localPath))] {
+			continue
+		}
+		localFull := filepath.Join(scope.RootDir, sf.localPath)
+		if _, err := os.Stat(localFull); os.IsNotExist(err) {
+			continue // sync treats this as an intentional deletion
+		}
+		sourceFull := sf.sourceFull(src.Dir)
+		_, statErr := os.Stat(sourceFull)
+		gone := os.IsNotExist(statErr)
+
+		if !sf.isDir {
+			if !diffPathSelected(sf.localPath, paths, matched) {
+				continue
+			}
+			if gone {
+				sourceFull = ""
+			}
+			df, err := diffArtifactFile(sf.localPath, localFull, sourceFull, sf.subproject)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", sf.localPath, err)
+			}
+			if df != nil {
+				df.Source = sf.sourceRepo
+				result.Files = append(result.Files, *df)
+			}
+			continue
+		}
+
+		// Directory artifacts (skills) are diffed file by file.
+		dir := strings.TrimSuffix(filepath.ToSlash(sf.localPath), "/")
+		rels := listRelFiles(localFull)
+		if !gone {
+			for rel := range listRelFiles(sourceFull) {
+				rels[rel] = true
+			}
+		}
+		sorted := make([]string, 0, len(rels))
+		for rel := range rels {
+			sorted = append(sorted, rel)
+		}
+		sort.Strings(sorted)
+		for _, rel := range sorted {
+			path := dir + "/" + rel
+			if !diffPathSelected(path, paths, matched) {
+				continue
+			}
+			local := filepath.Join(localFull, filepath.FromSlash(rel))
+			remote := ""
+			if !gone {
+				remote = filepath.Join(sourceFull, filepath.FromSlash(rel))
+			}
+			df, err := diffArtifactFile(path, local, remote, "")
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", path, err)
+			}
+			if df != nil {
+				df.Source = sf.sourceRepo
+				result.Files = append(result.Files, *df)
+			}
+		}
+	}
+	return result, nil
+}
+
+// diffPathSelected reports whether path is selected by the path filters
+// (everything when there are none), recording which filters matched.
+func diffPathSelected(path string, filters []string, matched map[string]bool) bool {
+	if len(filters) == 0 {
+		return true
+	}
+	path = strings.TrimSuffix(filepath.ToSlash(path), "/")
+	selected := false
+	for _, f := range filters {
+		if f == "." || path == f || strings.HasPrefix(path, f+"/") {
+			matched[f] = true
+			selected = true
+		}
+	}
+	return selected
+}
+
+// diffArtifactFile diffs one installed file against its source. A missing
+// file on either side (or an empty sourcePath) is diffed as empty. Markdown
+// that only differs in formatting sync ignores is reported as unchanged.
+// Subproject instructions are diffed against the source as installed.
+func diffArtifactFile(path, localPath, sourcePath, subproject string) (*diffFile, error) {
+	local, localErr := readOptionalFile(localPath)
+	if localErr != nil {
+		return nil, localErr
+	}
+	var remote []byte
+	if sourcePath != "" {
+		var err error
+		if remote, err = readOptionalFile(sourcePath); err != nil {
+			return nil, err
+		}
+		if remote != nil && subproject != "" {
+			if remote, err = scopeApplyTo(remote, subproject); err != nil {
+				return nil, fmt.Errorf("%s: %w", sourcePath, err)
+			}
+		}
+	}
+
+	switch {
+	case local == nil && remote == nil:
+		return nil, nil
+	case (local == nil) == (remote == nil) && bytes.Equal(local, remote):
+		// An empty file on one side only is still added or deleted.
+		return nil, nil
+	case local != nil && remote != nil && strings.HasSuffix(strings.ToLower(path), ".md") &&
+		bytes.Equal(normalizeMarkdown(local), normalizeMarkdown(remote)):
+		return nil, nil
+	}
+
+	df := &diffFile{Path: path, Status: "modified"}
+	switch {
+	case remote == nil:
+		df.Status = "deleted"
+	case local == nil:
+		df.Status = "added"
+	}
+	if isBinaryContent(local) || isBinaryContent(remote) {
+		df.Binary = true
+		return df, nil
+	}
+	df.Hunks = diffLines(local, remote, diffContext)
+	df.Added, df.Deleted = diffStat(df.Hunks)
+	return df, nil
+}
+
+// readOptionalFile reads path, returning nil data (and no error) when absent.
+func readOptionalFile(path string) ([]byte, error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if data == nil {
+		data = []byte{}
+	}
+	return data, nil
+}
+
+// printUnifiedDiff prints colored git-style unified diffs, installed file
+// as a/ and source as b/.
+func printUnifiedDiff(files []diffFile) {
+	for _, f := range files {
+		oldName, newName := "a/"+f.Path, "b/"+f.Path
+		switch f.Status {
+		case "deleted":
+			newName = "/dev/null"
+		case "added":
+			oldName = "/dev/null"
+		}
+		header := "diff " + f.Path
+		if f.Source != "" {
+			header += " " + dim("("+f.Source+")")
+		}
+		fmt.Println(bold(header))
+		if f.Binary {
+			fmt.Printf("Binary files %s and %s differ\n", oldName, newName)
+			continue
+		}
+		fmt.Println(bold("--- " + oldName))
+		fmt.Println(bold("+++ " + newName))
+		for _, h := range f.Hunks {
+			fmt.Println(dim(h.Header()))
+			for _, l := range h.Lines {
+				switch {
+				case strings.HasPrefix(l, "+"):
+					fmt.Println(green(l))
+				case strings.HasPrefix(l, "-"):
+					fmt.Println(red(l))
+				default:
+					fmt.Println(l)
+				}
+			}
+		}
+	}
+}
+
+// diffStatWidth caps the +/- bar in --stat output.
+const diffStatWidth = 40
+
+// printDiffStat prints a git-style diffstat: one line per file and a total.
+func printDiffStat(files []diffFile) {
+	nameWidth, maxChanges := 0, 0
+	for _, f := range files {
+		nameWidth = max(nameWidth, len(f.Path))
+		maxChanges = max(maxChanges, f.Added+f.Deleted)
+	}
+	added, deleted := 0, 0
+	for _, f := range files {
+		added += f.Added
+		deleted += f.Deleted
+		if f.Binary {
+			fmt.Printf(" %-*s | Bin\n", nameWidth, f.Path)
+			continue
+		}
+		plus, minus := f.Added, f.Deleted
+		if maxChanges > diffStatWidth {
+			plus = plus * diffStatWidth / maxChanges
+			minus = minus * diffStatWidth / maxChanges
+		}
+		fmt.Printf(" %-*s | %4d %s%s\n", nameWidth, f.Path, f.Added+f.Deleted,
+			green(strings.Repeat("+", plus)), red(strings.Repeat("-", minus)))
+	}
+	fmt.Printf(" %d file(s) changed, %d insertion(s)(+), %d deletion(s)(-)\n", len(files), added, deleted)
+}
@@ -0,0 +1,132 @@
+package cli
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestDiff_ShowsSourceChanges(t *testing.T) {
+	scope, srcDir := mergeFixture(t, mergeAgentV1)
+	os.WriteFile(filepath.Join(srcDir, "agents", "nais.agent.md"),
+		[]byte(strings.Replace(mergeAgentV1, "- two\n", "- two\n- three\n", 1)), 0o644)
+	os.WriteFile(filepath.Join(srcDir, "skills", "api-design", "examples.md"), []byte("ex\n"), 0o644)
+
+	var err error
+	out := captureStdout(func() {
+		err = cmdDiff([]*InstallScope{scope}, nil, "", "", false, false)
+	})
+	if err != errUpdatesAvailable {
+		t.Fatalf("cmdDiff err = %v, want errUpdatesAvailable", err)
+	}
+	for _, want := range []string{
+		"--- a/.github/agents/nais.agent.md",
+		"+++ b/.github/agents/nais.agent.md",
+		"@@ -5,6 +5,7 @@",
+		"+- three",
+		"--- /dev/null",
+		"+++ b/.github/skills/api-design/examples.md",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("diff output missing %q:\n%s", want, out)
+		}
+	}
+
+	// Nothing was applied.
+	data, _ := os.ReadFile(filepath.Join(scope.RootDir, ".github", "agents", "nais.agent.md"))
+	if string(data) != mergeAgentV1 {
+		t.Error("diff must not modify installed files")
+	}
+}
+
+func TestDiff_PathFilterStatAndJSON(t *testing.T) {
+	scope, srcDir := mergeFixture(t, mergeAgentV1)
+	os.WriteFile(filepath.Join(srcDir, "agents", "nais.agent.md"), []byte("# Nais\n"), 0o644)
+	os.WriteFile(filepath.Join(srcDir, "skills", "api-design", "SKILL.md"), []byte("# API\n\nv2\n"), 0o644)
+
+	var err error
+	out := captureStdout(func() {
+		err = cmdDiff([]*InstallScope{scope}, []string{".github/skills/"}, "", "", false, true)
+	})
+	if err != errUpdatesAvailable {
+		t.Fatalf("cmdDiff --stat err = %v", err)
+	}
+	if !strings.Contains(out, ".github/skills/api-design/SKILL.md |    2") || strings.Contains(out, "nais.agent.md") {
+		t.Errorf("stat output:\n%s", out)
+	}
+	if !strings.Contains(out, "1 file(s) changed, 1 insertion(s)(+), 1 deletion(s)(-)") {
+		t.Errorf("stat summary missing:\n%s", out)
+	}
+
+	out = captureStdout(func() {
+		err = cmdDiff([]*InstallScope{scope}, []string{".github/agents/nais.agent.md"}, "", "", true, false)
+	})
+	var res struct {
+		UpToDate bool              `json:"up_to_date"`
+		Scopes   []diffScopeResult `json:"scopes"`
+	}
+	if jerr := json.Unmarshal([]byte(out), &res); jerr != nil {
+		t.Fatalf("invalid JSON: %v\n%s", jerr, out)
+	}
+	if res.UpToDate || len(res.Scopes) != 1 || len(res.Scopes[0].Files) != 1 {
+		t.Fatalf("json result = %+v", res)
+	}
+	f := res.Scopes[0].Files[0]
+	if f.Path != ".github/agents/nais.agent.md" || f.Status != "modified" || f.Deleted != 9 || len(f.Hunks) != 1 {
+		t.Errorf("json file = %+v", f)
+	}
+
+	if err := cmdDiff([]*InstallScope{scope}, []string{"nope.md"}, "", "", true, false); err == nil || !strings.Contains(err.Error(), "nope.md") {
+		t.Errorf("unmatched path err = %v", err)
+	}
+}
+
+func TestDiff_EmptyFileRemovedFromSource(t *testing.T) {
+	scope, _ := mergeFixture(t, mergeAgentV1)
+	os.WriteFile(filepath.Join(scope.RootDir, ".github", "skills", "api-design", ".gitkeep"), nil, 0o644)
+
+	var err error
+	out := captureStdout(func() {
+		err = cmdDiff([]*InstallScope{scope}, nil, "", "", true, false)
+	})
+	if err != errUpdatesAvailable {
+		t.Fatalf("cmdDiff err = %v, want errUpdatesAvailable\n%s", err, out)
+	}
+	var res struct {
+		Scopes []diffScopeResult `json:"scopes"`
+	}
+	if jerr := json.Unmarshal([]byte(out), &res); jerr != nil {
+		t.Fatalf("invalid JSON: %v\n%s", jerr, out)
+	}
+	if len(res.Scopes) != 1 || len(res.Scopes[0].Files) != 1 {
+		t.Fatalf("json result = %+v", res)
+	}
+	f := res.Scopes[0].Files[0]
+	if f.Path != ".github/skills/api-design/.gitkeep" || f.Status != "deleted" || f.Added != 0 || f.Deleted != 0 {
+		t.Errorf("json file = %+v", f)
+	}
+}
+
+func TestDiff_HonorsOverridesAndIgnored(t *testing.T) {
+	scope, srcDir := mergeFixture(t, mergeAgentV1)
+	os.WriteFile(filepath.Join(srcDir, "agents", "nais.agent.md"), []byte("# Nais v2\n"), 0o644)
+	os.WriteFile(filepath.Join(srcDir, "skills", "api-design", "SKILL.md"), []byte("# API\n\nv2\n"), 0o644)
+
+	os.WriteFile(filepath.Join(scope.RootDir, syncConfigPath), []byte(`{"overrides":[".github/agents/nais.agent.md"]}`), 0o644)
+	if err := markFilesIgnored(scope, []string{".github/skills/api-design/"}); err != nil {
+		t.Fatal(err)
+	}
+
+	var err error
+	out := captureStdout(func() {
+		err = cmdDiff([]*InstallScope{scope}, nil, "", "", false, false)
+	})
+	if err != nil {
+		t.Fatalf("cmdDiff err = %v, want nil (nothing to diff)", err)
+	}
+	if !strings.Contains(out, "No differences") {
+		t.Errorf("output:\n%s", out)
+	}
+}
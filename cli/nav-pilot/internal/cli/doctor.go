@@ -9,202 +9,522 @@ import (
 	"strings"
 	"time"
 
-	"github.com/BurntSushi/toml"
+	"github.com/charmbracelet/huh"
 )
 
+// Doctor severities: how serious a failing check is.
+const (
+	doctorError   = "error"
+	doctorWarning = "warning"
+	doctorInfo    = "info"
+)
+
+// Doctor statuses. Skipped checks did not apply (optional tool missing,
+// nothing installed) and never count as failures.
+const (
+	doctorOK   = "ok"
+	doctorFail = "fail"
+	doctorSkip = "skip"
+)
+
+// doctorCheck is one registered health check.
+type doctorCheck struct {
+	ID       string
+	Section  string
+	Severity string // severity of a failure unless the result overrides it
+	Run      func() doctorResult
+}
+
+// doctorResult is the machine-readable outcome of one check. A failing result
+// may carry a safe fix that `doctor --fix` applies.
+type doctorResult struct {
+	ID       string   `json:"id"`
+	Section  string   `json:"section"`
+	Severity string   `json:"severity"`
+	Status   string   `json:"status"`
+	Message  string   `json:"message"`
+	Details  []string `json:"details,omitempty"`
+	Solution string   `json:"solution,omitempty"`
+	Fix      string   `json:"fix,omitempty"`
+
+	fix func() error
+}
+
+func doctorPass(msg string) doctorResult {
+	return doctorResult{Status: doctorOK, Message: msg}
+}
+
+func doctorFailed(msg, solution string) doctorResult {
+	return doctorResult{Status: doctorFail, Message: msg, Solution: solution}
+}
+
+func doctorSkipped(msg string) doctorResult {
+	return doctorResult{Status: doctorSkip, Message: msg}
+}
+
+// withFix attaches a safe remediation to a failing result.
+func (r doctorResult) withFix(desc string, fn func() error) doctorResult {
+	r.Fix = desc
+	r.fix = fn
+	return r
+}
+
+// doctorChecks is the check registry, in display order.
+var doctorChecks = []doctorCheck{
+	{ID: "config.file", Section: "Configuration", Severity: doctorError, Run: checkConfigFile},
+	{ID: "state.user", Section: "Context Installation", Severity: doctorError, Run: checkUserState},
+	{ID: "state.repo", Section: "Context Installation", Severity: doctorError, Run: checkRepoState},
+	{ID: "cli.version", Section: "nav-pilot CLI", Severity: doctorWarning, Run: checkCLIVersion},
+	{ID: "client.cplt", Section: "Client Agents", Severity: doctorError, Run: checkCpltBinary},
+	{ID: "client.cplt_agent", Section: "Client Agents", Severity: doctorError, Run: checkCpltAgent},
+	{ID: "client.opencode", Section: "Client Agents", Severity: doctorError, Run: checkOpenCode},
+	{ID: "client.opencode_otel", Section: "Client Agents", Severity: doctorWarning, Run: checkOpenCodeOTel},
+	{ID: "client.pi", Section: "Client Agents", Severity: doctorInfo, Run: checkPi},
+	{ID: "client.plugins", Section: "Client Agents", Severity: doctorError, Run: checkProviderPlugins},
+	{ID: "security.cplt_trust", Section: "Project Security (.cplt.toml)", Severity: doctorError, Run: checkCpltTrust},
+	{ID: "deps.git", Section: "Dependencies", Severity: doctorError, Run: checkGit},
+	{ID: "deps.rtk", Section: "Dependencies", Severity: doctorWarning, Run: checkRtk},
+	{ID: "deps.rtk_hooks", Section: "Dependencies", Severity: doctorWarning, Run: checkRtkHooks},
+}
+
+// confirmDoctorFix asks before applying n fixes; without a terminal there is
+// nobody to ask, so --force is required. Overridable in tests.
+var confirmDoctorFix = func(n int) (bool, error) {
+	if !isInteractive() {
+		return false, fmt.Errorf("doctor --fix needs confirmation; re-run with --force to apply without a prompt")
+	}
+	var ok bool
+	err := huh.NewConfirm().
+		Title(fmt.Sprintf("Apply %d fix(es)?", n)).
+		Value(&ok).
+		WithTheme(navTheme()).
+		Run()
+	return err == nil && ok, nil
+}
+
+// runDoctorChecks runs checks in order, filling in registry metadata.
+func runDoctorChecks(checks []doctorCheck) []doctorResult {
+	results := make([]doctorResult, 0, len(checks))
+	for _, c := range checks {
+		r := c.Run()
+		r.ID = c.ID
+		r.Section = c.Section
+		if r.Severity == "" {
+			r.Severity = c.Severity
+		}
+		results = append(results, r)
+	}
+	return results
+}
+
 // cmdDoctor runs system health checks and outputs actionable diagnostics.
-func cmdDoctor() error {
+// With fix, the safe remediations of failing checks are applied after a
+// confirmation prompt (skipped by force).
+func cmdDoctor(jsonOutput, fix, force bool) error {
+	if fix && jsonOutput {
+		return fmt.Errorf("--fix cannot be combined with --json")
+	}
+	results := runDoctorChecks(doctorChecks)
+
+	if jsonOutput {
+		errs, warns := doctorCounts(results)
+		return outputJSON(map[string]interface{}{
+			"checks":   results,
+			"errors":   errs,
+			"warnings": warns,
+			"healthy":  errs == 0 && warns == 0,
+		})
+	}
+
 	fmt.Printf("%s\n\n", bold("nav-pilot doctor"))
-	hasErrors := false
+	printDoctorResults(results)
 
-	// 1. Configuration
-	configPath := filepath.Join(os.Getenv("HOME"), ".nav-pilot", "config.toml")
-	fmt.Printf("[i] Configuration (%s)\n", configPath)
-	data, err := os.ReadFile(configPath)
-	if err != nil {
-		if os.IsNotExist(err) {
-			fmt.Printf("    • File not found (using default values)\n")
-			fmt.Printf("      %s To create configuration, run: %s\n\n", yellow("Solution:"), bold("nav-pilot config init"))
-		} else {
-			hasErrors = true
-			fmt.Printf("    %s Error reading config: %v\n\n", red("[✗]"), err)
-		}
-	} else {
-		var cfg Config
-		_, parseErr := toml.Decode(string(data), &cfg)
-		if parseErr != nil {
-			hasErrors = true
-			fmt.Printf("    %s TOML parse error: %v\n", red("[✗]"), parseErr)
-			fmt.Printf("      %s Fix syntax in %s or run %s\n\n", red("Solution:"), configPath, bold("nav-pilot config validate"))
-		} else {
-			fmt.Printf("    %s Valid syntax and known keys\n\n", green("✓"))
-		}
-	}
-
-	// 2. Context Installation
-	fmt.Printf("[i] Context Installation\n")
-	userScope, userErr := ScopeUser()
-	var userState *StateFile
-	if userErr != nil {
-		hasErrors = true
-		fmt.Printf("    • User scope (~/.copilot): %s Failed to determine user home: %v\n", red("[✗]"), userErr)
-	} else {
-		userState, _ = readScopedState(userScope)
-		if userState != nil {
-			ok, modified, missing, _, _ := countFileIntegrity(userScope.RootDir, userState)
-			if missing > 0 || modified > 0 {
-				hasErrors = true
-				fmt.Printf("    • User scope (~/.copilot): %q collection\n", userState.Collection)
-				fmt.Printf("      %s %d missing files, %d modified\n", red("[✗]"), missing, modified)
-				fmt.Printf("          %s Run %s to restore missing files.\n", red("Solution:"), bold("nav-pilot sync"))
-			} else {
-				fmt.Printf("    • User scope (~/.copilot): %q collection\n", userState.Collection)
-				fmt.Printf("      %s %d files OK\n", green("✓"), ok)
+	fixable := fixableResults(results)
+	if !fix {
+		if errs, warns := doctorCounts(results); errs+warns > 0 {
+			fmt.Printf("%s Health check complete with warnings. See solutions above.\n", yellow("⚠"))
+			if len(fixable) > 0 {
+				fmt.Printf("%s %d issue(s) can be fixed automatically: %s\n", dim("→"), len(fixable), bold("nav-pilot doctor --fix"))
 			}
 		} else {
-			fmt.Printf("    • User scope (~/.copilot): Not installed\n")
+			fmt.Printf("%s All systems healthy! 🚀\n", green("✓"))
 		}
+		return nil
 	}
+	return applyDoctorFixes(fixable, force)
+}
 
-	repoDir, err := os.Getwd()
-	var repoState *StateFile
-	if err != nil {
-		hasErrors = true
-		fmt.Printf("    • Repo scope (.github): %s Failed to determine current directory: %v\n", red("[✗]"), err)
-	} else {
-		repoScope := ScopeRepo(repoDir)
-		repoState, _ = readScopedState(repoScope)
-		if repoState != nil {
-			ok, modified, missing, _, _ := countFileIntegrity(repoScope.RootDir, repoState)
-			if missing > 0 || modified > 0 {
-				hasErrors = true
-				fmt.Printf("    • Repo scope (.github): %q collection\n", repoState.Collection)
-				fmt.Printf("      %s %d missing files, %d modified\n", red("[✗]"), missing, modified)
-				fmt.Printf("          %s Run %s to restore missing files.\n", red("Solution:"), bold("nav-pilot sync"))
-			} else {
-				fmt.Printf("    • Repo scope (.github): %q collection\n", repoState.Collection)
-				fmt.Printf("      %s %d files OK\n", green("✓"), ok)
-			}
-		} else {
-			fmt.Printf("    • Repo scope (.github): Not installed\n")
-		}
+// applyDoctorFixes confirms and runs the fixes, reporting each outcome.
+func applyDoctorFixes(fixable []*doctorResult, force bool) error {
+	if len(fixable) == 0 {
+		fmt.Printf("%s Nothing to fix automatically.\n", green("✓"))
+		return nil
 	}
-	if userState == nil && repoState == nil {
-		fmt.Printf("      %s Run %s to install a collection.\n", yellow("Solution:"), bold("nav-pilot install <collection>"))
+	fmt.Println(bold("Fixes:"))
+	for _, r := range fixable {
+		fmt.Printf("  %s %s %s\n", dim("→"), r.Fix, dim("("+r.ID+")"))
 	}
 	fmt.Println()
-
-	// 3. Client Agents
-	fmt.Printf("[i] Client Agents\n")
-	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
-	defer cancel()
-
-	// copilot (cplt)
-	fmt.Printf("    • copilot (cplt)\n")
-	cpltPath, _ := exec.LookPath("cplt")
-	if cpltPath == "" {
-		hasErrors = true
-		fmt.Printf("      %s Binary not found on PATH\n", red("[✗]"))
-		fmt.Printf("          %s Install cplt via Homebrew: %s\n", red("Solution:"), bold("brew install navikt/tap/cplt"))
-	} else {
-		versionOut, err := exec.CommandContext(ctx, cpltPath, "--version").Output()
-		version := strings.TrimSpace(string(versionOut))
+	if !force {
+		ok, err := confirmDoctorFix(len(fixable))
 		if err != nil {
-			version = "unknown"
+			return err
 		}
-		fmt.Printf("      %s Binary found: %s (%s)\n", green("✓"), cpltPath, version)
+		if !ok {
+			fmt.Println(dim("Cancelled."))
+			return nil
+		}
+	}
 
-		// Check pinning
-		cfgOut, _ := exec.CommandContext(ctx, cpltPath, "config", "show").CombinedOutput()
-		if strings.Contains(string(cfgOut), "nav-pilot") {
-			fmt.Printf("      %s Agent properly pinned to nav-pilot\n", green("✓"))
-		} else {
-			hasErrors = true
-			fmt.Printf("      %s Agent not pinned to nav-pilot\n", red("[✗]"))
-			fmt.Printf("          %s Set agent alias via %s\n", red("Solution:"), bold("cplt config set copilot.agent_name nav-pilot"))
+	failed := 0
+	for _, r := range fixable {
+		if err := r.fix(); err != nil {
+			failed++
+			fmt.Printf("  %s %s: %v\n", red("×"), r.ID, err)
+			continue
 		}
+		fmt.Printf("  %s %s\n", green("✓"), r.Fix)
+	}
+	fmt.Println()
+	if failed > 0 {
+		return fmt.Errorf("%d of %d fix(es) failed", failed, len(fixable))
 	}
+	fmt.Printf("%s Applied %d fix(es). Run %s again to verify.\n", green("✓"), len(fixable), bold("nav-pilot doctor"))
+	return nil
+}
 
-	// opencode
-	fmt.Printf("    • opencode\n")
-	ocPath, _ := exec.LookPath("opencode")
-	if ocPath == "" {
-		fmt.Printf("      [i] Binary not found on PATH (optional)\n")
-	} else {
-		fmt.Printf("      %s Binary found: %s\n", green("✓"), ocPath)
-		// Check opencode context
-		configDir, err := os.UserConfigDir()
-		if err != nil {
-			configDir = filepath.Join(os.Getenv("HOME"), ".config")
-		}
-		ocDir := filepath.Join(configDir, "opencode")
-		ocScope := &InstallScope{Name: "opencode", RootDir: ocDir, StateFile: ".nav-pilot-state.json"}
-		ocState, _ := readScopedState(ocScope)
-		if ocState != nil {
-			ok, _, missing, _, _ := countFileIntegrity(ocDir, ocState)
-			if missing > 0 {
-				hasErrors = true
-				fmt.Printf("      %s Context is missing %d files\n", red("[✗]"), missing)
-				fmt.Printf("          %s Run %s to fix.\n", red("Solution:"), bold("nav-pilot sync"))
-			} else {
-				fmt.Printf("      %s Context securely materialized (%d files OK)\n", green("✓"), ok)
-			}
-		} else {
-			fmt.Printf("      [i] Context not initialized yet\n")
+// fixableResults returns the failing results that carry a fix.
+func fixableResults(results []doctorResult) []*doctorResult {
+	var out []*doctorResult
+	for i := range results {
+		if results[i].Status == doctorFail && results[i].fix != nil {
+			out = append(out, &results[i])
 		}
 	}
+	return out
+}
 
-	// pi
-	fmt.Printf("    • pi\n")
-	piPath, _ := exec.LookPath("pi")
-	if piPath == "" {
-		fmt.Printf("      [i] Binary not found on PATH (optional)\n")
-	} else {
-		fmt.Printf("      %s Binary found: %s\n", green("✓"), piPath)
+// doctorCounts counts failing error- and warning-severity checks.
+func doctorCounts(results []doctorResult) (errs, warns int) {
+	for _, r := range results {
+		if r.Status != doctorFail {
+			continue
+		}
+		switch r.Severity {
+		case doctorError:
+			errs++
+		case doctorWarning:
+			warns++
+		}
 	}
-	fmt.Println()
+	return errs, warns
+}
 
-	// 4. Project Security
-	fmt.Printf("[i] Project Security (.cplt.toml)\n")
-	if cpltPath != "" {
-		cfgOut, _ := exec.CommandContext(ctx, cpltPath, "config", "show").CombinedOutput()
-		if strings.Contains(string(cfgOut), "pending approval") || strings.Contains(string(cfgOut), "pending") {
-			hasErrors = true
-			fmt.Printf("    %s Pending permissions detected!\n", red("[✗]"))
-			fmt.Printf("        %s Run %s in this directory to approve new sandbox rules.\n", red("Solution:"), bold("cplt trust"))
-		} else {
-			if _, err := os.Stat(".cplt.toml"); err == nil {
-				fmt.Printf("    %s .cplt.toml rules are trusted\n", green("✓"))
-			} else {
-				fmt.Printf("    • No .cplt.toml found in current directory\n")
+// printDoctorResults prints results grouped under their section headings.
+func printDoctorResults(results []doctorResult) {
+	section := ""
+	for _, r := range results {
+		if r.Section != section {
+			if section != "" {
+				fmt.Println()
 			}
+			section = r.Section
+			fmt.Printf("[i] %s\n", section)
+		}
+		switch {
+		case r.Status == doctorOK:
+			fmt.Printf("    %s %s\n", green("✓"), r.Message)
+		case r.Status == doctorSkip || r.Severity == doctorInfo:
+			fmt.Printf("    • %s\n", r.Message)
+		case r.Severity == doctorError:
+			fmt.Printf("    %s %s\n", red("[✗]"), r.Message)
+		default:
+			fmt.Printf("    %s %s\n", yellow("⚠"), r.Message)
+		}
+		for _, d := range r.Details {
+			fmt.Printf("      %s\n", d)
+		}
+		if r.Solution != "" {
+			label := yellow("Solution:")
+			if r.Status == doctorFail && r.Severity == doctorError {
+				label = red("Solution:")
+			}
+			fmt.Printf("      %s %s\n", label, r.Solution)
+		}
+		if r.Status == doctorFail && r.Fix != "" {
+			fmt.Printf("      %s %s\n", dim("Fix:"), r.Fix)
 		}
-	} else {
-		fmt.Printf("    • Skipped (cplt not installed)\n")
 	}
 	fmt.Println()
+}
 
-	// 5. Dependencies
-	fmt.Printf("[i] Dependencies\n")
-	checkDep := func(name string) {
-		p, _ := exec.LookPath(name)
-		if p == "" {
-			hasErrors = true
-			fmt.Printf("    %s %s: Not found on PATH\n", red("[✗]"), name)
-			fmt.Printf("        %s Install %s to use nav-pilot fully.\n", red("Solution:"), name)
-		} else {
-			fmt.Printf("    %s %s: OK\n", green("✓"), name)
+// ─── Checks ─────────────────────────────────────────────────────────────────
+
+func checkConfigFile() doctorResult {
+	path := configPath()
+	cfg, meta, err := readConfigWithMeta()
+	if err != nil {
+		return doctorFailed(err.Error(), fmt.Sprintf("Fix syntax in %s or run nav-pilot config validate", path))
+	}
+	if cfg == nil {
+		r := doctorSkipped(fmt.Sprintf("%s not found (using default values)", path))
+		r.Solution = "To create configuration, run: nav-pilot config init"
+		return r
+	}
+	problems := validateConfigProblems(cfg)
+	for _, k := range meta.Undecoded() {
+		problems = append(problems, "unknown key: "+k.String())
+	}
+	if len(problems) > 0 {
+		r := doctorFailed(fmt.Sprintf("%s has %d problem(s)", path, len(problems)), fmt.Sprintf("Fix %s or run nav-pilot config setup", path))
+		r.Details = problems
+		return r
+	}
+	return doctorPass(fmt.Sprintf("%s: valid syntax and known keys", path))
+}
+
+func checkUserState() doctorResult {
+	scope, err := ScopeUser()
+	if err != nil {
+		return doctorFailed(fmt.Sprintf("User scope (~/.copilot): failed to determine user home: %v", err), "")
+	}
+	return checkInstallState("User scope (~/.copilot)", scope)
+}
+
+func checkRepoState() doctorResult {
+	dir, err := os.Getwd()
+	if err != nil {
+		return doctorFailed(fmt.Sprintf("Repo scope (.github): failed to determine current directory: %v", err), "")
+	}
+	if root := findGitRoot(dir); root != "" {
+		dir = root
+	}
+	return checkInstallState("Repo scope (.github)", ScopeRepo(dir))
+}
+
+// checkInstallState checks that a scope's state file parses and that the
+// files it tracks are intact. A corrupt state file is moved aside by --fix.
+func checkInstallState(label string, scope *InstallScope) doctorResult {
+	state, err := readScopedState(scope)
+	if err != nil {
+		path := scope.StatePath()
+		aside := path + ".corrupt"
+		return doctorFailed(fmt.Sprintf("%s: state file is corrupt: %v", label, err),
+			fmt.Sprintf("Reinstall with nav-pilot install after moving %s aside", path)).
+			withFix(fmt.Sprintf("Move corrupt %s to %s", filepath.Base(path), filepath.Base(aside)), func() error {
+				return os.Rename(path, aside)
+			})
+	}
+	if state == nil {
+		r := doctorSkipped(label + ": not installed")
+		r.Solution = "Run nav-pilot install <collection> to install a collection."
+		return r
+	}
+	ok, modified, missing, _, _ := countFileIntegrity(scope.RootDir, state)
+	if missing > 0 || modified > 0 {
+		return doctorFailed(fmt.Sprintf("%s: %q collection, %d missing file(s), %d modified", label, state.Collection, missing, modified),
+			"Run nav-pilot diff to see what changed, or reinstall with nav-pilot install --force.")
+	}
+	return doctorPass(fmt.Sprintf("%s: %q collection, %d files OK", label, state.Collection, ok))
+}
+
+func checkCLIVersion() doctorResult {
+	if Version == "dev" {
+		return doctorSkipped("Development build (version checks disabled)")
+	}
+	if versionPinned() {
+		return doctorPass(fmt.Sprintf("nav-pilot %s (pinned; nav-pilot upgrade unpins)", Version))
+	}
+	a := assessStaleness(Version)
+	if a.LatestVersion == "" {
+		return doctorSkipped(fmt.Sprintf("nav-pilot %s (could not check for updates)", Version))
+	}
+	if versionNewer(a.LatestVersion, Version) {
+		return doctorFailed(fmt.Sprintf("nav-pilot %s is available (current: %s, %d days behind)", a.LatestVersion, Version, a.SkewDays),
+			"Run nav-pilot upgrade").
+			withFix("Upgrade nav-pilot to "+a.LatestVersion, func() error {
+				_, err := doUpdate(false)
+				return err
+			})
+	}
+	return doctorPass(fmt.Sprintf("nav-pilot %s is up to date", Version))
+}
+
+func checkCpltBinary() doctorResult {
+	path, _ := exec.LookPath("cplt")
+	if path == "" {
+		r := doctorFailed("copilot (cplt): binary not found on PATH", "Install cplt via Homebrew: brew install navikt/tap/cplt")
+		if _, err := exec.LookPath("brew"); err == nil {
+			r = r.withFix("Install cplt with brew install navikt/tap/cplt", func() error {
+				return runDoctorCommand("brew", "install", "navikt/tap/cplt")
+			})
 		}
+		return r
 	}
-	checkDep("rtk")
-	checkDep("git")
-	fmt.Println()
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	out, err := exec.CommandContext(ctx, path, "--version").Output()
+	version := strings.TrimSpace(string(out))
+	if err != nil {
+		version = "unknown"
+	}
+	return doctorPass(fmt.Sprintf("copilot (cplt): %s (%s)", path, version))
+}
+
+func checkCpltAgent() doctorResult {
+	path, _ := exec.LookPath("cplt")
+	if path == "" {
+		return doctorSkipped("copilot agent pinning: skipped (cplt not installed)")
+	}
+	if strings.Contains(cpltConfigShow(path), "nav-pilot") {
+		return doctorPass("copilot agent pinned to nav-pilot")
+	}
+	return doctorFailed("copilot agent not pinned to nav-pilot", "Set agent alias via cplt config set copilot.agent_name nav-pilot").
+		withFix("Pin the copilot agent to nav-pilot", func() error {
+			return runDoctorCommand(path, "config", "set", "copilot.agent_name", "nav-pilot")
+		})
+}
 
-	if hasErrors {
-		fmt.Printf("%s Health check complete with warnings. See solutions above.\n", yellow("⚠"))
-	} else {
-		fmt.Printf("%s All systems healthy! 🚀\n", green("✓"))
+func checkOpenCode() doctorResult {
+	path, _ := exec.LookPath("opencode")
+	if path == "" {
+		return doctorSkipped("opencode: binary not found on PATH (optional)")
+	}
+	configDir, err := os.UserConfigDir()
+	if err != nil {
+		configDir = filepath.Join(os.Getenv("HOME"), ".config")
+	}
+	ocDir := filepath.Join(configDir, "opencode")
+	ocScope := &InstallScope{Name: "opencode", RootDir: ocDir, StateFile: ".nav-pilot-state.json"}
+	state, _ := readScopedState(ocScope)
+	if state == nil {
+		return doctorSkipped(fmt.Sprintf("opencode: %s (context not initialized yet)", path))
 	}
+	ok, _, missing, _, _ := countFileIntegrity(ocDir, state)
+	if missing > 0 {
+		return doctorFailed(fmt.Sprintf("opencode: context is missing %d file(s)", missing), "Run nav-pilot sync to fix.")
+	}
+	return doctorPass(fmt.Sprintf("opencode: %s, context materialized (%d files OK)", path, ok))
+}
 
-	return nil
+func checkOpenCodeOTel() doctorResult {
+	if _, err := exec.LookPath("opencode"); err != nil {
+		return doctorSkipped("opencode OTel: skipped (opencode not installed)")
+	}
+	on, err := openCodeOTelEnabled()
+	if err != nil {
+		return doctorFailed("opencode OTel: "+err.Error(), "Fix the JSON syntax in the opencode config")
+	}
+	if !on {
+		return doctorFailed("opencode OTel: experimental.openTelemetry is not enabled in opencode.json",
+			"Launch opencode via nav-pilot once, or enable it manually").
+			withFix("Enable experimental.openTelemetry in opencode.json", ensureOpenCodeOTelConfig)
+	}
+	return doctorPass("opencode OTel: enabled")
+}
+
+func checkPi() doctorResult {
+	path, _ := exec.LookPath("pi")
+	if path == "" {
+		return doctorSkipped("pi: binary not found on PATH (optional)")
+	}
+	return doctorPass("pi: " + path)
+}
+
+// checkProviderPlugins checks every nav-pilot-provider-<id> plugin on PATH.
+func checkProviderPlugins() doctorResult {
+	var details []string
+	failed := 0
+	for _, p := range allProviders() {
+		path := pluginPath(p)
+		if path == "" {
+			continue
+		}
+		switch err := pluginError(p); {
+		case err != nil:
+			failed++
+			details = append(details, fmt.Sprintf("× %s: %v", p.ID(), err))
+		case p.Available():
+			details = append(details, fmt.Sprintf("✓ %s: %s (%d known models)", p.ID(), path, len(p.KnownModels())))
+		default:
+			details = append(details, fmt.Sprintf("• %s: %s reports its client is not available", p.ID(), p.DisplayName()))
+		}
+	}
+	if len(details) == 0 {
+		return doctorSkipped("Provider plugins: none found on PATH")
+	}
+	if failed > 0 {
+		r := doctorFailed(fmt.Sprintf("Provider plugins: %d of %d failing", failed, len(details)),
+			"Check that each plugin answers info with valid JSON.")
+		r.Details = details
+		return r
+	}
+	r := doctorPass(fmt.Sprintf("Provider plugins: %d found", len(details)))
+	r.Details = details
+	return r
+}
+
+func checkCpltTrust() doctorResult {
+	path, _ := exec.LookPath("cplt")
+	if path == "" {
+		return doctorSkipped("Skipped (cplt not installed)")
+	}
+	if strings.Contains(cpltConfigShow(path), "pending") {
+		return doctorFailed("Pending permissions detected", "Run cplt trust in this directory to approve new sandbox rules.")
+	}
+	if _, err := os.Stat(".cplt.toml"); err == nil {
+		return doctorPass(".cplt.toml rules are trusted")
+	}
+	return doctorSkipped("No .cplt.toml found in current directory")
+}
+
+func checkGit() doctorResult {
+	if _, err := exec.LookPath("git"); err != nil {
+		return doctorFailed("git: not found on PATH", "Install git to use nav-pilot fully.")
+	}
+	return doctorPass("git: OK")
+}
+
+func checkRtk() doctorResult {
+	if !isRtkInstalled() {
+		return doctorFailed("rtk: not found on PATH", "Launch nav-pilot interactively to set it up, or install rtk manually.")
+	}
+	return doctorPass("rtk: OK")
+}
+
+// checkRtkHooks verifies rtk is wired into the configured client. Only the
+// opencode plugin can be verified from disk; other clients are skipped.
+func checkRtkHooks() doctorResult {
+	rtkPath, err := exec.LookPath("rtk")
+	if err != nil {
+		return doctorSkipped("rtk hooks: skipped (rtk not installed)")
+	}
+	file, _ := readConfig()
+	client := resolve(file, CLIOverrides{}).Client
+	if client != "opencode" {
+		return doctorSkipped(fmt.Sprintf("rtk hooks: cannot be verified for %s", client))
+	}
+	if _, err := os.Stat(filepath.Join(getOpenCodeConfigDir(), "plugins", "rtk.ts")); err == nil {
+		return doctorPass("rtk hooks: initialized for opencode")
+	}
+	return doctorFailed("rtk hooks: not initialized for opencode", "Run rtk init --global --opencode").
+		withFix("Initialize rtk hooks for opencode", func() error {
+			return initRtkHooks(client, rtkPath)
+		})
+}
+
+// cpltConfigShow returns the output of `cplt config show`, or "" on timeout.
+func cpltConfigShow(path string) string {
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	out, _ := exec.CommandContext(ctx, path, "config", "show").CombinedOutput()
+	return string(out)
+}
+
+// runDoctorCommand runs a fix command with its output shown to the user.
+func runDoctorCommand(name string, args ...string) error {
+	cmd := exec.Command(name, args...)
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	return cmd.Run()
 }
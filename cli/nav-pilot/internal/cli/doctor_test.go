@@ -0,0 +1,165 @@
+package cli
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// withDoctorChecks swaps the check registry for the duration of a test.
+func withDoctorChecks(t *testing.T, checks []doctorCheck) {
+	t.Helper()
+	orig := doctorChecks
+	t.Cleanup(func() { doctorChecks = orig })
+	doctorChecks = checks
+}
+
+func TestDoctor_JSONOutput(t *testing.T) {
+	withDoctorChecks(t, []doctorCheck{
+		{ID: "a.ok", Section: "A", Severity: doctorError, Run: func() doctorResult { return doctorPass("fine") }},
+		{ID: "a.warn", Section: "A", Severity: doctorWarning, Run: func() doctorResult {
+			return doctorFailed("stale", "upgrade").withFix("Upgrade", func() error { return nil })
+		}},
+		{ID: "b.skip", Section: "B", Severity: doctorError, Run: func() doctorResult { return doctorSkipped("n/a") }},
+	})
+
+	out := captureStdout(func() {
+		if err := cmdDoctor(true, false, false); err != nil {
+			t.Fatal(err)
+		}
+	})
+	var got struct {
+		Checks   []doctorResult `json:"checks"`
+		Errors   int            `json:"errors"`
+		Warnings int            `json:"warnings"`
+		Healthy  bool           `json:"healthy"`
+	}
+	if err := json.Unmarshal([]byte(out), &got); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, out)
+	}
+	if len(got.Checks) != 3 || got.Errors != 0 || got.Warnings != 1 || got.Healthy {
+		t.Fatalf("got %+v", got)
+	}
+	warn := got.Checks[1]
+	if warn.ID != "a.warn" || warn.Section != "A" || warn.Severity != doctorWarning || warn.Status != doctorFail || warn.Fix != "Upgrade" {
+		t.Errorf("warn = %+v", warn)
+	}
+	if got.Checks[2].Status != doctorSkip {
+		t.Errorf("skip = %+v", got.Checks[2])
+	}
+
+	if err := cmdDoctor(true, true, true); err == nil {
+		t.Error("--fix with --json should be rejected")
+	}
+}
+
+func TestDoctor_FixAppliesOnlyFailingFixes(t *testing.T) {
+	var applied []string
+	fixFn := func(id string, err error) func() error {
+		return func() error {
+			applied = append(applied, id)
+			return err
+		}
+	}
+	withDoctorChecks(t, []doctorCheck{
+		{ID: "ok", Section: "A", Severity: doctorError, Run: func() doctorResult {
+			return doctorPass("fine").withFix("never", fixFn("ok", nil))
+		}},
+		{ID: "fixable", Section: "A", Severity: doctorError, Run: func() doctorResult {
+			return doctorFailed("broken", "").withFix("Repair", fixFn("fixable", nil))
+		}},
+		{ID: "manual", Section: "A", Severity: doctorError, Run: func() doctorResult { return doctorFailed("broken", "by hand") }},
+	})
+
+	forceNonInteractive = true
+	defer func() { forceNonInteractive = false }()
+
+	// Non-interactive without --force must not apply anything.
+	captureStdout(func() {
+		if err := cmdDoctor(false, true, false); err == nil || !strings.Contains(err.Error(), "--force") {
+			t.Errorf("err = %v, want a --force hint", err)
+		}
+	})
+	if len(applied) != 0 {
+		t.Fatalf("applied without confirmation: %v", applied)
+	}
+
+	out := captureStdout(func() {
+		if err := cmdDoctor(false, true, true); err != nil {
+			t.Fatal(err)
+		}
+	})
+	if strings.Join(applied, ",") != "fixable" {
+		t.Errorf("applied = %v, want only the failing fixable check", applied)
+	}
+	if !strings.Contains(out, "Applied 1 fix(es)") {
+		t.Errorf("output = %s", out)
+	}
+}
+
+func TestDoctor_FixConfirmationAndFailure(t *testing.T) {
+	withDoctorChecks(t, []doctorCheck{
+		{ID: "x", Section: "A", Severity: doctorWarning, Run: func() doctorResult {
+			return doctorFailed("broken", "").withFix("Repair", func() error { return errors.New("boom") })
+		}},
+	})
+	orig := confirmDoctorFix
+	t.Cleanup(func() { confirmDoctorFix = orig })
+
+	confirmDoctorFix = func(int) (bool, error) { return false, nil }
+	out := captureStdout(func() {
+		if err := cmdDoctor(false, true, false); err != nil {
+			t.Fatal(err)
+		}
+	})
+	if !strings.Contains(out, "Cancelled.") {
+		t.Errorf("output = %s", out)
+	}
+
+	confirmDoctorFix = func(int) (bool, error) { return true, nil }
+	captureStdout(func() {
+		if err := cmdDoctor(false, true, false); err == nil || !strings.Contains(err.Error(), "1 of 1 fix(es) failed") {
+			t.Errorf("err = %v", err)
+		}
+	})
+}
+
+func TestCheckInstallState_CorruptStateIsMovedAside(t *testing.T) {
+	dir := t.TempDir()
+	scope := ScopeRepo(dir)
+	if err := os.MkdirAll(filepath.Dir(scope.StatePath()), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(scope.StatePath(), []byte("{not json"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	r := checkInstallState("Repo scope", scope)
+	if r.Status != doctorFail || r.fix == nil {
+		t.Fatalf("result = %+v, want a fixable failure", r)
+	}
+	if err := r.fix(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(scope.StatePath() + ".corrupt"); err != nil {
+		t.Errorf("corrupt state not preserved: %v", err)
+	}
+	if r := checkInstallState("Repo scope", scope); r.Status != doctorSkip {
+		t.Errorf("after fix = %+v, want not installed", r)
+	}
+}
+
+func TestCheckInstallState_ModifiedFilesHaveNoFix(t *testing.T) {
+	dir := t.TempDir()
+	writeState(dir, &StateFile{
+		Collection: "kotlin-backend",
+		Files:      []InstalledFile{{Path: ".github/agents/gone.agent.md", Hash: "x"}},
+	})
+	r := checkInstallState("Repo scope", ScopeRepo(dir))
+	if r.Status != doctorFail || r.fix != nil || !strings.Contains(r.Message, "1 missing file(s)") {
+		t.Errorf("result = %+v", r)
+	}
+}
@@ -0,0 +1,125 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+)
+
+// exportTarget is one export format previously written into one scope.
+type exportTarget struct {
+	format *ExportFormat
+	scope  *InstallScope
+	layout ExportLayout
+	state  *StateFile
+}
+
+// exportedTargets returns the formats with a state file in any of scopes.
+func exportedTargets(scopes ...*InstallScope) []exportTarget {
+	var targets []exportTarget
+	for _, scope := range scopes {
+		if scope == nil {
+			continue
+		}
+		for _, f := range exportFormats() {
+			layout, err := f.Layout(scope)
+			if err != nil {
+				continue
+			}
+			state, err := readExportState(f, layout)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "%s Ignoring %s export state: %v\n", yellow("⚠"), f.Name, err)
+				continue
+			}
+			if state != nil {
+				targets = append(targets, exportTarget{format: f, scope: scope, layout: layout, state: state})
+			}
+		}
+	}
+	return targets
+}
+
+// syncExportTargets re-renders each exported format from the source it was
+// exported from, across all its layers. Without apply it only reports what
+// would change. With jsonOutput each target is reported as its own object,
+// like the scopes before it.
+func syncExportTargets(targets []exportTarget, ref, sourceRepo string, apply, jsonOutput, hasPrevOutput bool) error {
+	var firstErr error
+	for _, t := range targets {
+		label := fmt.Sprintf("%s export (%s)", t.format.Label, t.scope.Name)
+		if !jsonOutput {
+			if hasPrevOutput {
+				fmt.Println()
+			}
+			fmt.Printf("%s Syncing %s...\n", dim("→"), bold(label))
+		}
+		hasPrevOutput = true
+
+		sRepo := sourceRepo
+		if sRepo == "" {
+			sRepo = t.state.SourceRepo
+		}
+		report := map[string]interface{}{
+			"export":     t.format.Name,
+			"scope":      t.scope.Name,
+			"output_dir": t.layout.Root,
+			"dry_run":    !apply,
+		}
+		src, err := resolveSourceForSync(ref, sRepo)
+		if err != nil {
+			if jsonOutput {
+				report["error"] = "could not resolve source: " + err.Error()
+				outputJSON(report)
+			} else {
+				fmt.Fprintf(os.Stderr, "%s %s sync failed: could not resolve source: %v\n", yellow("⚠"), label, err)
+			}
+			if firstErr == nil {
+				firstErr = errSyncFailed
+			}
+			continue
+		}
+		result, err := syncExport(t.format, t.layout, src.Resolver(), src.Version, src.SHA, src.Repo, !apply, false)
+		src.Cleanup()
+		if err != nil {
+			if jsonOutput {
+				report["error"] = err.Error()
+				outputJSON(report)
+			} else {
+				fmt.Fprintf(os.Stderr, "%s %s sync error: %v\n", yellow("⚠"), label, err)
+			}
+			if firstErr == nil {
+				firstErr = errSyncFailed
+			}
+			continue
+		}
+
+		pending := len(result.Written) + len(result.Removed)
+		if jsonOutput {
+			report["written"] = result.Written
+			report["unchanged"] = result.Unchanged
+			report["conflicts"] = result.Conflicts
+			report["removed"] = result.Removed
+			report["up_to_date"] = pending == 0
+			if err := outputJSON(report); err != nil {
+				return err
+			}
+		} else {
+			printExportResult(result, !apply)
+		}
+		switch {
+		case !apply && pending > 0:
+			if !jsonOutput {
+				fmt.Printf("%s %s has %d update(s) available. Run with %s to apply.\n", yellow("⚠"), label, pending, bold("--apply"))
+			}
+			if firstErr == nil {
+				firstErr = errUpdatesAvailable
+			}
+		case len(result.Conflicts) > 0:
+			if !jsonOutput {
+				fmt.Printf("%s %s synced (%d conflict(s)).\n", yellow("⚠"), label, len(result.Conflicts))
+			}
+		case !jsonOutput:
+			fmt.Printf("%s %s synced.\n", green("✓"), label)
+		}
+	}
+	return firstErr
+}
@@ -0,0 +1,119 @@
+package cli
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/navikt/copilot/cli/nav-pilot/internal/source"
+)
+
+func TestCmdSyncAuto_SyncsExportedFormats(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	repoDir := t.TempDir()
+	os.MkdirAll(filepath.Join(repoDir, ".git"), 0o755)
+	srcDir := t.TempDir()
+	os.MkdirAll(filepath.Join(srcDir, "instructions"), 0o755)
+	instr := filepath.Join(srcDir, "instructions", "kotlin.instructions.md")
+	os.WriteFile(instr, []byte("---\napplyTo: \"**/*.kt\"\n---\n\nv1\n"), 0o644)
+
+	orig := resolveSourceForSync
+	t.Cleanup(func() { resolveSourceForSync = orig })
+	resolveSourceForSync = func(ref, sourceRepo string) (*source.Source, error) {
+		return &source.Source{Dir: srcDir, SHA: "v2", Version: "dev"}, nil
+	}
+
+	// Export once; no collection is installed, but sync still picks it up.
+	f := artifactsFormat(t, "cursor")
+	layout, _ := f.Layout(ScopeRepo(repoDir))
+	if _, err := syncExport(f, layout, source.NewSourceResolver(srcDir), "dev", "v1", "navikt/copilot", false, false); err != nil {
+		t.Fatalf("export: %v", err)
+	}
+	rule := filepath.Join(repoDir, ".cursor", "rules", "kotlin.mdc")
+
+	os.WriteFile(instr, []byte("---\napplyTo: \"**/*.kt\"\n---\n\nv2\n"), 0o644)
+	var err error
+	out := captureStdout(func() { err = cmdSyncAuto(repoDir, "", "", lockFollow, false, false) })
+	if err != errUpdatesAvailable {
+		t.Fatalf("check err = %v, want errUpdatesAvailable", err)
+	}
+	if !strings.Contains(out, "Cursor export (repo)") || !strings.Contains(out, ".cursor/rules/kotlin.mdc") {
+		t.Errorf("check output:\n%s", out)
+	}
+	if data, _ := os.ReadFile(rule); strings.Contains(string(data), "v2") {
+		t.Error("check mode must not write")
+	}
+
+	captureStdout(func() { err = cmdSyncAuto(repoDir, "", "", lockFollow, true, false) })
+	if err != nil {
+		t.Fatalf("apply: %v", err)
+	}
+	if data, _ := os.ReadFile(rule); !strings.Contains(string(data), "v2") {
+		t.Errorf("rule not updated:\n%s", data)
+	}
+}
+
+func TestCmdSyncAuto_ExportsUseOverlaysAndReportJSON(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	repoDir := t.TempDir()
+	os.MkdirAll(filepath.Join(repoDir, ".git"), 0o755)
+	baseDir := t.TempDir()
+	overlayDir := t.TempDir()
+	os.MkdirAll(filepath.Join(baseDir, "instructions"), 0o755)
+	os.WriteFile(filepath.Join(baseDir, "instructions", "kotlin.instructions.md"), []byte("---\napplyTo: \"**/*.kt\"\n---\n\nbase\n"), 0o644)
+	os.MkdirAll(filepath.Join(overlayDir, "instructions"), 0o755)
+	os.WriteFile(filepath.Join(overlayDir, "instructions", "kotlin.instructions.md"), []byte("---\napplyTo: \"**/*.kt\"\n---\n\nteam\n"), 0o644)
+	os.WriteFile(filepath.Join(overlayDir, "instructions", "team.instructions.md"), []byte("---\napplyTo: \"**/*.sql\"\n---\n\nteam only\n"), 0o644)
+
+	layered := func() *source.Source {
+		return &source.Source{Dir: baseDir, SHA: "v2", Version: "dev", Repo: "navikt/copilot,team/copilot",
+			Overlays: []*source.Source{{Dir: overlayDir, SHA: "t1", Repo: "team/copilot"}}}
+	}
+	orig := resolveSourceForSync
+	t.Cleanup(func() { resolveSourceForSync = orig })
+	resolveSourceForSync = func(ref, sourceRepo string) (*source.Source, error) { return layered(), nil }
+
+	f := artifactsFormat(t, "cursor")
+	layout, _ := f.Layout(ScopeRepo(repoDir))
+	if _, err := syncExport(f, layout, source.NewSourceResolver(baseDir), "dev", "v1", "navikt/copilot", false, false); err != nil {
+		t.Fatalf("export: %v", err)
+	}
+
+	var err error
+	out := captureStdout(func() { err = cmdSyncAuto(repoDir, "", "", lockFollow, false, true) })
+	if err != errUpdatesAvailable {
+		t.Fatalf("check err = %v, want errUpdatesAvailable", err)
+	}
+	var report struct {
+		Export  string   `json:"export"`
+		Written []string `json:"written"`
+		DryRun  bool     `json:"dry_run"`
+	}
+	if err := json.Unmarshal([]byte(out), &report); err != nil {
+		t.Fatalf("sync --json output is not the export report: %v\n%s", err, out)
+	}
+	if report.Export != "cursor" || !report.DryRun || strings.Join(report.Written, ",") != ".cursor/rules/kotlin.mdc,.cursor/rules/team.mdc" {
+		t.Errorf("report = %+v", report)
+	}
+
+	captureStdout(func() { err = cmdSyncAuto(repoDir, "", "", lockFollow, true, false) })
+	if err != nil {
+		t.Fatalf("apply: %v", err)
+	}
+	if data, _ := os.ReadFile(filepath.Join(repoDir, ".cursor", "rules", "kotlin.mdc")); !strings.Contains(string(data), "team") {
+		t.Errorf("overlay instruction not exported:\n%s", data)
+	}
+}
+
+func artifactsFormat(t *testing.T, name string) *ExportFormat {
+	t.Helper()
+	for _, f := range exportFormats() {
+		if f.Name == name {
+			return f
+		}
+	}
+	t.Fatalf("no export format %q", name)
+	return nil
+}
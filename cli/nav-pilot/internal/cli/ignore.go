@@ -16,7 +16,13 @@ type ignoreResult struct {
 
 // cmdIgnore marks a named item as ignored in the state file so it no longer
 // appears in new-item reminders. Only meaningful for user-scope (all) installs.
-func cmdIgnore(itemType, name string, scope *InstallScope, jsonOutput bool) error {
+func cmdIgnore(itemType, name string, scope *InstallScope, jsonOutput bool) (err error) {
+	tx, err := beginJournal(scope, "ignore")
+	if err != nil {
+		return err
+	}
+	defer tx.finish(&err)
+
 	kind, ok := kindByName[itemType]
 	if !ok || kind == KindPrompt {
 		return fmt.Errorf("unknown type %q. Valid types: agent, skill, instruction", itemType)
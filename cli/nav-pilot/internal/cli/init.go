@@ -14,14 +14,25 @@ import (
 type DetectedStack struct {
 	Go      bool
 	Node    bool
+	Next    bool // Next.js app (implies Node)
 	Kotlin  bool
 	Nais    bool
 	RepoDir string
 	Name    string // basename of repo dir
+	Path    string // subprojects: slash-separated path from the repo root
+
+	// Custom lists the stacks declared by the source's init templates that
+	// were found in the directory.
+	Custom []InitStack
+
+	// Subprojects of a monorepo whose stacks differ; each gets its own
+	// path-scoped instructions file.
+	Subprojects []DetectedStack
 }
 
-// detectStack checks for stack indicators in the target directory.
-func detectStack(dir string) DetectedStack {
+// detectStack checks for stack indicators in the target directory, including
+// the custom stacks declared by the source's init templates.
+func detectStack(dir string, custom []InitStack) DetectedStack {
 	ds := DetectedStack{
 		RepoDir: dir,
 		Name:    filepath.Base(dir),
@@ -29,8 +40,15 @@ func detectStack(dir string) DetectedStack {
 	if _, err := os.Stat(filepath.Join(dir, "go.mod")); err == nil {
 		ds.Go = true
 	}
-	if _, err := os.Stat(filepath.Join(dir, "package.json")); err == nil {
+	if data, err := os.ReadFile(filepath.Join(dir, "package.json")); err == nil {
 		ds.Node = true
+		ds.Next = strings.Contains(string(data), `"next":`)
+	}
+	for _, f := range []string{"next.config.js", "next.config.mjs", "next.config.ts"} {
+		if _, err := os.Stat(filepath.Join(dir, f)); err == nil {
+			ds.Node, ds.Next = true, true
+			break
+		}
 	}
 	for _, f := range []string{"build.gradle.kts", "build.gradle", "pom.xml"} {
 		if _, err := os.Stat(filepath.Join(dir, f)); err == nil {
@@ -41,6 +59,11 @@ func detectStack(dir string) DetectedStack {
 	if _, err := os.Stat(filepath.Join(dir, ".nais")); err == nil {
 		ds.Nais = true
 	}
+	for _, s := range custom {
+		if s.Detect(dir) {
+			ds.Custom = append(ds.Custom, s)
+		}
+	}
 	return ds
 }
 
@@ -53,12 +76,69 @@ func (ds DetectedStack) Languages() []string {
 	if ds.Node {
 		langs = append(langs, "Node.js/TypeScript")
 	}
+	if ds.Next {
+		langs = append(langs, "Next.js")
+	}
 	if ds.Kotlin {
 		langs = append(langs, "Kotlin")
 	}
+	for _, s := range ds.Custom {
+		langs = append(langs, s.Label)
+	}
 	return langs
 }
 
+// hasBuildFile reports whether dir has a build file detectStack recognizes.
+func hasBuildFile(dir string, custom []InitStack) bool {
+	for _, f := range []string{"go.mod", "package.json", "build.gradle.kts", "build.gradle", "pom.xml"} {
+		if _, err := os.Stat(filepath.Join(dir, f)); err == nil {
+			return true
+		}
+	}
+	for _, s := range custom {
+		if s.Detect(dir) {
+			return true
+		}
+	}
+	return false
+}
+
+// detectSubprojects runs detectStack for each directory up to two levels
+// below root that has its own build file (backend/, apps/web/), without
+// descending into one it found. It returns them only when their stacks
+// differ: a multi-module build in a single language needs no per-directory
+// guidance.
+func detectSubprojects(root string, custom []InitStack) []DetectedStack {
+	var found []DetectedStack
+	var walk func(dir, rel string, depth int)
+	walk = func(dir, rel string, depth int) {
+		for _, d := range keyDirectories(dir) {
+			name := strings.TrimSuffix(d, "/")
+			sub, subRel := filepath.Join(dir, name), name
+			if rel != "" {
+				subRel = rel + "/" + name
+			}
+			if hasBuildFile(sub, custom) {
+				ds := detectStack(sub, custom)
+				ds.Path = subRel
+				found = append(found, ds)
+			} else if depth < 2 {
+				walk(sub, subRel, depth+1)
+			}
+		}
+	}
+	walk(root, "", 1)
+
+	stacks := map[string]bool{}
+	for _, ds := range found {
+		stacks[strings.Join(ds.Languages(), "+")] = true
+	}
+	if len(stacks) < 2 {
+		return nil
+	}
+	return found
+}
+
 // StackLabel returns a short summary like "Go + Node.js/TypeScript on Nais".
 func (ds DetectedStack) StackLabel() string {
 	langs := ds.Languages()
@@ -103,6 +183,8 @@ func keyDirectories(dir string) []string {
 
 // ─── Templates ──────────────────────────────────────────────────────────────
 
+// The built-in templates, used when the source has no templates/init.
+
 func templateAgentsMD(ds DetectedStack) string {
 	var b strings.Builder
 
@@ -112,23 +194,7 @@ func templateAgentsMD(ds DetectedStack) string {
 	// Build & Test Commands
 	b.WriteString("## Build & Test Commands\n\n")
 	b.WriteString("```bash\n")
-	if ds.Go {
-		b.WriteString("go test ./...       # Run tests\n")
-		b.WriteString("go build ./...      # Build\n")
-		b.WriteString("go vet ./...        # Lint\n")
-	}
-	if ds.Kotlin {
-		b.WriteString("./gradlew test      # Run tests\n")
-		b.WriteString("./gradlew build     # Build\n")
-	}
-	if ds.Node {
-		b.WriteString("pnpm test           # Run tests\n")
-		b.WriteString("pnpm build          # Build\n")
-		b.WriteString("pnpm lint           # Lint\n")
-	}
-	if !ds.Go && !ds.Kotlin && !ds.Node {
-		b.WriteString("# TODO: Add build and test commands\n")
-	}
+	writeBuildCommands(&b, ds)
 	b.WriteString("```\n\n")
 
 	// Project Structure
@@ -144,6 +210,15 @@ func templateAgentsMD(ds DetectedStack) string {
 	}
 	b.WriteString("```\n\n")
 
+	if len(ds.Subprojects) > 0 {
+		b.WriteString("## Subprojects\n\n")
+		b.WriteString("Each subproject has path-scoped instructions in `.github/instructions/<path>/`.\n\n")
+		for _, sub := range ds.Subprojects {
+			b.WriteString("- `" + sub.Path + "/` — " + sub.StackLabel() + "\n")
+		}
+		b.WriteString("\n")
+	}
+
 	// Code Style
 	b.WriteString("## Code Style\n\n")
 	b.WriteString("### Minimal Editing\n\n")
@@ -173,6 +248,28 @@ func templateAgentsMD(ds DetectedStack) string {
 	return b.String()
 }
 
+// writeBuildCommands writes the build, test and lint commands for ds, one
+// per line, for a ```bash block.
+func writeBuildCommands(b *strings.Builder, ds DetectedStack) {
+	if ds.Go {
+		b.WriteString("go test ./...       # Run tests\n")
+		b.WriteString("go build ./...      # Build\n")
+		b.WriteString("go vet ./...        # Lint\n")
+	}
+	if ds.Kotlin {
+		b.WriteString("./gradlew test      # Run tests\n")
+		b.WriteString("./gradlew build     # Build\n")
+	}
+	if ds.Node {
+		b.WriteString("pnpm test           # Run tests\n")
+		b.WriteString("pnpm build          # Build\n")
+		b.WriteString("pnpm lint           # Lint\n")
+	}
+	if !ds.Go && !ds.Kotlin && !ds.Node {
+		b.WriteString("# TODO: Add build and test commands\n")
+	}
+}
+
 func templateCopilotInstructions(ds DetectedStack) string {
 	var b strings.Builder
 
@@ -185,10 +282,13 @@ func templateCopilotInstructions(ds DetectedStack) string {
 
 	b.WriteString("## Tech Stack\n\n")
 	langs := ds.Languages()
-	if len(langs) > 0 {
+	if len(langs) > 0 || len(ds.Subprojects) > 0 {
 		for _, l := range langs {
 			b.WriteString("- " + l + "\n")
 		}
+		for _, sub := range ds.Subprojects {
+			b.WriteString("- `" + sub.Path + "/`: " + strings.Join(sub.Languages(), " + ") + "\n")
+		}
 		if ds.Nais {
 			b.WriteString("- Nais (Kubernetes on GCP)\n")
 		}
@@ -214,7 +314,37 @@ func templateCopilotInstructions(ds DetectedStack) string {
 func templateReviewInstructions(ds DetectedStack) string {
 	var b strings.Builder
 
-	// Stack-specific rules
+	// Reviews cover the whole repo, so a monorepo gets every subproject's rules.
+	for _, sub := range ds.Subprojects {
+		ds.Go = ds.Go || sub.Go
+		ds.Kotlin = ds.Kotlin || sub.Kotlin
+		ds.Node = ds.Node || sub.Node
+		ds.Next = ds.Next || sub.Next
+	}
+	writeStackRules(&b, ds)
+
+	b.WriteString("## Norwegian text (all `.md` and user-facing strings)\n\n")
+	b.WriteString("- Use Norwegian bokmål for user-facing text\n")
+	b.WriteString("- Avoid unnecessary anglicisms when good Norwegian alternatives exist\n\n")
+
+	b.WriteString("## Security\n\n")
+	b.WriteString("- No secrets, tokens, or credentials in code\n")
+	b.WriteString("- SQL queries must be parameterized\n")
+	b.WriteString("- GitHub Actions pinned to full SHA with version comment\n\n")
+
+	b.WriteString("## Over-editing\n\n")
+	b.WriteString("Flag changes where the diff is disproportionate to the stated goal:\n\n")
+	b.WriteString("- Renamed variables or parameters not related to the fix\n")
+	b.WriteString("- Restructured working code without justification\n")
+	b.WriteString("- Added refactoring outside the PR scope\n")
+
+	// Copilot Code Review reads the first 4000 characters.
+	return limitInitContent(b.String(), 4000)
+}
+
+// writeStackRules writes the language- and framework-specific rules for ds,
+// one "## <stack>" section each.
+func writeStackRules(b *strings.Builder, ds DetectedStack) {
 	if ds.Go {
 		b.WriteString("## Go\n\n")
 		b.WriteString("- Error wrapping: use `fmt.Errorf(\"context: %w\", err)`, never `%v`\n")
@@ -233,30 +363,34 @@ func templateReviewInstructions(ds DetectedStack) string {
 		b.WriteString("- TypeScript strict mode — no `any` without justification\n")
 		b.WriteString("- Named imports from `@navikt/ds-react`, never `import *`\n\n")
 	}
+	if ds.Next {
+		b.WriteString("## Next.js\n\n")
+		b.WriteString("- Server Components by default; `\"use client\"` only where state or browser APIs are needed\n")
+		b.WriteString("- Fetch data on the server, not in `useEffect`\n\n")
+	}
+}
 
-	b.WriteString("## Norwegian text (all `.md` and user-facing strings)\n\n")
-	b.WriteString("- Use Norwegian bokmål for user-facing text\n")
-	b.WriteString("- Avoid unnecessary anglicisms when good Norwegian alternatives exist\n\n")
-
-	b.WriteString("## Security\n\n")
-	b.WriteString("- No secrets, tokens, or credentials in code\n")
-	b.WriteString("- SQL queries must be parameterized\n")
-	b.WriteString("- GitHub Actions pinned to full SHA with version comment\n\n")
+// templateSubprojectInstructions is the path-scoped instructions file for one
+// monorepo subproject: its stack, commands and rules, applied only to files
+// under its directory.
+func templateSubprojectInstructions(ds DetectedStack) string {
+	var b strings.Builder
 
-	b.WriteString("## Over-editing\n\n")
-	b.WriteString("Flag changes where the diff is disproportionate to the stated goal:\n\n")
-	b.WriteString("- Renamed variables or parameters not related to the fix\n")
-	b.WriteString("- Restructured working code without justification\n")
-	b.WriteString("- Added refactoring outside the PR scope\n")
+	b.WriteString("---\n")
+	b.WriteString("applyTo: \"" + ds.Path + "/**\"\n")
+	b.WriteString("---\n\n")
+	b.WriteString("# " + ds.Path + " — " + ds.StackLabel() + "\n\n")
+	b.WriteString("<!-- These instructions apply to files under " + ds.Path + "/. TODO: Describe what this part of the repo does -->\n\n")
 
-	content := b.String()
+	b.WriteString("## Build & Test Commands\n\n")
+	b.WriteString("```bash\n")
+	b.WriteString("cd " + ds.Path + "\n")
+	writeBuildCommands(&b, ds)
+	b.WriteString("```\n\n")
 
-	if len(content) > 4000 {
-		// Trim to fit — remove optional sections from end
-		content = content[:3990] + "\n"
-	}
+	writeStackRules(&b, ds)
 
-	return content
+	return strings.TrimRight(b.String(), "\n") + "\n"
 }
 
 // ─── Init targets ───────────────────────────────────────────────────────────
@@ -267,30 +401,65 @@ type initTarget struct {
 }
 
 func initTargets(ds DetectedStack) []initTarget {
-	return []initTarget{
+	targets := []initTarget{
 		{"AGENTS.md", templateAgentsMD(ds)},
 		{".github/copilot-instructions.md", templateCopilotInstructions(ds)},
 		{".github/copilot-review-instructions.md", templateReviewInstructions(ds)},
 	}
+	for _, sub := range ds.Subprojects {
+		targets = append(targets, initTarget{subprojectInitPath(sub.Path), templateSubprojectInstructions(sub)})
+	}
+	return targets
+}
+
+// subprojectInitPath is where init puts a subproject's instructions: next to
+// what `install --subproject` installs for it.
+func subprojectInitPath(subproject string) string {
+	return ".github/instructions/" + subproject + "/project.instructions.md"
 }
 
 // ─── Command ────────────────────────────────────────────────────────────────
 
-func cmdInit(targetDir string, dryRun, force bool) error {
+// cmdInit scaffolds the repo-local files from the source's init templates,
+// or from the built-in ones when the source has none or cannot be resolved.
+func cmdInit(targetDir, ref, sourceRepo string, dryRun, force bool) error {
 	if _, err := os.Stat(filepath.Join(targetDir, ".git")); os.IsNotExist(err) {
 		return fmt.Errorf("target %q does not appear to be a git repository (no .git directory)", targetDir)
 	}
 
-	ds := detectStack(targetDir)
+	templates, templatesLabel, err := fetchInitTemplates(ref, sourceRepo)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%s Could not load init templates from source: %v\n", yellow("⚠"), err)
+	}
+	var custom []InitStack
+	if templates != nil {
+		custom = templates.Stacks
+	}
+
+	ds := detectStack(targetDir, custom)
+	ds.Subprojects = detectSubprojects(targetDir, custom)
+
+	targets := initTargets(ds)
+	if templates != nil {
+		if targets, err = renderInitTemplates(templates, ds); err != nil {
+			return err
+		}
+	} else {
+		templatesLabel = "built-in"
+	}
 
 	fmt.Println(bold("nav-pilot init"))
 	fmt.Println()
 	fmt.Printf("  %s %s\n", dim("Repo:"), ds.Name)
-	fmt.Printf("  %s %s\n", dim("Stack:"), ds.StackLabel())
+	if len(ds.Subprojects) == 0 || len(ds.Languages()) > 0 {
+		fmt.Printf("  %s %s\n", dim("Stack:"), ds.StackLabel())
+	}
+	for _, sub := range ds.Subprojects {
+		fmt.Printf("  %s %s %s\n", dim("Subproject:"), sub.Path+"/", dim("("+sub.StackLabel()+")"))
+	}
+	fmt.Printf("  %s %s\n", dim("Templates:"), templatesLabel)
 	fmt.Println()
 
-	targets := initTargets(ds)
-
 	created := 0
 	skipped := 0
 
@@ -329,6 +498,9 @@ func cmdInit(targetDir string, dryRun, force bool) error {
 		fmt.Println(dim("  1. Fill in the TODO placeholders"))
 		fmt.Println(dim("  2. Commit and push to enable Copilot customization"))
 		fmt.Println(dim("  3. Install agents and skills: nav-pilot install <collection>"))
+		if len(ds.Subprojects) > 0 {
+			fmt.Println(dim("     Per subproject: nav-pilot install <collection> --subproject <path>"))
+		}
 		fmt.Println(dim("  4. See what's available: nav-pilot list"))
 	}
 	if skipped > 0 {
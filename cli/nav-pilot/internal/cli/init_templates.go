@@ -0,0 +1,176 @@
+package cli
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"text/template"
+	"unicode/utf8"
+)
+
+// Init renders the templates in the source's templates/init (see
+// source.LoadInitTemplates) when it has them, and falls back to the built-in
+// string builders in init.go for sources that do not: older refs and custom
+// sources without templates.
+
+// initData is what an init template sees as its dot.
+type initData struct {
+	Name        string   // repo directory name
+	Path        string   // subprojects: slash-separated path from the repo root
+	Languages   []string // "Go", "Node.js/TypeScript", custom stack labels, ...
+	Stack       string   // "Go + Node.js/TypeScript on Nais"
+	Nais        bool
+	Dirs        []string // key directories, with a trailing slash
+	Subprojects []initData
+
+	// Stacks holds the detected stacks by name: go, node, next, kotlin, nais
+	// and those declared in the templates manifest. AllStacks adds the
+	// subprojects' stacks, for repo-wide files like the review instructions.
+	Stacks    map[string]bool
+	AllStacks map[string]bool
+}
+
+// Has reports whether this repo or subproject has stack.
+func (d initData) Has(stack string) bool {
+	return d.Stacks[stack]
+}
+
+// HasAny reports whether the repo or any of its subprojects has stack.
+func (d initData) HasAny(stack string) bool {
+	return d.AllStacks[stack]
+}
+
+func newInitData(ds DetectedStack) initData {
+	d := initData{
+		Name:      ds.Name,
+		Path:      ds.Path,
+		Languages: ds.Languages(),
+		Stack:     ds.StackLabel(),
+		Nais:      ds.Nais,
+		Dirs:      keyDirectories(ds.RepoDir),
+		Stacks:    map[string]bool{},
+		AllStacks: map[string]bool{},
+	}
+	if d.Languages == nil {
+		d.Languages = []string{}
+	}
+	for name, ok := range map[string]bool{"go": ds.Go, "node": ds.Node, "next": ds.Next, "kotlin": ds.Kotlin, "nais": ds.Nais} {
+		if ok {
+			d.Stacks[name] = true
+		}
+	}
+	for _, s := range ds.Custom {
+		d.Stacks[s.Name] = true
+	}
+	for name := range d.Stacks {
+		d.AllStacks[name] = true
+	}
+	for _, sub := range ds.Subprojects {
+		sd := newInitData(sub)
+		d.Subprojects = append(d.Subprojects, sd)
+		for name := range sd.Stacks {
+			d.AllStacks[name] = true
+		}
+	}
+	return d
+}
+
+var initTemplateFuncs = template.FuncMap{
+	"join": strings.Join,
+}
+
+// renderInitTemplates renders every template whose stacks match ds: once for
+// the repo, or once per subproject for templates marked subprojects.
+func renderInitTemplates(t *InitTemplates, ds DetectedStack) ([]initTarget, error) {
+	data := newInitData(ds)
+	partials := make([]string, 0, len(t.Partials))
+	for name := range t.Partials {
+		partials = append(partials, name)
+	}
+	sort.Strings(partials)
+
+	var targets []initTarget
+	for _, tmpl := range t.Templates {
+		body := template.New(tmpl.Template).Funcs(initTemplateFuncs)
+		for _, name := range partials {
+			if _, err := body.New(name).Parse(t.Partials[name]); err != nil {
+				return nil, fmt.Errorf("init template %s: %w", name, err)
+			}
+		}
+		if _, err := body.Parse(tmpl.Body); err != nil {
+			return nil, fmt.Errorf("init template %s: %w", tmpl.Template, err)
+		}
+		pathTmpl, err := template.New(tmpl.Path).Parse(tmpl.Path)
+		if err != nil {
+			return nil, fmt.Errorf("init template %s: path: %w", tmpl.Template, err)
+		}
+
+		each := []initData{data}
+		if tmpl.Subprojects {
+			each = data.Subprojects
+		}
+		for _, d := range each {
+			if !initStacksMatch(tmpl.Stacks, d, !tmpl.Subprojects) {
+				continue
+			}
+			var path, content strings.Builder
+			if err := pathTmpl.Execute(&path, d); err != nil {
+				return nil, fmt.Errorf("init template %s: path: %w", tmpl.Template, err)
+			}
+			if err := validateInitPath(path.String()); err != nil {
+				return nil, fmt.Errorf("init template %s: %w", tmpl.Template, err)
+			}
+			if err := body.Execute(&content, d); err != nil {
+				return nil, fmt.Errorf("init template %s: %w", tmpl.Template, err)
+			}
+			// Conditional sections leave blank lines at the end; files end
+			// with exactly one newline.
+			out := strings.TrimRight(content.String(), "\n") + "\n"
+			targets = append(targets, initTarget{path.String(), limitInitContent(out, tmpl.MaxChars)})
+		}
+	}
+	return targets, nil
+}
+
+// initStacksMatch reports whether d has one of stacks (or stacks is empty).
+// Repo-wide templates match a stack found in any subproject.
+func initStacksMatch(stacks []string, d initData, repoWide bool) bool {
+	if len(stacks) == 0 {
+		return true
+	}
+	for _, s := range stacks {
+		if d.Has(s) || repoWide && d.HasAny(s) {
+			return true
+		}
+	}
+	return false
+}
+
+// limitInitContent cuts content longer than max bytes at a rune boundary so
+// it still fits with a final newline; 0 means no limit.
+func limitInitContent(content string, max int) string {
+	if max <= 0 || len(content) <= max {
+		return content
+	}
+	cut := max - 1
+	for cut > 0 && !utf8.RuneStart(content[cut]) {
+		cut--
+	}
+	return content[:cut] + "\n"
+}
+
+// fetchInitTemplates resolves the source and loads its init templates, with
+// the source label for the header. It returns nil templates, and no error,
+// when the source has none.
+func fetchInitTemplates(ref, sourceRepo string) (*InitTemplates, string, error) {
+	src, err := resolveSource(ref, sourceRepo)
+	if err != nil {
+		return nil, "", err
+	}
+	defer src.Cleanup()
+	t, err := loadInitTemplates(src.Layers())
+	if err != nil {
+		return nil, "", err
+	}
+	return t, sourceLabel(src), nil
+}
@@ -65,7 +65,7 @@ func TestDetectStack(t *testing.T) {
 				}
 			}
 
-			ds := detectStack(dir)
+			ds := detectStack(dir, nil)
 			if ds.Go != tt.wantGo {
 				t.Errorf("Go: got %v, want %v", ds.Go, tt.wantGo)
 			}
@@ -82,6 +82,53 @@ func TestDetectStack(t *testing.T) {
 	}
 }
 
+func TestDetectStack_Next(t *testing.T) {
+	dir := t.TempDir()
+	os.WriteFile(filepath.Join(dir, "package.json"), []byte(`{"dependencies": {"next": "15.0.0"}}`), 0o644)
+	if ds := detectStack(dir, nil); !ds.Node || !ds.Next {
+		t.Errorf("package.json with next: Node=%v Next=%v", ds.Node, ds.Next)
+	}
+
+	dir = t.TempDir()
+	os.WriteFile(filepath.Join(dir, "next.config.mjs"), []byte{}, 0o644)
+	ds := detectStack(dir, nil)
+	if !ds.Next || strings.Join(ds.Languages(), ",") != "Node.js/TypeScript,Next.js" {
+		t.Errorf("next.config.mjs: Next=%v languages %v", ds.Next, ds.Languages())
+	}
+}
+
+func TestDetectSubprojects(t *testing.T) {
+	t.Run("mixed stacks", func(t *testing.T) {
+		dir := t.TempDir()
+		os.MkdirAll(filepath.Join(dir, "backend"), 0o755)
+		os.WriteFile(filepath.Join(dir, "backend", "build.gradle.kts"), []byte{}, 0o644)
+		os.MkdirAll(filepath.Join(dir, "apps", "web"), 0o755)
+		os.WriteFile(filepath.Join(dir, "apps", "web", "package.json"), []byte(`{"dependencies":{"next":"15"}}`), 0o644)
+		os.MkdirAll(filepath.Join(dir, "docs"), 0o755)
+
+		subs := detectSubprojects(dir, nil)
+		var got []string
+		for _, s := range subs {
+			got = append(got, s.Path+"="+s.StackLabel())
+		}
+		want := "apps/web=Node.js/TypeScript + Next.js|backend=Kotlin"
+		if strings.Join(got, "|") != want {
+			t.Errorf("got %q, want %q", strings.Join(got, "|"), want)
+		}
+	})
+
+	t.Run("single stack", func(t *testing.T) {
+		dir := t.TempDir()
+		for _, m := range []string{"api", "worker"} {
+			os.MkdirAll(filepath.Join(dir, m), 0o755)
+			os.WriteFile(filepath.Join(dir, m, "go.mod"), []byte("module "+m), 0o644)
+		}
+		if subs := detectSubprojects(dir, nil); subs != nil {
+			t.Errorf("expected no subprojects for a single-language repo, got %d", len(subs))
+		}
+	})
+}
+
 func TestStackLabel(t *testing.T) {
 	tests := []struct {
 		name  string
@@ -206,12 +253,24 @@ func TestTemplateReviewInstructionsCharLimit(t *testing.T) {
 	}
 }
 
+// useInitSource makes init resolve srcDir as its source. An empty directory
+// has no templates/init, so init falls back to the built-in templates.
+func useInitSource(t *testing.T, srcDir string) {
+	t.Helper()
+	orig := resolveSource
+	t.Cleanup(func() { resolveSource = orig })
+	resolveSource = func(ref, sourceRepo string) (*Source, error) {
+		return &Source{Dir: srcDir, SHA: "abc1234", Repo: "navikt/copilot"}, nil
+	}
+}
+
 func TestCmdInit_DryRun(t *testing.T) {
 	dir := t.TempDir()
+	useInitSource(t, t.TempDir())
 	os.MkdirAll(filepath.Join(dir, ".git"), 0o755)
 	os.WriteFile(filepath.Join(dir, "go.mod"), []byte("module test"), 0o644)
 
-	err := cmdInit(dir, true, false)
+	err := cmdInit(dir, "", "", true, false)
 	if err != nil {
 		t.Fatalf("cmdInit dry run: %v", err)
 	}
@@ -224,11 +283,12 @@ func TestCmdInit_DryRun(t *testing.T) {
 
 func TestCmdInit_CreatesFiles(t *testing.T) {
 	dir := t.TempDir()
+	useInitSource(t, t.TempDir())
 	os.MkdirAll(filepath.Join(dir, ".git"), 0o755)
 	os.WriteFile(filepath.Join(dir, "go.mod"), []byte("module test"), 0o644)
 	os.MkdirAll(filepath.Join(dir, "cmd"), 0o755)
 
-	err := cmdInit(dir, false, false)
+	err := cmdInit(dir, "", "", false, false)
 	if err != nil {
 		t.Fatalf("cmdInit: %v", err)
 	}
@@ -252,14 +312,156 @@ func TestCmdInit_CreatesFiles(t *testing.T) {
 	}
 }
 
+func TestCmdInit_Monorepo(t *testing.T) {
+	dir := t.TempDir()
+	useInitSource(t, t.TempDir())
+	os.MkdirAll(filepath.Join(dir, ".git"), 0o755)
+	os.MkdirAll(filepath.Join(dir, "backend"), 0o755)
+	os.WriteFile(filepath.Join(dir, "backend", "pom.xml"), []byte{}, 0o644)
+	os.MkdirAll(filepath.Join(dir, "frontend"), 0o755)
+	os.WriteFile(filepath.Join(dir, "frontend", "package.json"), []byte("{}"), 0o644)
+
+	captureStdout(func() {
+		if err := cmdInit(dir, "", "", false, false); err != nil {
+			t.Fatalf("cmdInit: %v", err)
+		}
+	})
+
+	data, err := os.ReadFile(filepath.Join(dir, ".github", "instructions", "backend", "project.instructions.md"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), `applyTo: "backend/**"`) || !strings.Contains(string(data), "cd backend") {
+		t.Errorf("backend instructions =\n%s", data)
+	}
+	if _, err := os.Stat(filepath.Join(dir, ".github", "instructions", "frontend", "project.instructions.md")); err != nil {
+		t.Errorf("frontend instructions: %v", err)
+	}
+	agents, _ := os.ReadFile(filepath.Join(dir, "AGENTS.md"))
+	if !strings.Contains(string(agents), "## Subprojects") {
+		t.Errorf("AGENTS.md should list subprojects:\n%s", agents)
+	}
+}
+
+func TestCmdInit_SourceTemplates(t *testing.T) {
+	src := t.TempDir()
+	tmplDir := filepath.Join(src, "templates", "init")
+	os.MkdirAll(tmplDir, 0o755)
+	os.WriteFile(filepath.Join(tmplDir, "manifest.json"), []byte(`{
+		"stacks": [{"name": "python", "label": "Python", "files": ["pyproject.toml"]}],
+		"templates": [
+			{"path": "AGENTS.md", "template": "agents.tmpl"},
+			{"path": ".github/instructions/python.instructions.md", "template": "python.tmpl", "stacks": ["python"]},
+			{"path": ".github/instructions/go.instructions.md", "template": "go.tmpl", "stacks": ["go"]}
+		]
+	}`), 0o644)
+	os.WriteFile(filepath.Join(tmplDir, "agents.tmpl"), []byte("# {{.Name}} — {{.Stack}}\n{{if .Has \"python\"}}pytest\n{{end}}"), 0o644)
+	os.WriteFile(filepath.Join(tmplDir, "python.tmpl"), []byte("python rules\n"), 0o644)
+	os.WriteFile(filepath.Join(tmplDir, "go.tmpl"), []byte("go rules\n"), 0o644)
+	useInitSource(t, src)
+
+	dir := filepath.Join(t.TempDir(), "svc")
+	os.MkdirAll(filepath.Join(dir, ".git"), 0o755)
+	os.WriteFile(filepath.Join(dir, "pyproject.toml"), nil, 0o644)
+
+	out := captureStdout(func() {
+		if err := cmdInit(dir, "", "", false, false); err != nil {
+			t.Fatalf("cmdInit: %v", err)
+		}
+	})
+
+	agents, _ := os.ReadFile(filepath.Join(dir, "AGENTS.md"))
+	if string(agents) != "# svc — Python\npytest\n" {
+		t.Errorf("AGENTS.md = %q", agents)
+	}
+	if _, err := os.Stat(filepath.Join(dir, ".github", "instructions", "python.instructions.md")); err != nil {
+		t.Errorf("python template not rendered: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, ".github", "instructions", "go.instructions.md")); !os.IsNotExist(err) {
+		t.Errorf("go template rendered for a python repo: %v", err)
+	}
+	// Only the source's templates are written, not the built-in ones.
+	if _, err := os.Stat(filepath.Join(dir, ".github", "copilot-instructions.md")); !os.IsNotExist(err) {
+		t.Errorf("built-in template written: %v", err)
+	}
+	if !strings.Contains(out, "navikt/copilot@abc1234") {
+		t.Errorf("output should name the template source:\n%s", out)
+	}
+}
+
+func TestCmdInit_SourceTemplateError(t *testing.T) {
+	src := t.TempDir()
+	tmplDir := filepath.Join(src, "templates", "init")
+	os.MkdirAll(tmplDir, 0o755)
+	os.WriteFile(filepath.Join(tmplDir, "manifest.json"), []byte(`{"templates": [{"path": "AGENTS.md", "template": "agents.tmpl"}]}`), 0o644)
+	os.WriteFile(filepath.Join(tmplDir, "agents.tmpl"), []byte("{{.Nope}}"), 0o644)
+	useInitSource(t, src)
+
+	dir := t.TempDir()
+	os.MkdirAll(filepath.Join(dir, ".git"), 0o755)
+	var err error
+	captureStdout(func() { err = cmdInit(dir, "", "", false, false) })
+	if err == nil || !strings.Contains(err.Error(), "agents.tmpl") {
+		t.Fatalf("expected template error, got %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "AGENTS.md")); !os.IsNotExist(err) {
+		t.Error("nothing should be written when a template fails")
+	}
+}
+
+// TestRepoInitTemplates renders the templates this repo ships in
+// templates/init, so a broken template fails here before a release.
+func TestRepoInitTemplates(t *testing.T) {
+	root, err := filepath.Abs(filepath.Join("..", "..", "..", ".."))
+	if err != nil {
+		t.Fatal(err)
+	}
+	templates, err := loadInitTemplates([]Layer{{Dir: root}})
+	if err != nil || templates == nil {
+		t.Fatalf("loading %s/templates/init: %v", root, err)
+	}
+
+	dir := t.TempDir()
+	for _, f := range []string{"go.mod", "web/package.json", "web/next.config.ts", "ml/pyproject.toml", ".nais/app.yaml"} {
+		os.MkdirAll(filepath.Dir(filepath.Join(dir, f)), 0o755)
+		os.WriteFile(filepath.Join(dir, f), []byte("{}"), 0o644)
+	}
+	ds := detectStack(dir, templates.Stacks)
+	ds.Subprojects = detectSubprojects(dir, templates.Stacks)
+	targets, err := renderInitTemplates(templates, ds)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	files := map[string]string{}
+	for _, tg := range targets {
+		files[tg.RelPath] = tg.Content
+	}
+	for path, want := range map[string]string{
+		"AGENTS.md":                                        "- `ml/` — Python",
+		".github/copilot-instructions.md":                  "- Nais (Kubernetes on GCP)",
+		".github/copilot-review-instructions.md":           "## Next.js",
+		".github/instructions/ml/project.instructions.md":  `applyTo: "ml/**"`,
+		".github/instructions/web/project.instructions.md": "pnpm test",
+	} {
+		if !strings.Contains(files[path], want) {
+			t.Errorf("%s should contain %q:\n%s", path, want, files[path])
+		}
+	}
+	if n := len(files[".github/copilot-review-instructions.md"]); n > 4000 {
+		t.Errorf("review instructions exceed 4000 chars: %d", n)
+	}
+}
+
 func TestCmdInit_SkipsExisting(t *testing.T) {
 	dir := t.TempDir()
+	useInitSource(t, t.TempDir())
 	os.MkdirAll(filepath.Join(dir, ".git"), 0o755)
 
 	// Pre-create AGENTS.md with custom content
 	os.WriteFile(filepath.Join(dir, "AGENTS.md"), []byte("custom content"), 0o644)
 
-	err := cmdInit(dir, false, false)
+	err := cmdInit(dir, "", "", false, false)
 	if err != nil {
 		t.Fatalf("cmdInit: %v", err)
 	}
@@ -278,10 +480,11 @@ func TestCmdInit_SkipsExisting(t *testing.T) {
 
 func TestCmdInit_ForceOverwrites(t *testing.T) {
 	dir := t.TempDir()
+	useInitSource(t, t.TempDir())
 	os.MkdirAll(filepath.Join(dir, ".git"), 0o755)
 	os.WriteFile(filepath.Join(dir, "AGENTS.md"), []byte("old"), 0o644)
 
-	err := cmdInit(dir, false, true)
+	err := cmdInit(dir, "", "", false, true)
 	if err != nil {
 		t.Fatalf("cmdInit --force: %v", err)
 	}
@@ -294,7 +497,7 @@ func TestCmdInit_ForceOverwrites(t *testing.T) {
 
 func TestCmdInit_NotGitRepo(t *testing.T) {
 	dir := t.TempDir()
-	err := cmdInit(dir, false, false)
+	err := cmdInit(dir, "", "", false, false)
 	if err == nil {
 		t.Error("cmdInit should fail outside git repo")
 	}
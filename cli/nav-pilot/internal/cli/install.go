@@ -15,10 +15,14 @@ type installResult struct {
 	Files       []InstalledFile
 }
 
-func installItems(sourceDir string, scope *InstallScope, manifest *Manifest, dryRun, force bool) (*installResult, error) {
-	resolver := NewSourceResolver(sourceDir)
+func installItems(resolver *SourceResolver, scope *InstallScope, manifest *Manifest, dryRun, force bool) (*installResult, error) {
 	result := &installResult{}
 
+	manifest, err := withDependencies(resolver, manifest)
+	if err != nil {
+		return result, err
+	}
+
 	for _, group := range []struct {
 		label string
 		names []string
@@ -67,16 +71,21 @@ func installArtifact(resolver *SourceResolver, scope *InstallScope, kind *Artifa
 	if art.IsDir && !strings.HasSuffix(relPath, "/") {
 		relPath = scope.RelPath(kind.Dir, art.Name) + "/"
 	}
+	subproject := artifactSubproject(scope, kind)
+	if subproject != "" {
+		dst = scope.DstPath(kind.Dir, filepath.FromSlash(subproject), art.FileName())
+		relPath = scope.RelPath(kind.Dir, subproject, art.FileName())
+	}
 
-	if c, err := checkConflict(dst, art.AbsPath, art.IsDir); err != nil {
+	if differs, err := sourceConflicts(dst, art.AbsPath, art.IsDir, subproject); err != nil {
 		return err
-	} else if c != nil && !force {
+	} else if differs && !force {
 		// File exists and differs but we're not forcing — skip the overwrite
 		// but track as conflict so it's not lost from state or reported as "new".
 		fmt.Printf("  %s %s (exists, differs — use --force to overwrite)\n", yellow("⚠"), name)
 		existingHash, hashErr := rawArtifactHash(dst, art.IsDir)
 		if hashErr == nil {
-			result.Files = append(result.Files, InstalledFile{Path: relPath, Hash: existingHash, Status: fileStatusConflict})
+			result.Files = append(result.Files, InstalledFile{Path: relPath, Hash: existingHash, Status: fileStatusConflict, Source: art.Source, Subproject: subproject})
 		}
 		result.Conflicts++
 		return nil
@@ -95,16 +104,30 @@ func installArtifact(resolver *SourceResolver, scope *InstallScope, kind *Artifa
 		return nil
 	}
 
-	if err := copyArtifact(art.AbsPath, dst, scope.RootDir, art.IsDir); err != nil {
+	if err := journalTrack(scope, dst); err != nil {
+		return err
+	}
+	if err := installSourceArtifact(art.AbsPath, dst, scope.RootDir, art.IsDir, subproject); err != nil {
 		return fmt.Errorf("copying %s %s: %w", kind.Name, name, err)
 	}
 	hash, err := rawArtifactHash(dst, art.IsDir)
 	if err != nil {
 		return fmt.Errorf("hashing installed %s %s: %w", kind.Name, name, err)
 	}
-	result.Files = append(result.Files, InstalledFile{Path: relPath, Hash: hash})
+	result.Files = append(result.Files, InstalledFile{Path: relPath, Hash: hash, Source: art.Source, Subproject: subproject})
+	if err := saveSourceBase(scope, relPath, art.AbsPath, art.IsDir, subproject); err != nil {
+		fmt.Fprintf(os.Stderr, "  %s %s: could not store merge base: %v\n", yellow("⚠"), name, err)
+	}
 
-	fmt.Printf("  %s %s\n", green("✓"), name)
+	label := name
+	if subproject != "" {
+		label += " " + dim("→ "+subproject+"/")
+	}
+	if resolver.Layered() {
+		fmt.Printf("  %s %s %s\n", green("✓"), label, dim("("+art.Source+")"))
+	} else {
+		fmt.Printf("  %s %s\n", green("✓"), label)
+	}
 	result.Installed++
 
 	return nil
@@ -139,9 +162,11 @@ func cmdInstallAuto(name, itemType string, scope *InstallScope, ref, sourceRepo
 	}
 	defer src.Cleanup()
 
+	resolver := src.Resolver()
+
 	// Check if name matches a collection
 	isCollection := false
-	collections, _ := listCollectionDirs(src.Dir) // ignore error: missing dir = no collections
+	collections, _ := resolver.Collections() // ignore error: missing dir = no collections
 	for _, c := range collections {
 		if c == name {
 			isCollection = true
@@ -150,7 +175,6 @@ func cmdInstallAuto(name, itemType string, scope *InstallScope, ref, sourceRepo
 	}
 
 	// Check if name matches any artifact
-	resolver := NewSourceResolver(src.Dir)
 	var matchedKinds []*ArtifactKind
 	for _, kind := range AllKinds {
 		if _, ok := resolver.Get(kind, name); ok {
@@ -209,15 +233,45 @@ func articleFor(kind string) string {
 	return "a"
 }
 
+// sourceLabel returns the "repo@sha" label shown in install headers.
+// Layered sources list every layer, base first.
+func sourceLabel(src *Source) string {
+	var parts []string
+	for _, l := range src.Layers() {
+		parts = append(parts, repoLabel(l.Repo)+"@"+l.SHA)
+	}
+	return strings.Join(parts, " + ")
+}
+
+// stateSources returns the per-layer state entries for a layered source,
+// or nil for a single source (SourceRepo/SourceSHA already cover it).
+func stateSources(src *Source) []SourceLayer {
+	if len(src.Overlays) == 0 {
+		return nil
+	}
+	var sources []SourceLayer
+	for _, l := range src.Layers() {
+		sources = append(sources, SourceLayer{Repo: l.Repo, SHA: l.SHA})
+	}
+	return sources
+}
+
 // cmdInstallFromSource installs a collection from an already-resolved source.
-func cmdInstallFromSource(collection string, src *Source, scope *InstallScope, dryRun, force bool, jsonOutput bool) error {
-	manifest, err := loadManifest(src.Dir, collection)
+func cmdInstallFromSource(collection string, src *Source, scope *InstallScope, dryRun, force bool, jsonOutput bool) (err error) {
+	if !dryRun {
+		tx, txErr := beginJournal(scope, "install")
+		if txErr != nil {
+			return txErr
+		}
+		defer tx.finish(&err)
+	}
+
+	resolver := src.Resolver()
+	manifest, err := resolver.LoadManifest(collection)
 	if err != nil {
 		return err
 	}
 
-	sourceLabel := "navikt/copilot"
-
 	if !jsonOutput {
 		fmt.Println()
 		if dryRun {
@@ -225,13 +279,13 @@ func cmdInstallFromSource(collection string, src *Source, scope *InstallScope, d
 		} else {
 			fmt.Println(bold(fmt.Sprintf("Installing: %s", collection)))
 		}
-		fmt.Printf("%s %s\n", dim("Source:"), dim(fmt.Sprintf("%s@%s", sourceLabel, src.SHA)))
+		fmt.Printf("%s %s\n", dim("Source:"), dim(sourceLabel(src)))
 		fmt.Printf("%s %s\n", dim("Target:"), dim(scope.Label()))
 		printManifestContents(manifest)
 		fmt.Println()
 	}
 
-	result, err := installItems(src.Dir, scope, manifest, dryRun, force)
+	result, err := installItems(resolver, scope, manifest, dryRun, force)
 	if err != nil {
 		return err
 	}
@@ -277,12 +331,27 @@ func cmdInstallFromSource(collection string, src *Source, scope *InstallScope, d
 		Scope:       scope.Name,
 		SourceRepo:  src.Repo,
 		SourceSHA:   src.SHA,
+		Sources:     stateSources(src),
 		InstalledAt: timeNow().UTC().Format("2006-01-02T15:04:05Z07:00"),
 		Files:       result.Files,
 	}
+	keepMCPServers(scope, state)
+	// A subproject install adds to the repo's state instead of replacing it,
+	// so each subproject of a monorepo can get its own collection.
+	if scope.Subproject != "" {
+		if existing, readErr := readScopedState(scope); readErr == nil && existing != nil {
+			existing.Version = state.Version
+			existing.SourceRepo = state.SourceRepo
+			existing.SourceSHA = state.SourceSHA
+			existing.Sources = state.Sources
+			mergeStateFiles(existing, result.Files)
+			state = existing
+		}
+	}
 	if err := writeScopedState(scope, state); err != nil {
 		fmt.Fprintf(os.Stderr, "%s Could not write state file: %v\n", yellow("⚠"), err)
 	}
+	writeLockFromState(scope, src, state)
 
 	fmt.Printf("%s Installed %d items from %q (v%s, %s).\n",
 		green("✓"), result.Installed, collection, stateVersion, src.SHA)
@@ -302,13 +371,19 @@ func cmdInstallFromSource(collection string, src *Source, scope *InstallScope, d
 
 // cmdAddFromSource installs a single artifact from an already-resolved source.
 // It preserves the à-la-carte state semantics from cmdAdd.
-func cmdAddFromSource(itemType, name string, src *Source, scope *InstallScope, dryRun, force bool, jsonOutput bool) error {
+func cmdAddFromSource(itemType, name string, src *Source, scope *InstallScope, dryRun, force bool, jsonOutput bool) (err error) {
+	if !dryRun {
+		tx, txErr := beginJournal(scope, "install")
+		if txErr != nil {
+			return txErr
+		}
+		defer tx.finish(&err)
+	}
+
 	if !scope.SupportsType(itemType) {
 		return fmt.Errorf("type %q is not supported in user scope. Only agents, skills, and instructions can be installed to ~/.copilot", itemType)
 	}
 
-	sourceLabel := "navikt/copilot"
-
 	result := &installResult{}
 
 	if !jsonOutput {
@@ -318,21 +393,35 @@ func cmdAddFromSource(itemType, name string, src *Source, scope *InstallScope, d
 		} else {
 			fmt.Println(bold(fmt.Sprintf("Installing %s: %s", itemType, name)))
 		}
-		fmt.Printf("%s %s\n", dim("Source:"), dim(fmt.Sprintf("%s@%s", sourceLabel, src.SHA)))
+		fmt.Printf("%s %s\n", dim("Source:"), dim(sourceLabel(src)))
 		fmt.Printf("%s %s\n", dim("Target:"), dim(scope.Label()))
 		fmt.Println()
 	}
 
 	kind := kindByName[itemType]
-	resolver := NewSourceResolver(src.Dir)
+	resolver := src.Resolver()
 	installErr := installArtifact(resolver, scope, kind, name, dryRun, force, result)
 	if installErr != nil {
 		return installErr
 	}
+	if err := installDependencies(resolver, scope, Dependency{Kind: kind, Name: name}, dryRun, force, result); err != nil {
+		return err
+	}
 	if !dryRun {
 		telemetry.RecordInstallItems(scope.Name, telemetryMode(), int64(result.Installed))
 	}
 
+	if !jsonOutput && result.Conflicts > 0 {
+		fmt.Printf("\n%s File already exists and differs. Use %s to overwrite.\n",
+			yellow("⚠"), bold("--force"))
+	}
+
+	if !dryRun && result.Installed > 0 {
+		if err := trackAddedFiles(scope, src, result.Files); err != nil {
+			return err
+		}
+	}
+
 	if jsonOutput {
 		return outputJSON(map[string]interface{}{
 			"command":    "install",
@@ -345,16 +434,16 @@ func cmdAddFromSource(itemType, name string, src *Source, scope *InstallScope, d
 			"dry_run":    dryRun,
 		})
 	}
-
-	if result.Conflicts > 0 {
-		fmt.Printf("\n%s File already exists and differs. Use %s to overwrite.\n",
-			yellow("⚠"), bold("--force"))
-	}
-
 	if dryRun || result.Installed == 0 {
 		return nil
 	}
+	fmt.Printf("\n%s Installed %s %q.\n", green("✓"), itemType, name)
+	return nil
+}
 
+// trackAddedFiles records individually installed files in the scope's state
+// and lock file.
+func trackAddedFiles(scope *InstallScope, src *Source, files []InstalledFile) error {
 	// Append to state file if one exists, otherwise create a minimal one
 	state, err := readScopedState(scope)
 	if err != nil {
@@ -371,6 +460,9 @@ func cmdAddFromSource(itemType, name string, src *Source, scope *InstallScope, d
 		}
 	}
 	state.SourceSHA = src.SHA
+	if sources := stateSources(src); sources != nil {
+		state.Sources = sources
+	}
 	if state.SourceRepo == "" {
 		state.SourceRepo = src.Repo
 	}
@@ -378,12 +470,22 @@ func cmdAddFromSource(itemType, name string, src *Source, scope *InstallScope, d
 		state.Version = src.Version
 	}
 
-	// Merge new files into state, avoiding duplicates
+	mergeStateFiles(state, files)
+	if err := writeScopedState(scope, state); err != nil {
+		fmt.Fprintf(os.Stderr, "%s Could not write state file: %v\n", yellow("⚠"), err)
+	}
+	writeLockFromState(scope, src, state)
+	return nil
+}
+
+// mergeStateFiles adds files to state, replacing the entries for paths that
+// are already tracked.
+func mergeStateFiles(state *StateFile, files []InstalledFile) {
 	existing := make(map[string]bool)
 	for _, f := range state.Files {
 		existing[f.Path] = true
 	}
-	for _, f := range result.Files {
+	for _, f := range files {
 		if !existing[f.Path] {
 			state.Files = append(state.Files, f)
 		} else {
@@ -391,17 +493,13 @@ func cmdAddFromSource(itemType, name string, src *Source, scope *InstallScope, d
 				if sf.Path == f.Path {
 					state.Files[i].Hash = f.Hash
 					state.Files[i].Status = ""
+					state.Files[i].Source = f.Source
+					state.Files[i].Subproject = f.Subproject
 					break
 				}
 			}
 		}
 	}
-	if err := writeScopedState(scope, state); err != nil {
-		fmt.Fprintf(os.Stderr, "%s Could not write state file: %v\n", yellow("⚠"), err)
-	}
-
-	fmt.Printf("\n%s Installed %s %q.\n", green("✓"), itemType, name)
-	return nil
 }
 
 func cmdList(ref, sourceRepo string, showItems bool, jsonOutput bool) error {
@@ -414,44 +512,75 @@ func cmdList(ref, sourceRepo string, showItems bool, jsonOutput bool) error {
 	}
 	defer src.Cleanup()
 
-	names, err := listCollectionDirs(src.Dir)
+	resolver := src.Resolver()
+	names, err := resolver.Collections()
 	if err != nil {
 		return err
 	}
 
 	if jsonOutput {
+		type collectionItem struct {
+			Kind string `json:"kind"`
+			Name string `json:"name"`
+			From string `json:"from,omitempty"`
+		}
 		type collectionInfo struct {
-			Name        string `json:"name"`
-			Description string `json:"description"`
-			Items       int    `json:"items"`
+			Name        string           `json:"name"`
+			Description string           `json:"description"`
+			Items       int              `json:"items"`
+			Extends     []string         `json:"extends,omitempty"`
+			Contents    []collectionItem `json:"contents,omitempty"`
 		}
 		var collections []collectionInfo
 		for _, name := range names {
-			m, err := loadManifest(src.Dir, name)
+			m, err := resolver.LoadManifest(name)
 			if err != nil {
 				continue
 			}
 			total := len(m.Agents) + len(m.Skills) + len(m.Instructions) + len(m.Prompts)
-			collections = append(collections, collectionInfo{Name: name, Description: m.Description, Items: total})
+			info := collectionInfo{Name: name, Description: m.Description, Items: total, Extends: m.Extends}
+			if showItems {
+				for _, kind := range AllKinds {
+					for _, item := range m.Items(kind) {
+						info.Contents = append(info.Contents, collectionItem{kind.Name, item, m.Origin(kind.Name, item)})
+					}
+				}
+			}
+			collections = append(collections, info)
 		}
 		result := map[string]interface{}{"collections": collections}
 		if showItems {
-			result["items"] = collectAvailableItems(src.Dir)
+			result["items"] = collectAvailableItems(resolver)
+		}
+		if resolver.Layered() {
+			result["sources"] = stateSources(src)
 		}
 		return outputJSON(result)
 	}
 
 	fmt.Println()
+	if resolver.Layered() {
+		fmt.Println(bold("Sources (lowest to highest precedence):"))
+		for _, l := range resolver.Layers() {
+			fmt.Printf("  %s %s\n", l.Repo, dim("@"+l.SHA))
+		}
+		fmt.Println()
+	}
 	fmt.Println(bold("Available collections:"))
 	fmt.Println()
 	for _, name := range names {
-		m, err := loadManifest(src.Dir, name)
+		m, err := resolver.LoadManifest(name)
 		if err != nil {
 			continue
 		}
 		total := len(m.Agents) + len(m.Skills) + len(m.Instructions) + len(m.Prompts)
 		fmt.Printf("  %-20s %s %s\n", bold(name), m.Description, dim(fmt.Sprintf("(%d items)", total)))
-		if len(m.Agents) > 0 {
+		if len(m.Extends) > 0 {
+			fmt.Printf("  %-20s %s\n", "", dim("extends: "+strings.Join(m.Extends, ", ")))
+		}
+		if showItems {
+			printCollectionContents(m)
+		} else if len(m.Agents) > 0 {
 			fmt.Printf("  %-20s %s\n", "", dim("agents: "+strings.Join(m.Agents, ", ")))
 		}
 	}
@@ -461,7 +590,7 @@ func cmdList(ref, sourceRepo string, showItems bool, jsonOutput bool) error {
 
 	if showItems {
 		fmt.Println()
-		if err := listAvailableItems(src.Dir); err != nil {
+		if err := listAvailableItems(resolver); err != nil {
 			return err
 		}
 	} else {
@@ -470,9 +599,28 @@ func cmdList(ref, sourceRepo string, showItems bool, jsonOutput bool) error {
 	return nil
 }
 
+// printCollectionContents prints a collection's resolved items per kind.
+// Inherited items show the collection they came from.
+func printCollectionContents(m *Manifest) {
+	for _, kind := range AllKinds {
+		items := m.Items(kind)
+		if len(items) == 0 {
+			continue
+		}
+		labels := make([]string, len(items))
+		for i, item := range items {
+			labels[i] = item
+			if from := m.Origin(kind.Name, item); from != "" {
+				labels[i] += " ← " + from
+			}
+		}
+		fmt.Printf("  %-20s %s\n", "", dim(kind.Dir+": "+strings.Join(labels, ", ")))
+	}
+}
+
 // listAvailableItems prints all agents, skills, instructions, and prompts in the source.
-func listAvailableItems(sourceDir string) error {
-	resolver := NewSourceResolver(sourceDir)
+// For layered sources, each item shows the layer it comes from and any layers it overrides.
+func listAvailableItems(resolver *SourceResolver) error {
 	for _, kind := range AllKinds {
 		items := resolver.List(kind)
 		if len(items) == 0 {
@@ -480,7 +628,14 @@ func listAvailableItems(sourceDir string) error {
 		}
 		fmt.Println(bold(fmt.Sprintf("Available %s:", kind.Dir)))
 		for _, item := range items {
-			fmt.Printf("  %-30s %s\n", item.Name, dim("nav-pilot install "+item.Name))
+			origin := ""
+			if resolver.Layered() {
+				origin = " " + dim("("+item.Source+")")
+				for _, l := range resolver.Shadowed(kind, item.Name) {
+					origin += " " + yellow("overrides "+l.Repo)
+				}
+			}
+			fmt.Printf("  %-30s %s%s\n", item.Name, dim("nav-pilot install "+item.Name), origin)
 		}
 		fmt.Println()
 	}
@@ -488,8 +643,7 @@ func listAvailableItems(sourceDir string) error {
 }
 
 // collectAvailableItems returns all available items as a structured map for JSON output.
-func collectAvailableItems(sourceDir string) map[string][]string {
-	resolver := NewSourceResolver(sourceDir)
+func collectAvailableItems(resolver *SourceResolver) map[string][]string {
 	result := make(map[string][]string)
 	for _, kind := range AllKinds {
 		for _, art := range resolver.List(kind) {
@@ -562,13 +716,17 @@ func cmdInstallAll(scope *InstallScope, ref, sourceRepo string, dryRun, force bo
 // If manifest is nil, it scans the source directory to discover items.
 // extraStateFiles are appended to the state file after install (e.g. ignored items from picker).
 // Extracted so both cmdInstallAll and the interactive flow can share this.
-func installAllFromSource(scope *InstallScope, src *Source, manifest *Manifest, dryRun, force bool, jsonOutput bool, extraStateFiles ...InstalledFile) error {
-	if manifest == nil {
-		var err error
-		manifest, err = collectAllItems(src.Dir)
-		if err != nil {
-			return err
+func installAllFromSource(scope *InstallScope, src *Source, manifest *Manifest, dryRun, force bool, jsonOutput bool, extraStateFiles ...InstalledFile) (err error) {
+	if !dryRun {
+		tx, txErr := beginJournal(scope, "install")
+		if txErr != nil {
+			return txErr
 		}
+		defer tx.finish(&err)
+	}
+
+	if manifest == nil {
+		manifest = src.Resolver().CollectAll()
 	}
 
 	total := len(manifest.Agents) + len(manifest.Skills) + len(manifest.Instructions)
@@ -576,8 +734,6 @@ func installAllFromSource(scope *InstallScope, src *Source, manifest *Manifest,
 		return fmt.Errorf("no agents, skills, or instructions found in source")
 	}
 
-	sourceLabel := "navikt/copilot"
-
 	if !jsonOutput {
 		fmt.Println()
 		if dryRun {
@@ -585,12 +741,12 @@ func installAllFromSource(scope *InstallScope, src *Source, manifest *Manifest,
 		} else {
 			fmt.Println(bold(fmt.Sprintf("Installing: all agents, skills & instructions (%d items)", total)))
 		}
-		fmt.Printf("%s %s\n", dim("Source:"), dim(fmt.Sprintf("%s@%s", sourceLabel, src.SHA)))
+		fmt.Printf("%s %s\n", dim("Source:"), dim(sourceLabel(src)))
 		fmt.Printf("%s %s\n", dim("Target:"), dim(scope.Label()))
 		fmt.Println()
 	}
 
-	result, err := installItems(src.Dir, scope, manifest, dryRun, force)
+	result, err := installItems(src.Resolver(), scope, manifest, dryRun, force)
 	if err != nil {
 		return err
 	}
@@ -629,9 +785,11 @@ func installAllFromSource(scope *InstallScope, src *Source, manifest *Manifest,
 		Scope:       scope.Name,
 		SourceRepo:  src.Repo,
 		SourceSHA:   src.SHA,
+		Sources:     stateSources(src),
 		InstalledAt: timeNow().UTC().Format("2006-01-02T15:04:05Z07:00"),
 		Files:       result.Files,
 	}
+	keepMCPServers(scope, state)
 
 	// Append items the user explicitly deselected in the picker as ignored.
 	if len(extraStateFiles) > 0 {
@@ -641,6 +799,7 @@ func installAllFromSource(scope *InstallScope, src *Source, manifest *Manifest,
 	if err := writeScopedState(scope, state); err != nil {
 		fmt.Fprintf(os.Stderr, "%s Could not write state file: %v\n", yellow("⚠"), err)
 	}
+	writeLockFromState(scope, src, state)
 
 	fmt.Printf("%s Installed %d items to %s (v%s, %s).\n",
 		green("✓"), result.Installed, scope.Label(), stateVersion, src.SHA)
@@ -787,6 +946,8 @@ func cmdListInstalledScoped(scope *InstallScope, _ bool, jsonOutput bool) error
 			"modified":     modified,
 			"missing":      missing,
 			"ignored":      ignored,
+			"subprojects":  stateSubprojects(state),
+			"mcp_servers":  stateMCPKeys(state),
 		})
 	}
 
@@ -812,8 +973,17 @@ func printStatusBlock(scope *InstallScope, state *StateFile) {
 	fmt.Printf("  Version:     %s\n", state.Version)
 	fmt.Printf("  Scope:       %s\n", scope.Name)
 	fmt.Printf("  Source:      %s\n", state.SourceSHA)
+	for _, l := range state.Sources {
+		fmt.Printf("               %s %s\n", l.Repo, dim("@"+l.SHA))
+	}
 	fmt.Printf("  Installed:   %s\n", state.InstalledAt)
 	fmt.Printf("  Files:       %d\n", len(state.Files))
+	if subprojects := stateSubprojects(state); len(subprojects) > 0 {
+		fmt.Printf("  Subprojects: %s\n", strings.Join(subprojects, ", "))
+	}
+	if keys := stateMCPKeys(state); len(keys) > 0 {
+		fmt.Printf("  MCP servers: %s\n", strings.Join(keys, ", "))
+	}
 	fmt.Println()
 
 	for _, p := range modifiedPaths {
@@ -834,7 +1004,15 @@ func printStatusBlock(scope *InstallScope, state *StateFile) {
 	}
 }
 
-func cmdUninstall(scope *InstallScope, dryRun bool) error {
+func cmdUninstall(scope *InstallScope, dryRun bool) (err error) {
+	if !dryRun {
+		tx, txErr := beginJournal(scope, "uninstall")
+		if txErr != nil {
+			return txErr
+		}
+		defer tx.finish(&err)
+	}
+
 	state, err := readScopedState(scope)
 	if err != nil {
 		return fmt.Errorf("reading state: %w", err)
@@ -861,6 +1039,10 @@ func cmdUninstall(scope *InstallScope, dryRun bool) error {
 			continue
 		}
 
+		if err := journalTrack(scope, path); err != nil {
+			fmt.Printf("  %s Could not remove %s: %v\n", yellow("⚠"), f.Path, err)
+			continue
+		}
 		if strings.HasSuffix(f.Path, "/") {
 			if err := os.RemoveAll(path); err != nil && !os.IsNotExist(err) {
 				fmt.Printf("  %s Could not remove %s: %v\n", yellow("⚠"), f.Path, err)
@@ -877,7 +1059,16 @@ func cmdUninstall(scope *InstallScope, dryRun bool) error {
 	}
 
 	if !dryRun {
-		os.Remove(scope.StatePath())
+		// MCP servers are not part of the collection; they stay until
+		// `nav-pilot mcp remove`.
+		if len(state.MCPServers) > 0 {
+			state.Files = nil
+			writeScopedState(scope, state)
+		} else {
+			removeScopedState(scope)
+		}
+		removeLock(scope)
+		removeBaseStore(scope)
 		scope.CleanupDirs()
 	}
 
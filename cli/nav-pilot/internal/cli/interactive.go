@@ -186,7 +186,7 @@ func interactiveSyncAndLaunch(repoScope *InstallScope, repoState *StateFile, use
 			fmt.Printf("%s Syncing %s scope...\n", dim("→"), s.scope.Name)
 			ref := "nav-pilot/" + s.latest
 			if err := runWithCommandTelemetry("sync", "interactive", s.scope.Name, func() error {
-				return cmdSync(s.scope, ref, "", true, false)
+				return cmdSync(s.scope, ref, "", lockFollow, true, false)
 			}); err != nil {
 				fmt.Fprintf(os.Stderr, "%s Sync failed for %s scope: %v\n", yellow("⚠"), s.scope.Name, err)
 			}
@@ -267,10 +267,7 @@ func interactiveUserInstall(src *Source, resolved ResolvedConfig) error {
 // interactiveUserInstallFromSource is the shared implementation for user-scope interactive install.
 // Used by both the root `nav-pilot` command and `nav-pilot install --user`.
 func interactiveUserInstallFromSource(scope *InstallScope, src *Source) error {
-	manifest, err := collectAllItems(src.Dir)
-	if err != nil {
-		return err
-	}
+	manifest := src.Resolver().CollectAll()
 
 	total := len(manifest.Agents) + len(manifest.Skills) + len(manifest.Instructions)
 	if total == 0 {
@@ -285,7 +282,7 @@ func interactiveUserInstallFromSource(scope *InstallScope, src *Source) error {
 	if isInteractive() {
 		fmt.Println()
 		var installChoice string
-		err = huh.NewSelect[string]().
+		err := huh.NewSelect[string]().
 			Title(fmt.Sprintf("Install %d agents, skills & instructions to ~/.copilot?", total)).
 			Options(
 				huh.NewOption(fmt.Sprintf("Install everything (%d items)", total), "all"),
@@ -488,7 +485,8 @@ func computeSkippedItems(full, selected *Manifest, scope *InstallScope) []Instal
 
 // interactiveRepoInstall handles repo-scope collection picker flow.
 func interactiveRepoInstall(src *Source, scope *InstallScope) error {
-	names, err := listCollectionDirs(src.Dir)
+	resolver := src.Resolver()
+	names, err := resolver.Collections()
 	if err != nil {
 		return err
 	}
@@ -499,7 +497,7 @@ func interactiveRepoInstall(src *Source, scope *InstallScope) error {
 	// Build collection options
 	var options []huh.Option[string]
 	for _, name := range names {
-		m, err := loadManifest(src.Dir, name)
+		m, err := resolver.LoadManifest(name)
 		if err != nil {
 			continue
 		}
@@ -525,7 +523,7 @@ func interactiveRepoInstall(src *Source, scope *InstallScope) error {
 	}
 
 	// Show preview with contents
-	m, err := loadManifest(src.Dir, selected)
+	m, err := resolver.LoadManifest(selected)
 	if err != nil {
 		return err
 	}
@@ -0,0 +1,169 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/navikt/copilot/cli/nav-pilot/internal/artifacts"
+)
+
+// activeJournals holds the open journal per scope, keyed by state path, so
+// the write helpers below can record pre-images without threading the
+// journal through every install and sync function.
+var (
+	journalMu      sync.Mutex
+	activeJournals = map[string]*Journal{}
+)
+
+// journalTx is one journaled command on one scope.
+type journalTx struct {
+	scope   *InstallScope
+	journal *Journal
+}
+
+// beginJournal opens a journal for a mutating command on scope, after rolling
+// back any journal an earlier run left pending. Only commands that write call
+// it: check and dry-run modes must not touch disk, recovery included. Returns
+// a nil transaction when a journal is already open for the scope: nested
+// commands join the outer one.
+func beginJournal(scope *InstallScope, command string) (*journalTx, error) {
+	key := scope.StatePath()
+	journalMu.Lock()
+	defer journalMu.Unlock()
+	if activeJournals[key] != nil {
+		return nil, nil
+	}
+	if err := recoverJournals(scope); err != nil {
+		return nil, err
+	}
+	j := beginJournalAt(scope, command, timeNow())
+	activeJournals[key] = j
+	return &journalTx{scope: scope, journal: j}, nil
+}
+
+// finish closes the transaction. A failed command is rolled back as a whole;
+// errSyncFailed and errUpdatesAvailable still commit, since the state file
+// then describes exactly what was applied.
+func (tx *journalTx) finish(errp *error) {
+	if tx == nil {
+		return
+	}
+	journalMu.Lock()
+	delete(activeJournals, tx.scope.StatePath())
+	journalMu.Unlock()
+
+	j := tx.journal
+	if err := *errp; err != nil && !errors.Is(err, errSyncFailed) && !errors.Is(err, errUpdatesAvailable) {
+		if len(j.Entries) == 0 {
+			return
+		}
+		if rErr := j.Restore(); rErr != nil {
+			fmt.Fprintf(os.Stderr, "%s Could not roll back %s: %v. Run %s to retry.\n",
+				yellow("⚠"), j.Command, rErr, bold("nav-pilot rollback"))
+			return
+		}
+		tx.scope.CleanupDirs()
+		fmt.Fprintf(os.Stderr, "%s %s failed — restored %d path(s) to their previous state\n",
+			yellow("⚠"), j.Command, len(j.Entries))
+		return
+	}
+	if err := j.Commit(tx.scope, journalKeep); err != nil {
+		fmt.Fprintf(os.Stderr, "%s Could not finish journal: %v\n", yellow("⚠"), err)
+	}
+}
+
+// recoverJournals rolls back journals left pending by an interrupted run.
+// A pending journal whose process is still running is another nav-pilot
+// writing to the same scope, and is left alone.
+func recoverJournals(scope *InstallScope) error {
+	journals, err := listJournals(scope)
+	if err != nil {
+		return fmt.Errorf("reading journal: %w", err)
+	}
+	for _, j := range journals {
+		if j.Status != journalPending || j.OwnerRunning() {
+			continue
+		}
+		if err := j.Restore(); err != nil {
+			return fmt.Errorf("recovering interrupted %s from %s: %w", j.Command, j.StartedAt, err)
+		}
+		scope.CleanupDirs()
+		fmt.Fprintf(os.Stderr, "%s Rolled back interrupted %s from %s (%d path(s))\n",
+			yellow("⚠"), j.Command, j.StartedAt, len(j.Entries))
+	}
+	return nil
+}
+
+// journalTrack records path (absolute, under the scope root) in the scope's
+// open journal before it is changed. A no-op when no journal is open.
+func journalTrack(scope *InstallScope, path string) error {
+	journalMu.Lock()
+	j := activeJournals[scope.StatePath()]
+	journalMu.Unlock()
+	if j == nil {
+		return nil
+	}
+	rel, err := filepath.Rel(scope.RootDir, path)
+	if err != nil || !filepath.IsLocal(rel) {
+		return fmt.Errorf("journal: %s is outside %s", path, scope.RootDir)
+	}
+	return j.Track(rel)
+}
+
+// ─── Journaled write helpers ────────────────────────────────────────────────
+// Every state, lock and merge-base write goes through these, so commands only
+// track the artifact paths themselves.
+
+func writeScopedState(scope *InstallScope, state *StateFile) error {
+	if err := journalTrack(scope, scope.StatePath()); err != nil {
+		return err
+	}
+	return artifacts.WriteScopedState(scope, state)
+}
+
+// removeScopedState deletes the state file.
+func removeScopedState(scope *InstallScope) error {
+	if err := journalTrack(scope, scope.StatePath()); err != nil {
+		return err
+	}
+	return os.Remove(scope.StatePath())
+}
+
+func writeLock(scope *InstallScope, lock *LockFile) error {
+	if err := journalTrack(scope, lockPath(scope)); err != nil {
+		return err
+	}
+	return artifacts.WriteLock(scope, lock)
+}
+
+// removeLock deletes nav-pilot.lock.
+func removeLock(scope *InstallScope) error {
+	if err := journalTrack(scope, lockPath(scope)); err != nil {
+		return err
+	}
+	return os.Remove(lockPath(scope))
+}
+
+func saveBase(scope *InstallScope, relPath, srcPath string, isDir bool) error {
+	if err := journalTrack(scope, baseStorePath(scope, relPath)); err != nil {
+		return err
+	}
+	return artifacts.SaveBase(scope, relPath, srcPath, isDir)
+}
+
+func removeBase(scope *InstallScope, relPath string) error {
+	if err := journalTrack(scope, baseStorePath(scope, relPath)); err != nil {
+		return err
+	}
+	return artifacts.RemoveBase(scope, relPath)
+}
+
+func removeBaseStore(scope *InstallScope) error {
+	if err := journalTrack(scope, artifacts.BaseRoot(scope)); err != nil {
+		return err
+	}
+	return artifacts.RemoveBaseStore(scope)
+}
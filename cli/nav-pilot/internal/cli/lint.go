@@ -0,0 +1,166 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// SARIF 2.1.0 output — just enough of the schema for GitHub code scanning
+// (github/codeql-action/upload-sarif) to show findings inline on PRs.
+type sarifLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string      `json:"name"`
+	Version        string      `json:"version"`
+	InformationURI string      `json:"informationUri"`
+	Rules          []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID                   string       `json:"id"`
+	ShortDescription     sarifMessage `json:"shortDescription"`
+	DefaultConfiguration struct {
+		Level string `json:"level"`
+	} `json:"defaultConfiguration"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifResult struct {
+	RuleID    string          `json:"ruleId"`
+	Level     string          `json:"level"`
+	Message   sarifMessage    `json:"message"`
+	Locations []sarifLocation `json:"locations"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation struct {
+		ArtifactLocation struct {
+			URI       string `json:"uri"`
+			URIBaseID string `json:"uriBaseId"`
+		} `json:"artifactLocation"`
+		Region *sarifRegion `json:"region,omitempty"`
+	} `json:"physicalLocation"`
+}
+
+type sarifRegion struct {
+	StartLine int `json:"startLine"`
+}
+
+// cmdLint validates the customization artifacts under root. Any
+// error-level finding makes it fail (exit 1); warnings alone do not.
+func cmdLint(root string, jsonOutput, sarif bool) error {
+	if info, err := os.Stat(root); err != nil || !info.IsDir() {
+		return fmt.Errorf("%s is not a directory", root)
+	}
+	result, err := lintTree(root)
+	if err != nil {
+		return fmt.Errorf("linting %s: %w", root, err)
+	}
+	errors, warnings := result.Counts()
+
+	switch {
+	case sarif:
+		if err := outputJSON(buildSARIF(result.Findings)); err != nil {
+			return err
+		}
+	case jsonOutput:
+		if err := outputJSON(map[string]interface{}{
+			"root":     root,
+			"files":    result.Files,
+			"errors":   errors,
+			"warnings": warnings,
+			"findings": result.Findings,
+		}); err != nil {
+			return err
+		}
+	default:
+		printLintFindings(root, result.Findings, result.Files, errors, warnings)
+	}
+
+	if errors > 0 {
+		return fmt.Errorf("lint found %d error(s)", errors)
+	}
+	return nil
+}
+
+func printLintFindings(root string, findings []LintFinding, files, errors, warnings int) {
+	for _, f := range findings {
+		glyph := red("×")
+		if f.Severity != lintError {
+			glyph = yellow("⚠")
+		}
+		loc := f.Path
+		if f.Line > 0 {
+			loc = fmt.Sprintf("%s:%d", f.Path, f.Line)
+		}
+		fmt.Printf("%s %s  %s  %s\n", glyph, bold(loc), f.Message, dim("("+f.Rule+")"))
+	}
+	if files == 0 {
+		fmt.Printf("%s No agents, skills, instructions or prompts found under %s\n", dim("⊘"), filepath.Clean(root))
+		return
+	}
+	if len(findings) == 0 {
+		fmt.Printf("%s %d file(s) checked, no problems\n", green("✓"), files)
+		return
+	}
+	fmt.Println()
+	summary := fmt.Sprintf("%d error(s), %d warning(s) in %d file(s)", errors, warnings, files)
+	if errors > 0 {
+		fmt.Printf("%s %s\n", red("×"), summary)
+	} else {
+		fmt.Printf("%s %s\n", yellow("⚠"), summary)
+	}
+}
+
+func buildSARIF(findings []LintFinding) sarifLog {
+	driver := sarifDriver{
+		Name:           "nav-pilot",
+		Version:        Version,
+		InformationURI: "https://github.com/navikt/copilot",
+		Rules:          make([]sarifRule, 0, len(lintRules)),
+	}
+	for _, r := range lintRules {
+		rule := sarifRule{ID: r.ID, ShortDescription: sarifMessage{Text: r.Description}}
+		rule.DefaultConfiguration.Level = r.Severity
+		driver.Rules = append(driver.Rules, rule)
+	}
+
+	results := make([]sarifResult, 0, len(findings))
+	for _, f := range findings {
+		var loc sarifLocation
+		loc.PhysicalLocation.ArtifactLocation.URI = f.Path
+		loc.PhysicalLocation.ArtifactLocation.URIBaseID = "%SRCROOT%"
+		if f.Line > 0 {
+			loc.PhysicalLocation.Region = &sarifRegion{StartLine: f.Line}
+		}
+		results = append(results, sarifResult{
+			RuleID:    f.Rule,
+			Level:     f.Severity,
+			Message:   sarifMessage{Text: f.Message},
+			Locations: []sarifLocation{loc},
+		})
+	}
+
+	return sarifLog{
+		Schema:  "https://json.schemastore.org/sarif-2.1.0.json",
+		Version: "2.1.0",
+		Runs:    []sarifRun{{Tool: sarifTool{Driver: driver}, Results: results}},
+	}
+}
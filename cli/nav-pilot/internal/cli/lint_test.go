@@ -0,0 +1,67 @@
+package cli
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLint_SARIFAndExit(t *testing.T) {
+	root := t.TempDir()
+	agents := filepath.Join(root, ".github", "agents")
+	os.MkdirAll(agents, 0o755)
+	os.WriteFile(filepath.Join(agents, "nais.agent.md"), []byte("---\nname: nais\n---\n# Nais\n"), 0o644)
+
+	var err error
+	out := captureStdout(func() {
+		err = cmdLint(root, false, true)
+	})
+	if err == nil || !strings.Contains(err.Error(), "1 error") {
+		t.Errorf("cmdLint err = %v, want lint failure", err)
+	}
+	var log struct {
+		Version string `json:"version"`
+		Runs    []struct {
+			Tool struct {
+				Driver struct {
+					Name  string `json:"name"`
+					Rules []struct {
+						ID string `json:"id"`
+					} `json:"rules"`
+				} `json:"driver"`
+			} `json:"tool"`
+			Results []sarifResult `json:"results"`
+		} `json:"runs"`
+	}
+	if jerr := json.Unmarshal([]byte(out), &log); jerr != nil {
+		t.Fatalf("invalid SARIF JSON: %v\n%s", jerr, out)
+	}
+	if log.Version != "2.1.0" || len(log.Runs) != 1 || log.Runs[0].Tool.Driver.Name != "nav-pilot" {
+		t.Fatalf("sarif = %+v", log)
+	}
+	if len(log.Runs[0].Tool.Driver.Rules) != len(lintRules) {
+		t.Errorf("rules = %d, want %d", len(log.Runs[0].Tool.Driver.Rules), len(lintRules))
+	}
+	results := log.Runs[0].Results
+	if len(results) != 1 {
+		t.Fatalf("results = %+v", results)
+	}
+	r := results[0]
+	loc := r.Locations[0].PhysicalLocation
+	if r.RuleID != "frontmatter/required" || r.Level != "error" ||
+		loc.ArtifactLocation.URI != ".github/agents/nais.agent.md" || loc.ArtifactLocation.URIBaseID != "%SRCROOT%" ||
+		loc.Region == nil || loc.Region.StartLine != 1 {
+		t.Errorf("result = %+v", r)
+	}
+
+	// Fixing the error makes lint pass; text output reports it.
+	os.WriteFile(filepath.Join(agents, "nais.agent.md"), []byte("---\nname: nais\ndescription: Nais\n---\n"), 0o644)
+	out = captureStdout(func() {
+		err = cmdLint(root, false, false)
+	})
+	if err != nil || !strings.Contains(out, "1 file(s) checked, no problems") {
+		t.Errorf("cmdLint err = %v, output:\n%s", err, out)
+	}
+}
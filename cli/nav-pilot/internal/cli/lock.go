@@ -0,0 +1,349 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// syncLockMode controls how sync treats nav-pilot.lock.
+type syncLockMode int
+
+const (
+	lockFollow syncLockMode = iota // default: sync to latest source; the lock is left as is
+	lockPinned                     // --locked: sync to the lock's commits and refuse any drift
+	lockUpdate                     // --update-lock: apply the latest source and move the lock forward
+)
+
+// errLockDrift is returned when the resolved source or installed set no longer
+// matches nav-pilot.lock under --locked.
+var errLockDrift = errors.New("source does not match " + lockFileName)
+
+// repoLabel returns the display name for a layer's repo; single sources
+// resolved without --source leave it empty.
+func repoLabel(repo string) string {
+	if repo == "" {
+		return "navikt/copilot"
+	}
+	return repo
+}
+
+// buildLock pins every active state file to the layer it resolves to in src
+// and the hash of its pristine source content.
+func buildLock(scope *InstallScope, src *Source, state *StateFile) (*LockFile, error) {
+	layers := src.Layers()
+	lock := &LockFile{
+		LockVersion: lockFileVersion,
+		Collection:  state.Collection,
+		Scope:       scope.Name,
+		SourceRepo:  state.SourceRepo,
+	}
+	if lock.SourceRepo == "" {
+		lock.SourceRepo = repoLabel(src.Repo)
+	}
+	for _, l := range layers {
+		lock.Sources = append(lock.Sources, LockedSource{Repo: repoLabel(l.Repo), Commit: l.Commit})
+	}
+
+	resolver := src.Resolver()
+	for _, f := range state.Files {
+		if f.Status == fileStatusIgnored {
+			continue
+		}
+		isDir := strings.HasSuffix(f.Path, "/")
+		rel, layer := resolver.MapLocalPathLayer(unscopedPath(f.Path, f.Subproject), scope.IsUser())
+		hash, err := rawArtifactHash(filepath.Join(layer.Dir, rel), isDir)
+		if err != nil {
+			return nil, fmt.Errorf("%s: not found in source", f.Path)
+		}
+		commit := ""
+		for _, l := range layers {
+			if l.Dir == layer.Dir {
+				commit = l.Commit
+			}
+		}
+		lock.Artifacts = append(lock.Artifacts, LockedArtifact{
+			Path:       f.Path,
+			Source:     repoLabel(layer.Repo),
+			Commit:     commit,
+			Hash:       hash,
+			Subproject: f.Subproject,
+		})
+	}
+	return lock, nil
+}
+
+// writeLockFromState regenerates nav-pilot.lock after an install or
+// sync --update-lock. Failures are warnings: the install itself succeeded.
+func writeLockFromState(scope *InstallScope, src *Source, state *StateFile) {
+	lock, err := buildLock(scope, src, state)
+	if err == nil {
+		err = writeLock(scope, lock)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%s Could not write %s: %v\n", yellow("⚠"), lockFileName, err)
+	}
+}
+
+// lockSourceRefs returns the ref and --source value that resolve exactly the
+// lock's commits: the base layer via ref, overlays via "repo@commit".
+func lockSourceRefs(lock *LockFile) (ref, sourceRepo string) {
+	var repos []string
+	for i, s := range lock.Sources {
+		if i == 0 {
+			ref = s.Commit
+			repos = append(repos, s.Repo)
+			continue
+		}
+		if s.Commit != "" {
+			repos = append(repos, s.Repo+"@"+s.Commit)
+		} else {
+			repos = append(repos, s.Repo)
+		}
+	}
+	return ref, strings.Join(repos, sourceLayerSeparator)
+}
+
+// verifyLock checks that src is at the lock's commits, that every locked
+// artifact still has the locked content, and (when state is given) that the
+// installed set matches the lock.
+func verifyLock(scope *InstallScope, lock *LockFile, src *Source, state *StateFile) error {
+	var problems []string
+	layers := src.Layers()
+	for i, ls := range lock.Sources {
+		if i >= len(layers) {
+			problems = append(problems, fmt.Sprintf("source %s: missing layer", ls.Repo))
+			continue
+		}
+		got := layers[i].Commit
+		switch {
+		case ls.Commit == "":
+		case got == "":
+			problems = append(problems, fmt.Sprintf("source %s: has no commit to check, lock pins %s", ls.Repo, shortCommit(ls.Commit)))
+		case got != ls.Commit:
+			problems = append(problems, fmt.Sprintf("source %s: at %s, lock pins %s", ls.Repo, shortCommit(got), shortCommit(ls.Commit)))
+		}
+	}
+
+	resolver := src.Resolver()
+	locked := make(map[string]bool, len(lock.Artifacts))
+	for _, a := range lock.Artifacts {
+		locked[a.Path] = true
+		srcPath, ok := lockedSourcePath(scope, resolver, layers, a)
+		if !ok {
+			problems = append(problems, fmt.Sprintf("%s: missing from %s", a.Path, a.Source))
+			continue
+		}
+		hash, err := rawArtifactHash(srcPath, strings.HasSuffix(a.Path, "/"))
+		if err != nil || hash != a.Hash {
+			problems = append(problems, fmt.Sprintf("%s: source content differs from lock", a.Path))
+		}
+	}
+
+	if state != nil {
+		for _, f := range state.Files {
+			if f.Status != fileStatusIgnored && !locked[f.Path] {
+				problems = append(problems, fmt.Sprintf("%s: installed but not in lock", f.Path))
+			}
+		}
+	}
+
+	if len(problems) > 0 {
+		return fmt.Errorf("%w:\n  %s\n\nRun %s to move the lock forward", errLockDrift,
+			strings.Join(problems, "\n  "), bold("nav-pilot sync --update-lock"))
+	}
+	return nil
+}
+
+// lockedSourcePath returns the absolute source path of a locked artifact in
+// the layer it was locked from, falling back to normal precedence.
+func lockedSourcePath(scope *InstallScope, resolver *SourceResolver, layers []Layer, a LockedArtifact) (string, bool) {
+	rel, layer := resolver.MapLocalPathLayer(unscopedPath(a.Path, a.Subproject), scope.IsUser())
+	for _, l := range layers {
+		if repoLabel(l.Repo) == a.Source {
+			layer = l
+			break
+		}
+	}
+	p := filepath.Join(layer.Dir, rel)
+	if _, err := os.Stat(p); err != nil {
+		return "", false
+	}
+	return p, true
+}
+
+func shortCommit(c string) string {
+	if len(c) > 7 {
+		return c[:7]
+	}
+	return c
+}
+
+// cmdInstallLocked reinstalls exactly what nav-pilot.lock pins: the same
+// commits, the same artifact set, and content that must hash to the lock.
+// name, when given, must match the locked collection.
+func cmdInstallLocked(name string, scope *InstallScope, dryRun, force bool, jsonOutput bool) (err error) {
+	if !dryRun {
+		tx, txErr := beginJournal(scope, "install")
+		if txErr != nil {
+			return txErr
+		}
+		defer tx.finish(&err)
+	}
+
+	lock, err := readLock(scope)
+	if err != nil {
+		return err
+	}
+	if lock == nil {
+		return fmt.Errorf("no %s in %s. Run 'nav-pilot install <collection>' to create one", lockFileName, scope.Label())
+	}
+	if name != "" && name != lock.Collection {
+		return fmt.Errorf("%s pins collection %q, not %q", lockFileName, lock.Collection, name)
+	}
+
+	if !dryRun && !scope.IsUser() {
+		if _, err := os.Stat(filepath.Join(scope.RootDir, ".git")); os.IsNotExist(err) {
+			return fmt.Errorf("target %q does not appear to be a git repository (no .git directory)", scope.RootDir)
+		}
+	}
+
+	ref, sourceRepo := lockSourceRefs(lock)
+	if !jsonOutput {
+		fmt.Println(dim("Resolving locked source..."))
+	}
+	src, err := resolveSource(ref, sourceRepo)
+	if err != nil {
+		return err
+	}
+	defer src.Cleanup()
+
+	if err := verifyLock(scope, lock, src, nil); err != nil {
+		return err
+	}
+
+	if !jsonOutput {
+		fmt.Println()
+		if dryRun {
+			fmt.Println(bold(fmt.Sprintf("Dry run: %s (locked)", lock.Collection)))
+		} else {
+			fmt.Println(bold(fmt.Sprintf("Installing: %s (locked)", lock.Collection)))
+		}
+		fmt.Printf("%s %s\n", dim("Source:"), dim(sourceLabel(src)))
+		fmt.Printf("%s %s\n", dim("Target:"), dim(scope.Label()))
+		fmt.Println()
+	}
+
+	resolver := src.Resolver()
+	layers := src.Layers()
+	result := &installResult{}
+	for _, a := range lock.Artifacts {
+		srcPath, _ := lockedSourcePath(scope, resolver, layers, a)
+		isDir := strings.HasSuffix(a.Path, "/")
+		dst := filepath.Join(scope.RootDir, a.Path)
+
+		if differs, err := sourceConflicts(dst, srcPath, isDir, a.Subproject); err != nil {
+			return err
+		} else if differs && !force {
+			fmt.Printf("  %s %s (exists, differs — use --force to overwrite)\n", yellow("⚠"), a.Path)
+			if existingHash, hashErr := rawArtifactHash(dst, isDir); hashErr == nil {
+				result.Files = append(result.Files, InstalledFile{Path: a.Path, Hash: existingHash, Status: fileStatusConflict, Source: a.Source, Subproject: a.Subproject})
+			}
+			result.Conflicts++
+			continue
+		}
+		if dryRun {
+			fmt.Printf("  %s %s\n", dim("→"), a.Path)
+			result.Installed++
+			continue
+		}
+		if err := journalTrack(scope, dst); err != nil {
+			return err
+		}
+		if err := installSourceArtifact(srcPath, dst, scope.RootDir, isDir, a.Subproject); err != nil {
+			return fmt.Errorf("copying %s: %w", a.Path, err)
+		}
+		if err := saveSourceBase(scope, a.Path, srcPath, isDir, a.Subproject); err != nil {
+			fmt.Fprintf(os.Stderr, "  %s %s: could not store merge base: %v\n", yellow("⚠"), a.Path, err)
+		}
+		hash := a.Hash
+		if a.Subproject != "" {
+			// The lock pins the source; the installed copy is rendered.
+			if hash, err = rawArtifactHash(dst, isDir); err != nil {
+				return fmt.Errorf("hashing %s: %w", a.Path, err)
+			}
+		}
+		result.Files = append(result.Files, InstalledFile{Path: a.Path, Hash: hash, Source: a.Source, Subproject: a.Subproject})
+		fmt.Printf("  %s %s\n", green("✓"), a.Path)
+		result.Installed++
+	}
+	if !dryRun {
+		telemetry.RecordInstallItems(scope.Name, telemetryMode(), int64(result.Installed))
+	}
+
+	if jsonOutput {
+		return outputJSON(map[string]interface{}{
+			"command":    "install",
+			"collection": lock.Collection,
+			"scope":      scope.Name,
+			"source_sha": src.SHA,
+			"locked":     true,
+			"installed":  result.Installed,
+			"conflicts":  result.Conflicts,
+			"dry_run":    dryRun,
+		})
+	}
+
+	if result.Conflicts > 0 {
+		fmt.Printf("\n%s %d file(s) skipped due to conflicts. Use %s to overwrite.\n",
+			yellow("⚠"), result.Conflicts, bold("--force"))
+	}
+	if dryRun {
+		fmt.Printf("\n%s Would install %d locked items from %q.\n", dim("→"), result.Installed, lock.Collection)
+		return nil
+	}
+
+	state := &StateFile{
+		Collection:  lock.Collection,
+		Version:     src.Version,
+		Scope:       scope.Name,
+		SourceRepo:  lock.SourceRepo,
+		SourceSHA:   src.SHA,
+		Sources:     stateSources(src),
+		InstalledAt: timeNow().UTC().Format("2006-01-02T15:04:05Z07:00"),
+		Files:       result.Files,
+	}
+	keepMCPServers(scope, state)
+	if err := writeScopedState(scope, state); err != nil {
+		fmt.Fprintf(os.Stderr, "%s Could not write state file: %v\n", yellow("⚠"), err)
+	}
+
+	fmt.Printf("\n%s Installed %d locked items from %q (%s).\n",
+		green("✓"), result.Installed, lock.Collection, src.SHA)
+	return nil
+}
+
+// finishSyncLock applies the lock mode after a successful sync: --update-lock
+// rewrites the lock, and a plain sync warns when the lock now lags behind.
+func finishSyncLock(w, errW io.Writer, scope *InstallScope, src *Source, lock *LockFile, mode syncLockMode) {
+	switch mode {
+	case lockUpdate:
+		state, err := readScopedState(scope)
+		if err != nil || state == nil {
+			return
+		}
+		writeLockFromState(scope, src, state)
+		fmt.Fprintf(w, "%s Updated %s (%s).\n", green("✓"), lockFileName, src.SHA)
+	case lockFollow:
+		if lock == nil || len(lock.Sources) == 0 {
+			return
+		}
+		if c := lock.Sources[0].Commit; c != "" && src.Commit != "" && c != src.Commit {
+			fmt.Fprintf(errW, "%s %s still pins %s. Run %s to move it forward.\n",
+				yellow("⚠"), lockFileName, shortCommit(c), bold("nav-pilot sync --update-lock"))
+		}
+	}
+}
@@ -0,0 +1,200 @@
+package cli
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/navikt/copilot/cli/nav-pilot/internal/source"
+)
+
+const (
+	commitV1 = "1111111111111111111111111111111111111111"
+	commitV2 = "2222222222222222222222222222222222222222"
+)
+
+// lockFixture installs a one-agent collection from a local source directory
+// and points install --locked at the same directory, still at commitV1.
+func lockFixture(t *testing.T) (*InstallScope, string) {
+	t.Helper()
+	dir := t.TempDir()
+	os.MkdirAll(filepath.Join(dir, ".git"), 0o755)
+	srcDir := t.TempDir()
+	os.MkdirAll(filepath.Join(srcDir, "collections", "backend"), 0o755)
+	os.WriteFile(filepath.Join(srcDir, "collections", "backend", "manifest.json"),
+		[]byte(`{"name":"backend","agents":["nais"]}`), 0o644)
+	os.MkdirAll(filepath.Join(srcDir, "agents"), 0o755)
+	os.WriteFile(filepath.Join(srcDir, "agents", "nais.agent.md"), []byte("# Nais v1\n"), 0o644)
+
+	scope := ScopeRepo(dir)
+	src := &source.Source{Dir: srcDir, SHA: "1111111", Commit: commitV1, Version: "dev", Repo: srcDir}
+	if err := cmdInstallFromSource("backend", src, scope, false, false, false); err != nil {
+		t.Fatalf("install: %v", err)
+	}
+
+	orig := resolveSource
+	t.Cleanup(func() { resolveSource = orig })
+	resolveSource = func(ref, sourceRepo string) (*source.Source, error) {
+		if ref != commitV1 || sourceRepo != srcDir {
+			t.Errorf("resolved (%q, %q), want lock's commit and repo", ref, sourceRepo)
+		}
+		return &source.Source{Dir: srcDir, SHA: "1111111", Commit: commitV1, Version: "dev", Repo: srcDir}, nil
+	}
+	return scope, srcDir
+}
+
+func TestInstall_WritesLock(t *testing.T) {
+	scope, srcDir := lockFixture(t)
+
+	if _, err := os.Stat(filepath.Join(scope.RootDir, ".github", "nav-pilot.lock")); err != nil {
+		t.Fatalf("lockfile not written next to state: %v", err)
+	}
+	lock, err := readLock(scope)
+	if err != nil || lock == nil {
+		t.Fatalf("readLock: %v", err)
+	}
+	if lock.Collection != "backend" || len(lock.Sources) != 1 || lock.Sources[0].Commit != commitV1 {
+		t.Errorf("lock header = %+v", lock)
+	}
+	if len(lock.Artifacts) != 1 {
+		t.Fatalf("artifacts = %+v, want 1", lock.Artifacts)
+	}
+	a := lock.Artifacts[0]
+	wantHash, _ := rawArtifactHash(filepath.Join(srcDir, "agents", "nais.agent.md"), false)
+	if a.Path != ".github/agents/nais.agent.md" || a.Commit != commitV1 || a.Hash != wantHash || a.Source != srcDir {
+		t.Errorf("artifact = %+v", a)
+	}
+}
+
+func TestInstallLocked_ReproducesLock(t *testing.T) {
+	scope, _ := lockFixture(t)
+	agent := filepath.Join(scope.RootDir, ".github", "agents", "nais.agent.md")
+	os.Remove(agent)
+	os.Remove(scope.StatePath())
+
+	if err := cmdInstallLocked("", scope, false, false, false); err != nil {
+		t.Fatalf("install --locked: %v", err)
+	}
+	if data, _ := os.ReadFile(agent); string(data) != "# Nais v1\n" {
+		t.Errorf("agent = %q", data)
+	}
+	state, _ := readScopedState(scope)
+	if state == nil || state.Collection != "backend" || len(state.Files) != 1 {
+		t.Errorf("state = %+v", state)
+	}
+
+	if err := cmdInstallLocked("frontend", scope, false, false, false); err == nil {
+		t.Error("expected error for a collection other than the locked one")
+	}
+}
+
+func TestInstallLocked_RefusesContentDrift(t *testing.T) {
+	scope, srcDir := lockFixture(t)
+	os.WriteFile(filepath.Join(srcDir, "agents", "nais.agent.md"), []byte("# Nais (rewritten)\n"), 0o644)
+
+	err := cmdInstallLocked("", scope, false, true, false)
+	if !errors.Is(err, errLockDrift) {
+		t.Fatalf("err = %v, want errLockDrift", err)
+	}
+	if data, _ := os.ReadFile(filepath.Join(scope.RootDir, ".github", "agents", "nais.agent.md")); string(data) != "# Nais v1\n" {
+		t.Errorf("drifted content was installed: %q", data)
+	}
+}
+
+func TestInstallLocked_RefusesSourceWithoutCommit(t *testing.T) {
+	scope, srcDir := lockFixture(t)
+	// A plain directory has no commit, so the pin cannot be checked.
+	resolveSource = func(ref, sourceRepo string) (*source.Source, error) {
+		return &source.Source{Dir: srcDir, SHA: "local", Version: "dev", Repo: srcDir}, nil
+	}
+
+	err := cmdInstallLocked("", scope, false, true, false)
+	if !errors.Is(err, errLockDrift) || !strings.Contains(err.Error(), "has no commit to check, lock pins 1111111") {
+		t.Fatalf("err = %v, want errLockDrift for the missing commit", err)
+	}
+}
+
+func TestInstallLocked_NoLock(t *testing.T) {
+	dir := t.TempDir()
+	if err := cmdInstallLocked("", ScopeRepo(dir), false, false, false); err == nil {
+		t.Error("expected error without a lockfile")
+	}
+}
+
+func TestSyncLocked_PinsAndRefusesDrift(t *testing.T) {
+	scope, srcDir := lockFixture(t)
+
+	var gotRef, gotRepo string
+	commit := commitV1
+	orig := resolveSourceForSync
+	t.Cleanup(func() { resolveSourceForSync = orig })
+	resolveSourceForSync = func(ref, sourceRepo string) (*source.Source, error) {
+		gotRef, gotRepo = ref, sourceRepo
+		return &source.Source{Dir: srcDir, SHA: commit[:7], Commit: commit, Version: "dev", Repo: srcDir}, nil
+	}
+
+	// Without a merge base, a locally edited file is restored to the locked content.
+	agent := filepath.Join(scope.RootDir, ".github", "agents", "nais.agent.md")
+	os.WriteFile(agent, []byte("# edited\n"), 0o644)
+	removeBaseStore(scope)
+	if err := cmdSync(scope, "", "", lockPinned, true, false); err != nil {
+		t.Fatalf("sync --locked: %v", err)
+	}
+	if gotRef != commitV1 || gotRepo != srcDir {
+		t.Errorf("resolved (%q, %q), want lock's commit and repo", gotRef, gotRepo)
+	}
+	if data, _ := os.ReadFile(agent); string(data) != "# Nais v1\n" {
+		t.Errorf("agent = %q, want locked content", data)
+	}
+
+	// Upstream moved on: --locked refuses, --update-lock moves forward.
+	commit = commitV2
+	os.WriteFile(filepath.Join(srcDir, "agents", "nais.agent.md"), []byte("# Nais v2\n"), 0o644)
+	if err := cmdSync(scope, "", "", lockPinned, true, false); !errors.Is(err, errLockDrift) {
+		t.Fatalf("sync --locked err = %v, want errLockDrift", err)
+	}
+	if err := cmdSync(scope, "", "", lockUpdate, false, false); err != nil {
+		t.Fatalf("sync --update-lock: %v", err)
+	}
+	if data, _ := os.ReadFile(agent); string(data) != "# Nais v2\n" {
+		t.Errorf("agent = %q, want updated content", data)
+	}
+	lock, _ := readLock(scope)
+	if lock == nil || lock.Sources[0].Commit != commitV2 || lock.Artifacts[0].Commit != commitV2 {
+		t.Errorf("lock after --update-lock = %+v", lock)
+	}
+	if err := cmdSync(scope, "", "", lockPinned, false, false); err != nil {
+		t.Errorf("sync --locked after update: %v", err)
+	}
+}
+
+func TestSync_FollowLeavesLock(t *testing.T) {
+	scope, srcDir := lockFixture(t)
+	orig := resolveSourceForSync
+	t.Cleanup(func() { resolveSourceForSync = orig })
+	resolveSourceForSync = func(ref, sourceRepo string) (*source.Source, error) {
+		return &source.Source{Dir: srcDir, SHA: "2222222", Commit: commitV2, Version: "dev", Repo: srcDir}, nil
+	}
+	os.WriteFile(filepath.Join(srcDir, "agents", "nais.agent.md"), []byte("# Nais v2\n"), 0o644)
+
+	if err := cmdSync(scope, "", "", lockFollow, true, false); err != nil {
+		t.Fatalf("sync --apply: %v", err)
+	}
+	lock, _ := readLock(scope)
+	if lock == nil || lock.Sources[0].Commit != commitV1 {
+		t.Errorf("plain sync must not move the lock, got %+v", lock)
+	}
+}
+
+func TestLockSourceRefs(t *testing.T) {
+	lock := &LockFile{Sources: []LockedSource{
+		{Repo: "navikt/copilot", Commit: commitV1},
+		{Repo: "team/agents", Commit: commitV2},
+	}}
+	ref, repo := lockSourceRefs(lock)
+	if ref != commitV1 || repo != "navikt/copilot,team/agents@"+commitV2 {
+		t.Errorf("lockSourceRefs = (%q, %q)", ref, repo)
+	}
+}
@@ -0,0 +1,734 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/charmbracelet/huh"
+)
+
+// MCP clients nav-pilot writes server entries for. The ids are recorded in
+// the state file.
+const (
+	mcpClientVSCode   = "vscode"   // .vscode/mcp.json (repo scope)
+	mcpClientCopilot  = "copilot"  // ~/.copilot/mcp-config.json (user scope)
+	mcpClientOpenCode = "opencode" // opencode.json in the opencode config dir (user scope)
+)
+
+// mcpClient is one client config file that holds MCP server entries.
+type mcpClient struct {
+	ID       string
+	Path     string
+	Boundary string // writes must stay below this directory
+	Servers  string // top-level key of the server map
+}
+
+// mcpClientByID returns where client id keeps its MCP servers in scope.
+func mcpClientByID(scope *InstallScope, id string) (mcpClient, bool) {
+	switch id {
+	case mcpClientVSCode:
+		return mcpClient{id, filepath.Join(scope.RootDir, ".vscode", "mcp.json"), scope.RootDir, "servers"}, true
+	case mcpClientCopilot:
+		return mcpClient{id, filepath.Join(scope.RootDir, "mcp-config.json"), scope.RootDir, "mcpServers"}, true
+	case mcpClientOpenCode:
+		dir := getOpenCodeConfigDir()
+		return mcpClient{id, filepath.Join(dir, "opencode.json"), dir, "mcp"}, true
+	}
+	return mcpClient{}, false
+}
+
+// detectMCPClients returns the clients `mcp add` writes to: VS Code for a
+// repo, Copilot CLI for the user, plus opencode when it is set up.
+func detectMCPClients(scope *InstallScope) []mcpClient {
+	ids := []string{mcpClientVSCode}
+	if scope.IsUser() {
+		ids = []string{mcpClientCopilot}
+		if info, err := os.Stat(getOpenCodeConfigDir()); err == nil && info.IsDir() {
+			ids = append(ids, mcpClientOpenCode)
+		}
+	}
+	clients := make([]mcpClient, 0, len(ids))
+	for _, id := range ids {
+		c, _ := mcpClientByID(scope, id)
+		clients = append(clients, c)
+	}
+	return clients
+}
+
+// readMCPConfig reads a client config as a generic map, so keys nav-pilot
+// does not know survive the rewrite. A missing file is an empty config.
+func readMCPConfig(path string) (map[string]any, error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return map[string]any{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var cfg map[string]any
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return nil, fmt.Errorf("%s is not valid JSON (comments are not supported): %w", path, err)
+	}
+	if cfg == nil {
+		cfg = map[string]any{}
+	}
+	return cfg, nil
+}
+
+func writeMCPConfig(scope *InstallScope, c mcpClient, cfg map[string]any) error {
+	data, err := json.MarshalIndent(cfg, "", "  ")
+	if err != nil {
+		return err
+	}
+	// The opencode config lives outside the scope and is not journaled.
+	if inScopeRoot(scope, c.Path) {
+		if err := journalTrack(scope, c.Path); err != nil {
+			return err
+		}
+	}
+	return writeFileAtomic(c.Path, append(data, '\n'), c.Boundary)
+}
+
+func inScopeRoot(scope *InstallScope, path string) bool {
+	rel, err := filepath.Rel(scope.RootDir, path)
+	return err == nil && filepath.IsLocal(rel)
+}
+
+// servers returns the client's server map in cfg, creating it if needed.
+func (c mcpClient) servers(cfg map[string]any) map[string]any {
+	servers, _ := cfg[c.Servers].(map[string]any)
+	if servers == nil {
+		servers = map[string]any{}
+		cfg[c.Servers] = servers
+	}
+	return servers
+}
+
+// has reports whether cfg has an entry for key.
+func (c mcpClient) has(cfg map[string]any, key string) bool {
+	servers, _ := cfg[c.Servers].(map[string]any)
+	_, ok := servers[key]
+	return ok
+}
+
+// env returns the environment the entry for key sets.
+func (c mcpClient) env(cfg map[string]any, key string) map[string]string {
+	servers, _ := cfg[c.Servers].(map[string]any)
+	entry, _ := servers[key].(map[string]any)
+	field := "env"
+	if c.ID == mcpClientOpenCode {
+		field = "environment"
+	}
+	env := map[string]string{}
+	vars, _ := entry[field].(map[string]any)
+	for name, v := range vars {
+		if s, ok := v.(string); ok {
+			env[name] = s
+		}
+	}
+	return env
+}
+
+// put writes the entry for key in the client's format. VS Code configs are
+// committed, so secrets become inputs VS Code asks for instead of values.
+func (c mcpClient) put(cfg map[string]any, key string, l mcpLaunch, env map[string]string) {
+	entry := map[string]any{}
+	if l.Remote != nil {
+		remoteType := "http"
+		if l.Remote.Type == "sse" {
+			remoteType = "sse"
+		}
+		switch c.ID {
+		case mcpClientVSCode:
+			entry["type"] = remoteType
+		case mcpClientCopilot:
+			entry["type"] = remoteType
+			entry["tools"] = []string{"*"}
+		case mcpClientOpenCode:
+			entry["type"] = "remote"
+			entry["enabled"] = true
+		}
+		entry["url"] = l.Remote.URL
+		c.servers(cfg)[key] = entry
+		return
+	}
+
+	envField := "env"
+	switch c.ID {
+	case mcpClientVSCode:
+		entry["type"] = "stdio"
+		env = c.putInputs(cfg, l, env)
+	case mcpClientCopilot:
+		entry["type"] = "local"
+		entry["tools"] = []string{"*"}
+	case mcpClientOpenCode:
+		entry["type"] = "local"
+		entry["enabled"] = true
+		envField = "environment"
+	}
+	if c.ID == mcpClientOpenCode {
+		entry["command"] = append([]string{l.Command}, l.Args...)
+	} else {
+		entry["command"] = l.Command
+		entry["args"] = append([]string{}, l.Args...)
+	}
+	if len(env) > 0 {
+		entry[envField] = env
+	}
+	c.servers(cfg)[key] = entry
+}
+
+// putInputs declares a promptString input for every secret of l and returns
+// env with the secrets referring to them.
+func (c mcpClient) putInputs(cfg map[string]any, l mcpLaunch, env map[string]string) map[string]string {
+	out := make(map[string]string, len(env))
+	for name, v := range env {
+		out[name] = v
+	}
+	inputs, _ := cfg["inputs"].([]any)
+	for _, v := range l.Env {
+		if !v.IsRequired || !v.IsSecret {
+			continue
+		}
+		out[v.Name] = "${input:" + v.Name + "}"
+		if slices.ContainsFunc(inputs, func(in any) bool {
+			m, _ := in.(map[string]any)
+			return m["id"] == v.Name
+		}) {
+			continue
+		}
+		inputs = append(inputs, map[string]any{
+			"type":        "promptString",
+			"id":          v.Name,
+			"description": v.Description,
+			"password":    true,
+		})
+	}
+	if len(inputs) > 0 {
+		cfg["inputs"] = inputs
+	}
+	return out
+}
+
+// remove deletes the entry for key, and the VS Code inputs only it used.
+func (c mcpClient) remove(cfg map[string]any, key string) {
+	servers, _ := cfg[c.Servers].(map[string]any)
+	entry, ok := servers[key]
+	if !ok {
+		return
+	}
+	delete(servers, key)
+	inputs, _ := cfg["inputs"].([]any)
+	if c.ID != mcpClientVSCode || len(inputs) == 0 {
+		return
+	}
+	removed, _ := json.Marshal(entry)
+	rest, _ := json.Marshal(servers)
+	inputs = slices.DeleteFunc(inputs, func(in any) bool {
+		m, _ := in.(map[string]any)
+		id, _ := m["id"].(string)
+		ref := "${input:" + id + "}"
+		return id != "" && strings.Contains(string(removed), ref) && !strings.Contains(string(rest), ref)
+	})
+	if len(inputs) == 0 {
+		delete(cfg, "inputs")
+	} else {
+		cfg["inputs"] = inputs
+	}
+}
+
+// promptMCPEnv asks for the value of a required variable. Overridable in
+// tests.
+var promptMCPEnv = func(server string, v mcpEnvVar) (string, error) {
+	var value string
+	input := huh.NewInput().
+		Title(fmt.Sprintf("%s for %s", v.Name, server)).
+		Description(v.Description).
+		Value(&value).
+		Validate(func(s string) error {
+			if strings.TrimSpace(s) == "" {
+				return fmt.Errorf("%s is required", v.Name)
+			}
+			return nil
+		})
+	if v.IsSecret {
+		input = input.EchoMode(huh.EchoModePassword)
+	}
+	if err := input.WithTheme(navTheme()).Run(); err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(value), nil
+}
+
+// mcpEnvValues collects the launch's required variables: a known value
+// (the entry's current one on sync), else the environment, else a prompt.
+// With inputs set, secrets are left to VS Code to ask for.
+func mcpEnvValues(server string, l mcpLaunch, known map[string]string, inputs bool) (map[string]string, error) {
+	env := map[string]string{}
+	for _, v := range l.Env {
+		if !v.IsRequired || inputs && v.IsSecret {
+			continue
+		}
+		if value := known[v.Name]; value != "" {
+			env[v.Name] = value
+			continue
+		}
+		if value := os.Getenv(v.Name); value != "" {
+			env[v.Name] = value
+			continue
+		}
+		if !isInteractive() {
+			return nil, fmt.Errorf("%s needs %s: set it in the environment or run in a terminal", server, v.Name)
+		}
+		value, err := promptMCPEnv(server, v)
+		if err != nil {
+			return nil, fmt.Errorf("%s needs %s: %w", server, v.Name, err)
+		}
+		env[v.Name] = value
+	}
+	return env, nil
+}
+
+// requiredEnvNames lists the names of the launch's required variables.
+func requiredEnvNames(l mcpLaunch) []string {
+	var names []string
+	for _, v := range l.Env {
+		if v.IsRequired {
+			names = append(names, v.Name)
+		}
+	}
+	return names
+}
+
+// writeMCPServer writes the server's entry into every client config.
+func writeMCPServer(scope *InstallScope, s mcpServer, l mcpLaunch, env map[string]string, clients []mcpClient) error {
+	for _, c := range clients {
+		cfg, err := readMCPConfig(c.Path)
+		if err != nil {
+			return err
+		}
+		c.put(cfg, s.Key(), l, env)
+		if err := writeMCPConfig(scope, c, cfg); err != nil {
+			return fmt.Errorf("writing %s: %w", c.Path, err)
+		}
+	}
+	return nil
+}
+
+// trackMCPServer records srv in the scope's state, replacing an earlier
+// entry for the same server.
+func trackMCPServer(scope *InstallScope, srv InstalledMCPServer) error {
+	state, err := readScopedState(scope)
+	if err != nil {
+		return fmt.Errorf("reading state: %w", err)
+	}
+	if state == nil {
+		state = &StateFile{
+			Collection:  "(à la carte)",
+			Scope:       scope.Name,
+			InstalledAt: timeNow().UTC().Format("2006-01-02T15:04:05Z07:00"),
+		}
+	}
+	if i := slices.IndexFunc(state.MCPServers, func(m InstalledMCPServer) bool { return m.Name == srv.Name }); i >= 0 {
+		state.MCPServers[i] = srv
+	} else {
+		state.MCPServers = append(state.MCPServers, srv)
+	}
+	return writeScopedState(scope, state)
+}
+
+// keepMCPServers carries the MCP servers of the scope's current state over
+// to a state that replaces it.
+func keepMCPServers(scope *InstallScope, state *StateFile) {
+	if existing, err := readScopedState(scope); err == nil && existing != nil {
+		state.MCPServers = existing.MCPServers
+	}
+}
+
+// installedMCPServer finds a tracked server by name or key.
+func installedMCPServer(state *StateFile, name string) *InstalledMCPServer {
+	if state == nil {
+		return nil
+	}
+	for i, m := range state.MCPServers {
+		if m.Name == name || m.Key == name {
+			return &state.MCPServers[i]
+		}
+	}
+	return nil
+}
+
+// stateMCPKeys lists the keys of the MCP servers in state.
+func stateMCPKeys(state *StateFile) []string {
+	keys := []string{}
+	for _, m := range state.MCPServers {
+		keys = append(keys, m.Key)
+	}
+	return keys
+}
+
+// displayPath shows paths under the scope root relative to it.
+func displayPath(scope *InstallScope, path string) string {
+	if inScopeRoot(scope, path) {
+		rel, _ := filepath.Rel(scope.RootDir, path)
+		return filepath.ToSlash(rel)
+	}
+	return path
+}
+
+// ─── Commands ───────────────────────────────────────────────────────────────
+
+// cmdMCP manages MCP servers from the Nav registry in the scope's clients.
+func cmdMCP(args []string, scope *InstallScope, force, jsonOutput bool) error {
+	const subcommands = "\n\nSubcommands:\n  list            List the servers in the registry\n  add <name>      Add a server to the MCP client configs\n  remove <name>   Remove a server added with mcp add"
+	if len(args) == 0 {
+		return fmt.Errorf("mcp requires a subcommand.\n\nUsage: nav-pilot mcp <subcommand> [--user]" + subcommands)
+	}
+	switch args[0] {
+	case "list":
+		return cmdMCPList(scope, jsonOutput)
+	case "add":
+		if len(args) != 2 {
+			return fmt.Errorf("mcp add requires a server name.\n\nUsage: nav-pilot mcp add <name> [--user] [--force]")
+		}
+		return cmdMCPAdd(args[1], scope, force, jsonOutput)
+	case "remove", "rm":
+		if len(args) != 2 {
+			return fmt.Errorf("mcp remove requires a server name.\n\nUsage: nav-pilot mcp remove <name> [--user]")
+		}
+		return cmdMCPRemove(args[1], scope, jsonOutput)
+	default:
+		return fmt.Errorf("unknown mcp subcommand: %q"+subcommands, args[0])
+	}
+}
+
+type mcpListEntry struct {
+	Name        string `json:"name"`
+	Key         string `json:"key"`
+	Description string `json:"description"`
+	Version     string `json:"version"`
+	Status      string `json:"status"`
+	Transport   string `json:"transport,omitempty"`
+	Installed   string `json:"installed,omitempty"` // installed version in this scope
+}
+
+func cmdMCPList(scope *InstallScope, jsonOutput bool) error {
+	servers, loc, err := fetchMCPRegistry()
+	if err != nil {
+		return err
+	}
+	state, _ := readScopedState(scope)
+
+	entries := []mcpListEntry{}
+	for _, s := range servers {
+		if s.Status == mcpStatusDeleted {
+			continue
+		}
+		e := mcpListEntry{Name: s.Name, Key: s.Key(), Description: s.Description, Version: s.Version, Status: s.Status}
+		if l, err := planMCPServer(s); err == nil {
+			e.Transport = l.transportLabel()
+		}
+		if m := installedMCPServer(state, s.Name); m != nil && m.Name == s.Name {
+			e.Installed = m.Version
+		}
+		entries = append(entries, e)
+	}
+
+	if jsonOutput {
+		return outputJSON(map[string]interface{}{"registry": loc, "scope": scope.Name, "servers": entries})
+	}
+
+	fmt.Println(bold("MCP servers") + " " + dim("("+loc+")"))
+	fmt.Println()
+	if len(entries) == 0 {
+		fmt.Println("  No servers in the registry.")
+		return nil
+	}
+	width := 0
+	for _, e := range entries {
+		width = max(width, len(e.Key))
+	}
+	for _, e := range entries {
+		glyph := " "
+		if e.Installed != "" {
+			glyph = green("✓")
+		}
+		line := fmt.Sprintf("  %s %s %s", glyph, bold(fmt.Sprintf("%-*s", width, e.Key)), dim(e.Version))
+		if e.Transport == "" {
+			line += " " + dim("(no supported transport)")
+		} else {
+			line += " " + dim(e.Transport)
+		}
+		if e.Status == mcpStatusDeprecated {
+			line += " " + yellow("(deprecated)")
+		}
+		if e.Installed != "" && e.Installed != e.Version {
+			line += " " + yellow("(installed: "+e.Installed+")")
+		}
+		fmt.Println(line)
+		if e.Description != "" {
+			fmt.Printf("    %s\n", e.Description)
+		}
+	}
+	fmt.Println()
+	fmt.Printf("Add one to %s with: %s\n", scope.Label(), bold("nav-pilot mcp add <name>"))
+	return nil
+}
+
+// cmdMCPAdd writes a registry server into the scope's MCP client configs and
+// records it in the state file.
+func cmdMCPAdd(name string, scope *InstallScope, force, jsonOutput bool) (err error) {
+	servers, _, err := fetchMCPRegistry()
+	if err != nil {
+		return err
+	}
+	s, err := findMCPServer(servers, name)
+	if err != nil {
+		return err
+	}
+	if s.Status == mcpStatusDeprecated && !force {
+		return fmt.Errorf("MCP server %s is deprecated in the registry. Use --force to add it anyway", s.Name)
+	}
+	l, err := planMCPServer(*s)
+	if err != nil {
+		return err
+	}
+
+	state, err := readScopedState(scope)
+	if err != nil {
+		return fmt.Errorf("reading state: %w", err)
+	}
+	tracked := installedMCPServer(state, s.Name)
+	clients := detectMCPClients(scope)
+	for _, c := range clients {
+		cfg, err := readMCPConfig(c.Path)
+		if err != nil {
+			return err
+		}
+		if c.has(cfg, s.Key()) && (tracked == nil || tracked.Key != s.Key()) && !force {
+			return fmt.Errorf("%s already has an MCP server %q that nav-pilot did not add. Use --force to replace it", displayPath(scope, c.Path), s.Key())
+		}
+	}
+
+	env, err := mcpEnvValues(s.Key(), l, nil, !scope.IsUser())
+	if err != nil {
+		return err
+	}
+
+	tx, err := beginJournal(scope, "mcp add")
+	if err != nil {
+		return err
+	}
+	defer tx.finish(&err)
+
+	if err := writeMCPServer(scope, *s, l, env, clients); err != nil {
+		return err
+	}
+	srv := InstalledMCPServer{Name: s.Name, Key: s.Key(), Version: s.Version, Env: requiredEnvNames(l)}
+	var paths []string
+	for _, c := range clients {
+		srv.Clients = append(srv.Clients, c.ID)
+		paths = append(paths, displayPath(scope, c.Path))
+	}
+	if err := trackMCPServer(scope, srv); err != nil {
+		return fmt.Errorf("updating state: %w", err)
+	}
+
+	if jsonOutput {
+		return outputJSON(map[string]interface{}{
+			"name":      s.Name,
+			"key":       s.Key(),
+			"version":   s.Version,
+			"transport": l.transportLabel(),
+			"clients":   srv.Clients,
+			"paths":     paths,
+			"env":       srv.Env,
+		})
+	}
+	fmt.Printf("%s Added MCP server %s %s\n", green("✓"), bold(s.Key()), dim("("+s.Name+" "+s.Version+")"))
+	for _, p := range paths {
+		fmt.Printf("    %s %s\n", dim("→"), p)
+	}
+	if !scope.IsUser() && slices.ContainsFunc(l.Env, func(v mcpEnvVar) bool { return v.IsRequired && v.IsSecret }) {
+		fmt.Printf("  %s VS Code asks for the secret values when the server starts.\n", dim("ℹ"))
+	}
+	return nil
+}
+
+// cmdMCPRemove removes a server added with `mcp add` from the clients it
+// was written to. It works without the registry.
+func cmdMCPRemove(name string, scope *InstallScope, jsonOutput bool) (err error) {
+	state, err := readScopedState(scope)
+	if err != nil {
+		return fmt.Errorf("reading state: %w", err)
+	}
+	m := installedMCPServer(state, name)
+	if m == nil {
+		return fmt.Errorf("MCP server %q was not added by nav-pilot in %s. Run 'nav-pilot list --installed' to see what is installed", name, scope.Label())
+	}
+	removed := *m
+
+	tx, err := beginJournal(scope, "mcp remove")
+	if err != nil {
+		return err
+	}
+	defer tx.finish(&err)
+
+	var paths []string
+	for _, id := range removed.Clients {
+		c, ok := mcpClientByID(scope, id)
+		if !ok {
+			continue
+		}
+		cfg, err := readMCPConfig(c.Path)
+		if err != nil {
+			return err
+		}
+		if !c.has(cfg, removed.Key) {
+			continue
+		}
+		c.remove(cfg, removed.Key)
+		if err := writeMCPConfig(scope, c, cfg); err != nil {
+			return fmt.Errorf("writing %s: %w", c.Path, err)
+		}
+		paths = append(paths, displayPath(scope, c.Path))
+	}
+
+	state.MCPServers = slices.DeleteFunc(state.MCPServers, func(s InstalledMCPServer) bool { return s.Name == removed.Name })
+	if len(state.Files) == 0 && len(state.MCPServers) == 0 {
+		err = removeScopedState(scope)
+	} else {
+		err = writeScopedState(scope, state)
+	}
+	if err != nil {
+		return fmt.Errorf("updating state: %w", err)
+	}
+
+	if jsonOutput {
+		return outputJSON(map[string]interface{}{"name": removed.Name, "key": removed.Key, "paths": paths})
+	}
+	fmt.Printf("%s Removed MCP server %s\n", green("✓"), bold(removed.Key))
+	for _, p := range paths {
+		fmt.Printf("    %s %s\n", dim("→"), p)
+	}
+	return nil
+}
+
+// ─── Sync ───────────────────────────────────────────────────────────────────
+
+// MCP server statuses reported by sync.
+const (
+	mcpSyncUpdate     = "update"
+	mcpSyncDeprecated = "deprecated"
+	mcpSyncRemoved    = "removed" // deleted from the registry
+)
+
+type mcpSyncStatus struct {
+	Name           string `json:"name"`
+	Key            string `json:"key"`
+	Status         string `json:"status"`
+	Version        string `json:"version,omitempty"` // registry version
+	CurrentVersion string `json:"current_version"`
+
+	server *mcpServer
+}
+
+// checkMCPServers compares the scope's MCP servers with the registry. It
+// returns nothing when none are installed or the sync is offline.
+func checkMCPServers(scope *InstallScope) ([]mcpSyncStatus, error) {
+	state, err := readScopedState(scope)
+	if err != nil || state == nil || len(state.MCPServers) == 0 {
+		return nil, nil
+	}
+	if isOffline() && isRegistryURL(mcpRegistryLocation()) {
+		return nil, nil
+	}
+	servers, _, err := fetchMCPRegistry()
+	if err != nil {
+		return nil, err
+	}
+	var statuses []mcpSyncStatus
+	for _, m := range state.MCPServers {
+		st := mcpSyncStatus{Name: m.Name, Key: m.Key, CurrentVersion: m.Version}
+		i := slices.IndexFunc(servers, func(s mcpServer) bool { return s.Name == m.Name })
+		switch {
+		case i < 0 || servers[i].Status == mcpStatusDeleted:
+			st.Status = mcpSyncRemoved
+		case servers[i].Status == mcpStatusDeprecated:
+			st.Status, st.Version = mcpSyncDeprecated, servers[i].Version
+		case servers[i].Version != m.Version:
+			st.Status, st.Version, st.server = mcpSyncUpdate, servers[i].Version, &servers[i]
+		default:
+			continue
+		}
+		statuses = append(statuses, st)
+	}
+	return statuses, nil
+}
+
+func hasMCPUpdates(statuses []mcpSyncStatus) bool {
+	return slices.ContainsFunc(statuses, func(m mcpSyncStatus) bool { return m.Status == mcpSyncUpdate })
+}
+
+// applyMCPUpdate rewrites the entries of an updated server, keeping the
+// environment values they have.
+func applyMCPUpdate(scope *InstallScope, st mcpSyncStatus) error {
+	state, err := readScopedState(scope)
+	if err != nil {
+		return err
+	}
+	m := installedMCPServer(state, st.Name)
+	if m == nil || st.server == nil {
+		return fmt.Errorf("MCP server %s is not tracked", st.Name)
+	}
+	l, err := planMCPServer(*st.server)
+	if err != nil {
+		return err
+	}
+	var clients []mcpClient
+	known := map[string]string{}
+	for _, id := range m.Clients {
+		c, ok := mcpClientByID(scope, id)
+		if !ok {
+			continue
+		}
+		clients = append(clients, c)
+		if cfg, err := readMCPConfig(c.Path); err == nil {
+			for name, v := range c.env(cfg, m.Key) {
+				if known[name] == "" && !strings.HasPrefix(v, "${input:") {
+					known[name] = v
+				}
+			}
+		}
+	}
+	env, err := mcpEnvValues(m.Key, l, known, !scope.IsUser())
+	if err != nil {
+		return err
+	}
+	if err := writeMCPServer(scope, *st.server, l, env, clients); err != nil {
+		return err
+	}
+	m.Version = st.server.Version
+	m.Env = requiredEnvNames(l)
+	return writeScopedState(scope, state)
+}
+
+// printMCPSyncNotices reports deprecated and removed servers; sync leaves
+// them in place for the user to remove.
+func printMCPSyncNotices(w io.Writer, statuses []mcpSyncStatus) {
+	for _, m := range statuses {
+		switch m.Status {
+		case mcpSyncDeprecated:
+			fmt.Fprintf(w, "%s MCP server %s is deprecated in the registry. Remove it with %s\n",
+				yellow("⚠"), bold(m.Key), bold("nav-pilot mcp remove "+m.Key))
+		case mcpSyncRemoved:
+			fmt.Fprintf(w, "%s MCP server %s is no longer in the registry. Remove it with %s\n",
+				yellow("⚠"), bold(m.Key), bold("nav-pilot mcp remove "+m.Key))
+		}
+	}
+}
@@ -0,0 +1,320 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+)
+
+// mcpRegistryEnv points nav-pilot at another MCP registry: a base URL, or a
+// local file in the registry's allowlist.json or /v0.1/servers format.
+const mcpRegistryEnv = "NAV_PILOT_MCP_REGISTRY"
+
+// defaultMCPRegistry is Nav's registry of approved MCP servers
+// (apps/mcp-registry).
+const defaultMCPRegistry = "https://mcp-registry.nav.no"
+
+// Server statuses in the registry.
+const (
+	mcpStatusActive     = "active"
+	mcpStatusDeprecated = "deprecated"
+	mcpStatusDeleted    = "deleted"
+)
+
+// mcpRegistryPageLimit caps how many pages of /v0.1/servers are followed.
+const mcpRegistryPageLimit = 50
+
+// The registry types mirror apps/mcp-registry/types.go, keeping only what
+// nav-pilot needs to write client configs.
+
+type mcpTransport struct {
+	Type string `json:"type"` // "streamable-http", "sse" or "stdio"
+	URL  string `json:"url,omitempty"`
+}
+
+type mcpEnvVar struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	IsRequired  bool   `json:"isRequired,omitempty"`
+	IsSecret    bool   `json:"isSecret,omitempty"`
+}
+
+type mcpArgument struct {
+	Type  string `json:"type"` // "named" or "positional"
+	Name  string `json:"name,omitempty"`
+	Value string `json:"value,omitempty"`
+}
+
+type mcpPackage struct {
+	RegistryType         string        `json:"registryType"` // "npm", "pypi", "oci", "nuget" or "mcpb"
+	Identifier           string        `json:"identifier"`
+	Version              string        `json:"version,omitempty"`
+	RuntimeHint          string        `json:"runtimeHint,omitempty"`
+	Transport            mcpTransport  `json:"transport"`
+	EnvironmentVariables []mcpEnvVar   `json:"environmentVariables,omitempty"`
+	PackageArguments     []mcpArgument `json:"packageArguments,omitempty"`
+	RuntimeArguments     []mcpArgument `json:"runtimeArguments,omitempty"`
+}
+
+// mcpServer is one server in the registry.
+type mcpServer struct {
+	Name        string         `json:"name"` // reverse-DNS, e.g. "io.github.navikt/github-mcp"
+	Description string         `json:"description"`
+	Version     string         `json:"version"`
+	Status      string         `json:"status,omitempty"`
+	Remotes     []mcpTransport `json:"remotes,omitempty"`
+	Packages    []mcpPackage   `json:"packages,omitempty"`
+}
+
+// Key is the server's entry key in client configs: the last segment of its
+// name.
+func (s mcpServer) Key() string {
+	return mcpKey(s.Name)
+}
+
+func mcpKey(name string) string {
+	return name[strings.LastIndex(name, "/")+1:]
+}
+
+// mcpRegistryEntry is an entry of a server list in either format: the API
+// wraps the server in "server" with its status in _meta, allowlist.json has
+// the server fields and status inline.
+type mcpRegistryEntry struct {
+	mcpServer
+	Server *mcpServer `json:"server"`
+	Meta   struct {
+		Official *struct {
+			Status   string `json:"status"`
+			IsLatest bool   `json:"isLatest"`
+		} `json:"io.modelcontextprotocol.registry/official"`
+	} `json:"_meta"`
+}
+
+type mcpServerList struct {
+	Servers  []mcpRegistryEntry `json:"servers"`
+	Metadata struct {
+		NextCursor string `json:"nextCursor"`
+	} `json:"metadata"`
+}
+
+// mcpRegistryLocation returns the configured registry.
+func mcpRegistryLocation() string {
+	if loc := strings.TrimSpace(os.Getenv(mcpRegistryEnv)); loc != "" {
+		return loc
+	}
+	return defaultMCPRegistry
+}
+
+func isRegistryURL(loc string) bool {
+	return strings.HasPrefix(loc, "https://") || strings.HasPrefix(loc, "http://")
+}
+
+// fetchMCPRegistry returns the latest version of every server in the
+// registry, deleted ones included, with the registry location.
+func fetchMCPRegistry() ([]mcpServer, string, error) {
+	loc := mcpRegistryLocation()
+	var entries []mcpRegistryEntry
+	if isRegistryURL(loc) {
+		cursor := ""
+		for page := 0; ; page++ {
+			list, err := fetchMCPRegistryPage(loc, cursor)
+			if err != nil {
+				return nil, loc, err
+			}
+			entries = append(entries, list.Servers...)
+			cursor = list.Metadata.NextCursor
+			if cursor == "" || page == mcpRegistryPageLimit {
+				break
+			}
+		}
+	} else {
+		data, err := os.ReadFile(loc)
+		if err != nil {
+			return nil, loc, fmt.Errorf("reading MCP registry: %w", err)
+		}
+		var list mcpServerList
+		if err := json.Unmarshal(data, &list); err != nil {
+			return nil, loc, fmt.Errorf("parsing MCP registry %s: %w", loc, err)
+		}
+		entries = list.Servers
+	}
+
+	var servers []mcpServer
+	for _, e := range entries {
+		s := e.mcpServer
+		if e.Server != nil {
+			s = *e.Server
+			if official := e.Meta.Official; official != nil {
+				if !official.IsLatest {
+					continue
+				}
+				s.Status = official.Status
+			}
+		}
+		if s.Name == "" {
+			continue
+		}
+		if s.Status == "" {
+			s.Status = mcpStatusActive
+		}
+		servers = append(servers, s)
+	}
+	return servers, loc, nil
+}
+
+func fetchMCPRegistryPage(base, cursor string) (*mcpServerList, error) {
+	u := strings.TrimSuffix(base, "/") + "/v0.1/servers"
+	if cursor != "" {
+		u += "?cursor=" + url.QueryEscape(cursor)
+	}
+	resp, err := httpClient.Get(u)
+	if err != nil {
+		return nil, fmt.Errorf("fetching MCP registry: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("fetching MCP registry: %s returned %s", u, resp.Status)
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, 10<<20))
+	if err != nil {
+		return nil, fmt.Errorf("fetching MCP registry: %w", err)
+	}
+	var list mcpServerList
+	if err := json.Unmarshal(data, &list); err != nil {
+		return nil, fmt.Errorf("parsing MCP registry response: %w", err)
+	}
+	return &list, nil
+}
+
+// findMCPServer looks a server up by its full name or by its key. Deleted
+// servers are not found.
+func findMCPServer(servers []mcpServer, name string) (*mcpServer, error) {
+	var matches []*mcpServer
+	var keys []string
+	for i := range servers {
+		s := &servers[i]
+		if s.Status == mcpStatusDeleted {
+			continue
+		}
+		if s.Name == name {
+			return s, nil
+		}
+		if s.Key() == name {
+			matches = append(matches, s)
+		}
+		keys = append(keys, s.Key())
+	}
+	switch len(matches) {
+	case 0:
+		if hint := suggest(name, keys); hint != "" {
+			return nil, fmt.Errorf("MCP server %q not found in the registry. Did you mean %s?", name, hint)
+		}
+		return nil, fmt.Errorf("MCP server %q not found in the registry. Run 'nav-pilot mcp list' to see available servers", name)
+	case 1:
+		return matches[0], nil
+	}
+	names := make([]string, len(matches))
+	for i, s := range matches {
+		names[i] = s.Name
+	}
+	return nil, fmt.Errorf("%q matches several MCP servers; use the full name: %s", name, strings.Join(names, ", "))
+}
+
+// mcpLaunch is how a client reaches a server: a remote URL, or a command it
+// starts and talks to over stdio.
+type mcpLaunch struct {
+	Remote  *mcpTransport
+	Command string
+	Args    []string
+	Env     []mcpEnvVar
+}
+
+// planMCPServer picks how clients should run s. Remotes are preferred: they
+// need nothing installed locally.
+func planMCPServer(s mcpServer) (mcpLaunch, error) {
+	for _, r := range s.Remotes {
+		if r.Type == "streamable-http" || r.Type == "sse" {
+			return mcpLaunch{Remote: &r}, nil
+		}
+	}
+	for _, p := range s.Packages {
+		if p.Transport.Type != "" && p.Transport.Type != "stdio" {
+			continue
+		}
+		if l, ok := packageLaunch(p); ok {
+			return l, nil
+		}
+	}
+	return mcpLaunch{}, fmt.Errorf("%s has no remote or package nav-pilot can run (supported: npm, pypi, oci, nuget)", s.Name)
+}
+
+// packageLaunch builds the command that runs a package: runtime arguments
+// go to the runner, package arguments after the package.
+func packageLaunch(p mcpPackage) (mcpLaunch, bool) {
+	l := mcpLaunch{Env: p.EnvironmentVariables}
+	runtimeArgs := mcpArgs(p.RuntimeArguments)
+	id := p.Identifier
+	switch p.RegistryType {
+	case "npm":
+		if p.Version != "" {
+			id += "@" + p.Version
+		}
+		l.Command = "npx"
+		l.Args = append(runtimeArgs, "-y", id)
+	case "pypi":
+		if p.Version != "" {
+			id += "==" + p.Version
+		}
+		l.Command = "uvx"
+		l.Args = append(runtimeArgs, id)
+	case "oci":
+		if p.Version != "" {
+			id += ":" + p.Version
+		}
+		// docker only passes on the variables it is told about.
+		l.Command = "docker"
+		l.Args = append([]string{"run", "-i", "--rm"}, runtimeArgs...)
+		for _, v := range p.EnvironmentVariables {
+			l.Args = append(l.Args, "-e", v.Name)
+		}
+		l.Args = append(l.Args, id)
+	case "nuget":
+		if p.Version != "" {
+			id += "@" + p.Version
+		}
+		l.Command = "dnx"
+		l.Args = append(runtimeArgs, id, "--yes")
+	default:
+		return mcpLaunch{}, false
+	}
+	if p.RuntimeHint != "" {
+		l.Command = p.RuntimeHint
+	}
+	l.Args = append(l.Args, mcpArgs(p.PackageArguments)...)
+	return l, true
+}
+
+func mcpArgs(args []mcpArgument) []string {
+	var out []string
+	for _, a := range args {
+		if a.Type == "named" {
+			out = append(out, a.Name)
+		}
+		if a.Value != "" {
+			out = append(out, a.Value)
+		}
+	}
+	return out
+}
+
+// transportLabel describes a launch for list output.
+func (l mcpLaunch) transportLabel() string {
+	if l.Remote != nil {
+		return l.Remote.Type
+	}
+	return "stdio (" + l.Command + ")"
+}
@@ -0,0 +1,361 @@
+package cli
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+)
+
+// mcp-serve runs nav-pilot as an MCP server over stdio, so a coding agent can
+// search, install and check its own customizations. Tools call the same
+// cmd* functions as the CLI with --json; what they print becomes the tool
+// result. Tools take names, never paths: everything is written by the
+// install code, inside the repo or ~/.copilot, with its symlink checks.
+
+// mcpProtocolVersions are the MCP revisions the server speaks, newest first.
+var mcpProtocolVersions = []string{"2025-06-18", "2025-03-26", "2024-11-05"}
+
+// JSON-RPC error codes.
+const (
+	rpcParseError     = -32700
+	rpcInvalidRequest = -32600
+	rpcMethodNotFound = -32601
+	rpcInvalidParams  = -32602
+)
+
+// mcpServeLineLimit caps one JSON-RPC message.
+const mcpServeLineLimit = 4 << 20
+
+type rpcRequest struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id,omitempty"` // absent for notifications
+	Method  string          `json:"method"`
+	Params  json.RawMessage `json:"params,omitempty"`
+}
+
+type rpcResponse struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id"`
+	Result  any             `json:"result,omitempty"`
+	Error   *rpcError       `json:"error,omitempty"`
+}
+
+type rpcError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+// mcpToolArgs are the arguments of every tool; each tool's schema says which
+// it takes.
+type mcpToolArgs struct {
+	Query      string `json:"query,omitempty"`
+	Type       string `json:"type,omitempty"`
+	Name       string `json:"name,omitempty"`
+	Collection string `json:"collection,omitempty"`
+	User       bool   `json:"user,omitempty"`
+}
+
+type mcpServeTool struct {
+	Name        string         `json:"name"`
+	Description string         `json:"description"`
+	InputSchema map[string]any `json:"inputSchema"`
+
+	run func(s *mcpServeSession, args mcpToolArgs) error
+}
+
+// mcpServeSession is one client connection. Tool calls run one at a time:
+// they swap os.Stdout to capture the command's output.
+type mcpServeSession struct {
+	repoDir    string // git root the repo scope is rooted at
+	ref        string
+	sourceRepo string
+	out        io.Writer
+}
+
+var (
+	mcpUserProperty = map[string]any{
+		"type":        "boolean",
+		"description": "Use ~/.copilot (user scope) instead of the current repository.",
+	}
+	mcpTypeProperty = map[string]any{
+		"type": "string",
+		"enum": []string{"agent", "skill", "instruction", "prompt"},
+	}
+)
+
+var mcpServeTools = []mcpServeTool{
+	{
+		Name:        "search",
+		Description: "Search Nav's agents, skills, instructions and prompts. Returns ranked results with the collections they belong to.",
+		InputSchema: map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"query":      map[string]any{"type": "string", "description": "Search terms, e.g. \"kafka\"."},
+				"type":       mcpTypeProperty,
+				"collection": map[string]any{"type": "string", "description": "Only items in this collection."},
+			},
+			"required": []string{"query"},
+		},
+		run: func(s *mcpServeSession, args mcpToolArgs) error {
+			// With --json search only prints; the scope is for its install offer.
+			return cmdSearch(args.Query, args.Type, args.Collection, nil, s.ref, s.sourceRepo, true)
+		},
+	},
+	{
+		Name:        "add",
+		Description: "Install one agent, skill, instruction or prompt (and what it requires) into the repository's .github/, or into ~/.copilot with user. Files with local changes are not overwritten.",
+		InputSchema: map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"type": mcpTypeProperty,
+				"name": map[string]any{"type": "string", "description": "Item name as returned by search."},
+				"user": mcpUserProperty,
+			},
+			"required": []string{"type", "name"},
+		},
+		run: func(s *mcpServeSession, args mcpToolArgs) error {
+			if _, ok := kindByName[args.Type]; !ok {
+				return fmt.Errorf("unknown type %q. Valid types: agent, skill, instruction, prompt", args.Type)
+			}
+			if err := validateName(args.Name); err != nil {
+				return fmt.Errorf("invalid %s name: %w", args.Type, err)
+			}
+			scope, err := s.scope(args.User)
+			if err != nil {
+				return err
+			}
+			src, err := resolveSource(s.ref, s.sourceRepo)
+			if err != nil {
+				return err
+			}
+			defer src.Cleanup()
+			return cmdAddFromSource(args.Type, args.Name, src, scope, false, false, true)
+		},
+	},
+	{
+		Name:        "sync_check",
+		Description: "Check installed customizations against the source without changing anything. Lists files with updates, deletions and conflicts.",
+		InputSchema: map[string]any{
+			"type":       "object",
+			"properties": map[string]any{"user": mcpUserProperty},
+		},
+		run: func(s *mcpServeSession, args mcpToolArgs) error {
+			scope, err := s.scope(args.User)
+			if err != nil {
+				return err
+			}
+			if err := cmdSync(scope, s.ref, s.sourceRepo, lockFollow, false, true); err != errUpdatesAvailable {
+				return err
+			}
+			return nil
+		},
+	},
+	{
+		Name:        "list_installed",
+		Description: "Show what nav-pilot has installed: collection, version, source revision and file integrity.",
+		InputSchema: map[string]any{
+			"type":       "object",
+			"properties": map[string]any{"user": mcpUserProperty},
+		},
+		run: func(s *mcpServeSession, args mcpToolArgs) error {
+			scope, err := s.scope(args.User)
+			if err != nil {
+				return err
+			}
+			return cmdListInstalledScoped(scope, false, true)
+		},
+	},
+	{
+		Name:        "doctor",
+		Description: "Run nav-pilot's health checks: CLI version, installed clients, configuration and telemetry.",
+		InputSchema: map[string]any{"type": "object", "properties": map[string]any{}},
+		run: func(s *mcpServeSession, args mcpToolArgs) error {
+			return cmdDoctor(true, false, false)
+		},
+	},
+}
+
+// cmdMCPServe serves MCP on stdin and stdout until stdin closes.
+func cmdMCPServe(repoDir, ref, sourceRepo string) error {
+	// Nothing may prompt: stdin is the protocol.
+	forceNonInteractive = true
+	s := &mcpServeSession{repoDir: repoDir, ref: ref, sourceRepo: sourceRepo, out: os.Stdout}
+	return s.serve(os.Stdin)
+}
+
+// serve reads newline-delimited JSON-RPC messages from in and answers each
+// on s.out.
+func (s *mcpServeSession) serve(in io.Reader) error {
+	r := bufio.NewReaderSize(in, 64<<10)
+	for {
+		line, err := readMCPLine(r)
+		if len(bytes.TrimSpace(line)) > 0 {
+			if resp := s.handle(line); resp != nil {
+				if err := s.write(resp); err != nil {
+					return err
+				}
+			}
+		}
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+func readMCPLine(r *bufio.Reader) ([]byte, error) {
+	var line []byte
+	for {
+		chunk, err := r.ReadSlice('\n')
+		line = append(line, chunk...)
+		if len(line) > mcpServeLineLimit {
+			return nil, fmt.Errorf("mcp-serve: message larger than %d bytes", mcpServeLineLimit)
+		}
+		if err != bufio.ErrBufferFull {
+			return line, err
+		}
+	}
+}
+
+func (s *mcpServeSession) write(resp *rpcResponse) error {
+	data, err := json.Marshal(resp)
+	if err != nil {
+		return err
+	}
+	_, err = s.out.Write(append(data, '\n'))
+	return err
+}
+
+// handle answers one message. Notifications get no response.
+func (s *mcpServeSession) handle(line []byte) *rpcResponse {
+	var req rpcRequest
+	if err := json.Unmarshal(line, &req); err != nil {
+		return rpcFail(json.RawMessage("null"), rpcParseError, "parse error: "+err.Error())
+	}
+	if req.JSONRPC != "2.0" || req.Method == "" {
+		return rpcFail(req.ID, rpcInvalidRequest, "invalid request")
+	}
+	if len(req.ID) == 0 {
+		return nil
+	}
+
+	switch req.Method {
+	case "initialize":
+		var params struct {
+			ProtocolVersion string `json:"protocolVersion"`
+		}
+		json.Unmarshal(req.Params, &params)
+		version := mcpProtocolVersions[0]
+		if slices.Contains(mcpProtocolVersions, params.ProtocolVersion) {
+			version = params.ProtocolVersion
+		}
+		return rpcOK(req.ID, map[string]any{
+			"protocolVersion": version,
+			"capabilities":    map[string]any{"tools": map[string]any{}},
+			"serverInfo":      map[string]any{"name": "nav-pilot", "version": Version},
+			"instructions": "Manage Nav's Copilot customizations (agents, skills, instructions, prompts) for the current repository, " +
+				"or for ~/.copilot with user set. Use search to find items, add to install one, sync_check to see available updates.",
+		})
+	case "ping":
+		return rpcOK(req.ID, map[string]any{})
+	case "tools/list":
+		return rpcOK(req.ID, map[string]any{"tools": mcpServeTools})
+	case "tools/call":
+		var params struct {
+			Name      string          `json:"name"`
+			Arguments json.RawMessage `json:"arguments"`
+		}
+		if err := json.Unmarshal(req.Params, &params); err != nil {
+			return rpcFail(req.ID, rpcInvalidParams, "invalid params: "+err.Error())
+		}
+		i := slices.IndexFunc(mcpServeTools, func(t mcpServeTool) bool { return t.Name == params.Name })
+		if i < 0 {
+			return rpcFail(req.ID, rpcInvalidParams, fmt.Sprintf("unknown tool: %q", params.Name))
+		}
+		var args mcpToolArgs
+		if len(params.Arguments) > 0 && string(params.Arguments) != "null" {
+			dec := json.NewDecoder(bytes.NewReader(params.Arguments))
+			dec.DisallowUnknownFields()
+			if err := dec.Decode(&args); err != nil {
+				return rpcFail(req.ID, rpcInvalidParams, fmt.Sprintf("invalid arguments for %s: %v", params.Name, err))
+			}
+		}
+		return rpcOK(req.ID, s.callTool(mcpServeTools[i], args))
+	default:
+		return rpcFail(req.ID, rpcMethodNotFound, "method not found: "+req.Method)
+	}
+}
+
+// callTool runs a tool and wraps what it printed, and its error, as the
+// tool result. A failing tool is a result with isError, not a protocol
+// error, so the agent sees why.
+func (s *mcpServeSession) callTool(tool mcpServeTool, args mcpToolArgs) map[string]any {
+	output, err := captureToolOutput(func() error { return tool.run(s, args) })
+	text := strings.TrimSpace(output)
+	if err != nil {
+		if text != "" {
+			text += "\n\n"
+		}
+		text += "Error: " + err.Error()
+	}
+	return map[string]any{
+		"content": []map[string]any{{"type": "text", "text": text}},
+		"isError": err != nil,
+	}
+}
+
+// scope returns the scope a tool writes to: the repository the server was
+// started in, or ~/.copilot.
+func (s *mcpServeSession) scope(user bool) (*InstallScope, error) {
+	if user {
+		return ScopeUser()
+	}
+	if !isGitRepo(s.repoDir) {
+		return nil, fmt.Errorf("%s is not a git repository; set user to use ~/.copilot", s.repoDir)
+	}
+	return ScopeRepo(s.repoDir), nil
+}
+
+// captureToolOutput runs fn with os.Stdout redirected and returns what it
+// printed. The protocol keeps writing to the original stdout.
+func captureToolOutput(fn func() error) (output string, err error) {
+	r, w, pipeErr := os.Pipe()
+	if pipeErr != nil {
+		return "", pipeErr
+	}
+	orig := os.Stdout
+	os.Stdout = w
+	done := make(chan []byte)
+	go func() {
+		data, _ := io.ReadAll(r)
+		done <- data
+	}()
+	defer func() {
+		os.Stdout = orig
+		w.Close()
+		output = string(<-done)
+		r.Close()
+		if p := recover(); p != nil {
+			err = fmt.Errorf("internal error: %v", p)
+		}
+	}()
+	return "", fn()
+}
+
+func rpcOK(id json.RawMessage, result any) *rpcResponse {
+	return &rpcResponse{JSONRPC: "2.0", ID: id, Result: result}
+}
+
+func rpcFail(id json.RawMessage, code int, message string) *rpcResponse {
+	if len(id) == 0 {
+		id = json.RawMessage("null")
+	}
+	return &rpcResponse{JSONRPC: "2.0", ID: id, Error: &rpcError{Code: code, Message: message}}
+}
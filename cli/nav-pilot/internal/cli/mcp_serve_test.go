@@ -0,0 +1,138 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+type mcpServeReply struct {
+	ID     json.RawMessage `json:"id"`
+	Result struct {
+		ProtocolVersion string `json:"protocolVersion"`
+		Tools           []struct {
+			Name string `json:"name"`
+		} `json:"tools"`
+		Content []struct {
+			Type string `json:"type"`
+			Text string `json:"text"`
+		} `json:"content"`
+		IsError bool `json:"isError"`
+	} `json:"result"`
+	Error *rpcError `json:"error"`
+}
+
+// serveMCP feeds messages to a session rooted in repoDir and returns the
+// replies in order.
+func serveMCP(t *testing.T, repoDir string, messages ...string) []mcpServeReply {
+	t.Helper()
+	var out bytes.Buffer
+	s := &mcpServeSession{repoDir: repoDir, out: &out}
+	if err := s.serve(strings.NewReader(strings.Join(messages, "\n") + "\n")); err != nil {
+		t.Fatalf("serve: %v", err)
+	}
+	var replies []mcpServeReply
+	for _, line := range strings.Split(strings.TrimSpace(out.String()), "\n") {
+		if line == "" {
+			continue
+		}
+		var r mcpServeReply
+		if err := json.Unmarshal([]byte(line), &r); err != nil {
+			t.Fatalf("parsing reply %q: %v", line, err)
+		}
+		replies = append(replies, r)
+	}
+	return replies
+}
+
+func TestMCPServe_Protocol(t *testing.T) {
+	searchFixture(t)
+
+	replies := serveMCP(t, t.TempDir(),
+		`{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"2025-03-26","capabilities":{},"clientInfo":{"name":"test"}}}`,
+		`{"jsonrpc":"2.0","method":"notifications/initialized"}`,
+		`{"jsonrpc":"2.0","id":2,"method":"tools/list"}`,
+		`{"jsonrpc":"2.0","id":3,"method":"tools/call","params":{"name":"search","arguments":{"query":"kafka","type":"skill"}}}`,
+		`{"jsonrpc":"2.0","id":4,"method":"tools/call","params":{"name":"rm","arguments":{}}}`,
+		`{"jsonrpc":"2.0","id":5,"method":"tools/call","params":{"name":"search","arguments":{"query":"kafka","path":"/etc"}}}`,
+		`{"jsonrpc":"2.0","id":6,"method":"resources/list"}`,
+		`not json`,
+	)
+	if len(replies) != 7 {
+		t.Fatalf("got %d replies, want 7 (no reply to the notification): %+v", len(replies), replies)
+	}
+
+	if replies[0].Result.ProtocolVersion != "2025-03-26" {
+		t.Errorf("protocolVersion = %q, want the client's", replies[0].Result.ProtocolVersion)
+	}
+	var tools []string
+	for _, tool := range replies[1].Result.Tools {
+		tools = append(tools, tool.Name)
+	}
+	if got := strings.Join(tools, ","); got != "search,add,sync_check,list_installed,doctor" {
+		t.Errorf("tools = %s", got)
+	}
+
+	search := replies[2].Result
+	if search.IsError || len(search.Content) != 1 {
+		t.Fatalf("search result = %+v", search)
+	}
+	var found struct {
+		Results []searchResult `json:"results"`
+	}
+	if err := json.Unmarshal([]byte(search.Content[0].Text), &found); err != nil {
+		t.Fatalf("search output is not JSON: %q", search.Content[0].Text)
+	}
+	if len(found.Results) != 1 || found.Results[0].Name != "kafka" {
+		t.Errorf("search results = %+v", found.Results)
+	}
+
+	for i, want := range []int{rpcInvalidParams, rpcInvalidParams, rpcMethodNotFound, rpcParseError} {
+		r := replies[3+i]
+		if r.Error == nil || r.Error.Code != want {
+			t.Errorf("reply %s: error = %+v, want code %d", r.ID, r.Error, want)
+		}
+	}
+}
+
+func TestMCPServe_Add(t *testing.T) {
+	searchFixture(t)
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	repo := t.TempDir()
+
+	add := `{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":"add","arguments":{"type":"skill","name":"kafka"}}}`
+
+	// Not a repository: the agent is told to use user scope, nothing is written.
+	replies := serveMCP(t, repo, add)
+	if !replies[0].Result.IsError || !strings.Contains(replies[0].Result.Content[0].Text, "not a git repository") {
+		t.Fatalf("add outside a repo = %+v", replies[0].Result)
+	}
+
+	os.MkdirAll(filepath.Join(repo, ".git"), 0o755)
+	replies = serveMCP(t, repo,
+		add,
+		`{"jsonrpc":"2.0","id":2,"method":"tools/call","params":{"name":"add","arguments":{"type":"skill","name":"../../etc"}}}`,
+		`{"jsonrpc":"2.0","id":3,"method":"tools/call","params":{"name":"list_installed"}}`,
+	)
+	if r := replies[0].Result; r.IsError {
+		t.Fatalf("add = %+v", r)
+	}
+	if _, err := os.Stat(filepath.Join(repo, ".github", "skills", "kafka", "SKILL.md")); err != nil {
+		t.Errorf("skill not installed: %v", err)
+	}
+	state, err := readState(repo)
+	if err != nil || state == nil || len(state.Files) == 0 {
+		t.Fatalf("state after add = %+v, %v", state, err)
+	}
+
+	if r := replies[1].Result; !r.IsError || !strings.Contains(r.Content[0].Text, "invalid skill name") {
+		t.Errorf("add with a path = %+v", r)
+	}
+	if r := replies[2].Result; r.IsError || !strings.Contains(r.Content[0].Text, `"files": 1`) {
+		t.Errorf("list_installed = %+v", r)
+	}
+}
@@ -0,0 +1,354 @@
+package cli
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/navikt/copilot/cli/nav-pilot/internal/source"
+)
+
+// writeMCPRegistry writes a registry file in the allowlist.json format and
+// points nav-pilot at it.
+func writeMCPRegistry(t *testing.T, servers ...mcpServer) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "allowlist.json")
+	data, _ := json.Marshal(map[string]any{"servers": servers})
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv(mcpRegistryEnv, path)
+	return path
+}
+
+var (
+	testMCPRemote = mcpServer{
+		Name: "io.github.navikt/github-mcp", Description: "GitHub", Version: "1.0.0",
+		Remotes: []mcpTransport{{Type: "streamable-http", URL: "https://api.githubcopilot.com/mcp/"}},
+	}
+	testMCPPackage = mcpServer{
+		Name: "io.github.navikt/slack-mcp", Description: "Slack", Version: "2.0.0",
+		Packages: []mcpPackage{{
+			RegistryType: "npm", Identifier: "@navikt/slack-mcp", Version: "2.0.0",
+			Transport: mcpTransport{Type: "stdio"},
+			EnvironmentVariables: []mcpEnvVar{
+				{Name: "SLACK_TOKEN", Description: "Bot token", IsRequired: true, IsSecret: true},
+				{Name: "SLACK_TEAM", IsRequired: true},
+				{Name: "SLACK_DEBUG"},
+			},
+		}},
+	}
+)
+
+func readJSONFile(t *testing.T, path string) map[string]any {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var v map[string]any
+	if err := json.Unmarshal(data, &v); err != nil {
+		t.Fatalf("%s: %v", path, err)
+	}
+	return v
+}
+
+func TestFetchMCPRegistry_APIFormat(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v0.1/servers" {
+			http.NotFound(w, r)
+			return
+		}
+		official := func(status string, latest bool) map[string]any {
+			return map[string]any{"io.modelcontextprotocol.registry/official": map[string]any{"status": status, "isLatest": latest}}
+		}
+		var page map[string]any
+		if r.URL.Query().Get("cursor") == "" {
+			page = map[string]any{
+				"servers": []any{
+					map[string]any{"server": testMCPRemote, "_meta": official("active", true)},
+					map[string]any{"server": mcpServer{Name: testMCPRemote.Name, Version: "0.9.0"}, "_meta": official("active", false)},
+				},
+				"metadata": map[string]any{"nextCursor": "next"},
+			}
+		} else {
+			page = map[string]any{"servers": []any{map[string]any{"server": testMCPPackage, "_meta": official("deprecated", true)}}}
+		}
+		json.NewEncoder(w).Encode(page)
+	}))
+	defer srv.Close()
+	t.Setenv(mcpRegistryEnv, srv.URL+"/")
+
+	servers, loc, err := fetchMCPRegistry()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if loc != srv.URL+"/" {
+		t.Errorf("location = %q", loc)
+	}
+	var got []string
+	for _, s := range servers {
+		got = append(got, s.Key()+"@"+s.Version+":"+s.Status)
+	}
+	if strings.Join(got, "|") != "github-mcp@1.0.0:active|slack-mcp@2.0.0:deprecated" {
+		t.Errorf("servers = %v", got)
+	}
+}
+
+func TestFindMCPServer(t *testing.T) {
+	other := mcpServer{Name: "io.github.other/github-mcp", Version: "1.0.0"}
+	deleted := mcpServer{Name: "io.github.navikt/old-mcp", Status: mcpStatusDeleted}
+	servers := []mcpServer{testMCPRemote, other, testMCPPackage, deleted}
+
+	if s, err := findMCPServer(servers, "slack-mcp"); err != nil || s.Name != testMCPPackage.Name {
+		t.Errorf("by key: %v, %v", s, err)
+	}
+	if s, err := findMCPServer(servers, other.Name); err != nil || s.Name != other.Name {
+		t.Errorf("by full name: %v, %v", s, err)
+	}
+	if _, err := findMCPServer(servers, "github-mcp"); err == nil || !strings.Contains(err.Error(), "several") {
+		t.Errorf("ambiguous key: err = %v", err)
+	}
+	if _, err := findMCPServer(servers, "old-mcp"); err == nil {
+		t.Error("deleted server was found")
+	}
+	if _, err := findMCPServer(servers, "slak-mcp"); err == nil || !strings.Contains(err.Error(), "slack-mcp") {
+		t.Errorf("typo: err = %v, want a suggestion", err)
+	}
+}
+
+func TestPlanMCPServer(t *testing.T) {
+	pkg := func(p mcpPackage) mcpServer {
+		return mcpServer{Name: "x/y", Packages: []mcpPackage{p}}
+	}
+	tests := []struct {
+		name   string
+		server mcpServer
+		want   string
+	}{
+		{"remote preferred", mcpServer{Remotes: []mcpTransport{{Type: "sse", URL: "https://x/sse"}}, Packages: testMCPPackage.Packages}, "sse https://x/sse"},
+		{"npm", pkg(mcpPackage{RegistryType: "npm", Identifier: "pkg", Version: "1.2.0",
+			PackageArguments: []mcpArgument{{Type: "named", Name: "--port", Value: "0"}, {Type: "positional", Value: "serve"}}}),
+			"npx -y pkg@1.2.0 --port 0 serve"},
+		{"pypi", pkg(mcpPackage{RegistryType: "pypi", Identifier: "tool", Version: "3.1"}), "uvx tool==3.1"},
+		{"oci", pkg(mcpPackage{RegistryType: "oci", Identifier: "ghcr.io/navikt/mcp", Version: "v1",
+			RuntimeArguments:     []mcpArgument{{Type: "named", Name: "--network", Value: "host"}},
+			EnvironmentVariables: []mcpEnvVar{{Name: "TOKEN", IsRequired: true}}}),
+			"docker run -i --rm --network host -e TOKEN ghcr.io/navikt/mcp:v1"},
+		{"nuget", pkg(mcpPackage{RegistryType: "nuget", Identifier: "Nav.Mcp"}), "dnx Nav.Mcp --yes"},
+		{"runtime hint", pkg(mcpPackage{RegistryType: "npm", Identifier: "pkg", RuntimeHint: "bunx"}), "bunx -y pkg"},
+		{"unsupported", pkg(mcpPackage{RegistryType: "mcpb", Identifier: "https://x/y.mcpb"}), "error"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l, err := planMCPServer(tt.server)
+			got := "error"
+			switch {
+			case err != nil:
+			case l.Remote != nil:
+				got = l.Remote.Type + " " + l.Remote.URL
+			default:
+				got = strings.Join(append([]string{l.Command}, l.Args...), " ")
+			}
+			if got != tt.want {
+				t.Errorf("launch = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCmdMCP_AddAndRemoveRepo(t *testing.T) {
+	writeMCPRegistry(t, testMCPRemote, testMCPPackage)
+	forceNonInteractive = true
+	t.Cleanup(func() { forceNonInteractive = false })
+	t.Setenv("SLACK_TEAM", "nav")
+
+	scope := ScopeRepo(t.TempDir())
+	vscodePath := filepath.Join(scope.RootDir, ".vscode", "mcp.json")
+	os.MkdirAll(filepath.Dir(vscodePath), 0o755)
+	os.WriteFile(vscodePath, []byte(`{"servers":{"figma":{"type":"http","url":"https://mcp.figma.com/mcp"}},"inputs":[{"type":"promptString","id":"FIGMA"}]}`), 0o644)
+
+	captureStdout(func() {
+		if err := cmdMCP([]string{"add", "slack-mcp"}, scope, false, false); err != nil {
+			t.Fatalf("add slack-mcp: %v", err)
+		}
+		if err := cmdMCP([]string{"add", "io.github.navikt/github-mcp"}, scope, false, false); err != nil {
+			t.Fatalf("add github-mcp: %v", err)
+		}
+	})
+
+	cfg := readJSONFile(t, vscodePath)
+	servers := cfg["servers"].(map[string]any)
+	slack, _ := json.Marshal(servers["slack-mcp"])
+	want := `{"args":["-y","@navikt/slack-mcp@2.0.0"],"command":"npx","env":{"SLACK_TEAM":"nav","SLACK_TOKEN":"${input:SLACK_TOKEN}"},"type":"stdio"}`
+	if string(slack) != want {
+		t.Errorf("slack entry = %s\nwant %s", slack, want)
+	}
+	if github, _ := json.Marshal(servers["github-mcp"]); string(github) != `{"type":"http","url":"https://api.githubcopilot.com/mcp/"}` {
+		t.Errorf("github entry = %s", github)
+	}
+	if servers["figma"] == nil || len(cfg["inputs"].([]any)) != 2 {
+		t.Errorf("existing config not preserved: %v", cfg)
+	}
+
+	state, _ := readScopedState(scope)
+	if state == nil || len(state.MCPServers) != 2 {
+		t.Fatalf("state = %+v", state)
+	}
+	if m := state.MCPServers[0]; m.Key != "slack-mcp" || m.Version != "2.0.0" ||
+		strings.Join(m.Clients, ",") != "vscode" || strings.Join(m.Env, ",") != "SLACK_TOKEN,SLACK_TEAM" {
+		t.Errorf("tracked = %+v", m)
+	}
+
+	// An entry nav-pilot did not write is only replaced with --force.
+	captureStdout(func() {
+		if err := cmdMCP([]string{"remove", "github-mcp"}, scope, false, false); err != nil {
+			t.Fatalf("remove github-mcp: %v", err)
+		}
+	})
+	cfg = readJSONFile(t, vscodePath)
+	cfg["servers"].(map[string]any)["github-mcp"] = map[string]any{"type": "http", "url": "https://example.com"}
+	data, _ := json.Marshal(cfg)
+	os.WriteFile(vscodePath, data, 0o644)
+	if err := cmdMCP([]string{"add", "github-mcp"}, scope, false, true); err == nil || !strings.Contains(err.Error(), "--force") {
+		t.Errorf("add over untracked entry: err = %v", err)
+	}
+
+	captureStdout(func() {
+		if err := cmdMCP([]string{"remove", "slack-mcp"}, scope, false, false); err != nil {
+			t.Fatalf("remove slack-mcp: %v", err)
+		}
+	})
+	cfg = readJSONFile(t, vscodePath)
+	servers = cfg["servers"].(map[string]any)
+	if servers["slack-mcp"] != nil || servers["figma"] == nil || servers["github-mcp"] == nil {
+		t.Errorf("servers after remove = %v", servers)
+	}
+	if inputs, _ := json.Marshal(cfg["inputs"]); string(inputs) != `[{"id":"FIGMA","type":"promptString"}]` {
+		t.Errorf("inputs after remove = %s", inputs)
+	}
+	if _, err := os.Stat(scope.StatePath()); !os.IsNotExist(err) {
+		t.Errorf("state file left after removing the last server: %v", err)
+	}
+	if err := cmdMCP([]string{"remove", "slack-mcp"}, scope, false, false); err == nil {
+		t.Error("removing a server that is not installed succeeded")
+	}
+}
+
+func TestCmdMCP_AddUserWritesCopilotAndOpenCode(t *testing.T) {
+	writeMCPRegistry(t, testMCPPackage)
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_CONFIG_HOME", filepath.Join(home, ".config"))
+	os.MkdirAll(filepath.Join(home, ".config", "opencode"), 0o755)
+	forceNonInteractive = true
+	t.Cleanup(func() { forceNonInteractive = false })
+	scope, err := ScopeUser()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Required variables are not guessed outside a terminal.
+	if err := cmdMCP([]string{"add", "slack-mcp"}, scope, false, true); err == nil || !strings.Contains(err.Error(), "SLACK_TOKEN") {
+		t.Fatalf("add without env: err = %v", err)
+	}
+
+	t.Setenv("SLACK_TOKEN", "xoxb-1")
+	t.Setenv("SLACK_TEAM", "nav")
+	out := captureStdout(func() {
+		if err := cmdMCP([]string{"add", "slack-mcp"}, scope, false, true); err != nil {
+			t.Fatalf("add: %v", err)
+		}
+	})
+	var got struct {
+		Clients []string `json:"clients"`
+		Paths   []string `json:"paths"`
+	}
+	if err := json.Unmarshal([]byte(out), &got); err != nil {
+		t.Fatalf("parsing %q: %v", out, err)
+	}
+	if strings.Join(got.Clients, ",") != "copilot,opencode" || got.Paths[0] != "mcp-config.json" {
+		t.Errorf("output = %+v", got)
+	}
+
+	copilot, _ := json.Marshal(readJSONFile(t, filepath.Join(home, ".copilot", "mcp-config.json"))["mcpServers"])
+	if want := `{"slack-mcp":{"args":["-y","@navikt/slack-mcp@2.0.0"],"command":"npx","env":{"SLACK_TEAM":"nav","SLACK_TOKEN":"xoxb-1"},"tools":["*"],"type":"local"}}`; string(copilot) != want {
+		t.Errorf("copilot = %s\nwant %s", copilot, want)
+	}
+	opencode, _ := json.Marshal(readJSONFile(t, filepath.Join(home, ".config", "opencode", "opencode.json"))["mcp"])
+	if want := `{"slack-mcp":{"command":["npx","-y","@navikt/slack-mcp@2.0.0"],"enabled":true,"environment":{"SLACK_TEAM":"nav","SLACK_TOKEN":"xoxb-1"},"type":"local"}}`; string(opencode) != want {
+		t.Errorf("opencode = %s\nwant %s", opencode, want)
+	}
+}
+
+func TestSync_MCPServerUpdates(t *testing.T) {
+	registry := writeMCPRegistry(t, testMCPRemote, testMCPPackage)
+	forceNonInteractive = true
+	t.Cleanup(func() { forceNonInteractive = false })
+	t.Setenv("SLACK_TEAM", "nav")
+	scope := ScopeRepo(t.TempDir())
+	captureStdout(func() {
+		for _, name := range []string{"github-mcp", "slack-mcp"} {
+			if err := cmdMCP([]string{"add", name}, scope, false, true); err != nil {
+				t.Fatalf("add %s: %v", name, err)
+			}
+		}
+	})
+
+	srcDir := t.TempDir()
+	orig := resolveSourceForSync
+	t.Cleanup(func() { resolveSourceForSync = orig })
+	resolveSourceForSync = func(ref, sourceRepo string) (*source.Source, error) {
+		return &source.Source{Dir: srcDir, SHA: "abc1234"}, nil
+	}
+
+	// The registry moves slack-mcp forward and deprecates github-mcp. The
+	// team value stays as written, even without it in the environment.
+	os.Unsetenv("SLACK_TEAM")
+	next := testMCPPackage
+	next.Version = "2.1.0"
+	next.Packages = []mcpPackage{testMCPPackage.Packages[0]}
+	next.Packages[0].Version = "2.1.0"
+	deprecated := testMCPRemote
+	deprecated.Status = mcpStatusDeprecated
+	data, _ := json.Marshal(map[string]any{"servers": []mcpServer{deprecated, next}})
+	os.WriteFile(registry, data, 0o644)
+
+	var syncErr error
+	out := captureStdout(func() { syncErr = cmdSync(scope, "", "", lockFollow, false, true) })
+	if !errors.Is(syncErr, errUpdatesAvailable) {
+		t.Fatalf("sync err = %v, want errUpdatesAvailable", syncErr)
+	}
+	var result syncResult
+	if err := json.Unmarshal([]byte(out), &result); err != nil {
+		t.Fatalf("parsing %q: %v", out, err)
+	}
+	var got []string
+	for _, m := range result.MCP {
+		got = append(got, m.Key+":"+m.Status+":"+m.CurrentVersion+"→"+m.Version)
+	}
+	if strings.Join(got, "|") != "github-mcp:deprecated:1.0.0→1.0.0|slack-mcp:update:2.0.0→2.1.0" {
+		t.Errorf("mcp = %v", got)
+	}
+
+	out = captureStdout(func() { syncErr = cmdSync(scope, "", "", lockFollow, true, false) })
+	if syncErr != nil {
+		t.Fatalf("sync --apply: %v\n%s", syncErr, out)
+	}
+	if !strings.Contains(out, "nav-pilot mcp remove github-mcp") {
+		t.Errorf("deprecation not reported:\n%s", out)
+	}
+	servers := readJSONFile(t, filepath.Join(scope.RootDir, ".vscode", "mcp.json"))["servers"].(map[string]any)
+	if slack, _ := json.Marshal(servers["slack-mcp"]); !strings.Contains(string(slack), `@navikt/slack-mcp@2.1.0"`) || !strings.Contains(string(slack), `"SLACK_TEAM":"nav"`) {
+		t.Errorf("slack entry after sync = %s", slack)
+	}
+	state, _ := readScopedState(scope)
+	if m := installedMCPServer(state, "slack-mcp"); m == nil || m.Version != "2.1.0" {
+		t.Errorf("tracked after sync = %+v", m)
+	}
+}
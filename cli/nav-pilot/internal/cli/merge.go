@@ -0,0 +1,278 @@
+package cli
+
+import (
+	"bytes"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Merge outcomes recorded on syncUpdate.Merge. Empty means the local copy is
+// pristine (or has no stored base) and the source version is copied over.
+const (
+	mergeClean    = "clean"    // local edits and source changes merge without overlap
+	mergeConflict = "conflict" // overlapping edits; conflict markers are written
+)
+
+// mergeLocalLabel is the conflict-marker label for the user's side.
+const mergeLocalLabel = "local"
+
+// classifySyncUpdate decides how u would be applied without touching disk.
+// Only files with a stored base and local modifications are merged.
+func classifySyncUpdate(scope *InstallScope, sourceDir string, u syncUpdate) (string, error) {
+	outcome, _, err := mergeSyncUpdate(scope, sourceDir, u, "", false)
+	return outcome, err
+}
+
+// applySyncMerge applies u, three-way merging local edits against the stored
+// base when one exists, and records the new source content as the next base.
+// Returns the merge outcome ("" for a plain copy).
+//
+// Conflicts that leave no new markers in the file (unresolved markers from an
+// earlier sync, binary files, files deleted on one side and changed on the
+// other) keep the old base, for the file or the directory holding it: the
+// upstream change has not been merged, and must conflict again next time
+// instead of being taken as resolved.
+func applySyncMerge(scope *InstallScope, sourceDir string, u syncUpdate, remoteLabel string) (string, error) {
+	localPath := filepath.Join(scope.RootDir, u.Path)
+	if err := journalTrack(scope, localPath); err != nil {
+		return "", err
+	}
+	outcome, keepBase, err := mergeSyncUpdate(scope, sourceDir, u, remoteLabel, true)
+	if err != nil {
+		return outcome, err
+	}
+	if outcome == "" {
+		if err := applySyncUpdate(scope, sourceDir, u); err != nil {
+			return "", err
+		}
+	}
+	if keepBase {
+		return outcome, nil
+	}
+	if err := saveSourceBase(scope, u.Path, u.sourceFull(sourceDir), strings.HasSuffix(u.Path, "/"), u.Subproject); err != nil {
+		return outcome, err
+	}
+	return outcome, nil
+}
+
+// mergeSyncUpdate merges one update in place when write is set; otherwise it
+// only reports the outcome. keepBase reports a conflict left without markers.
+func mergeSyncUpdate(scope *InstallScope, sourceDir string, u syncUpdate, remoteLabel string, write bool) (outcome string, keepBase bool, err error) {
+	isDir := strings.HasSuffix(u.Path, "/")
+	if !hasBase(scope, u.Path) {
+		return "", false, nil
+	}
+	basePath := baseStorePath(scope, u.Path)
+	localPath := filepath.Join(scope.RootDir, u.Path)
+	remotePath := u.sourceFull(sourceDir)
+
+	baseHash, baseErr := comparableArtifactHash(basePath, isDir)
+	localHash, localErr := comparableArtifactHash(localPath, isDir)
+	if baseErr == nil && localErr == nil && baseHash == localHash {
+		return "", false, nil // local copy is pristine: fast-forward
+	}
+
+	m := &artifactMerge{boundary: scope.RootDir, remoteLabel: remoteLabel, write: write, subproject: u.Subproject}
+	if isDir {
+		if err := m.mergeDir(basePath, localPath, remotePath); err != nil {
+			return "", false, err
+		}
+	} else if err := m.mergeFile(basePath, localPath, remotePath); err != nil {
+		return "", false, err
+	}
+	if m.conflict {
+		return mergeConflict, m.keepBase, nil
+	}
+	return mergeClean, false, nil
+}
+
+// artifactMerge carries state for one file or directory merge.
+type artifactMerge struct {
+	boundary    string
+	remoteLabel string
+	write       bool
+	conflict    bool
+	keepBase    bool   // a conflict was left without writing markers
+	subproject  string // renders the remote side for subproject instructions
+}
+
+// mergeFile merges a single file. A missing base is treated as empty.
+func (m *artifactMerge) mergeFile(basePath, localPath, remotePath string) error {
+	base, err := os.ReadFile(basePath)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	local, err := os.ReadFile(localPath)
+	if err != nil {
+		return err
+	}
+	remote, err := renderedSource(remotePath, m.subproject)
+	if err != nil {
+		return err
+	}
+
+	if hasConflictMarkers(local) {
+		// Unresolved markers from an earlier sync: leave the file alone.
+		m.conflict, m.keepBase = true, true
+		return nil
+	}
+	if isBinaryContent(base) || isBinaryContent(local) || isBinaryContent(remote) {
+		switch {
+		case bytes.Equal(local, base) || bytes.Equal(local, remote):
+			return m.writeFile(localPath, remote)
+		case bytes.Equal(remote, base):
+			return nil
+		default:
+			m.conflict, m.keepBase = true, true // no markers in binary content; keep local
+			return nil
+		}
+	}
+
+	merged, conflict := merge3(base, local, remote, mergeLocalLabel, m.remoteLabel)
+	if conflict {
+		m.conflict = true
+	}
+	if bytes.Equal(merged, local) {
+		return nil
+	}
+	return m.writeFile(localPath, merged)
+}
+
+// mergeDir merges a directory artifact (skills, prompt dirs) file by file,
+// including files added or removed on either side.
+func (m *artifactMerge) mergeDir(baseDir, localDir, remoteDir string) error {
+	baseFiles := listRelFiles(baseDir)
+	localFiles := listRelFiles(localDir)
+	remoteFiles := listRelFiles(remoteDir)
+
+	all := make(map[string]bool)
+	for _, set := range []map[string]bool{baseFiles, localFiles, remoteFiles} {
+		for rel := range set {
+			all[rel] = true
+		}
+	}
+	rels := make([]string, 0, len(all))
+	for rel := range all {
+		rels = append(rels, rel)
+	}
+	sort.Strings(rels)
+
+	for _, rel := range rels {
+		b, l, r := baseFiles[rel], localFiles[rel], remoteFiles[rel]
+		basePath := filepath.Join(baseDir, rel)
+		localPath := filepath.Join(localDir, rel)
+		remotePath := filepath.Join(remoteDir, rel)
+
+		switch {
+		case l && r:
+			if err := m.mergeFile(basePath, localPath, remotePath); err != nil {
+				return err
+			}
+		case r && !b:
+			// Added upstream.
+			data, err := os.ReadFile(remotePath)
+			if err != nil {
+				return err
+			}
+			if err := m.writeFile(localPath, data); err != nil {
+				return err
+			}
+		case l && b:
+			// Removed upstream: drop it unless it was edited locally.
+			if sameFile(basePath, localPath) {
+				if m.write {
+					if err := os.Remove(localPath); err != nil && !os.IsNotExist(err) {
+						return err
+					}
+				}
+			} else {
+				m.conflict, m.keepBase = true, true
+			}
+		case r && b:
+			// Removed locally: conflict only if upstream changed it since.
+			if !sameFile(basePath, remotePath) {
+				m.conflict, m.keepBase = true, true
+			}
+		}
+		// l && !b && !r: local addition, kept as is.
+	}
+	return nil
+}
+
+func (m *artifactMerge) writeFile(path string, data []byte) error {
+	if !m.write {
+		return nil
+	}
+	if err := checkSymlink(path, m.boundary); err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".nav-pilot-merge-*")
+	if err != nil {
+		return err
+	}
+	tmpPath := tmp.Name()
+	defer os.Remove(tmpPath)
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmpPath, path)
+}
+
+// listRelFiles returns the regular files under dir as slash-separated relative paths.
+func listRelFiles(dir string) map[string]bool {
+	files := make(map[string]bool)
+	_ = filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || !d.Type().IsRegular() {
+			return nil
+		}
+		if rel, relErr := filepath.Rel(dir, path); relErr == nil {
+			files[filepath.ToSlash(rel)] = true
+		}
+		return nil
+	})
+	return files
+}
+
+// artifactHasConflictMarkers reports whether a file, or any file in a
+// directory artifact, still contains unresolved conflict markers.
+func artifactHasConflictMarkers(path string, isDir bool) bool {
+	if !isDir {
+		data, err := os.ReadFile(path)
+		return err == nil && hasConflictMarkers(data)
+	}
+	for rel := range listRelFiles(path) {
+		if artifactHasConflictMarkers(filepath.Join(path, filepath.FromSlash(rel)), false) {
+			return true
+		}
+	}
+	return false
+}
+
+func sameFile(a, b string) bool {
+	da, errA := os.ReadFile(a)
+	db, errB := os.ReadFile(b)
+	return errA == nil && errB == nil && bytes.Equal(da, db)
+}
+
+// mergeRemoteLabel returns the conflict-marker label for the source side,
+// e.g. "navikt/copilot@abc1234".
+func mergeRemoteLabel(src *Source, u syncUpdate) string {
+	layers := src.Layers()
+	layer := layers[0]
+	for _, l := range layers {
+		if u.Source != "" && l.Repo == u.Source {
+			layer = l
+		}
+	}
+	return repoLabel(layer.Repo) + "@" + layer.SHA
+}
@@ -0,0 +1,297 @@
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/navikt/copilot/cli/nav-pilot/internal/source"
+)
+
+// mergeFixture installs a one-agent collection and a one-skill collection from
+// a temp source and points sync at the same directory.
+func mergeFixture(t *testing.T, agent string) (scope *InstallScope, srcDir string) {
+	t.Helper()
+	dir := t.TempDir()
+	os.MkdirAll(filepath.Join(dir, ".git"), 0o755)
+	srcDir = t.TempDir()
+
+	os.MkdirAll(filepath.Join(srcDir, "collections", "backend"), 0o755)
+	os.WriteFile(filepath.Join(srcDir, "collections", "backend", "manifest.json"),
+		[]byte(`{"name":"backend","agents":["nais"],"skills":["api-design"]}`), 0o644)
+	os.MkdirAll(filepath.Join(srcDir, "agents"), 0o755)
+	os.WriteFile(filepath.Join(srcDir, "agents", "nais.agent.md"), []byte(agent), 0o644)
+	os.MkdirAll(filepath.Join(srcDir, "skills", "api-design"), 0o755)
+	os.WriteFile(filepath.Join(srcDir, "skills", "api-design", "SKILL.md"), []byte("# API\n\nv1\n"), 0o644)
+
+	scope = ScopeRepo(dir)
+	src := &source.Source{Dir: srcDir, SHA: "v1", Version: "dev"}
+	if err := cmdInstallFromSource("backend", src, scope, false, false, false); err != nil {
+		t.Fatalf("install: %v", err)
+	}
+
+	orig := resolveSourceForSync
+	t.Cleanup(func() { resolveSourceForSync = orig })
+	resolveSourceForSync = func(ref, sourceRepo string) (*source.Source, error) {
+		return &source.Source{Dir: srcDir, SHA: "v2", Version: "dev"}, nil
+	}
+	return scope, srcDir
+}
+
+const mergeAgentV1 = "# Nais\n\nintro\n\n## Rules\n- one\n- two\n\n## Footer\nend\n"
+
+func TestInstall_SavesMergeBase(t *testing.T) {
+	scope, _ := mergeFixture(t, mergeAgentV1)
+
+	if !hasBase(scope, ".github/agents/nais.agent.md") {
+		t.Error("expected base for agent")
+	}
+	if !hasBase(scope, ".github/skills/api-design/") {
+		t.Error("expected base for skill dir")
+	}
+	data, err := os.ReadFile(filepath.Join(scope.RootDir, ".github", ".nav-pilot-base", "agents", "nais.agent.md"))
+	if err != nil || string(data) != mergeAgentV1 {
+		t.Errorf("base content = %q, %v", data, err)
+	}
+
+	if err := cmdUninstall(scope, false); err != nil {
+		t.Fatalf("uninstall: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(scope.RootDir, ".github", ".nav-pilot-base")); !os.IsNotExist(err) {
+		t.Errorf("base store should be removed on uninstall, stat err = %v", err)
+	}
+}
+
+func TestSync_MergesLocalChangesCleanly(t *testing.T) {
+	scope, srcDir := mergeFixture(t, mergeAgentV1)
+	local := filepath.Join(scope.RootDir, ".github", "agents", "nais.agent.md")
+
+	os.WriteFile(local, []byte("# Nais\n\nintro\n\n## Rules\n- one\n- two\n- team rule\n\n## Footer\nend\n"), 0o644)
+	os.WriteFile(filepath.Join(srcDir, "agents", "nais.agent.md"),
+		[]byte("# Nais\n\nintro v2\n\n## Rules\n- one\n- two\n\n## Footer\nend\n"), 0o644)
+
+	if err := cmdSync(scope, "", "", lockFollow, true, false); err != nil {
+		t.Fatalf("sync apply: %v", err)
+	}
+
+	data, _ := os.ReadFile(local)
+	want := "# Nais\n\nintro v2\n\n## Rules\n- one\n- two\n- team rule\n\n## Footer\nend\n"
+	if string(data) != want {
+		t.Errorf("merged =\n%s\nwant\n%s", data, want)
+	}
+	base, _ := os.ReadFile(baseStorePath(scope, ".github/agents/nais.agent.md"))
+	if !strings.Contains(string(base), "intro v2") || strings.Contains(string(base), "team rule") {
+		t.Errorf("base should be the new source content, got %q", base)
+	}
+	if paths := conflictStatePaths(scope); len(paths) != 0 {
+		t.Errorf("conflicts = %v, want none", paths)
+	}
+
+	// Only local edits remain: a check afterwards is up to date.
+	var err error
+	out := captureStdout(func() { err = cmdSync(scope, "", "", lockFollow, false, false) })
+	if err != nil {
+		t.Fatalf("sync check after merge err = %v, want up to date:\n%s", err, out)
+	}
+}
+
+func TestSync_ConflictWritesMarkers(t *testing.T) {
+	scope, srcDir := mergeFixture(t, mergeAgentV1)
+	local := filepath.Join(scope.RootDir, ".github", "agents", "nais.agent.md")
+
+	os.WriteFile(local, []byte("# Nais\n\nintro (local)\n\n## Rules\n- one\n- two\n\n## Footer\nend\n"), 0o644)
+	os.WriteFile(filepath.Join(srcDir, "agents", "nais.agent.md"),
+		[]byte("# Nais\n\nintro (upstream)\n\n## Rules\n- one\n- two\n\n## Footer\nend\n"), 0o644)
+
+	// Check mode reports the conflict up front.
+	var err error
+	out := captureStdout(func() { err = cmdSync(scope, "", "", lockFollow, false, true) })
+	if err != errUpdatesAvailable {
+		t.Fatalf("sync check err = %v, want errUpdatesAvailable", err)
+	}
+	if !strings.Contains(out, `"conflicts"`) || !strings.Contains(out, `"merge": "conflict"`) {
+		t.Errorf("JSON output should report the conflict:\n%s", out)
+	}
+
+	if err := cmdSync(scope, "", "", lockFollow, true, false); err != errUpdatesAvailable {
+		t.Fatalf("sync apply err = %v, want errUpdatesAvailable", err)
+	}
+	data, _ := os.ReadFile(local)
+	for _, want := range []string{"<<<<<<< local\nintro (local)\n", "=======\nintro (upstream)\n", ">>>>>>> navikt/copilot@v2\n"} {
+		if !strings.Contains(string(data), want) {
+			t.Errorf("merged file missing %q:\n%s", want, data)
+		}
+	}
+	if paths := conflictStatePaths(scope); len(paths) != 1 || paths[0] != ".github/agents/nais.agent.md" {
+		t.Errorf("conflict state = %v", paths)
+	}
+
+	// A second apply leaves unresolved markers alone.
+	if err := cmdSync(scope, "", "", lockFollow, true, false); err != errUpdatesAvailable {
+		t.Fatalf("second sync apply err = %v, want errUpdatesAvailable", err)
+	}
+	if again, _ := os.ReadFile(local); string(again) != string(data) {
+		t.Errorf("file with unresolved markers was rewritten:\n%s", again)
+	}
+
+	// Resolving the markers clears the conflict; the resolution is kept as a local edit.
+	resolved := "# Nais\n\nintro (resolved)\n\n## Rules\n- one\n- two\n\n## Footer\nend\n"
+	os.WriteFile(local, []byte(resolved), 0o644)
+	if err := cmdSync(scope, "", "", lockFollow, true, false); err != nil {
+		t.Fatalf("sync after resolve: %v", err)
+	}
+	if data, _ := os.ReadFile(local); string(data) != resolved {
+		t.Errorf("resolved file = %q, want %q", data, resolved)
+	}
+	if paths := conflictStatePaths(scope); len(paths) != 0 {
+		t.Errorf("conflicts after resolve = %v, want none", paths)
+	}
+}
+
+func TestSync_UnresolvedMarkersKeepBase(t *testing.T) {
+	scope, srcDir := mergeFixture(t, mergeAgentV1)
+	local := filepath.Join(scope.RootDir, ".github", "agents", "nais.agent.md")
+	remote := filepath.Join(srcDir, "agents", "nais.agent.md")
+
+	os.WriteFile(local, []byte("# Nais\n\nintro (local)\n\n## Rules\n- one\n- two\n\n## Footer\nend\n"), 0o644)
+	os.WriteFile(remote, []byte("# Nais\n\nintro (upstream)\n\n## Rules\n- one\n- two\n\n## Footer\nend\n"), 0o644)
+	if err := cmdSync(scope, "", "", lockFollow, true, false); err != errUpdatesAvailable {
+		t.Fatalf("sync apply err = %v, want errUpdatesAvailable", err)
+	}
+	base, _ := os.ReadFile(baseStorePath(scope, ".github/agents/nais.agent.md"))
+
+	// Upstream moves on while the markers are still there: the file is
+	// skipped, so its base must stay where it was.
+	os.WriteFile(remote, []byte("# Nais\n\nintro (upstream)\n\n## Rules\n- one\n- two\n\n## Footer\nend v3\n"), 0o644)
+	if err := cmdSync(scope, "", "", lockFollow, true, false); err != errUpdatesAvailable {
+		t.Fatalf("second sync apply err = %v, want errUpdatesAvailable", err)
+	}
+	if again, _ := os.ReadFile(baseStorePath(scope, ".github/agents/nais.agent.md")); string(again) != string(base) {
+		t.Errorf("base advanced past a skipped file:\n%s", again)
+	}
+
+	// Once resolved, the next sync still brings in the footer change.
+	os.WriteFile(local, []byte("# Nais\n\nintro (resolved)\n\n## Rules\n- one\n- two\n\n## Footer\nend\n"), 0o644)
+	if err := cmdSync(scope, "", "", lockFollow, true, false); err != nil {
+		t.Fatalf("sync after resolve: %v", err)
+	}
+	want := "# Nais\n\nintro (resolved)\n\n## Rules\n- one\n- two\n\n## Footer\nend v3\n"
+	if data, _ := os.ReadFile(local); string(data) != want {
+		t.Errorf("merged =\n%s\nwant\n%s", data, want)
+	}
+}
+
+func TestSync_MergesSkillDirectory(t *testing.T) {
+	scope, srcDir := mergeFixture(t, mergeAgentV1)
+	localSkill := filepath.Join(scope.RootDir, ".github", "skills", "api-design")
+
+	// Local: add a team note. Upstream: edit SKILL.md and add a reference.
+	os.WriteFile(filepath.Join(localSkill, "TEAM.md"), []byte("team\n"), 0o644)
+	os.WriteFile(filepath.Join(srcDir, "skills", "api-design", "SKILL.md"), []byte("# API\n\nv2\n"), 0o644)
+	os.MkdirAll(filepath.Join(srcDir, "skills", "api-design", "references"), 0o755)
+	os.WriteFile(filepath.Join(srcDir, "skills", "api-design", "references", "rest.md"), []byte("rest\n"), 0o644)
+
+	if err := cmdSync(scope, "", "", lockFollow, true, false); err != nil {
+		t.Fatalf("sync apply: %v", err)
+	}
+	for rel, want := range map[string]string{
+		"SKILL.md":           "# API\n\nv2\n",
+		"TEAM.md":            "team\n",
+		"references/rest.md": "rest\n",
+	} {
+		data, err := os.ReadFile(filepath.Join(localSkill, filepath.FromSlash(rel)))
+		if err != nil || string(data) != want {
+			t.Errorf("%s = %q (%v), want %q", rel, data, err, want)
+		}
+	}
+}
+
+func TestSync_MarkerlessConflictsKeepBase(t *testing.T) {
+	scope, srcDir := mergeFixture(t, mergeAgentV1)
+	localSkill := filepath.Join(scope.RootDir, ".github", "skills", "api-design")
+	baseSkill := filepath.Join(scope.RootDir, ".github", ".nav-pilot-base", "skills", "api-design")
+	remoteSkill := filepath.Join(srcDir, "skills", "api-design")
+	for _, dir := range []string{localSkill, baseSkill, remoteSkill} {
+		os.WriteFile(filepath.Join(dir, "logo.bin"), []byte("\x00v1"), 0o644)
+		os.WriteFile(filepath.Join(dir, "notes.md"), []byte("notes\n"), 0o644)
+	}
+
+	// A binary file changed on both sides, and a file edited locally but
+	// removed upstream: neither can carry markers.
+	os.WriteFile(filepath.Join(localSkill, "logo.bin"), []byte("\x00local"), 0o644)
+	os.WriteFile(filepath.Join(remoteSkill, "logo.bin"), []byte("\x00v2"), 0o644)
+	os.WriteFile(filepath.Join(localSkill, "notes.md"), []byte("notes (team)\n"), 0o644)
+	os.Remove(filepath.Join(remoteSkill, "notes.md"))
+
+	for i := 1; i <= 2; i++ {
+		if err := cmdSync(scope, "", "", lockFollow, true, false); err != errUpdatesAvailable {
+			t.Fatalf("sync apply %d err = %v, want errUpdatesAvailable", i, err)
+		}
+		if paths := conflictStatePaths(scope); len(paths) != 1 || paths[0] != ".github/skills/api-design/" {
+			t.Errorf("sync %d conflicts = %v, want the skill", i, paths)
+		}
+		if data, _ := os.ReadFile(filepath.Join(baseSkill, "logo.bin")); string(data) != "\x00v1" {
+			t.Errorf("sync %d advanced the base to %q", i, data)
+		}
+	}
+	if data, _ := os.ReadFile(filepath.Join(localSkill, "logo.bin")); string(data) != "\x00local" {
+		t.Errorf("local binary = %q, want it kept", data)
+	}
+
+	// Taking upstream's binary and dropping the notes resolves it.
+	os.WriteFile(filepath.Join(localSkill, "logo.bin"), []byte("\x00v2"), 0o644)
+	os.Remove(filepath.Join(localSkill, "notes.md"))
+	if err := cmdSync(scope, "", "", lockFollow, true, false); err != nil {
+		t.Fatalf("sync after resolve: %v", err)
+	}
+	if paths := conflictStatePaths(scope); len(paths) != 0 {
+		t.Errorf("conflicts after resolve = %v, want none", paths)
+	}
+}
+
+func TestSync_SkillDirectoryWithEmptyFile(t *testing.T) {
+	scope, srcDir := mergeFixture(t, mergeAgentV1)
+	localSkill := filepath.Join(scope.RootDir, ".github", "skills", "api-design")
+
+	// .gitkeep is empty in the base, locally and in the source; the local
+	// TEAM.md sends the directory through the merge.
+	os.WriteFile(filepath.Join(srcDir, "skills", "api-design", ".gitkeep"), nil, 0o644)
+	os.WriteFile(filepath.Join(localSkill, ".gitkeep"), nil, 0o644)
+	os.WriteFile(filepath.Join(scope.RootDir, ".github", ".nav-pilot-base", "skills", "api-design", ".gitkeep"), nil, 0o644)
+	os.WriteFile(filepath.Join(localSkill, "TEAM.md"), []byte("team\n"), 0o644)
+	os.WriteFile(filepath.Join(srcDir, "skills", "api-design", "SKILL.md"), []byte("# API\n\nv2\n"), 0o644)
+
+	var err error
+	captureStdout(func() { err = cmdSync(scope, "", "", lockFollow, false, false) })
+	if err != errUpdatesAvailable {
+		t.Fatalf("sync check err = %v, want errUpdatesAvailable", err)
+	}
+	if err := cmdSync(scope, "", "", lockFollow, true, false); err != nil {
+		t.Fatalf("sync apply: %v", err)
+	}
+	if data, _ := os.ReadFile(filepath.Join(localSkill, "SKILL.md")); string(data) != "# API\n\nv2\n" {
+		t.Errorf("SKILL.md = %q", data)
+	}
+}
+
+func TestSync_WithoutBaseOverwrites(t *testing.T) {
+	scope, srcDir := mergeFixture(t, mergeAgentV1)
+	local := filepath.Join(scope.RootDir, ".github", "agents", "nais.agent.md")
+
+	// Installs from before the base store existed have no base: keep the old
+	// overwrite behavior, then start tracking a base from this sync on.
+	removeBaseStore(scope)
+	os.WriteFile(local, []byte("# Nais\n\nlocal\n"), 0o644)
+	os.WriteFile(filepath.Join(srcDir, "agents", "nais.agent.md"), []byte("# Nais\n\nv2\n"), 0o644)
+
+	if err := cmdSync(scope, "", "", lockFollow, true, false); err != nil {
+		t.Fatalf("sync apply: %v", err)
+	}
+	if data, _ := os.ReadFile(local); string(data) != "# Nais\n\nv2\n" {
+		t.Errorf("local = %q, want source content", data)
+	}
+	if !hasBase(scope, ".github/agents/nais.agent.md") {
+		t.Error("sync should record a base after applying")
+	}
+}
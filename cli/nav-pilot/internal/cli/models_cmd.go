@@ -59,5 +59,39 @@ func cmdModels(jsonOutput bool) error {
 	fmt.Printf("    3. See model config: %s\n", bold("nav-pilot config explain model"))
 	fmt.Println()
 
+	printProviderPlugins(resolved.Client)
+
 	return nil
 }
+
+// printProviderPlugins lists provider plugins on PATH other than the current
+// client, so users can discover them and their models.
+func printProviderPlugins(current string) {
+	var plugins []Provider
+	for _, p := range allProviders() {
+		if pluginPath(p) != "" && p.ID() != current {
+			plugins = append(plugins, p)
+		}
+	}
+	if len(plugins) == 0 {
+		return
+	}
+	fmt.Printf("  %s\n", bold("Provider plugins"))
+	for _, p := range plugins {
+		if err := pluginError(p); err != nil {
+			fmt.Printf("    %s %s  %s\n", red("×"), bold(p.ID()), dim(err.Error()))
+			continue
+		}
+		var ids []string
+		for _, m := range p.KnownModels() {
+			ids = append(ids, m.ID)
+		}
+		models := "no curated models"
+		if len(ids) > 0 {
+			models = strings.Join(ids, ", ")
+		}
+		fmt.Printf("    %s  %s\n", bold(p.ID()), dim(models))
+	}
+	fmt.Printf("  Switch with: %s\n", bold("nav-pilot config set client <id>"))
+	fmt.Println()
+}
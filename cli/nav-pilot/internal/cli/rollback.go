@@ -0,0 +1,147 @@
+package cli
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// cmdRollback undoes the last n committed operations on scope, newest first,
+// restoring the files and state file each one replaced. `rollback list`
+// shows the journal instead. Interrupted operations are recovered first.
+func cmdRollback(scope *InstallScope, args []string, dryRun, jsonOutput bool) error {
+	n := 1
+	switch {
+	case len(args) == 1 && args[0] == "list":
+		return listRollbackJournals(scope, jsonOutput)
+	case len(args) == 1:
+		v, err := strconv.Atoi(args[0])
+		if err != nil || v < 1 {
+			return fmt.Errorf("invalid operation count %q. Usage: nav-pilot rollback [n|list]", args[0])
+		}
+		n = v
+	case len(args) > 1:
+		return fmt.Errorf("usage: nav-pilot rollback [n|list]")
+	}
+
+	if !dryRun {
+		journalMu.Lock()
+		err := recoverJournals(scope)
+		journalMu.Unlock()
+		if err != nil {
+			return err
+		}
+	}
+
+	journals, err := listJournals(scope)
+	if err != nil {
+		return fmt.Errorf("reading journal: %w", err)
+	}
+	var committed []*Journal
+	for i := len(journals) - 1; i >= 0; i-- {
+		if journals[i].Status == journalCommitted {
+			committed = append(committed, journals[i])
+		}
+	}
+	if len(committed) == 0 {
+		if jsonOutput {
+			return outputJSON(map[string]interface{}{"command": "rollback", "scope": scope.Name, "rolled_back": []string{}, "dry_run": dryRun})
+		}
+		fmt.Printf("Nothing to roll back in %s.\n", scope.Label())
+		return nil
+	}
+	if n > len(committed) {
+		return fmt.Errorf("only %d operation(s) can be rolled back in %s scope (the last %d are kept)", len(committed), scope.Name, journalKeep)
+	}
+
+	ids := []string{}
+	for _, j := range committed[:n] {
+		if !jsonOutput {
+			printJournal(j, dryRun)
+		}
+		ids = append(ids, j.ID)
+		if dryRun {
+			continue
+		}
+		if err := j.Restore(); err != nil {
+			return fmt.Errorf("rolling back %s from %s: %w", j.Command, j.StartedAt, err)
+		}
+	}
+	if !dryRun {
+		scope.CleanupDirs()
+	}
+
+	if jsonOutput {
+		return outputJSON(map[string]interface{}{"command": "rollback", "scope": scope.Name, "rolled_back": ids, "dry_run": dryRun})
+	}
+	fmt.Println()
+	if dryRun {
+		fmt.Printf("%s Would roll back %d operation(s).\n", dim("→"), n)
+	} else {
+		fmt.Printf("%s Rolled back %d operation(s) in %s.\n", green("✓"), n, scope.Label())
+	}
+	return nil
+}
+
+// printJournal prints one operation and the paths it touched.
+func printJournal(j *Journal, dryRun bool) {
+	if dryRun {
+		fmt.Println(bold(fmt.Sprintf("Would roll back: %s (%s)", j.Command, j.StartedAt)))
+	} else {
+		fmt.Println(bold(fmt.Sprintf("Rolling back: %s (%s)", j.Command, j.StartedAt)))
+	}
+	for _, e := range j.Entries {
+		if e.Existed {
+			fmt.Printf("  %s %s\n", yellow("~"), e.Path)
+		} else {
+			fmt.Printf("  %s %s\n", red("×"), e.Path)
+		}
+	}
+}
+
+// listRollbackJournals prints the kept journals for scope, newest first.
+func listRollbackJournals(scope *InstallScope, jsonOutput bool) error {
+	journals, err := listJournals(scope)
+	if err != nil {
+		return fmt.Errorf("reading journal: %w", err)
+	}
+	if jsonOutput {
+		type journalInfo struct {
+			ID        string   `json:"id"`
+			Command   string   `json:"command"`
+			StartedAt string   `json:"started_at"`
+			Status    string   `json:"status"`
+			Paths     []string `json:"paths"`
+		}
+		out := []journalInfo{}
+		for i := len(journals) - 1; i >= 0; i-- {
+			j := journals[i]
+			info := journalInfo{ID: j.ID, Command: j.Command, StartedAt: j.StartedAt, Status: j.Status, Paths: []string{}}
+			for _, e := range j.Entries {
+				info.Paths = append(info.Paths, e.Path)
+			}
+			out = append(out, info)
+		}
+		return outputJSON(map[string]interface{}{"scope": scope.Name, "journals": out})
+	}
+
+	if len(journals) == 0 {
+		fmt.Printf("No journaled operations in %s.\n", scope.Label())
+		return nil
+	}
+	fmt.Println(bold(fmt.Sprintf("Journaled operations (%s, newest first):", scope.Name)))
+	fmt.Println()
+	for i := len(journals) - 1; i >= 0; i-- {
+		j := journals[i]
+		glyph := green("✓")
+		switch j.Status {
+		case journalRolledBack:
+			glyph = dim("⊘")
+		case journalPending:
+			glyph = yellow("⚠")
+		}
+		fmt.Printf("  %s %-10s %s %s\n", glyph, j.Command, j.StartedAt, dim(fmt.Sprintf("(%d path(s), %s)", len(j.Entries), j.Status)))
+	}
+	fmt.Println()
+	fmt.Printf("Undo the latest with: %s\n", bold("nav-pilot rollback"))
+	return nil
+}
@@ -0,0 +1,281 @@
+package cli
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/navikt/copilot/cli/nav-pilot/internal/source"
+)
+
+// setupRollbackSync installs two agents tracked in state and points sync at a
+// source where nais changed and deprecated is gone.
+func setupRollbackSync(t *testing.T) (*InstallScope, string) {
+	t.Helper()
+	dir := t.TempDir()
+	sourceDir := t.TempDir()
+	mustWrite(t, filepath.Join(dir, ".github", "agents", "nais.agent.md"), "# Nais\n")
+	mustWrite(t, filepath.Join(dir, ".github", "agents", "deprecated.agent.md"), "# Deprecated\n")
+	writeState(dir, &StateFile{
+		Collection: "kotlin-backend",
+		Version:    "2026.06",
+		SourceSHA:  "old-sha",
+		Files: []InstalledFile{
+			{Path: ".github/agents/nais.agent.md", Hash: "nais-hash"},
+			{Path: ".github/agents/deprecated.agent.md", Hash: "deprecated-hash"},
+		},
+	})
+	mustWrite(t, filepath.Join(sourceDir, "agents", "nais.agent.md"), "# Nais v2\n")
+
+	orig := resolveSourceForSync
+	t.Cleanup(func() { resolveSourceForSync = orig })
+	resolveSourceForSync = func(ref, sourceRepo string) (*source.Source, error) {
+		return &source.Source{Dir: sourceDir, SHA: "new-sha", Version: "2026.07", Repo: "navikt/copilot"}, nil
+	}
+	return ScopeRepo(dir), dir
+}
+
+func TestRollback_UndoesSyncApply(t *testing.T) {
+	scope, dir := setupRollbackSync(t)
+	stateBefore, _ := os.ReadFile(scope.StatePath())
+
+	captureStdout(func() {
+		if err := cmdSync(scope, "", "", lockFollow, true, false); err != nil {
+			t.Fatalf("sync apply: %v", err)
+		}
+	})
+	if got, _ := os.ReadFile(filepath.Join(dir, ".github", "agents", "nais.agent.md")); string(got) != "# Nais v2\n" {
+		t.Fatalf("sync did not update nais: %q", got)
+	}
+
+	out := captureStdout(func() {
+		if err := cmdRollback(scope, nil, false, false); err != nil {
+			t.Fatalf("rollback: %v", err)
+		}
+	})
+	if !strings.Contains(out, "Rolling back: sync") {
+		t.Errorf("output = %s", out)
+	}
+	if got, _ := os.ReadFile(filepath.Join(dir, ".github", "agents", "nais.agent.md")); string(got) != "# Nais\n" {
+		t.Errorf("nais = %q, want the pre-sync content", got)
+	}
+	if got, _ := os.ReadFile(filepath.Join(dir, ".github", "agents", "deprecated.agent.md")); string(got) != "# Deprecated\n" {
+		t.Errorf("deprecated = %q, want it restored", got)
+	}
+	if stateAfter, _ := os.ReadFile(scope.StatePath()); string(stateAfter) != string(stateBefore) {
+		t.Errorf("state not restored:\n%s\nwant:\n%s", stateAfter, stateBefore)
+	}
+
+	// The rolled-back operation is not undone twice.
+	out = captureStdout(func() {
+		if err := cmdRollback(scope, nil, false, false); err != nil {
+			t.Fatal(err)
+		}
+	})
+	if !strings.Contains(out, "Nothing to roll back") {
+		t.Errorf("second rollback output = %s", out)
+	}
+}
+
+func TestRollback_DryRunAndList(t *testing.T) {
+	scope, dir := setupRollbackSync(t)
+	captureStdout(func() {
+		if err := cmdSync(scope, "", "", lockFollow, true, false); err != nil {
+			t.Fatal(err)
+		}
+	})
+
+	out := captureStdout(func() {
+		if err := cmdRollback(scope, nil, true, false); err != nil {
+			t.Fatal(err)
+		}
+	})
+	if !strings.Contains(out, "Would roll back: sync") || !strings.Contains(out, ".github/agents/deprecated.agent.md") {
+		t.Errorf("dry-run output = %s", out)
+	}
+	if got, _ := os.ReadFile(filepath.Join(dir, ".github", "agents", "nais.agent.md")); string(got) != "# Nais v2\n" {
+		t.Error("dry run must not restore files")
+	}
+
+	out = captureStdout(func() {
+		if err := cmdRollback(scope, []string{"list"}, false, false); err != nil {
+			t.Fatal(err)
+		}
+	})
+	if !strings.Contains(out, "sync") || !strings.Contains(out, "committed") {
+		t.Errorf("list output = %s", out)
+	}
+
+	err := cmdRollback(scope, []string{"2"}, false, false)
+	if err == nil || !strings.Contains(err.Error(), "only 1 operation(s)") {
+		t.Errorf("rollback 2 = %v", err)
+	}
+	for _, args := range [][]string{{"0"}, {"x"}, {"1", "2"}} {
+		if err := cmdRollback(scope, args, false, false); err == nil {
+			t.Errorf("rollback %v: expected error", args)
+		}
+	}
+}
+
+func TestJournal_FailedCommandIsRestored(t *testing.T) {
+	dir := t.TempDir()
+	scope := ScopeRepo(dir)
+	agent := filepath.Join(dir, ".github", "agents", "nais.agent.md")
+	mustWrite(t, agent, "# Nais\n")
+
+	run := func() (err error) {
+		tx, err := beginJournal(scope, "install")
+		if err != nil {
+			return err
+		}
+		defer tx.finish(&err)
+		if err := journalTrack(scope, agent); err != nil {
+			return err
+		}
+		mustWrite(t, agent, "# half-written\n")
+		if err := writeScopedState(scope, &StateFile{Collection: "x", Scope: "repo"}); err != nil {
+			return err
+		}
+		return errors.New("copy failed")
+	}
+	captureStderr(func() {
+		if err := run(); err == nil {
+			t.Fatal("expected error")
+		}
+	})
+
+	if got, _ := os.ReadFile(agent); string(got) != "# Nais\n" {
+		t.Errorf("agent = %q, want it restored", got)
+	}
+	if _, err := os.Stat(scope.StatePath()); !os.IsNotExist(err) {
+		t.Error("state file created by the failed command should be removed")
+	}
+}
+
+func TestJournal_RecoversInterruptedOperation(t *testing.T) {
+	dir := t.TempDir()
+	scope := ScopeRepo(dir)
+	agent := filepath.Join(dir, ".github", "agents", "nais.agent.md")
+	mustWrite(t, agent, "# Nais\n")
+
+	// Simulate a crash: the journal is opened and written, but never finished.
+	if _, err := beginJournal(scope, "sync"); err != nil {
+		t.Fatal(err)
+	}
+	if err := journalTrack(scope, agent); err != nil {
+		t.Fatal(err)
+	}
+	mustWrite(t, agent, "# half-written\n")
+	journalMu.Lock()
+	delete(activeJournals, scope.StatePath())
+	journalMu.Unlock()
+
+	var tx *journalTx
+	errOut := captureStderr(func() {
+		var err error
+		if tx, err = beginJournal(scope, "install"); err != nil {
+			t.Fatal(err)
+		}
+	})
+	var err error
+	tx.finish(&err)
+
+	if !strings.Contains(errOut, "Rolled back interrupted sync") {
+		t.Errorf("stderr = %s", errOut)
+	}
+	if got, _ := os.ReadFile(agent); string(got) != "# Nais\n" {
+		t.Errorf("agent = %q, want the pre-crash content", got)
+	}
+}
+
+func TestJournal_LeavesRunningOperationAlone(t *testing.T) {
+	scope, dir := setupRollbackSync(t)
+	agent := filepath.Join(dir, ".github", "agents", "nais.agent.md")
+
+	// Another nav-pilot (a sleeping child stands in) is mid-sync.
+	owner := exec.Command("sleep", "60")
+	if err := owner.Start(); err != nil {
+		t.Skipf("starting sleep: %v", err)
+	}
+	t.Cleanup(func() { owner.Process.Kill(); owner.Wait() })
+	tx, err := beginJournal(scope, "sync")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tx.journal.PID = owner.Process.Pid
+	if err := journalTrack(scope, agent); err != nil {
+		t.Fatal(err)
+	}
+	mustWrite(t, agent, "# half-written\n")
+	journalMu.Lock()
+	delete(activeJournals, scope.StatePath())
+	journalMu.Unlock()
+
+	// A check writes nothing, and a second writer does not roll the first back.
+	captureStdout(func() { cmdSync(scope, "", "", lockFollow, false, false) })
+	other, err := beginJournal(scope, "install")
+	if err != nil {
+		t.Fatal(err)
+	}
+	other.finish(&err)
+	if got, _ := os.ReadFile(agent); string(got) != "# half-written\n" {
+		t.Errorf("agent = %q, want the running operation's write kept", got)
+	}
+
+	// Once its process is gone, the journal is recovered.
+	owner.Process.Kill()
+	owner.Wait()
+	captureStderr(func() {
+		if err := recoverJournals(scope); err != nil {
+			t.Fatal(err)
+		}
+	})
+	if got, _ := os.ReadFile(agent); string(got) != "# Nais\n" {
+		t.Errorf("agent = %q, want it restored", got)
+	}
+}
+
+func TestSync_CheckDoesNotRecoverJournals(t *testing.T) {
+	scope, dir := setupRollbackSync(t)
+	agent := filepath.Join(dir, ".github", "agents", "nais.agent.md")
+
+	if _, err := beginJournal(scope, "sync"); err != nil {
+		t.Fatal(err)
+	}
+	if err := journalTrack(scope, agent); err != nil {
+		t.Fatal(err)
+	}
+	mustWrite(t, agent, "# half-written\n")
+	journalMu.Lock()
+	delete(activeJournals, scope.StatePath())
+	journalMu.Unlock()
+
+	var err error
+	captureStdout(func() { err = cmdSync(scope, "", "", lockFollow, false, true) })
+	if err != errUpdatesAvailable {
+		t.Fatalf("sync check err = %v", err)
+	}
+	if got, _ := os.ReadFile(agent); string(got) != "# half-written\n" {
+		t.Errorf("agent = %q, check mode must not roll back journals", got)
+	}
+}
+
+func TestJournal_NestedCommandsShareOneJournal(t *testing.T) {
+	scope := ScopeRepo(t.TempDir())
+	outer, err := beginJournal(scope, "install")
+	if err != nil || outer == nil {
+		t.Fatalf("outer = %v, %v", outer, err)
+	}
+	inner, err := beginJournal(scope, "add")
+	if err != nil || inner != nil {
+		t.Errorf("inner = %v, %v; want nil", inner, err)
+	}
+	inner.finish(&err)
+	outer.finish(&err)
+	if len(activeJournals) != 0 {
+		t.Errorf("activeJournals = %v", activeJournals)
+	}
+}
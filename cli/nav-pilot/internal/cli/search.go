@@ -0,0 +1,157 @@
+package cli
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/charmbracelet/huh"
+)
+
+// searchResultLimit is how many results search prints. --json returns all.
+const searchResultLimit = 10
+
+type searchResult struct {
+	Kind        string   `json:"kind"`
+	Name        string   `json:"name"`
+	Description string   `json:"description"`
+	Collections []string `json:"collections"`
+	Source      string   `json:"source,omitempty"`
+	Score       float64  `json:"score"`
+	Snippet     string   `json:"snippet,omitempty"`
+}
+
+// cmdSearch ranks every agent, skill, instruction and prompt in the source
+// against query. In a terminal it offers to install one of the results.
+func cmdSearch(query, itemType, collection string, scope *InstallScope, ref, sourceRepo string, jsonOutput bool) error {
+	query = strings.TrimSpace(query)
+	if query == "" {
+		return fmt.Errorf("search requires a query.\n\nUsage: nav-pilot search <query> [--type <type>] [--collection <name>]")
+	}
+	var kind *ArtifactKind
+	if itemType != "" {
+		var ok bool
+		if kind, ok = kindByName[itemType]; !ok {
+			return fmt.Errorf("unknown type %q. Valid types: agent, skill, instruction, prompt", itemType)
+		}
+	}
+
+	if !jsonOutput {
+		fmt.Println(dim("Resolving source..."))
+	}
+	src, err := resolveSource(ref, sourceRepo)
+	if err != nil {
+		return err
+	}
+	defer src.Cleanup()
+	resolver := src.Resolver()
+
+	if collection != "" {
+		names, err := resolver.Collections()
+		if err != nil {
+			return err
+		}
+		if !containsStr(names, collection) {
+			if hint := suggest(collection, names); hint != "" {
+				return fmt.Errorf("collection %q not found. Did you mean %s?", collection, hint)
+			}
+			return fmt.Errorf("collection %q not found. Run 'nav-pilot list' to see available collections", collection)
+		}
+	}
+
+	hits := resolver.SearchIndex().Search(query, func(d *SearchDoc) bool {
+		return (kind == nil || d.Kind == kind) && (collection == "" || containsStr(d.Collections, collection))
+	})
+
+	results := make([]searchResult, 0, len(hits))
+	for _, h := range hits {
+		collections := h.Doc.Collections
+		if collections == nil {
+			collections = []string{}
+		}
+		results = append(results, searchResult{
+			Kind:        h.Doc.Kind.Name,
+			Name:        h.Doc.Name,
+			Description: h.Doc.Description,
+			Collections: collections,
+			Source:      h.Doc.Source,
+			Score:       math.Round(h.Score*100) / 100,
+			Snippet:     h.Snippet,
+		})
+	}
+
+	if jsonOutput {
+		return outputJSON(map[string]interface{}{"query": query, "results": results})
+	}
+
+	fmt.Println()
+	if len(results) == 0 {
+		fmt.Printf("No results for %s.\n", bold(query))
+		return nil
+	}
+	shown := results
+	if len(shown) > searchResultLimit {
+		shown = shown[:searchResultLimit]
+	}
+	printSearchResults(shown)
+	if more := len(results) - len(shown); more > 0 {
+		fmt.Printf("%s %d more result(s). Narrow with --type or --collection.\n", dim("→"), more)
+	}
+
+	if !isInteractive() {
+		fmt.Println()
+		fmt.Printf("Install with: %s\n", bold(fmt.Sprintf("nav-pilot install %s --type %s", shown[0].Name, shown[0].Kind)))
+		return nil
+	}
+	return offerSearchInstall(shown, scope, ref, sourceRepo)
+}
+
+func printSearchResults(results []searchResult) {
+	width := 0
+	for _, r := range results {
+		width = max(width, len(r.Name))
+	}
+	for i, r := range results {
+		line := fmt.Sprintf("  %s %-11s %s", dim(fmt.Sprintf("%2d.", i+1)), r.Kind, bold(fmt.Sprintf("%-*s", width, r.Name)))
+		if len(r.Collections) > 0 {
+			line += "  " + dim("("+strings.Join(r.Collections, ", ")+")")
+		}
+		fmt.Println(line)
+		if r.Description != "" {
+			fmt.Printf("      %s\n", r.Description)
+		}
+		if r.Snippet != "" && r.Snippet != r.Description {
+			fmt.Printf("      %s\n", dim("“"+r.Snippet+"”"))
+		}
+	}
+}
+
+// offerSearchInstall lets the user pick a result to install. The top result
+// is preselected, so installing it is a single Enter.
+func offerSearchInstall(results []searchResult, scope *InstallScope, ref, sourceRepo string) error {
+	fmt.Println()
+	var choice int
+	err := searchInstallSelect(results, scope, &choice).WithTheme(navTheme()).Run()
+	if err != nil || choice < 0 {
+		return nil
+	}
+	r := results[choice]
+	fmt.Println()
+	return cmdAdd(r.Kind, r.Name, scope, ref, sourceRepo, false, false, false)
+}
+
+// searchInstallSelect builds the install picker. The cursor starts on the
+// option matching *choice, so it is set to the top result before binding.
+func searchInstallSelect(results []searchResult, scope *InstallScope, choice *int) *huh.Select[int] {
+	options := make([]huh.Option[int], 0, len(results)+1)
+	for i, r := range results {
+		options = append(options, huh.NewOption(fmt.Sprintf("%d. %s %s", i+1, r.Kind, r.Name), i))
+	}
+	options = append(options, huh.NewOption("Done", -1))
+
+	*choice = 0
+	return huh.NewSelect[int]().
+		Title("Install a result to " + scope.Label() + "?").
+		Options(options...).
+		Value(choice)
+}
@@ -0,0 +1,120 @@
+package cli
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func searchFixture(t *testing.T) {
+	t.Helper()
+	srcDir := t.TempDir()
+	os.MkdirAll(filepath.Join(srcDir, "collections", "backend"), 0o755)
+	os.WriteFile(filepath.Join(srcDir, "collections", "backend", "manifest.json"),
+		[]byte(`{"name":"backend","skills":["kafka"],"instructions":["kotlin"]}`), 0o644)
+	os.MkdirAll(filepath.Join(srcDir, "skills", "kafka"), 0o755)
+	os.WriteFile(filepath.Join(srcDir, "skills", "kafka", "SKILL.md"),
+		[]byte("---\nname: kafka\ndescription: Kafka og Rapids & Rivers\n---\n\n# Kafka\n"), 0o644)
+	os.MkdirAll(filepath.Join(srcDir, "agents"), 0o755)
+	os.WriteFile(filepath.Join(srcDir, "agents", "observability.agent.md"),
+		[]byte("---\ndescription: Metrikker\n---\n\nMonitor Kafka consumer lag.\n"), 0o644)
+	os.MkdirAll(filepath.Join(srcDir, "instructions"), 0o755)
+	os.WriteFile(filepath.Join(srcDir, "instructions", "kotlin.instructions.md"),
+		[]byte("---\napplyTo: \"**/*.kt\"\n---\n\n# Kotlin\n"), 0o644)
+
+	orig := resolveSource
+	t.Cleanup(func() { resolveSource = orig })
+	resolveSource = func(ref, sourceRepo string) (*Source, error) {
+		return &Source{Dir: srcDir, SHA: "abc1234"}, nil
+	}
+	forceNonInteractive = true
+	t.Cleanup(func() { forceNonInteractive = false })
+}
+
+func TestCmdSearch_JSON(t *testing.T) {
+	searchFixture(t)
+	scope := ScopeRepo(t.TempDir())
+
+	search := func(query, itemType, collection string) []searchResult {
+		t.Helper()
+		out := captureStdout(func() {
+			if err := cmdSearch(query, itemType, collection, scope, "", "", true); err != nil {
+				t.Fatalf("search %q: %v", query, err)
+			}
+		})
+		var got struct {
+			Results []searchResult `json:"results"`
+		}
+		if err := json.Unmarshal([]byte(out), &got); err != nil {
+			t.Fatalf("parsing %q: %v", out, err)
+		}
+		return got.Results
+	}
+
+	results := search("kafka", "", "")
+	if len(results) != 2 || results[0].Kind != "skill" || results[0].Name != "kafka" || results[1].Name != "observability" {
+		t.Fatalf("results = %+v", results)
+	}
+	if strings.Join(results[0].Collections, ",") != "backend" || results[1].Collections == nil {
+		t.Errorf("collections = %v, %v", results[0].Collections, results[1].Collections)
+	}
+	if results[1].Snippet != "Monitor Kafka consumer lag." {
+		t.Errorf("snippet = %q", results[1].Snippet)
+	}
+
+	if results := search("kafka", "agent", ""); len(results) != 1 || results[0].Name != "observability" {
+		t.Errorf("--type agent: %+v", results)
+	}
+	if results := search("kafka", "", "backend"); len(results) != 1 || results[0].Name != "kafka" {
+		t.Errorf("--collection backend: %+v", results)
+	}
+	if results := search("postgres", "", ""); len(results) != 0 {
+		t.Errorf("no match: %+v", results)
+	}
+}
+
+func TestCmdSearch_Text(t *testing.T) {
+	searchFixture(t)
+	out := captureStdout(func() {
+		if err := cmdSearch("kafka lag", "", "", ScopeRepo(t.TempDir()), "", "", false); err != nil {
+			t.Fatal(err)
+		}
+	})
+	for _, want := range []string{"1.", "observability", "Monitor Kafka consumer lag.", "nav-pilot install observability --type agent"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output should contain %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestCmdSearch_Errors(t *testing.T) {
+	searchFixture(t)
+	scope := ScopeRepo(t.TempDir())
+	for _, tt := range []struct {
+		query, itemType, collection, want string
+	}{
+		{"", "", "", "requires a query"},
+		{"kafka", "widget", "", "unknown type"},
+		{"kafka", "", "bakend", "Did you mean backend?"},
+	} {
+		err := cmdSearch(tt.query, tt.itemType, tt.collection, scope, "", "", true)
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("cmdSearch(%q, %q, %q) = %v, want %q", tt.query, tt.itemType, tt.collection, err, tt.want)
+		}
+	}
+
+	if err := run([]string{"list", "--collection", "backend"}); err == nil || !strings.Contains(err.Error(), "only supported for search") {
+		t.Errorf("--collection outside search: %v", err)
+	}
+}
+
+func TestSearchInstallSelect_PreselectsTopResult(t *testing.T) {
+	results := []searchResult{{Kind: "skill", Name: "kafka"}, {Kind: "agent", Name: "observability"}}
+	choice := -1
+	sel := searchInstallSelect(results, ScopeRepo(t.TempDir()), &choice)
+	if got := sel.GetValue(); got != 0 || choice != 0 {
+		t.Errorf("initial selection = %v (choice %d), want the top result", got, choice)
+	}
+}
@@ -0,0 +1,120 @@
+package cli
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Releases sign SHA256SUMS with minisign (legacy Ed25519 mode, `minisign -S -l`)
+// and publish the signature as SHA256SUMS.minisig. The public key is injected
+// at build time (-X main.releaseKey=...), so a tampered release cannot ship
+// its own key. The trusted comment is the release tag, which stops a valid
+// signature from an older release being replayed under a newer tag.
+
+// releasePublicKey is the minisign public key update trusts. Empty in
+// development builds. Overridable in tests.
+var releasePublicKey string
+
+const signatureSuffix = ".minisig"
+
+// errUnsigned marks a release that cannot be verified because it has no
+// signature or this build has no key. --allow-unsigned overrides it; a
+// signature that does not verify is never overridable.
+var errUnsigned = errors.New("release is not signed")
+
+type minisignPublicKey struct {
+	id  [8]byte
+	key ed25519.PublicKey
+}
+
+// parseMinisignPublicKey accepts a minisign public key file or just its
+// base64 line.
+func parseMinisignPublicKey(s string) (*minisignPublicKey, error) {
+	var line string
+	for _, l := range strings.Split(strings.TrimSpace(s), "\n") {
+		l = strings.TrimSpace(l)
+		if l != "" && !strings.HasPrefix(l, "untrusted comment:") {
+			line = l
+		}
+	}
+	raw, err := base64.StdEncoding.DecodeString(line)
+	if err != nil || len(raw) != 2+8+ed25519.PublicKeySize || string(raw[:2]) != "Ed" {
+		return nil, fmt.Errorf("invalid minisign public key")
+	}
+	pk := &minisignPublicKey{key: ed25519.PublicKey(raw[10:])}
+	copy(pk.id[:], raw[2:10])
+	return pk, nil
+}
+
+// verifyMinisign checks sig (a .minisig file) over msg and returns its
+// trusted comment.
+func verifyMinisign(pk *minisignPublicKey, msg, sig []byte) (string, error) {
+	lines := strings.Split(strings.TrimRight(string(sig), "\n"), "\n")
+	if len(lines) != 4 || !strings.HasPrefix(lines[0], "untrusted comment:") || !strings.HasPrefix(lines[2], "trusted comment: ") {
+		return "", fmt.Errorf("malformed minisign signature")
+	}
+	raw, err := base64.StdEncoding.DecodeString(strings.TrimSpace(lines[1]))
+	if err != nil || len(raw) != 2+8+ed25519.SignatureSize {
+		return "", fmt.Errorf("malformed minisign signature")
+	}
+	switch string(raw[:2]) {
+	case "Ed":
+	case "ED":
+		return "", fmt.Errorf("prehashed minisign signatures are not supported (sign with minisign -S -l)")
+	default:
+		return "", fmt.Errorf("unknown minisign signature algorithm %q", raw[:2])
+	}
+	if !bytes.Equal(raw[2:10], pk.id[:]) {
+		return "", fmt.Errorf("signed with key %X, expected %X", raw[2:10], pk.id)
+	}
+	if !ed25519.Verify(pk.key, msg, raw[10:]) {
+		return "", fmt.Errorf("signature verification failed")
+	}
+
+	trusted := strings.TrimPrefix(lines[2], "trusted comment: ")
+	global, err := base64.StdEncoding.DecodeString(strings.TrimSpace(lines[3]))
+	if err != nil || len(global) != ed25519.SignatureSize {
+		return "", fmt.Errorf("malformed minisign signature")
+	}
+	signed := append(append([]byte{}, raw[10:]...), trusted...)
+	if !ed25519.Verify(pk.key, signed, global) {
+		return "", fmt.Errorf("trusted comment signature verification failed")
+	}
+	return trusted, nil
+}
+
+// verifyReleaseSignature downloads the signature for sums and verifies it
+// against releasePublicKey and tag. It returns errUnsigned (wrapped) when the
+// release has no signature or this build has no key to check it with.
+func verifyReleaseSignature(sums []byte, tag, sigURL string) error {
+	fmt.Print("→ Verifying signature...")
+	if releasePublicKey == "" {
+		fmt.Println()
+		return fmt.Errorf("%w: this build of nav-pilot has no release key", errUnsigned)
+	}
+	pk, err := parseMinisignPublicKey(releasePublicKey)
+	if err != nil {
+		fmt.Println()
+		return err
+	}
+	sig, err := httpGet(sigURL)
+	if err != nil {
+		fmt.Println()
+		return fmt.Errorf("%w: %s has no signature (%v)", errUnsigned, tag, err)
+	}
+	trusted, err := verifyMinisign(pk, sums, sig)
+	if err != nil {
+		fmt.Println()
+		return fmt.Errorf("%s: %w", tag, err)
+	}
+	if trusted != tag {
+		fmt.Println()
+		return fmt.Errorf("signature is for %q, not %s", trusted, tag)
+	}
+	fmt.Println(" ✓")
+	return nil
+}
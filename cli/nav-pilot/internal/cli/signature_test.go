@@ -0,0 +1,151 @@
+package cli
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+// testSigner produces minisign signatures (legacy Ed25519 mode) and installs
+// its public key as releasePublicKey for the test.
+type testSigner struct {
+	id   [8]byte
+	priv ed25519.PrivateKey
+}
+
+func newTestSigner(t *testing.T) *testSigner {
+	t.Helper()
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := &testSigner{priv: priv}
+	rand.Read(s.id[:])
+	orig := releasePublicKey
+	releasePublicKey = s.publicKey(pub)
+	t.Cleanup(func() { releasePublicKey = orig })
+	return s
+}
+
+func (s *testSigner) publicKey(pub ed25519.PublicKey) string {
+	raw := append(append([]byte("Ed"), s.id[:]...), pub...)
+	return "untrusted comment: minisign public key\n" + base64.StdEncoding.EncodeToString(raw) + "\n"
+}
+
+func (s *testSigner) sign(msg []byte, trusted string) []byte {
+	sig := ed25519.Sign(s.priv, msg)
+	raw := append(append([]byte("Ed"), s.id[:]...), sig...)
+	global := ed25519.Sign(s.priv, append(append([]byte{}, sig...), trusted...))
+	return fmt.Appendf(nil, "untrusted comment: signature from minisign secret key\n%s\ntrusted comment: %s\n%s\n",
+		base64.StdEncoding.EncodeToString(raw), trusted, base64.StdEncoding.EncodeToString(global))
+}
+
+func TestVerifyMinisign(t *testing.T) {
+	s := newTestSigner(t)
+	pk, err := parseMinisignPublicKey(releasePublicKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	msg := []byte("abc  nav-pilot-linux-amd64\n")
+	sig := s.sign(msg, "nav-pilot/2026.04.13-170138-abc1234")
+
+	trusted, err := verifyMinisign(pk, msg, sig)
+	if err != nil || trusted != "nav-pilot/2026.04.13-170138-abc1234" {
+		t.Fatalf("verifyMinisign = %q, %v", trusted, err)
+	}
+	if _, err := verifyMinisign(pk, []byte("tampered"), sig); err == nil {
+		t.Error("tampered message verified")
+	}
+	forged := strings.Replace(string(sig), "2026.04.13", "2026.05.01", 1)
+	if _, err := verifyMinisign(pk, msg, []byte(forged)); err == nil {
+		t.Error("tampered trusted comment verified")
+	}
+	other := newTestSigner(t)
+	if _, err := verifyMinisign(pk, msg, other.sign(msg, "x")); err == nil || !strings.Contains(err.Error(), "signed with key") {
+		t.Errorf("signature from another key: %v", err)
+	}
+	if _, err := parseMinisignPublicKey("not a key"); err == nil {
+		t.Error("expected invalid key error")
+	}
+}
+
+func TestUpdate_RefusesUnsignedAndBadSignatures(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("USERPROFILE", home)
+	const current, next = "2026.04.01-120000-aaa1111", "2026.04.13-170138-bbb2222"
+	asset := fmt.Sprintf("nav-pilot-%s-%s", runtime.GOOS, runtime.GOARCH)
+	sums := fmt.Sprintf("%s  %s\n", sha256sum([]byte(next)), asset)
+	signer := newTestSigner(t)
+	var sig []byte
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch strings.TrimPrefix(r.URL.Path, "/nav-pilot/"+next+"/") {
+		case asset:
+			fmt.Fprint(w, next)
+		case "SHA256SUMS":
+			fmt.Fprint(w, sums)
+		case "SHA256SUMS.minisig":
+			if sig == nil {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			w.Write(sig)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+	origURL, origClient := downloadURL, httpClient
+	downloadURL, httpClient = srv.URL, srv.Client()
+	defer func() { downloadURL, httpClient = origURL, origClient }()
+	self := fakeSelf(t, current)
+
+	install := func(allowUnsigned bool) error {
+		var err error
+		captureStderr(func() {
+			captureStdout(func() { err = cmdUpdate(next, false, allowUnsigned) })
+		})
+		return err
+	}
+
+	// No signature published.
+	if err := install(false); err == nil || !strings.Contains(err.Error(), "--allow-unsigned") {
+		t.Fatalf("unsigned release: %v", err)
+	}
+	if data, _ := os.ReadFile(self); string(data) != current {
+		t.Fatal("unsigned release was installed")
+	}
+
+	// A valid signature for another release cannot be replayed. The error is
+	// a plain message; the progress line is ended before it is returned.
+	sig = signer.sign([]byte(sums), "nav-pilot/2026.03.01-120000-old0000")
+	if err := install(true); err == nil || !strings.HasPrefix(err.Error(), "signature is for") {
+		t.Fatalf("replayed signature: %v", err)
+	}
+
+	// A bad signature is refused even with the override.
+	sig = newTestSigner(t).sign([]byte(sums), "nav-pilot/"+next)
+	releasePublicKey = signer.publicKey(signer.priv.Public().(ed25519.PublicKey))
+	if err := install(true); err == nil || !strings.HasPrefix(err.Error(), "nav-pilot/"+next+": ") {
+		t.Fatalf("signature from an untrusted key: %v", err)
+	}
+	if data, _ := os.ReadFile(self); string(data) != current {
+		t.Fatal("badly signed release was installed")
+	}
+
+	// The override installs an unsigned release.
+	sig = nil
+	if err := install(true); err != nil {
+		t.Fatalf("--allow-unsigned: %v", err)
+	}
+	if data, _ := os.ReadFile(self); string(data) != next {
+		t.Errorf("binary = %q, want %s", data, next)
+	}
+}
@@ -0,0 +1,154 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Monorepo subprojects. `install --subproject <dir>` keeps the one state file
+// at the git root, but installs instructions to
+// .github/instructions/<dir>/<name>.instructions.md with applyTo rooted at
+// <dir>. The subproject is recorded per file in state and lock, so sync,
+// diff and merge render the source the same way before comparing.
+
+// normalizeSubproject validates a --subproject value (relative to the git
+// root, or absolute inside it) and returns it slash-separated.
+func normalizeSubproject(root, dir string) (string, error) {
+	rel := dir
+	if filepath.IsAbs(dir) {
+		var err error
+		if rel, err = filepath.Rel(root, dir); err != nil {
+			return "", fmt.Errorf("--subproject %q is not inside %s", dir, root)
+		}
+	}
+	p := filepath.ToSlash(filepath.Clean(rel))
+	switch {
+	case p == ".":
+		return "", fmt.Errorf("--subproject must be a directory below the repository root; omit it to install for the whole repo")
+	case p == ".." || strings.HasPrefix(p, "../"):
+		return "", fmt.Errorf("--subproject %q is not inside %s", dir, root)
+	case strings.ContainsAny(p, `*?[]{},"`):
+		return "", fmt.Errorf("--subproject %q must not contain glob characters", dir)
+	}
+	for _, part := range strings.Split(p, "/") {
+		if strings.HasPrefix(part, ".") {
+			return "", fmt.Errorf("--subproject %q must not be a hidden directory", dir)
+		}
+	}
+	if info, err := os.Stat(filepath.Join(root, filepath.FromSlash(p))); err != nil || !info.IsDir() {
+		return "", fmt.Errorf("--subproject %q is not a directory in %s", dir, root)
+	}
+	return p, nil
+}
+
+// stateSubprojects returns the subprojects that have instructions in state,
+// sorted.
+func stateSubprojects(state *StateFile) []string {
+	seen := map[string]bool{}
+	subprojects := []string{}
+	for _, f := range state.Files {
+		if f.Subproject != "" && !seen[f.Subproject] {
+			seen[f.Subproject] = true
+			subprojects = append(subprojects, f.Subproject)
+		}
+	}
+	sort.Strings(subprojects)
+	return subprojects
+}
+
+// artifactSubproject returns the subproject an artifact of kind is scoped to
+// in scope. Only instructions can be path-scoped; agents, skills and prompts
+// always apply to the whole repo.
+func artifactSubproject(scope *InstallScope, kind *ArtifactKind) string {
+	if kind != KindInstruction {
+		return ""
+	}
+	return scope.Subproject
+}
+
+// unscopedPath maps a subproject instruction path back to the path it has
+// without --subproject, which is what the resolver maps to source. The
+// result is slash-separated.
+func unscopedPath(path, subproject string) string {
+	path = filepath.ToSlash(path)
+	if subproject == "" {
+		return path
+	}
+	dir := KindInstruction.Dir + "/"
+	return strings.Replace(path, dir+subproject+"/", dir, 1)
+}
+
+// renderedSource reads a source file as it is installed for subproject.
+func renderedSource(path, subproject string) ([]byte, error) {
+	data, err := os.ReadFile(path)
+	if err != nil || subproject == "" {
+		return data, err
+	}
+	return scopeApplyTo(data, subproject)
+}
+
+// sourceArtifactHash is comparableArtifactHash of a source artifact as it is
+// installed for subproject.
+func sourceArtifactHash(path string, isDir bool, subproject string) (string, error) {
+	if subproject == "" || isDir {
+		return comparableArtifactHash(path, isDir)
+	}
+	data, err := renderedSource(path, subproject)
+	if err != nil {
+		return "", err
+	}
+	return normalizedDataHash(data), nil
+}
+
+// installSourceArtifact copies a source artifact to dst, rendering it for
+// subproject.
+func installSourceArtifact(src, dst, rootDir string, isDir bool, subproject string) error {
+	if subproject == "" || isDir {
+		return copyArtifact(src, dst, rootDir, isDir)
+	}
+	data, err := renderedSource(src, subproject)
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(dst, data, rootDir)
+}
+
+// sourceConflicts reports whether dst exists and differs from the source
+// artifact as it is installed for subproject.
+func sourceConflicts(dst, src string, isDir bool, subproject string) (bool, error) {
+	if subproject == "" || isDir {
+		c, err := checkConflict(dst, src, isDir)
+		return c != nil, err
+	}
+	if _, err := os.Stat(dst); os.IsNotExist(err) {
+		return false, nil
+	}
+	current, err := comparableArtifactHash(dst, false)
+	if err != nil {
+		return false, fmt.Errorf("hashing %s: %w", dst, err)
+	}
+	rendered, err := sourceArtifactHash(src, false, subproject)
+	if err != nil {
+		return false, fmt.Errorf("hashing %s: %w", src, err)
+	}
+	return current != rendered, nil
+}
+
+// saveSourceBase is saveBase for a source artifact as it is installed for
+// subproject.
+func saveSourceBase(scope *InstallScope, relPath, srcPath string, isDir bool, subproject string) error {
+	if subproject == "" || isDir {
+		return saveBase(scope, relPath, srcPath, isDir)
+	}
+	data, err := renderedSource(srcPath, subproject)
+	if err != nil {
+		return err
+	}
+	if err := journalTrack(scope, baseStorePath(scope, relPath)); err != nil {
+		return err
+	}
+	return saveBaseData(scope, relPath, data)
+}
@@ -0,0 +1,172 @@
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/navikt/copilot/cli/nav-pilot/internal/source"
+)
+
+// subprojectFixture is a monorepo with backend/ and frontend/ and a source
+// with one collection per stack. Sync resolves to the same source dir.
+func subprojectFixture(t *testing.T) (repo, srcDir string, src *source.Source) {
+	t.Helper()
+	repo = t.TempDir()
+	os.MkdirAll(filepath.Join(repo, ".git"), 0o755)
+	os.MkdirAll(filepath.Join(repo, "backend"), 0o755)
+	os.MkdirAll(filepath.Join(repo, "frontend"), 0o755)
+
+	srcDir = t.TempDir()
+	for name, manifest := range map[string]string{
+		"kotlin": `{"name":"kotlin","agents":["nais"],"instructions":["kotlin"]}`,
+		"nextjs": `{"name":"nextjs","instructions":["nextjs"]}`,
+	} {
+		os.MkdirAll(filepath.Join(srcDir, "collections", name), 0o755)
+		os.WriteFile(filepath.Join(srcDir, "collections", name, "manifest.json"), []byte(manifest), 0o644)
+	}
+	os.MkdirAll(filepath.Join(srcDir, "agents"), 0o755)
+	os.WriteFile(filepath.Join(srcDir, "agents", "nais.agent.md"), []byte("# Nais\n"), 0o644)
+	os.MkdirAll(filepath.Join(srcDir, "instructions"), 0o755)
+	os.WriteFile(filepath.Join(srcDir, "instructions", "kotlin.instructions.md"),
+		[]byte("---\napplyTo: \"**/*.kt\"\n---\n\n# Kotlin\n\nv1\n"), 0o644)
+	os.WriteFile(filepath.Join(srcDir, "instructions", "nextjs.instructions.md"),
+		[]byte("---\napplyTo: \"**/*.ts, **/*.{tsx,jsx}\"\n---\n\n# Next.js\n"), 0o644)
+
+	src = &source.Source{Dir: srcDir, SHA: "v1", Version: "dev"}
+	orig := resolveSourceForSync
+	t.Cleanup(func() { resolveSourceForSync = orig })
+	resolveSourceForSync = func(ref, sourceRepo string) (*source.Source, error) {
+		return &source.Source{Dir: srcDir, SHA: "v2", Version: "dev"}, nil
+	}
+	return repo, srcDir, src
+}
+
+func TestInstallSubproject_ScopesInstructions(t *testing.T) {
+	repo, srcDir, src := subprojectFixture(t)
+
+	captureStdout(func() {
+		if err := cmdInstallFromSource("kotlin", src, ScopeSubproject(repo, "backend"), false, false, false); err != nil {
+			t.Fatalf("install backend: %v", err)
+		}
+		if err := cmdInstallFromSource("nextjs", src, ScopeSubproject(repo, "frontend"), false, false, false); err != nil {
+			t.Fatalf("install frontend: %v", err)
+		}
+	})
+
+	backend, err := os.ReadFile(filepath.Join(repo, ".github", "instructions", "backend", "kotlin.instructions.md"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(backend), `applyTo: "backend/**/*.kt"`) || !strings.Contains(string(backend), "# Kotlin") {
+		t.Errorf("backend instruction =\n%s", backend)
+	}
+	frontend, _ := os.ReadFile(filepath.Join(repo, ".github", "instructions", "frontend", "nextjs.instructions.md"))
+	if !strings.Contains(string(frontend), `applyTo: "frontend/**/*.ts, frontend/**/*.{tsx,jsx}"`) {
+		t.Errorf("frontend instruction =\n%s", frontend)
+	}
+	// Agents cannot be path-scoped: they install repo-wide.
+	if _, err := os.Stat(filepath.Join(repo, ".github", "agents", "nais.agent.md")); err != nil {
+		t.Errorf("agent not installed repo-wide: %v", err)
+	}
+
+	// The second subproject install adds to the state instead of replacing it.
+	state, err := readScopedState(ScopeRepo(repo))
+	if err != nil || state == nil {
+		t.Fatalf("state: %v", err)
+	}
+	subprojects := map[string]string{}
+	for _, f := range state.Files {
+		subprojects[f.Path] = f.Subproject
+	}
+	want := map[string]string{
+		".github/agents/nais.agent.md":                         "",
+		".github/instructions/backend/kotlin.instructions.md":  "backend",
+		".github/instructions/frontend/nextjs.instructions.md": "frontend",
+	}
+	if len(subprojects) != len(want) {
+		t.Errorf("state files = %v", subprojects)
+	}
+	for p, sub := range want {
+		if got, ok := subprojects[p]; !ok || got != sub {
+			t.Errorf("state %s: subproject %q (tracked %v), want %q", p, got, ok, sub)
+		}
+	}
+
+	// Sync and diff compare against the rendered source: nothing to do yet.
+	captureStdout(func() {
+		if err := cmdSync(ScopeRepo(repo), "", "", lockFollow, false, false); err != nil {
+			t.Fatalf("sync after install: %v", err)
+		}
+		if err := cmdDiff([]*InstallScope{ScopeRepo(repo)}, nil, "", "", false, false); err != nil {
+			t.Fatalf("diff after install: %v", err)
+		}
+	})
+
+	// A source change is picked up and re-rendered for the subproject.
+	os.WriteFile(filepath.Join(srcDir, "instructions", "kotlin.instructions.md"),
+		[]byte("---\napplyTo: \"**/*.kt\"\n---\n\n# Kotlin\n\nv2\n"), 0o644)
+	captureStdout(func() {
+		if err := cmdSync(ScopeRepo(repo), "", "", lockFollow, false, false); err != errUpdatesAvailable {
+			t.Fatalf("sync check: got %v, want errUpdatesAvailable", err)
+		}
+		if err := cmdSync(ScopeRepo(repo), "", "", lockFollow, true, false); err != nil {
+			t.Fatalf("sync apply: %v", err)
+		}
+	})
+	backend, _ = os.ReadFile(filepath.Join(repo, ".github", "instructions", "backend", "kotlin.instructions.md"))
+	if !strings.Contains(string(backend), "v2") || !strings.Contains(string(backend), `applyTo: "backend/**/*.kt"`) {
+		t.Errorf("synced backend instruction =\n%s", backend)
+	}
+}
+
+func TestUninstallSubproject_RemovesOnlyThatCopy(t *testing.T) {
+	repo, _, src := subprojectFixture(t)
+	captureStdout(func() {
+		if err := cmdInstallFromSource("kotlin", src, ScopeRepo(repo), false, false, false); err != nil {
+			t.Fatal(err)
+		}
+		if err := cmdInstallFromSource("kotlin", src, ScopeSubproject(repo, "backend"), false, false, false); err != nil {
+			t.Fatal(err)
+		}
+		if err := cmdUninstallItem(ScopeSubproject(repo, "backend"), "kotlin", "instruction", false, false); err != nil {
+			t.Fatalf("uninstall: %v", err)
+		}
+	})
+	if _, err := os.Stat(filepath.Join(repo, ".github", "instructions", "backend")); !os.IsNotExist(err) {
+		t.Errorf("empty subproject directory not cleaned up: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(repo, ".github", "instructions", "kotlin.instructions.md")); err != nil {
+		t.Errorf("repo-wide copy removed: %v", err)
+	}
+}
+
+func TestNormalizeSubproject(t *testing.T) {
+	root := t.TempDir()
+	os.MkdirAll(filepath.Join(root, "apps", "web"), 0o755)
+	os.MkdirAll(filepath.Join(root, ".github"), 0o755)
+
+	for _, in := range []string{"apps/web", "apps/web/", "./apps/web", filepath.Join(root, "apps", "web")} {
+		if got, err := normalizeSubproject(root, in); err != nil || got != "apps/web" {
+			t.Errorf("normalizeSubproject(%q) = %q, %v", in, got, err)
+		}
+	}
+	for _, in := range []string{".", "..", "../x", ".github", "apps/*", "missing"} {
+		if _, err := normalizeSubproject(root, in); err == nil {
+			t.Errorf("normalizeSubproject(%q): expected error", in)
+		}
+	}
+}
+
+func TestRun_SubprojectFlagOnlyForInstall(t *testing.T) {
+	for _, args := range [][]string{
+		{"sync", "--subproject", "backend"},
+		{"install", "--user", "kotlin", "--subproject", "backend"},
+		{"install", "--locked", "--subproject", "backend"},
+	} {
+		if err := run(args); err == nil || !strings.Contains(err.Error(), "subproject") && !strings.Contains(err.Error(), "--locked") {
+			t.Errorf("run(%v) = %v", args, err)
+		}
+	}
+}
@@ -70,7 +70,19 @@ var knownFlags = []string{
 	"-n", "--dry-run",
 	"-f", "--force",
 	"--apply",
+	"--stat",
+	"--locked",
+	"--update-lock",
+	"--offline",
 	"--json",
+	"--sarif",
+	"--fix",
+	"--subproject",
+	"--collection",
+	"--version",
+	"--rollback",
+	"--allow-unsigned",
+	"--profile",
 	"--items",
 	"-F", "--feature",
 	"-u", "--user",
@@ -3,6 +3,7 @@ package cli
 import (
 	"encoding/json"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 	"strings"
@@ -18,15 +19,29 @@ type syncResult struct {
 	Overrides []string     `json:"overrides,omitempty"`
 	Ignored   []string     `json:"ignored,omitempty"`
 	Conflicts []string     `json:"conflicts,omitempty"`
+	// MCP lists installed MCP servers that are outdated, deprecated or gone
+	// in the registry.
+	MCP []mcpSyncStatus `json:"mcp,omitempty"`
 }
 
 type syncUpdate struct {
 	Path        string `json:"path"`
 	SourcePath  string `json:"-"` // resolved source path, not serialized
+	SourceRoot  string `json:"-"` // layer directory SourcePath is relative to; empty = source dir
+	Source      string `json:"source,omitempty"`
 	CurrentHash string `json:"current_hash"`
 	SourceHash  string `json:"source_hash"`
+	Merge       string `json:"merge,omitempty"` // "clean" or "conflict" when local edits are merged
+	Subproject  string `json:"subproject,omitempty"`
+	// Changelog lists the source commits that touched the artifact since
+	// the installed revision, newest first.
+	Changelog []LogEntry `json:"changelog,omitempty"`
 }
 
+// syncChangelogLimit is how many commits per file the text output shows.
+// --json includes all.
+const syncChangelogLimit = 5
+
 // errUpdatesAvailable is returned when sync finds updates but --apply is not set.
 // main() maps this to exit code 1 for CI use.
 var errUpdatesAvailable = fmt.Errorf("updates available")
@@ -44,35 +59,92 @@ var cmdSyncFn = cmdSync
 //   - check (default): report which files differ, exit 1 if updates available
 //   - apply: update differing files in place
 //
+// lockMode decides how nav-pilot.lock is treated (see syncLockMode).
+//
 // Works with both state-based repos (nav-pilot install) and auto-detected repos.
-func cmdSync(scope *InstallScope, ref, sourceRepo string, apply, jsonOutput bool) error {
+func cmdSync(scope *InstallScope, ref, sourceRepo string, lockMode syncLockMode, apply, jsonOutput bool) error {
+	r := &syncRun{out: os.Stdout, errOut: os.Stderr, resolve: resolveSourceForSync}
+	return r.sync(scope, ref, sourceRepo, lockMode, apply, jsonOutput)
+}
+
+// syncRun is one sync of one scope. cmdSync writes to the terminal; workspace
+// sync runs many at once, each into its own buffers and with a shared source.
+type syncRun struct {
+	out, errOut io.Writer
+	resolve     func(ref, sourceRepo string) (*Source, error)
+
+	// Filled in as the sync progresses, for callers that aggregate runs.
+	files  int         // files compared against source
+	result *syncResult // nil if the sync failed before comparing
+}
+
+func (r *syncRun) sync(scope *InstallScope, ref, sourceRepo string, lockMode syncLockMode, apply, jsonOutput bool) (err error) {
+	if apply || lockMode == lockUpdate {
+		tx, txErr := beginJournal(scope, "sync")
+		if txErr != nil {
+			return txErr
+		}
+		defer tx.finish(&err)
+	}
+
+	lock, err := readLock(scope)
+	if err != nil {
+		return err
+	}
+	switch lockMode {
+	case lockPinned:
+		if lock == nil {
+			return fmt.Errorf("--locked: no %s in %s", lockFileName, scope.Label())
+		}
+		ref, sourceRepo = lockSourceRefs(lock)
+	case lockUpdate:
+		if jsonOutput {
+			return fmt.Errorf("--update-lock cannot be combined with --json")
+		}
+		apply = true
+	}
+
 	if sourceRepo == "" {
 		if state, err := readScopedState(scope); err == nil && state != nil && state.SourceRepo != "" {
 			sourceRepo = state.SourceRepo
 		}
 	}
-	src, err := resolveSourceForSync(ref, sourceRepo)
+	src, err := r.resolve(ref, sourceRepo)
 	if err != nil {
 		return err
 	}
 	defer src.Cleanup()
 
+	if lockMode == lockPinned {
+		state, _ := readScopedState(scope)
+		if err := verifyLock(scope, lock, src, state); err != nil {
+			return err
+		}
+	}
+
+	resolver := src.Resolver()
+
 	// Determine which files to check
-	files, _, err := resolveSyncFiles(scope, src.Dir, apply)
+	files, _, err := resolveSyncFiles(scope, resolver, apply)
 	if err != nil {
 		return err
 	}
 
 	conflictPaths := conflictStatePaths(scope)
-	if err := clearResolvedConflicts(scope, src.Dir, conflictPaths); err != nil {
+	if err := clearResolvedConflicts(scope, resolver, conflictPaths); err != nil {
 		if !jsonOutput {
-			fmt.Fprintf(os.Stderr, "%s Could not clear resolved conflicts: %v\n", yellow("⚠"), err)
+			fmt.Fprintf(r.errOut, "%s Could not clear resolved conflicts: %v\n", yellow("⚠"), err)
 		}
 	}
 	// Re-fetch conflictPaths in case any were resolved
 	conflictPaths = conflictStatePaths(scope)
 
-	if len(files) == 0 {
+	mcpStatus, err := checkMCPServers(scope)
+	if err != nil && !jsonOutput {
+		fmt.Fprintf(r.errOut, "%s Could not check MCP servers: %v\n", yellow("⚠"), err)
+	}
+
+	if len(files) == 0 && len(mcpStatus) == 0 {
 		if len(conflictPaths) > 0 && !apply {
 			telemetry.RecordSyncConflicts(scope.Name, telemetryMode(), int64(len(conflictPaths)))
 			result := syncResult{
@@ -80,25 +152,27 @@ func cmdSync(scope *InstallScope, ref, sourceRepo string, apply, jsonOutput bool
 				Source:    src.SHA,
 				Conflicts: conflictPaths,
 			}
+			r.result = &result
 			if jsonOutput {
-				if err := outputJSON(result); err != nil {
+				if err := writeJSON(r.out, result); err != nil {
 					return err
 				}
 				return errUpdatesAvailable
 			}
-			fmt.Printf("%s %d file(s) are in conflict state and were skipped (source: %s)\n\n",
+			fmt.Fprintf(r.out, "%s %d file(s) are in conflict state and were skipped (source: %s)\n\n",
 				yellow("⚠"), len(conflictPaths), src.SHA)
 			for _, p := range conflictPaths {
-				fmt.Printf("  %s %s\n", dim("⊘"), p)
+				fmt.Fprintf(r.out, "  %s %s\n", dim("⊘"), p)
 			}
-			fmt.Println()
-			fmt.Printf("Run %s to apply updates.\n", bold("nav-pilot sync --apply"))
+			fmt.Fprintln(r.out)
+			fmt.Fprintf(r.out, "Run %s to apply updates.\n", bold("nav-pilot sync --apply"))
 			return errUpdatesAvailable
 		}
+		r.result = &syncResult{UpToDate: true, Source: src.SHA}
 		if jsonOutput {
-			return outputJSON(syncResult{UpToDate: true, Source: src.SHA})
+			return writeJSON(r.out, r.result)
 		}
-		fmt.Println("No customization files found to sync.")
+		fmt.Fprintln(r.out, "No customization files found to sync.")
 		return nil
 	}
 
@@ -122,9 +196,9 @@ func cmdSync(scope *InstallScope, ref, sourceRepo string, apply, jsonOutput bool
 
 	if !jsonOutput && len(overriddenPaths) > 0 {
 		for _, p := range overriddenPaths {
-			fmt.Printf("  %s %s (override)\n", dim("⊘"), p)
+			fmt.Fprintf(r.out, "  %s %s (override)\n", dim("⊘"), p)
 		}
-		fmt.Println()
+		fmt.Fprintln(r.out)
 	}
 
 	// Compare each file against source.
@@ -143,16 +217,16 @@ func cmdSync(scope *InstallScope, ref, sourceRepo string, apply, jsonOutput bool
 		}
 
 		// Check if it exists in the source
-		sourceFull := filepath.Join(src.Dir, sf.sourcePath)
+		sourceFull := sf.sourceFull(src.Dir)
 		if _, statErr := os.Stat(sourceFull); os.IsNotExist(statErr) {
 			deletedPaths = append(deletedPaths, sf.localPath)
 			continue
 		}
 
-		u, err := checkSyncFile(scope.RootDir, src.Dir, sf)
+		u, err := checkSyncFile(scope, src.Dir, sf)
 		if err != nil {
 			if !jsonOutput {
-				fmt.Fprintf(os.Stderr, "%s %s: %v\n", yellow("⚠"), sf.localPath, err)
+				fmt.Fprintf(r.errOut, "%s %s: %v\n", yellow("⚠"), sf.localPath, err)
 			}
 			syncErrors = append(syncErrors, fmt.Sprintf("%s: %v", sf.localPath, err))
 			continue
@@ -166,19 +240,36 @@ func cmdSync(scope *InstallScope, ref, sourceRepo string, apply, jsonOutput bool
 	if len(ignoredPaths) > 0 {
 		if err := markFilesIgnored(scope, ignoredPaths); err != nil {
 			if !jsonOutput {
-				fmt.Fprintf(os.Stderr, "%s Could not update state for deleted files: %v\n", yellow("⚠"), err)
+				fmt.Fprintf(r.errOut, "%s Could not update state for deleted files: %v\n", yellow("⚠"), err)
 			}
 		}
 		if !jsonOutput {
 			for _, p := range ignoredPaths {
-				fmt.Printf("  %s %s (deleted — marked ignored)\n", dim("⊘"), p)
+				fmt.Fprintf(r.out, "  %s %s (deleted — marked ignored)\n", dim("⊘"), p)
 			}
-			fmt.Println()
+			fmt.Fprintln(r.out)
 		}
 	}
 
+	// Classify updates that touch locally edited files against the stored base,
+	// so check mode can tell clean merges from real conflicts up front.
+	for i := range updates {
+		outcome, err := classifySyncUpdate(scope, src.Dir, updates[i])
+		if err != nil {
+			continue
+		}
+		updates[i].Merge = outcome
+		if outcome == mergeConflict && !containsStr(conflictPaths, updates[i].Path) {
+			conflictPaths = append(conflictPaths, updates[i].Path)
+		}
+	}
+
+	if err := addSyncChangelogs(scope, src, updates); err != nil && !jsonOutput {
+		fmt.Fprintf(r.errOut, "%s Could not read source history: %v\n", yellow("⚠"), err)
+	}
+
 	result := syncResult{
-		UpToDate:  len(updates) == 0 && len(deletedPaths) == 0 && len(syncErrors) == 0 && (apply || len(conflictPaths) == 0),
+		UpToDate:  len(updates) == 0 && len(deletedPaths) == 0 && len(syncErrors) == 0 && (apply || len(conflictPaths) == 0) && !hasMCPUpdates(mcpStatus),
 		Source:    src.SHA,
 		Updates:   updates,
 		Deletions: deletedPaths,
@@ -186,7 +277,10 @@ func cmdSync(scope *InstallScope, ref, sourceRepo string, apply, jsonOutput bool
 		Overrides: overriddenPaths,
 		Ignored:   ignoredPaths,
 		Conflicts: conflictPaths,
+		MCP:       mcpStatus,
 	}
+	r.files = len(files)
+	r.result = &result
 	tMode := telemetryMode()
 	if !apply {
 		tMode += "_dry_run"
@@ -195,7 +289,7 @@ func cmdSync(scope *InstallScope, ref, sourceRepo string, apply, jsonOutput bool
 	telemetry.RecordSyncConflicts(scope.Name, tMode, int64(len(result.Conflicts)))
 
 	if jsonOutput {
-		if err := outputJSON(result); err != nil {
+		if err := writeJSON(r.out, result); err != nil {
 			return err
 		}
 		// Exit 2 if any errors occurred (even with updates/deletions)
@@ -208,8 +302,10 @@ func cmdSync(scope *InstallScope, ref, sourceRepo string, apply, jsonOutput bool
 		return nil
 	}
 
+	printMCPSyncNotices(r.out, mcpStatus)
+
 	if result.UpToDate {
-		fmt.Printf("%s All %d files up to date (source: %s)\n",
+		fmt.Fprintf(r.out, "%s All %d files up to date (source: %s)\n",
 			green("✓"), len(files), src.SHA)
 		// Bump state version so staleness check won't re-trigger for this release
 		if src.Version != "" {
@@ -217,62 +313,94 @@ func cmdSync(scope *InstallScope, ref, sourceRepo string, apply, jsonOutput bool
 				if state.Version != src.Version || state.SourceSHA != src.SHA {
 					state.Version = src.Version
 					state.SourceSHA = src.SHA
+					state.Sources = stateSources(src)
 					if err := writeScopedState(scope, state); err != nil {
-						fmt.Fprintf(os.Stderr, "%s Could not update state: %v\n", yellow("⚠"), err)
+						fmt.Fprintf(r.errOut, "%s Could not update state: %v\n", yellow("⚠"), err)
 					}
 				}
 			}
 		}
-		reportNewItems(scope, src.Dir)
+		finishSyncLock(r.out, r.errOut, scope, src, lock, lockMode)
+		reportNewItems(r.out, scope, resolver)
 		return nil
 	}
 
 	// Report updates
 	if len(updates) > 0 {
-		fmt.Printf("%s %d of %d files have updates available (source: %s)\n\n",
+		fmt.Fprintf(r.out, "%s %d of %d files have updates available (source: %s)\n\n",
 			yellow("⚠"), len(updates), len(files), src.SHA)
 		for _, u := range updates {
-			fmt.Printf("  %s %s\n", yellow("~"), u.Path)
+			switch u.Merge {
+			case mergeClean:
+				fmt.Fprintf(r.out, "  %s %s %s\n", yellow("~"), u.Path, dim("(local changes will be merged)"))
+			case mergeConflict:
+				fmt.Fprintf(r.out, "  %s %s %s\n", yellow("~"), u.Path, dim("(conflicts with local changes)"))
+			default:
+				fmt.Fprintf(r.out, "  %s %s\n", yellow("~"), u.Path)
+			}
+			printSyncChangelog(r.out, u.Changelog)
 		}
-		fmt.Println()
+		fmt.Fprintln(r.out)
 	}
 
 	// Report deletions
 	if len(deletedPaths) > 0 {
-		fmt.Printf("%s %d file(s) deleted in source and will be removed (source: %s)\n\n",
+		fmt.Fprintf(r.out, "%s %d file(s) deleted in source and will be removed (source: %s)\n\n",
 			yellow("⚠"), len(deletedPaths), src.SHA)
 		for _, p := range deletedPaths {
-			fmt.Printf("  %s %s\n", red("-"), p)
+			fmt.Fprintf(r.out, "  %s %s\n", red("-"), p)
 		}
-		fmt.Println()
+		fmt.Fprintln(r.out)
 	}
 
-	if len(conflictPaths) > 0 && !apply {
-		fmt.Printf("%s %d file(s) are in conflict state and were skipped (source: %s)\n\n",
-			yellow("⚠"), len(conflictPaths), src.SHA)
-		for _, p := range conflictPaths {
-			fmt.Printf("  %s %s\n", dim("⊘"), p)
+	// Report MCP server updates
+	if hasMCPUpdates(mcpStatus) {
+		fmt.Fprintf(r.out, "%s MCP servers have updates in the registry\n\n", yellow("⚠"))
+		for _, m := range mcpStatus {
+			if m.Status == mcpSyncUpdate {
+				fmt.Fprintf(r.out, "  %s %s %s\n", yellow("~"), m.Key, dim("("+m.CurrentVersion+" → "+m.Version+")"))
+			}
 		}
-		fmt.Println()
+		fmt.Fprintln(r.out)
+	}
+
+	if stateConflicts := conflictStatePaths(scope); len(stateConflicts) > 0 && !apply {
+		fmt.Fprintf(r.out, "%s %d file(s) are in conflict state and were skipped (source: %s)\n\n",
+			yellow("⚠"), len(stateConflicts), src.SHA)
+		for _, p := range stateConflicts {
+			fmt.Fprintf(r.out, "  %s %s\n", dim("⊘"), p)
+		}
+		fmt.Fprintln(r.out)
 	}
 
 	if !apply {
-		fmt.Printf("Run %s to apply updates.\n", bold("nav-pilot sync --apply"))
+		fmt.Fprintf(r.out, "Run %s to apply updates.\n", bold("nav-pilot sync --apply"))
 		return errUpdatesAvailable
 	}
 
 	// Apply updates
 	applied := 0
 	var appliedUpdates []syncUpdate
+	var mergeConflicts []string
 	var applyErrors int
 	for _, u := range updates {
-		if err := applySyncUpdate(scope, src.Dir, u); err != nil {
-			fmt.Fprintf(os.Stderr, "%s Could not update %s: %v\n", yellow("⚠"), u.Path, err)
+		outcome, err := applySyncMerge(scope, src.Dir, u, mergeRemoteLabel(src, u))
+		if err != nil {
+			fmt.Fprintf(r.errOut, "%s Could not update %s: %v\n", yellow("⚠"), u.Path, err)
 			applyErrors++
 			continue
 		}
-		fmt.Printf("  %s %s\n", green("✓"), u.Path)
-		applied++
+		switch outcome {
+		case mergeConflict:
+			fmt.Fprintf(r.out, "  %s %s (conflict — resolve the markers and run sync again)\n", yellow("⚠"), u.Path)
+			mergeConflicts = append(mergeConflicts, u.Path)
+		case mergeClean:
+			fmt.Fprintf(r.out, "  %s %s (merged with local changes)\n", green("✓"), u.Path)
+			applied++
+		default:
+			fmt.Fprintf(r.out, "  %s %s\n", green("✓"), u.Path)
+			applied++
+		}
 		appliedUpdates = append(appliedUpdates, u)
 	}
 
@@ -281,37 +409,66 @@ func cmdSync(scope *InstallScope, ref, sourceRepo string, apply, jsonOutput bool
 	var deletedSuccessPaths []string
 	for _, p := range deletedPaths {
 		localFull := filepath.Join(scope.RootDir, p)
-		var rmErr error
-		if strings.HasSuffix(p, "/") {
-			rmErr = os.RemoveAll(localFull)
-		} else {
-			rmErr = os.Remove(localFull)
+		rmErr := journalTrack(scope, localFull)
+		if rmErr == nil {
+			if strings.HasSuffix(p, "/") {
+				rmErr = os.RemoveAll(localFull)
+			} else {
+				rmErr = os.Remove(localFull)
+			}
 		}
 		if rmErr != nil && !os.IsNotExist(rmErr) {
-			fmt.Fprintf(os.Stderr, "%s Could not remove %s: %v\n", yellow("⚠"), p, rmErr)
+			fmt.Fprintf(r.errOut, "%s Could not remove %s: %v\n", yellow("⚠"), p, rmErr)
 			applyErrors++
 			continue
 		}
-		fmt.Printf("  %s %s (deleted)\n", red("×"), p)
+		if err := removeBase(scope, p); err != nil {
+			fmt.Fprintf(r.errOut, "%s Could not remove merge base for %s: %v\n", yellow("⚠"), p, err)
+		}
+		fmt.Fprintf(r.out, "  %s %s (deleted)\n", red("×"), p)
 		deleted++
 		deletedSuccessPaths = append(deletedSuccessPaths, p)
 	}
 
+	// Apply MCP server updates
+	mcpApplied := 0
+	for _, m := range mcpStatus {
+		if m.Status != mcpSyncUpdate {
+			continue
+		}
+		if err := applyMCPUpdate(scope, m); err != nil {
+			fmt.Fprintf(r.errOut, "%s Could not update MCP server %s: %v\n", yellow("⚠"), m.Key, err)
+			applyErrors++
+			continue
+		}
+		fmt.Fprintf(r.out, "  %s %s %s\n", green("✓"), m.Key, dim("(MCP server "+m.Version+")"))
+		mcpApplied++
+	}
+
 	if len(updates) > 0 {
-		fmt.Printf("\n%s Updated %d file(s).\n", green("✓"), applied)
+		fmt.Fprintf(r.out, "\n%s Updated %d file(s).\n", green("✓"), applied)
+	}
+	if mcpApplied > 0 {
+		fmt.Fprintf(r.out, "%s Updated %d MCP server(s).\n", green("✓"), mcpApplied)
 	}
 	if len(deletedPaths) > 0 {
-		fmt.Printf("%s Removed %d file(s).\n", green("✓"), deleted)
+		fmt.Fprintf(r.out, "%s Removed %d file(s).\n", green("✓"), deleted)
 	}
 
 	// Update state with new hashes
 	if err := updateScopedStateHashes(scope, appliedUpdates); err != nil {
-		fmt.Fprintf(os.Stderr, "%s Could not update state file: %v\n", yellow("⚠"), err)
+		fmt.Fprintf(r.errOut, "%s Could not update state file: %v\n", yellow("⚠"), err)
+	}
+	if len(mergeConflicts) > 0 {
+		if err := markFilesStatus(scope, mergeConflicts, fileStatusConflict); err != nil {
+			fmt.Fprintf(r.errOut, "%s Could not record conflicts in state: %v\n", yellow("⚠"), err)
+		}
+		fmt.Fprintf(r.out, "%s %d file(s) have conflicts with local changes.\n", yellow("⚠"), len(mergeConflicts))
 	}
 
 	if len(deletedSuccessPaths) > 0 {
 		if err := removeFilesFromState(scope, deletedSuccessPaths); err != nil {
-			fmt.Fprintf(os.Stderr, "%s Could not remove files from state: %v\n", yellow("⚠"), err)
+			fmt.Fprintf(r.errOut, "%s Could not remove files from state: %v\n", yellow("⚠"), err)
 		}
 		scope.CleanupDirs()
 	}
@@ -320,6 +477,7 @@ func cmdSync(scope *InstallScope, ref, sourceRepo string, apply, jsonOutput bool
 	if state, err := readScopedState(scope); err == nil && state != nil {
 		if applyErrors == 0 {
 			state.SourceSHA = src.SHA
+			state.Sources = stateSources(src)
 			// Use the binary's release version directly.
 			// "dev" means local/unreleased build — checkStaleness() skips it.
 			if src.Version != "" {
@@ -327,7 +485,7 @@ func cmdSync(scope *InstallScope, ref, sourceRepo string, apply, jsonOutput bool
 			}
 		}
 		if err := writeScopedState(scope, state); err != nil {
-			fmt.Fprintf(os.Stderr, "%s Could not update state: %v\n", yellow("⚠"), err)
+			fmt.Fprintf(r.errOut, "%s Could not update state: %v\n", yellow("⚠"), err)
 		}
 	}
 
@@ -335,14 +493,18 @@ func cmdSync(scope *InstallScope, ref, sourceRepo string, apply, jsonOutput bool
 		return errSyncFailed
 	}
 
-	reportNewItems(scope, src.Dir)
+	finishSyncLock(r.out, r.errOut, scope, src, lock, lockMode)
+	reportNewItems(r.out, scope, resolver)
+	if len(mergeConflicts) > 0 {
+		return errUpdatesAvailable
+	}
 	return nil
 }
 
 // cmdSyncAuto syncs all detected scopes (repo + user) when the user didn't
 // explicitly pick one with --user or --target. Mirrors how the interactive
 // flow and `list --installed` handle scope discovery.
-func cmdSyncAuto(repoDir, ref, sourceRepo string, apply, jsonOutput bool) error {
+func cmdSyncAuto(repoDir, ref, sourceRepo string, lockMode syncLockMode, apply, jsonOutput bool) error {
 	repoScope := ScopeRepo(repoDir)
 	repoState, _ := readScopedState(repoScope)
 
@@ -352,7 +514,9 @@ func cmdSyncAuto(repoDir, ref, sourceRepo string, apply, jsonOutput bool) error
 		userState, _ = readScopedState(userScope)
 	}
 
-	if repoState == nil && userState == nil {
+	exports := exportedTargets(repoScope, userScope)
+
+	if repoState == nil && userState == nil && len(exports) == 0 {
 		if jsonOutput {
 			return outputJSON(map[string]interface{}{"installed": false})
 		}
@@ -367,7 +531,7 @@ func cmdSyncAuto(repoDir, ref, sourceRepo string, apply, jsonOutput bool) error
 		if !jsonOutput {
 			fmt.Printf("%s Syncing %s scope...\n", dim("→"), bold("repo"))
 		}
-		if err := cmdSyncFn(repoScope, ref, sourceRepo, apply, jsonOutput); err != nil {
+		if err := cmdSyncFn(repoScope, ref, sourceRepo, lockMode, apply, jsonOutput); err != nil {
 			if firstErr == nil {
 				firstErr = err
 			}
@@ -390,7 +554,7 @@ func cmdSyncAuto(repoDir, ref, sourceRepo string, apply, jsonOutput bool) error
 			}
 			fmt.Printf("%s Syncing %s scope...\n", dim("→"), bold("user"))
 		}
-		if err := cmdSyncFn(userScope, ref, sourceRepo, apply, jsonOutput); err != nil {
+		if err := cmdSyncFn(userScope, ref, sourceRepo, lockMode, apply, jsonOutput); err != nil {
 			if firstErr == nil {
 				firstErr = err
 			}
@@ -419,6 +583,10 @@ func cmdSyncAuto(repoDir, ref, sourceRepo string, apply, jsonOutput bool) error
 		}
 	}
 
+	if err := syncExportTargets(exports, ref, sourceRepo, apply, jsonOutput, hasPrevOutput); err != nil && firstErr == nil {
+		firstErr = err
+	}
+
 	return firstErr
 }
 
@@ -427,12 +595,23 @@ type syncFile struct {
 	localPath  string // relative path in target repo (e.g. ".github/agents/nais.agent.md")
 	sourcePath string // relative path in source repo (same unless remapped)
 	isDir      bool
+	sourceRoot string // layer directory sourcePath is relative to; empty = source dir
+	sourceRepo string // repo label of that layer
+	subproject string // set for instructions installed with --subproject
+}
+
+// sourceFull returns the absolute source path, honoring the file's layer.
+func (sf syncFile) sourceFull(sourceDir string) string {
+	if sf.sourceRoot != "" {
+		sourceDir = sf.sourceRoot
+	}
+	return filepath.Join(sourceDir, sf.sourcePath)
 }
 
 // resolveSyncFiles determines which files to sync.
 // If a state file exists, uses the installed file list.
 // Otherwise, auto-detects customization files in the target repo.
-func resolveSyncFiles(scope *InstallScope, sourceDir string, includeConflicts bool) ([]syncFile, string, error) {
+func resolveSyncFiles(scope *InstallScope, resolver *SourceResolver, includeConflicts bool) ([]syncFile, string, error) {
 	state, err := readScopedState(scope)
 	if err != nil {
 		return nil, "", fmt.Errorf("reading state: %w", err)
@@ -440,7 +619,6 @@ func resolveSyncFiles(scope *InstallScope, sourceDir string, includeConflicts bo
 
 	if state != nil {
 		// State-based: check all installed files, skip ignored and conflicted ones
-		resolver := NewSourceResolver(sourceDir)
 		var files []syncFile
 		for _, f := range state.Files {
 			if f.Status == fileStatusIgnored {
@@ -449,11 +627,14 @@ func resolveSyncFiles(scope *InstallScope, sourceDir string, includeConflicts bo
 			if f.Status == fileStatusConflict && !includeConflicts {
 				continue
 			}
-			sp := resolver.MapLocalPath(f.Path, scope.IsUser())
+			sp, layer := resolver.MapLocalPathLayer(unscopedPath(f.Path, f.Subproject), scope.IsUser())
 			files = append(files, syncFile{
 				localPath:  f.Path,
 				sourcePath: sp,
 				isDir:      strings.HasSuffix(f.Path, "/"),
+				sourceRoot: layer.Dir,
+				sourceRepo: layer.Repo,
+				subproject: f.Subproject,
 			})
 		}
 		return files, state.Collection, nil
@@ -465,7 +646,7 @@ func resolveSyncFiles(scope *InstallScope, sourceDir string, includeConflicts bo
 	}
 
 	// Auto-detect: scan for customization files that also exist in source
-	return autoDetectSyncFiles(scope.RootDir, sourceDir)
+	return autoDetectSyncFiles(scope.RootDir, resolver)
 }
 
 func conflictStatePaths(scope *InstallScope) []string {
@@ -484,14 +665,12 @@ func conflictStatePaths(scope *InstallScope) []string {
 
 // detectNewItems checks if the source has agents/skills/instructions not in the state file.
 // Only relevant for "(all)" user-scope installs where new items may appear.
-func detectNewItems(scope *InstallScope, sourceDir string) []string {
+func detectNewItems(scope *InstallScope, resolver *SourceResolver) []string {
 	state, err := readScopedState(scope)
 	if err != nil || state == nil || state.Collection != CollectionAll || !scope.IsUser() {
 		return nil
 	}
 
-	resolver := NewSourceResolver(sourceDir)
-
 	installed := make(map[string]bool)
 	for _, f := range state.Files {
 		installed[f.Path] = true
@@ -511,9 +690,7 @@ func detectNewItems(scope *InstallScope, sourceDir string) []string {
 
 // autoDetectSyncFiles finds customization files in the target that also exist in source.
 // Target files are always under .github/. Source may be at root or .github/.
-func autoDetectSyncFiles(targetDir, sourceDir string) ([]syncFile, string, error) {
-	resolver := NewSourceResolver(sourceDir)
-
+func autoDetectSyncFiles(targetDir string, resolver *SourceResolver) ([]syncFile, string, error) {
 	// Build file scan patterns from artifact kind definitions.
 	type scanPattern struct {
 		glob    string
@@ -546,12 +723,12 @@ func autoDetectSyncFiles(targetDir, sourceDir string) ([]syncFile, string, error
 			}
 			// Resolve source: check root-level first, then .github/
 			fileName := filepath.Base(m)
-			_, srcRel, ok := resolver.GetFile(p.typeDir, fileName)
+			_, srcRel, layer, ok := resolver.LocateFile(p.typeDir, fileName)
 			if !ok {
 				continue
 			}
 			seen[rel] = true
-			files = append(files, syncFile{localPath: rel, sourcePath: srcRel, isDir: false})
+			files = append(files, syncFile{localPath: rel, sourcePath: srcRel, isDir: false, sourceRoot: layer.Dir, sourceRepo: layer.Repo})
 		}
 	}
 
@@ -578,37 +755,103 @@ func autoDetectSyncFiles(targetDir, sourceDir string) ([]syncFile, string, error
 				continue
 			}
 			seen[rel] = true
-			files = append(files, syncFile{localPath: rel, sourcePath: art.RelPath + "/", isDir: true})
+			files = append(files, syncFile{localPath: rel, sourcePath: art.RelPath + "/", isDir: true, sourceRoot: art.Root, sourceRepo: art.Source})
 		}
 	}
 
 	return files, "", nil
 }
 
-// checkSyncFile compares a single file/dir between target and source.
-func checkSyncFile(targetDir, sourceDir string, sf syncFile) (*syncUpdate, error) {
-	localFull := filepath.Join(targetDir, sf.localPath)
-	sourceFull := filepath.Join(sourceDir, sf.sourcePath)
+// checkSyncFile compares a single file/dir between target and source. A
+// local copy that differs only by local edits is up to date: its stored base
+// already matches the source, so there is nothing to merge.
+func checkSyncFile(scope *InstallScope, sourceDir string, sf syncFile) (*syncUpdate, error) {
+	localFull := filepath.Join(scope.RootDir, sf.localPath)
+	sourceFull := sf.sourceFull(sourceDir)
 
 	localHash, err := comparableArtifactHash(localFull, sf.isDir)
 	if err != nil {
 		return nil, fmt.Errorf("hashing local: %w", err)
 	}
-	sourceHash, err := comparableArtifactHash(sourceFull, sf.isDir)
+	sourceHash, err := sourceArtifactHash(sourceFull, sf.isDir, sf.subproject)
 	if err != nil {
 		return nil, fmt.Errorf("hashing source: %w", err)
 	}
 	if localHash == sourceHash {
 		return nil, nil
 	}
-	return &syncUpdate{Path: sf.localPath, SourcePath: sf.sourcePath, CurrentHash: localHash, SourceHash: sourceHash}, nil
+	if hasBase(scope, sf.localPath) && !artifactHasConflictMarkers(localFull, sf.isDir) {
+		if baseHash, err := comparableArtifactHash(baseStorePath(scope, sf.localPath), sf.isDir); err == nil && baseHash == sourceHash {
+			return nil, nil
+		}
+	}
+	return &syncUpdate{Path: sf.localPath, SourcePath: sf.sourcePath, SourceRoot: sf.sourceRoot, Source: sf.sourceRepo, CurrentHash: localHash, SourceHash: sourceHash, Subproject: sf.subproject}, nil
+}
+
+// addSyncChangelogs annotates updates with the source commits that touched
+// them since the revision in state. Auto-detected installs have no recorded
+// revision and get none. It returns the first error; updates it could not
+// annotate are left without a changelog.
+func addSyncChangelogs(scope *InstallScope, src *Source, updates []syncUpdate) error {
+	if len(updates) == 0 {
+		return nil
+	}
+	state, err := readScopedState(scope)
+	if err != nil || state == nil {
+		return nil
+	}
+	var firstErr error
+	layers := src.Layers()
+	for i, u := range updates {
+		layer := layers[0]
+		for _, l := range layers {
+			if u.SourceRoot != "" && l.Dir == u.SourceRoot {
+				layer = l
+			}
+		}
+		from := state.SourceSHA
+		for _, s := range state.Sources {
+			if s.Repo == layer.Repo {
+				from = s.SHA
+			}
+		}
+		if from == "" || layer.Commit == "" || strings.HasPrefix(layer.Commit, from) {
+			continue
+		}
+		entries, err := layer.Changelog(from, u.SourcePath)
+		if err != nil {
+			if firstErr == nil {
+				firstErr = err
+			}
+			continue
+		}
+		updates[i].Changelog = entries
+	}
+	return firstErr
+}
+
+func printSyncChangelog(w io.Writer, entries []LogEntry) {
+	for i, e := range entries {
+		if i == syncChangelogLimit {
+			fmt.Fprintf(w, "      %s\n", dim(fmt.Sprintf("… %d more commit(s)", len(entries)-i)))
+			return
+		}
+		fmt.Fprintf(w, "      %s %s %s\n", dim(e.Commit[:7]), e.Subject, dim("("+e.Author+", "+e.Date+")"))
+	}
+}
+
+// sourceFull returns the absolute source path, honoring the update's layer.
+func (u syncUpdate) sourceFull(sourceDir string) string {
+	if u.SourceRoot != "" {
+		sourceDir = u.SourceRoot
+	}
+	return filepath.Join(sourceDir, u.SourcePath)
 }
 
 // applySyncUpdate copies a single file/dir from source to target.
 func applySyncUpdate(scope *InstallScope, sourceDir string, u syncUpdate) error {
-	sourceFull := filepath.Join(sourceDir, u.SourcePath)
 	targetFull := filepath.Join(scope.RootDir, u.Path)
-	return copyArtifact(sourceFull, targetFull, scope.RootDir, strings.HasSuffix(u.Path, "/"))
+	return installSourceArtifact(u.sourceFull(sourceDir), targetFull, scope.RootDir, strings.HasSuffix(u.Path, "/"), u.Subproject)
 }
 
 // updateScopedStateHashes updates the state file with new hashes after applying updates.
@@ -618,13 +861,14 @@ func updateScopedStateHashes(scope *InstallScope, updates []syncUpdate) error {
 		return nil // no state file, nothing to update
 	}
 
-	updateMap := make(map[string]bool)
+	updateMap := make(map[string]syncUpdate)
 	for _, u := range updates {
-		updateMap[u.Path] = true
+		updateMap[u.Path] = u
 	}
 
 	for i, f := range state.Files {
-		if !updateMap[f.Path] {
+		u, ok := updateMap[f.Path]
+		if !ok {
 			continue
 		}
 		path := filepath.Join(scope.RootDir, f.Path)
@@ -634,13 +878,16 @@ func updateScopedStateHashes(scope *InstallScope, updates []syncUpdate) error {
 		}
 		state.Files[i].Hash = hash
 		state.Files[i].Status = ""
+		if u.Source != "" {
+			state.Files[i].Source = u.Source
+		}
 	}
 
 	return writeScopedState(scope, state)
 }
 
 // clearResolvedConflicts clears conflict status for files that currently match source.
-func clearResolvedConflicts(scope *InstallScope, sourceDir string, conflictPaths []string) error {
+func clearResolvedConflicts(scope *InstallScope, resolver *SourceResolver, conflictPaths []string) error {
 	if len(conflictPaths) == 0 {
 		return nil
 	}
@@ -655,7 +902,6 @@ func clearResolvedConflicts(scope *InstallScope, sourceDir string, conflictPaths
 		conflictSet[p] = true
 	}
 
-	resolver := NewSourceResolver(sourceDir)
 	changed := false
 	for i, f := range state.Files {
 		if !conflictSet[f.Path] {
@@ -663,16 +909,20 @@ func clearResolvedConflicts(scope *InstallScope, sourceDir string, conflictPaths
 		}
 
 		localFull := filepath.Join(scope.RootDir, f.Path)
-		sourcePath := resolver.MapLocalPath(f.Path, scope.IsUser())
-		sourceFull := filepath.Join(sourceDir, sourcePath)
+		sourcePath, layer := resolver.MapLocalPathLayer(unscopedPath(f.Path, f.Subproject), scope.IsUser())
+		sourceFull := filepath.Join(layer.Dir, sourcePath)
 		isDir := strings.HasSuffix(f.Path, "/")
 
 		localHash, localErr := comparableArtifactHash(localFull, isDir)
-		sourceHash, sourceErr := comparableArtifactHash(sourceFull, isDir)
+		sourceHash, sourceErr := sourceArtifactHash(sourceFull, isDir, f.Subproject)
 		if localErr != nil || sourceErr != nil {
 			continue
 		}
-		if localHash == sourceHash && state.Files[i].Status != "" {
+		// A merge conflict counts as resolved once its markers are gone;
+		// the edited result is merged as local changes on the next sync.
+		resolved := localHash == sourceHash ||
+			(hasBase(scope, f.Path) && !artifactHasConflictMarkers(localFull, isDir))
+		if resolved && state.Files[i].Status != "" {
 			state.Files[i].Status = ""
 			changed = true
 
@@ -698,6 +948,11 @@ func updateStateHashes(targetDir string, updates []syncUpdate) error {
 // markFilesIgnored updates the state file to mark the given paths as "ignored".
 // This prevents future syncs from re-adding files that were intentionally deleted.
 func markFilesIgnored(scope *InstallScope, paths []string) error {
+	return markFilesStatus(scope, paths, fileStatusIgnored)
+}
+
+// markFilesStatus sets the state status of the given paths.
+func markFilesStatus(scope *InstallScope, paths []string, status string) error {
 	state, err := readScopedState(scope)
 	if err != nil || state == nil {
 		return nil
@@ -710,7 +965,7 @@ func markFilesIgnored(scope *InstallScope, paths []string) error {
 
 	for i, f := range state.Files {
 		if pathSet[f.Path] {
-			state.Files[i].Status = fileStatusIgnored
+			state.Files[i].Status = status
 		}
 	}
 
@@ -737,29 +992,33 @@ func removeFilesFromState(scope *InstallScope, paths []string) error {
 	}
 	state.Files = keptFiles
 
-	if len(state.Files) == 0 {
-		return os.Remove(scope.StatePath())
+	if len(state.Files) == 0 && len(state.MCPServers) == 0 {
+		return removeScopedState(scope)
 	}
 
 	return writeScopedState(scope, state)
 }
 
 func outputJSON(v interface{}) error {
-	enc := json.NewEncoder(os.Stdout)
+	return writeJSON(os.Stdout, v)
+}
+
+func writeJSON(w io.Writer, v interface{}) error {
+	enc := json.NewEncoder(w)
 	enc.SetIndent("", "  ")
 	return enc.Encode(v)
 }
 
 // reportNewItems prints a notice if the source has new items not yet installed.
-func reportNewItems(scope *InstallScope, sourceDir string) {
-	newItems := detectNewItems(scope, sourceDir)
+func reportNewItems(w io.Writer, scope *InstallScope, resolver *SourceResolver) {
+	newItems := detectNewItems(scope, resolver)
 	if len(newItems) == 0 {
 		return
 	}
-	fmt.Println()
-	fmt.Printf("%s %d new item(s) in source not yet installed:\n", dim("ℹ"), len(newItems))
+	fmt.Fprintln(w)
+	fmt.Fprintf(w, "%s %d new item(s) in source not yet installed:\n", dim("ℹ"), len(newItems))
 	for _, item := range newItems {
-		fmt.Printf("    %s\n", item)
+		fmt.Fprintf(w, "    %s\n", item)
 	}
-	fmt.Printf("  Run %s to add them.\n", bold("nav-pilot install --user"))
+	fmt.Fprintf(w, "  Run %s to add them.\n", bold("nav-pilot install --user"))
 }
@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"io"
 	"os"
+	"os/exec"
 	"path/filepath"
 	"strings"
 	"testing"
@@ -32,7 +33,7 @@ func TestResolveSyncFiles_WithState(t *testing.T) {
 
 	writeState(dir, state)
 
-	files, collection, err := resolveSyncFiles(ScopeRepo(dir), sourceDir, false)
+	files, collection, err := resolveSyncFiles(ScopeRepo(dir), NewSourceResolver(sourceDir), false)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -64,7 +65,7 @@ func TestResolveSyncFiles_ConflictHandling(t *testing.T) {
 	writeState(dir, state)
 
 	// Default sync check should skip conflicts
-	files, _, err := resolveSyncFiles(ScopeRepo(dir), sourceDir, false)
+	files, _, err := resolveSyncFiles(ScopeRepo(dir), NewSourceResolver(sourceDir), false)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -73,7 +74,7 @@ func TestResolveSyncFiles_ConflictHandling(t *testing.T) {
 	}
 
 	// Apply mode should include conflicts so they can be overwritten
-	files, _, err = resolveSyncFiles(ScopeRepo(dir), sourceDir, true)
+	files, _, err = resolveSyncFiles(ScopeRepo(dir), NewSourceResolver(sourceDir), true)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -101,7 +102,7 @@ func TestResolveSyncFiles_AutoDetect(t *testing.T) {
 	os.MkdirAll(filepath.Join(sourceDir, "skills", "api-design"), 0o755)
 	os.WriteFile(filepath.Join(sourceDir, "skills", "api-design", "SKILL.md"), []byte("# API"), 0o644)
 
-	files, collection, err := resolveSyncFiles(ScopeRepo(targetDir), sourceDir, false)
+	files, collection, err := resolveSyncFiles(ScopeRepo(targetDir), NewSourceResolver(sourceDir), false)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -146,7 +147,7 @@ func TestResolveSyncFiles_AutoDetect_RootLevelSource(t *testing.T) {
 	os.MkdirAll(filepath.Join(sourceDir, "skills", "api-design"), 0o755)
 	os.WriteFile(filepath.Join(sourceDir, "skills", "api-design", "SKILL.md"), []byte("new"), 0o644)
 
-	files, _, err := resolveSyncFiles(ScopeRepo(targetDir), sourceDir, false)
+	files, _, err := resolveSyncFiles(ScopeRepo(targetDir), NewSourceResolver(sourceDir), false)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -181,7 +182,7 @@ func TestCheckSyncFile_CarriesSourcePath(t *testing.T) {
 		sourcePath: filepath.Join("skills", "s") + "/",
 		isDir:      true,
 	}
-	u, err := checkSyncFile(targetDir, sourceDir, sf)
+	u, err := checkSyncFile(ScopeRepo(targetDir), sourceDir, sf)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -404,7 +405,7 @@ func TestResolveSyncFiles_UserScope_NoState_ReturnsEmpty(t *testing.T) {
 		SupportedTypes: []string{"agent", "skill", "instruction"},
 	}
 
-	files, collection, err := resolveSyncFiles(scope, "", false)
+	files, collection, err := resolveSyncFiles(scope, NewSourceResolver(""), false)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -530,7 +531,7 @@ func TestResolveSyncFiles_SkipsIgnoredFiles(t *testing.T) {
 	}
 	writeState(dir, state)
 
-	files, _, err := resolveSyncFiles(ScopeRepo(dir), sourceDir, false)
+	files, _, err := resolveSyncFiles(ScopeRepo(dir), NewSourceResolver(sourceDir), false)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -564,7 +565,7 @@ func TestResolveSyncFiles_SkipsConflictedFiles(t *testing.T) {
 	}
 	writeState(dir, state)
 
-	files, _, err := resolveSyncFiles(ScopeRepo(dir), sourceDir, false)
+	files, _, err := resolveSyncFiles(ScopeRepo(dir), NewSourceResolver(sourceDir), false)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -648,7 +649,7 @@ func TestStateFile_BackwardsCompat_NoStatusField(t *testing.T) {
 	}
 
 	// resolveSyncFiles should include all files (none ignored)
-	files, _, err := resolveSyncFiles(ScopeRepo(dir), "", false)
+	files, _, err := resolveSyncFiles(ScopeRepo(dir), NewSourceResolver(""), false)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -699,7 +700,7 @@ func TestResolveSyncFiles_AutoDetect_RootLevelAgents(t *testing.T) {
 	os.MkdirAll(filepath.Join(sourceDir, "agents"), 0o755)
 	os.WriteFile(filepath.Join(sourceDir, "agents", "nais.agent.md"), []byte("new"), 0o644)
 
-	files, _, err := resolveSyncFiles(ScopeRepo(targetDir), sourceDir, false)
+	files, _, err := resolveSyncFiles(ScopeRepo(targetDir), NewSourceResolver(sourceDir), false)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -731,7 +732,7 @@ func TestResolveSyncFiles_AutoDetect_RootLevelInstructions(t *testing.T) {
 	os.MkdirAll(filepath.Join(sourceDir, "instructions"), 0o755)
 	os.WriteFile(filepath.Join(sourceDir, "instructions", "go.instructions.md"), []byte("new"), 0o644)
 
-	files, _, err := resolveSyncFiles(ScopeRepo(targetDir), sourceDir, false)
+	files, _, err := resolveSyncFiles(ScopeRepo(targetDir), NewSourceResolver(sourceDir), false)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -763,7 +764,7 @@ func TestResolveSyncFiles_AutoDetect_RootLevelPromptDir(t *testing.T) {
 	os.MkdirAll(filepath.Join(sourceDir, "prompts", "review"), 0o755)
 	os.WriteFile(filepath.Join(sourceDir, "prompts", "review", "prompt.md"), []byte("new"), 0o644)
 
-	files, _, err := resolveSyncFiles(ScopeRepo(targetDir), sourceDir, false)
+	files, _, err := resolveSyncFiles(ScopeRepo(targetDir), NewSourceResolver(sourceDir), false)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -790,7 +791,7 @@ func TestCmdSyncAuto_BothScopes(t *testing.T) {
 	// cmdSyncAuto with no installed scopes should report nothing
 	emptyDir := t.TempDir()
 	os.MkdirAll(filepath.Join(emptyDir, ".git"), 0o755)
-	err := cmdSyncAuto(emptyDir, "", "", false, false)
+	err := cmdSyncAuto(emptyDir, "", "", lockFollow, false, false)
 	if err != nil {
 		t.Fatalf("expected nil for empty scopes, got: %v", err)
 	}
@@ -808,7 +809,7 @@ func TestCmdSyncAuto_NoInstall(t *testing.T) {
 	r, w, _ := os.Pipe()
 	os.Stdout = w
 
-	err := cmdSyncAuto(dir, "", "", false, false)
+	err := cmdSyncAuto(dir, "", "", lockFollow, false, false)
 
 	w.Close()
 	out, _ := io.ReadAll(r)
@@ -845,7 +846,7 @@ func TestCmdSyncAuto_BothScopes_PrintsScopeFeedback(t *testing.T) {
 
 	origCmdSyncFn := cmdSyncFn
 	t.Cleanup(func() { cmdSyncFn = origCmdSyncFn })
-	cmdSyncFn = func(scope *InstallScope, ref, sourceRepo string, apply, jsonOutput bool) error {
+	cmdSyncFn = func(scope *InstallScope, ref, sourceRepo string, lockMode syncLockMode, apply, jsonOutput bool) error {
 		return nil
 	}
 
@@ -853,7 +854,7 @@ func TestCmdSyncAuto_BothScopes_PrintsScopeFeedback(t *testing.T) {
 	r, w, _ := os.Pipe()
 	os.Stdout = w
 
-	err = cmdSyncAuto(repoDir, "", "", false, false)
+	err = cmdSyncAuto(repoDir, "", "", lockFollow, false, false)
 
 	w.Close()
 	out, _ := io.ReadAll(r)
@@ -907,7 +908,7 @@ func TestSync_RemoteDeletions(t *testing.T) {
 	}
 
 	// 3. Sync check (dry run): should report deletion and return errUpdatesAvailable
-	err := cmdSync(targetScope, "", "", false, false)
+	err := cmdSync(targetScope, "", "", lockFollow, false, false)
 	if err != errUpdatesAvailable {
 		t.Fatalf("expected errUpdatesAvailable, got %v", err)
 	}
@@ -918,7 +919,7 @@ func TestSync_RemoteDeletions(t *testing.T) {
 	}
 
 	// 4. Sync apply: should delete the deprecated file and update the state
-	err = cmdSync(targetScope, "", "", true, false)
+	err = cmdSync(targetScope, "", "", lockFollow, true, false)
 	if err != nil {
 		t.Fatalf("sync apply failed: %v", err)
 	}
@@ -987,8 +988,160 @@ func TestSync_DirectoryPromptAndLocalSource(t *testing.T) {
 	}
 
 	// 3. Test that sync works correctly using the tracked local source path
-	err = cmdSync(targetScope, "", "", false, false)
+	err = cmdSync(targetScope, "", "", lockFollow, false, false)
 	if err != nil && err != errUpdatesAvailable {
 		t.Fatalf("sync check failed: %v", err)
 	}
 }
+
+func TestLayeredSource_InstallRecordsLayerAndSyncUsesOverlay(t *testing.T) {
+	dir := t.TempDir()
+	os.MkdirAll(filepath.Join(dir, ".git"), 0o755)
+	baseDir := t.TempDir()
+	teamDir := t.TempDir()
+
+	os.MkdirAll(filepath.Join(baseDir, "collections", "kotlin-backend"), 0o755)
+	os.WriteFile(filepath.Join(baseDir, "collections", "kotlin-backend", "manifest.json"),
+		[]byte(`{"name":"kotlin-backend","agents":["nais","shared"]}`), 0o644)
+	os.MkdirAll(filepath.Join(baseDir, "agents"), 0o755)
+	os.WriteFile(filepath.Join(baseDir, "agents", "nais.agent.md"), []byte("# Nais"), 0o644)
+	os.WriteFile(filepath.Join(baseDir, "agents", "shared.agent.md"), []byte("# Shared (base)"), 0o644)
+	os.MkdirAll(filepath.Join(teamDir, "agents"), 0o755)
+	os.WriteFile(filepath.Join(teamDir, "agents", "shared.agent.md"), []byte("# Shared (team)"), 0o644)
+
+	newSource := func(sha string) *source.Source {
+		return &source.Source{
+			Dir: baseDir, SHA: sha, Version: "dev", Repo: "navikt/copilot,team/agents",
+			Overlays: []*source.Source{{Dir: teamDir, SHA: "team-" + sha, Repo: "team/agents"}},
+		}
+	}
+
+	scope := ScopeRepo(dir)
+	if err := cmdInstallFromSource("kotlin-backend", newSource("v1"), scope, false, false, false); err != nil {
+		t.Fatalf("install: %v", err)
+	}
+
+	data, _ := os.ReadFile(filepath.Join(dir, ".github", "agents", "shared.agent.md"))
+	if string(data) != "# Shared (team)" {
+		t.Errorf("shared agent = %q, want overlay content", data)
+	}
+
+	state, err := readScopedState(scope)
+	if err != nil || state == nil {
+		t.Fatalf("readScopedState: %v", err)
+	}
+	sources := map[string]string{}
+	for _, f := range state.Files {
+		sources[f.Path] = f.Source
+	}
+	if sources[".github/agents/nais.agent.md"] != "navikt/copilot" {
+		t.Errorf("nais source = %q, want navikt/copilot", sources[".github/agents/nais.agent.md"])
+	}
+	if sources[".github/agents/shared.agent.md"] != "team/agents" {
+		t.Errorf("shared source = %q, want team/agents", sources[".github/agents/shared.agent.md"])
+	}
+	if len(state.Sources) != 2 || state.Sources[1].SHA != "team-v1" {
+		t.Errorf("state.Sources = %+v, want both layers", state.Sources)
+	}
+
+	// Update the overlay copy; sync must compare against and apply from the team layer.
+	os.WriteFile(filepath.Join(teamDir, "agents", "shared.agent.md"), []byte("# Shared (team v2)"), 0o644)
+
+	origResolveSourceForSync := resolveSourceForSync
+	t.Cleanup(func() { resolveSourceForSync = origResolveSourceForSync })
+	resolveSourceForSync = func(ref, sourceRepo string) (*source.Source, error) {
+		if sourceRepo != "navikt/copilot,team/agents" {
+			t.Errorf("sync sourceRepo = %q, want layered list from state", sourceRepo)
+		}
+		return newSource("v2"), nil
+	}
+
+	if err := cmdSync(scope, "", "", lockFollow, true, false); err != nil {
+		t.Fatalf("sync apply: %v", err)
+	}
+	data, _ = os.ReadFile(filepath.Join(dir, ".github", "agents", "shared.agent.md"))
+	if string(data) != "# Shared (team v2)" {
+		t.Errorf("shared agent after sync = %q, want updated overlay content", data)
+	}
+	state, _ = readScopedState(scope)
+	if len(state.Sources) != 2 || state.Sources[1].SHA != "team-v2" {
+		t.Errorf("state.Sources after sync = %+v, want team-v2", state.Sources)
+	}
+}
+
+func TestSync_ChangelogBetweenRevisions(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+	dir := t.TempDir()
+	sourceDir := t.TempDir()
+	git := func(args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", args...)
+		cmd.Dir = sourceDir
+		cmd.Env = append(os.Environ(), "GIT_AUTHOR_NAME=Kari", "GIT_AUTHOR_EMAIL=k@nav.no", "GIT_COMMITTER_NAME=Kari", "GIT_COMMITTER_EMAIL=k@nav.no")
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+	commit := func(path, content, msg string) {
+		t.Helper()
+		os.MkdirAll(filepath.Join(sourceDir, filepath.Dir(path)), 0o755)
+		os.WriteFile(filepath.Join(sourceDir, path), []byte(content), 0o644)
+		git("add", "-A")
+		git("commit", "--quiet", "-m", msg)
+	}
+	git("init", "--quiet")
+	commit("agents/nais.agent.md", "# Nais\n", "Add nais agent")
+
+	src, err := source.ResolveSource("", sourceDir, "dev")
+	if err != nil {
+		t.Fatal(err)
+	}
+	scope := ScopeRepo(dir)
+	captureStdout(func() {
+		if err := cmdAddFromSource("agent", "nais", src, scope, false, false, false); err != nil {
+			t.Fatal(err)
+		}
+	})
+
+	commit("agents/nais.agent.md", "# Nais\n\nv2\n", "Explain Nais alerts")
+	commit("README.md", "readme\n", "Unrelated change")
+	commit("agents/nais.agent.md", "# Nais\n\nv3\n", "Tighten Nais tone")
+
+	orig := resolveSourceForSync
+	t.Cleanup(func() { resolveSourceForSync = orig })
+	resolveSourceForSync = func(ref, sourceRepo string) (*source.Source, error) {
+		return source.ResolveSource("", sourceDir, "dev")
+	}
+
+	out := captureStdout(func() {
+		if err := cmdSync(scope, "", "", lockFollow, false, true); err != errUpdatesAvailable {
+			t.Fatalf("sync: got %v, want errUpdatesAvailable", err)
+		}
+	})
+	var result syncResult
+	if err := json.Unmarshal([]byte(out), &result); err != nil {
+		t.Fatalf("parsing %q: %v", out, err)
+	}
+	if len(result.Updates) != 1 {
+		t.Fatalf("updates = %+v", result.Updates)
+	}
+	var subjects []string
+	for _, e := range result.Updates[0].Changelog {
+		subjects = append(subjects, e.Subject)
+		if e.Author != "Kari" || len(e.Commit) != 40 || e.Date == "" {
+			t.Errorf("entry = %+v", e)
+		}
+	}
+	if got := strings.Join(subjects, "|"); got != "Tighten Nais tone|Explain Nais alerts" {
+		t.Errorf("changelog = %s", got)
+	}
+
+	out = captureStdout(func() {
+		cmdSync(scope, "", "", lockFollow, false, false)
+	})
+	if !strings.Contains(out, "Tighten Nais tone") || !strings.Contains(out, "(Kari, ") {
+		t.Errorf("text output missing changelog:\n%s", out)
+	}
+}
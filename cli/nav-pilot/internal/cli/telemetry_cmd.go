@@ -0,0 +1,178 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	providerpkg "github.com/navikt/copilot/cli/nav-pilot/internal/provider"
+)
+
+// cmdTelemetry dispatches `nav-pilot telemetry <subcommand>`.
+func cmdTelemetry(args []string, jsonOutput bool) error {
+	if len(args) == 0 {
+		return fmt.Errorf("telemetry requires a subcommand.\n\nUsage: nav-pilot telemetry <subcommand> [options]\n\nSubcommands:\n  status    Show where metrics are sent and what is buffered offline\n  inspect   Run a command and show every metric it records, without sending it")
+	}
+	switch args[0] {
+	case "status":
+		return cmdTelemetryStatus(jsonOutput)
+	case "inspect":
+		return cmdTelemetryInspect(args[1:])
+	default:
+		return fmt.Errorf("unknown telemetry subcommand: %q\n\nSubcommands: status, inspect", args[0])
+	}
+}
+
+// cmdTelemetryStatus shows the export endpoint and the exports queued in the
+// offline buffer, oldest first.
+func cmdTelemetryStatus(jsonOutput bool) error {
+	status, err := readTelemetryBuffer()
+	if err != nil {
+		return fmt.Errorf("reading telemetry buffer: %w", err)
+	}
+	optedIn := telemetryOptedIn()
+	status.Enabled = status.Enabled && optedIn
+	if jsonOutput {
+		return outputJSON(status)
+	}
+
+	enabled := green("enabled") + dim(" (opt out: nav-pilot config set telemetry false)")
+	switch {
+	case !optedIn:
+		enabled = yellow("disabled") + dim(" (telemetry = false in "+configPath()+")")
+	case !status.Enabled:
+		enabled = yellow("disabled") + dim(" (NAV_PILOT_TELEMETRY_ENABLED)")
+	}
+	fmt.Printf("Telemetry: %s\n", enabled)
+	fmt.Printf("Endpoint:  %s\n", status.Endpoint)
+	fmt.Printf("Buffer:    %s\n", status.Dir)
+	fmt.Printf("Caps:      %s, exports older than %s are dropped\n", formatSize(status.MaxBytes), formatBufferAge(status.MaxAge))
+	fmt.Println()
+
+	if len(status.Queued) == 0 {
+		fmt.Printf("%s No exports queued.\n", green("✓"))
+		return nil
+	}
+	fmt.Println(bold(fmt.Sprintf("Queued exports (%d, %s):", len(status.Queued), formatSize(status.Bytes))))
+	for _, q := range status.Queued {
+		fmt.Printf("  %s %s  %s\n", yellow("~"), q.QueueAt.Local().Format("2006-01-02 15:04:05"), dim(formatSize(q.Bytes)))
+	}
+	fmt.Println()
+	fmt.Printf("%s Replayed to %s on the next run that reaches it.\n", dim("→"), status.Endpoint)
+	return nil
+}
+
+// formatBufferAge renders the buffer's age cap in days when it is whole days.
+func formatBufferAge(s string) string {
+	d, err := time.ParseDuration(s)
+	if err != nil || d%(24*time.Hour) != 0 {
+		return s
+	}
+	return fmt.Sprintf("%d days", d/(24*time.Hour))
+}
+
+// telemetryOptedIn reports whether the user config allows telemetry. The
+// `telemetry = false` key is the permanent opt-out; NAV_PILOT_TELEMETRY_ENABLED
+// is checked separately by the telemetry package. An unreadable config counts
+// as opted out, so a typo never re-enables telemetry.
+func telemetryOptedIn() bool {
+	cfg, err := readConfig()
+	if err != nil {
+		return false
+	}
+	return cfg == nil || cfg.Telemetry == nil || *cfg.Telemetry
+}
+
+// rtkInstalledLabel is the rtk_installed dimension of the info metric.
+func rtkInstalledLabel() string {
+	if isRtkInstalled() {
+		return "true"
+	}
+	return "false"
+}
+
+// cmdTelemetryInspect runs args as a nav-pilot command with a local recorder
+// swapped in, then prints every metric and attribute the command recorded.
+// Nothing from the inspected command is sent. With --json (before the
+// command) the command's own output goes to stderr.
+func cmdTelemetryInspect(args []string) error {
+	jsonOutput := false
+	for len(args) > 0 && args[0] == "--json" {
+		jsonOutput = true
+		args = args[1:]
+	}
+	if len(args) == 0 {
+		return fmt.Errorf("usage: nav-pilot telemetry inspect [--json] <command> [args...]\n\nExample: nav-pilot telemetry inspect sync --dry-run")
+	}
+	if args[0] == "telemetry" {
+		return fmt.Errorf("telemetry inspect cannot inspect telemetry commands")
+	}
+
+	ctx := context.Background()
+	insp, err := newTelemetryInspector(ctx, Version, rtkInstalledLabel())
+	if err != nil {
+		return fmt.Errorf("creating telemetry inspector: %w", err)
+	}
+	prev := telemetry
+	telemetry = insp
+	providerpkg.SetTelemetry(insp)
+	defer func() {
+		telemetry = prev
+		providerpkg.SetTelemetry(prev)
+	}()
+	// Mirror what Main records at startup.
+	for _, p := range builtinProviders() {
+		telemetry.RecordClientAvailable(p.ID(), p.Available())
+	}
+
+	stdout := os.Stdout
+	if jsonOutput {
+		os.Stdout = os.Stderr
+	}
+	runErr := run(args)
+	os.Stdout = stdout
+
+	result, err := insp.Collect(ctx)
+	if err != nil {
+		return err
+	}
+	if jsonOutput {
+		if err := outputJSON(map[string]interface{}{
+			"command":  strings.Join(args, " "),
+			"endpoint": result.Endpoint,
+			"sent":     false,
+			"resource": result.Resource,
+			"metrics":  result.Points,
+		}); err != nil {
+			return err
+		}
+		return runErr
+	}
+
+	fmt.Println()
+	fmt.Println(bold("Telemetry recorded by: nav-pilot " + strings.Join(args, " ")))
+	fmt.Printf("%s Nothing was sent. A normal run exports this to %s\n", dim("→"), result.Endpoint)
+	fmt.Println()
+	fmt.Println(bold("Resource (sent with every metric):"))
+	for _, kv := range sortedTelemetryAttributes(result.Resource) {
+		fmt.Printf("  %s\n", kv)
+	}
+	fmt.Println()
+	fmt.Println(bold(fmt.Sprintf("Metrics (%d):", len(result.Points))))
+	width := 0
+	for _, p := range result.Points {
+		width = max(width, len(p.Name))
+	}
+	for _, p := range result.Points {
+		value := fmt.Sprintf("%d", p.Value)
+		if p.Kind == "histogram" {
+			value = fmt.Sprintf("sum=%d count=%d", p.Value, p.Count)
+		}
+		fmt.Printf("  %-*s  %-9s  %-8s  %s\n", width, p.Name, p.Kind, value, dim(strings.Join(sortedTelemetryAttributes(p.Attributes), " ")))
+	}
+	fmt.Println()
+	fmt.Printf("Opt out permanently: %s\n", bold("nav-pilot config set telemetry false"))
+	return runErr
+}
@@ -0,0 +1,145 @@
+package cli
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestTelemetryStatus_Command(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("USERPROFILE", home)
+	t.Setenv("NAV_PILOT_TELEMETRY_ENDPOINT", "https://collector.example/v1/metrics")
+
+	out := captureStdout(func() {
+		if err := run([]string{"telemetry", "status"}); err != nil {
+			t.Fatalf("telemetry status: %v", err)
+		}
+	})
+	if !strings.Contains(out, "https://collector.example/v1/metrics") || !strings.Contains(out, "No exports queued") {
+		t.Errorf("empty buffer output = %q", out)
+	}
+
+	buf := filepath.Join(home, ".nav-pilot", "telemetry-buffer")
+	os.MkdirAll(buf, 0o700)
+	os.WriteFile(filepath.Join(buf, "1792324800000000000.pb"), make([]byte, 2048), 0o600)
+	os.WriteFile(filepath.Join(buf, "notes.txt"), []byte("ignored"), 0o600)
+
+	out = captureStdout(func() {
+		if err := run([]string{"telemetry", "status"}); err != nil {
+			t.Fatal(err)
+		}
+	})
+	if !strings.Contains(out, "Queued exports (1, 2.0 KB)") || !strings.Contains(out, "7 days") {
+		t.Errorf("queued output = %q", out)
+	}
+
+	out = captureStdout(func() {
+		if err := run([]string{"telemetry", "status", "--json"}); err != nil {
+			t.Fatal(err)
+		}
+	})
+	var status struct {
+		Endpoint string `json:"endpoint"`
+		Bytes    int64  `json:"bytes"`
+		Queued   []struct {
+			QueuedAt string `json:"queued_at"`
+		} `json:"queued"`
+	}
+	if err := json.Unmarshal([]byte(out), &status); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, out)
+	}
+	if len(status.Queued) != 1 || status.Bytes != 2048 || status.Queued[0].QueuedAt != "2026-10-18T12:00:00Z" {
+		t.Errorf("status = %+v", status)
+	}
+}
+
+func TestTelemetry_UnknownSubcommand(t *testing.T) {
+	if err := cmdTelemetry(nil, false); err == nil {
+		t.Error("expected error without subcommand")
+	}
+	if err := cmdTelemetry([]string{"flush"}, false); err == nil || !strings.Contains(err.Error(), "unknown telemetry subcommand") {
+		t.Errorf("err = %v", err)
+	}
+}
+
+func TestTelemetryInspect_JSON(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("USERPROFILE", home)
+	t.Chdir(t.TempDir())
+
+	var out string
+	captureStderr(func() {
+		out = captureStdout(func() {
+			if err := run([]string{"telemetry", "inspect", "--json", "list", "--installed"}); err != nil {
+				t.Fatalf("telemetry inspect: %v", err)
+			}
+		})
+	})
+	var got struct {
+		Command string `json:"command"`
+		Sent    bool   `json:"sent"`
+		Metrics []struct {
+			Name       string            `json:"name"`
+			Value      int64             `json:"value"`
+			Attributes map[string]string `json:"attributes"`
+		} `json:"metrics"`
+	}
+	if err := json.Unmarshal([]byte(out), &got); err != nil {
+		t.Fatalf("stdout must hold only the JSON report: %v\n%s", err, out)
+	}
+	if got.Command != "list --installed" || got.Sent {
+		t.Errorf("report = %+v", got)
+	}
+	found := false
+	for _, m := range got.Metrics {
+		if m.Name == "nav_pilot_command_total" && m.Attributes["command"] == "list" && m.Value == 1 {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("command_total for list not recorded: %+v", got.Metrics)
+	}
+	if _, ok := telemetry.(noopTelemetry); !ok {
+		t.Errorf("recorder not restored after inspect: %T", telemetry)
+	}
+
+	for _, args := range [][]string{{"telemetry", "inspect"}, {"telemetry", "inspect", "telemetry", "status"}} {
+		if err := run(args); err == nil {
+			t.Errorf("run(%v): expected error", args)
+		}
+	}
+}
+
+func TestTelemetryOptOut_Config(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("USERPROFILE", home)
+	cfgPath := filepath.Join(home, "config.toml")
+	t.Setenv("NAV_PILOT_CONFIG", cfgPath)
+
+	if !telemetryOptedIn() {
+		t.Error("no config file should leave telemetry on")
+	}
+	os.WriteFile(cfgPath, []byte("version = 1\ntelemetry = false\n"), 0o600)
+	if telemetryOptedIn() {
+		t.Error("telemetry = false should opt out")
+	}
+	out := captureStdout(func() {
+		if err := run([]string{"telemetry", "status"}); err != nil {
+			t.Fatal(err)
+		}
+	})
+	if !strings.Contains(out, "disabled") || !strings.Contains(out, "telemetry = false") {
+		t.Errorf("status output = %q", out)
+	}
+
+	os.WriteFile(cfgPath, []byte("version = 1\ntelemetry = [broken\n"), 0o600)
+	if telemetryOptedIn() {
+		t.Error("an unreadable config must not re-enable telemetry")
+	}
+}
@@ -80,7 +80,7 @@ func configModelLabel(model string) string {
 	if strings.TrimSpace(model) == "" {
 		return "unset"
 	}
-	for _, p := range allProviders() {
+	for _, p := range builtinProviders() {
 		for _, m := range p.KnownModels() {
 			if strings.EqualFold(m.ID, model) {
 				return m.ID
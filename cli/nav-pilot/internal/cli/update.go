@@ -5,6 +5,7 @@ import (
 	"crypto/sha256"
 	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
@@ -22,6 +23,9 @@ var (
 	downloadURL = "https://github.com/navikt/copilot/releases/download"
 )
 
+// releaseTagPrefix namespaces nav-pilot releases in the monorepo.
+const releaseTagPrefix = "nav-pilot/"
+
 // httpClient is the client used for all HTTP requests. Overridable in tests.
 var httpClient = &http.Client{
 	Timeout: 30 * time.Second,
@@ -36,8 +40,21 @@ type ghRelease struct {
 
 // cmdUpdate checks for a newer version and updates the binary in-place.
 // If installed via Homebrew, it tells the user to use brew upgrade instead.
-func cmdUpdate() error {
-	_, err := doUpdate()
+// With version set it installs that release and pins it; with rollback it
+// switches back to the binary that was replaced last. Releases must be signed
+// unless allowUnsigned is set.
+func cmdUpdate(version string, rollback, allowUnsigned bool) error {
+	if version != "" && rollback {
+		return fmt.Errorf("--version and --rollback are mutually exclusive")
+	}
+	switch {
+	case rollback:
+		return doRollback()
+	case version != "":
+		_, err := doInstallVersion(version, allowUnsigned)
+		return err
+	}
+	_, err := doUpdate(allowUnsigned)
 	return err
 }
 
@@ -45,11 +62,10 @@ func cmdUpdate() error {
 // downloads and installs it. It returns updated=true only if the binary was
 // actually replaced, so callers can distinguish "already up to date" (no-op)
 // from "successfully updated" and avoid re-executing when nothing changed.
-func doUpdate() (updated bool, err error) {
+// A successful check clears any pinned version.
+func doUpdate(allowUnsigned bool) (updated bool, err error) {
 	if isBrewManaged() {
-		fmt.Println("nav-pilot is managed by Homebrew.")
-		fmt.Println()
-		fmt.Println("  brew upgrade navikt/tap/nav-pilot")
+		printBrewManaged()
 		return false, nil
 	}
 
@@ -61,6 +77,11 @@ func doUpdate() (updated bool, err error) {
 		return false, fmt.Errorf("could not check for updates: %w", err)
 	}
 
+	if pinned := readPinnedVersion(); pinned != "" {
+		clearPinnedVersion()
+		fmt.Printf("→ Unpinned nav-pilot %s\n", pinned)
+	}
+
 	if !versionNewer(latest, current) {
 		fmt.Printf("✓ nav-pilot is up to date (%s)\n", current)
 		return false, nil
@@ -68,15 +89,87 @@ func doUpdate() (updated bool, err error) {
 
 	fmt.Printf("Update available: %s → %s\n", current, latest)
 
-	self, err := os.Executable()
+	if err := installRelease(tag, allowUnsigned); err != nil {
+		return false, err
+	}
+
+	// Invalidate the staleness cache now that we're on the latest version,
+	// so a subsequent process doesn't see a stale "update available" entry
+	// (e.g. if this rename raced with a fresh release check elsewhere).
+	artifacts.WriteCache(&artifacts.StalenessCache{
+		LastChecked:   time.Now().UTC().Format(time.RFC3339),
+		LatestVersion: latest,
+	})
+
+	fmt.Printf("✓ Updated to nav-pilot %s\n", latest)
+	return true, nil
+}
+
+// doInstallVersion installs a specific release and pins it, so staleness
+// checks stop offering upgrades until the user runs `nav-pilot upgrade`.
+// version is either a bare version or a full "nav-pilot/<version>" tag.
+func doInstallVersion(version string, allowUnsigned bool) (updated bool, err error) {
+	if isBrewManaged() {
+		printBrewManaged()
+		return false, nil
+	}
+	ver := strings.TrimPrefix(strings.TrimSpace(version), releaseTagPrefix)
+	if ver == "" {
+		return false, fmt.Errorf("--version requires a release, e.g. 2026.04.13-170138-abc1234")
+	}
+
+	if ver != Version {
+		fmt.Printf("Installing nav-pilot %s (current: %s)\n", ver, Version)
+		if err := installRelease(releaseTagPrefix+ver, allowUnsigned); err != nil {
+			return false, err
+		}
+		updated = true
+	}
+	if err := writePinnedVersion(ver); err != nil {
+		return updated, err
+	}
+	fmt.Printf("✓ nav-pilot %s installed and pinned\n", ver)
+	fmt.Printf("%s Run %s to return to the latest release\n", dim("→"), bold("nav-pilot upgrade"))
+	return updated, nil
+}
+
+// doRollback switches back to the binary that the last update replaced and
+// pins it, so the release that was rolled back is not offered again.
+func doRollback() error {
+	if isBrewManaged() {
+		printBrewManaged()
+		return fmt.Errorf("rollback is not supported for Homebrew installs")
+	}
+	prev, err := previousKeptVersion(Version)
 	if err != nil {
-		return false, fmt.Errorf("cannot determine binary path: %w", err)
+		return err
 	}
-	self, err = filepath.EvalSymlinks(self)
+	if prev == nil {
+		dir, _ := versionsDir()
+		return fmt.Errorf("no previous nav-pilot version kept in %s", dir)
+	}
+	bin, err := os.ReadFile(prev.Path)
 	if err != nil {
-		return false, fmt.Errorf("cannot resolve binary path: %w", err)
+		return fmt.Errorf("reading %s: %w", prev.Path, err)
+	}
+
+	fmt.Printf("Rolling back: %s → %s\n", Version, prev.Version)
+	if err := installBinary(bin); err != nil {
+		return err
+	}
+	if err := writePinnedVersion(prev.Version); err != nil {
+		return err
 	}
+	fmt.Printf("✓ Rolled back to nav-pilot %s (pinned)\n", prev.Version)
+	fmt.Printf("%s Run %s to return to the latest release\n", dim("→"), bold("nav-pilot upgrade"))
+	return nil
+}
 
+// installRelease downloads the release asset for this platform, verifies the
+// release's SHA256SUMS against its signature and the asset against
+// SHA256SUMS, and installs it over the running binary. An unsigned release is
+// refused unless allowUnsigned is set; a bad signature is always refused.
+func installRelease(tag string, allowUnsigned bool) error {
 	asset := fmt.Sprintf("nav-pilot-%s-%s", runtime.GOOS, runtime.GOARCH)
 	assetURL := fmt.Sprintf("%s/%s/%s", downloadURL, tag, asset)
 	checksumURL := fmt.Sprintf("%s/%s/SHA256SUMS", downloadURL, tag)
@@ -84,55 +177,88 @@ func doUpdate() (updated bool, err error) {
 	fmt.Printf("→ Downloading %s...\n", asset)
 	bin, err := httpGet(assetURL)
 	if err != nil {
-		return false, fmt.Errorf("download failed: %w", err)
+		return fmt.Errorf("download failed: %w", err)
 	}
 
-	if err := verifyChecksum(bin, asset, checksumURL); err != nil {
-		return false, err
+	sums, err := httpGet(checksumURL)
+	if err != nil {
+		return fmt.Errorf("failed to download checksums: %w", err)
+	}
+	if err := verifyReleaseSignature(sums, tag, checksumURL+signatureSuffix); err != nil {
+		if !errors.Is(err, errUnsigned) {
+			return err
+		}
+		if !allowUnsigned {
+			return fmt.Errorf("%w\n\nRefusing to install an unverified binary. Re-run with --allow-unsigned to install it anyway", err)
+		}
+		fmt.Fprintf(os.Stderr, "%s %v — installing anyway (--allow-unsigned)\n", yellow("⚠"), err)
+	}
+
+	if err := checkChecksum(bin, asset, sums); err != nil {
+		return err
+	}
+	return installBinary(bin)
+}
+
+// installBinary keeps a copy of the running binary for --rollback, then
+// atomically replaces it with bin.
+func installBinary(bin []byte) error {
+	self, err := executablePath()
+	if err != nil {
+		return err
+	}
+
+	if err := keepBinary(self, Version); err != nil {
+		fmt.Fprintf(os.Stderr, "%s Could not keep nav-pilot %s for rollback: %v\n", yellow("⚠"), Version, err)
 	}
 
 	// Atomic replace: write temp file next to binary, then rename
 	dir := filepath.Dir(self)
 	tmp, err := os.CreateTemp(dir, ".nav-pilot-update-*")
 	if err != nil {
-		return false, fmt.Errorf("cannot create temp file (is %s writable?): %w", dir, err)
+		return fmt.Errorf("cannot create temp file (is %s writable?): %w", dir, err)
 	}
 	tmpPath := tmp.Name()
 	defer os.Remove(tmpPath)
 
 	if _, err := tmp.Write(bin); err != nil {
 		tmp.Close()
-		return false, fmt.Errorf("write failed: %w", err)
+		return fmt.Errorf("write failed: %w", err)
 	}
 	tmp.Close()
 
 	if err := os.Chmod(tmpPath, 0o755); err != nil {
-		return false, fmt.Errorf("chmod failed: %w", err)
+		return fmt.Errorf("chmod failed: %w", err)
 	}
 
 	if err := os.Rename(tmpPath, self); err != nil {
-		return false, fmt.Errorf("replace failed: %w", err)
+		return fmt.Errorf("replace failed: %w", err)
 	}
-
-	// Invalidate the staleness cache now that we're on the latest version,
-	// so a subsequent process doesn't see a stale "update available" entry
-	// (e.g. if this rename raced with a fresh release check elsewhere).
-	artifacts.WriteCache(&artifacts.StalenessCache{
-		LastChecked:   time.Now().UTC().Format(time.RFC3339),
-		LatestVersion: latest,
-	})
-
-	fmt.Printf("✓ Updated to nav-pilot %s\n", latest)
-	return true, nil
+	return nil
 }
 
-// isBrewManaged returns true if the running binary lives inside a Homebrew prefix.
-func isBrewManaged() bool {
+// executablePath resolves the running binary. Overridable in tests.
+var executablePath = func() (string, error) {
 	self, err := os.Executable()
 	if err != nil {
-		return false
+		return "", fmt.Errorf("cannot determine binary path: %w", err)
 	}
 	self, err = filepath.EvalSymlinks(self)
+	if err != nil {
+		return "", fmt.Errorf("cannot resolve binary path: %w", err)
+	}
+	return self, nil
+}
+
+func printBrewManaged() {
+	fmt.Println("nav-pilot is managed by Homebrew.")
+	fmt.Println()
+	fmt.Println("  brew upgrade navikt/tap/nav-pilot")
+}
+
+// isBrewManaged returns true if the running binary lives inside a Homebrew prefix.
+func isBrewManaged() bool {
+	self, err := executablePath()
 	if err != nil {
 		return false
 	}
@@ -169,9 +295,9 @@ func fetchLatestVersion(ctx context.Context) (ver string, tag string, err error)
 	}
 
 	for _, rel := range releases {
-		if strings.HasPrefix(rel.TagName, "nav-pilot/") {
+		if strings.HasPrefix(rel.TagName, releaseTagPrefix) {
 			tag = rel.TagName
-			ver = strings.TrimPrefix(tag, "nav-pilot/")
+			ver = strings.TrimPrefix(tag, releaseTagPrefix)
 			return ver, tag, nil
 		}
 	}
@@ -198,15 +324,9 @@ func httpGet(url string) ([]byte, error) {
 	return io.ReadAll(resp.Body)
 }
 
-// verifyChecksum downloads SHA256SUMS and verifies the binary checksum.
-// Fails hard if checksums cannot be fetched or the asset entry is missing.
-func verifyChecksum(data []byte, asset, checksumURL string) error {
+// checkChecksum verifies data against the asset's entry in a SHA256SUMS file.
+func checkChecksum(data []byte, asset string, sums []byte) error {
 	fmt.Print("→ Verifying checksum...")
-	sums, err := httpGet(checksumURL)
-	if err != nil {
-		return fmt.Errorf(" failed to download checksums: %w", err)
-	}
-
 	var expected string
 	for _, line := range strings.Split(string(sums), "\n") {
 		if strings.HasSuffix(strings.TrimSpace(line), asset) {
@@ -219,12 +339,14 @@ func verifyChecksum(data []byte, asset, checksumURL string) error {
 	}
 
 	if expected == "" {
-		return fmt.Errorf(" no checksum entry found for %s", asset)
+		fmt.Println()
+		return fmt.Errorf("no checksum entry found for %s", asset)
 	}
 
 	actual := sha256sum(data)
 	if actual != expected {
-		return fmt.Errorf(" checksum mismatch!\n  Expected: %s\n  Got:      %s", expected, actual)
+		fmt.Println()
+		return fmt.Errorf("checksum mismatch!\n  Expected: %s\n  Got:      %s", expected, actual)
 	}
 
 	fmt.Println(" ✓")
@@ -2,10 +2,16 @@ package cli
 
 import (
 	"crypto/sha256"
+	"errors"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
 	"testing"
+	"time"
 )
 
 func TestIsBrewManaged(t *testing.T) {
@@ -23,61 +29,58 @@ func TestSha256sum(t *testing.T) {
 	}
 }
 
-func TestVerifyChecksum_Valid(t *testing.T) {
+func TestCheckChecksum(t *testing.T) {
 	data := []byte("binary-data")
-	checksum := sha256sum(data)
-
-	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		fmt.Fprintf(w, "%s  nav-pilot-linux-amd64\n", checksum)
-	}))
-	defer srv.Close()
-
-	err := verifyChecksum(data, "nav-pilot-linux-amd64", srv.URL+"/SHA256SUMS")
-	if err != nil {
-		t.Fatalf("expected no error, got: %v", err)
+	tests := []struct {
+		name    string
+		sums    string
+		wantErr string
+	}{
+		{"valid", sha256sum(data) + "  nav-pilot-linux-amd64\n", ""},
+		{"mismatch", strings.Repeat("0", 64) + "  nav-pilot-linux-amd64\n", "checksum mismatch"},
+		{"no entry", "abcdef1234567890  nav-pilot-linux-arm64\n", "no checksum entry"},
+		{"empty", "", "no checksum entry"},
 	}
-}
-
-func TestVerifyChecksum_Mismatch(t *testing.T) {
-	data := []byte("binary-data")
-
-	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		fmt.Fprintf(w, "0000000000000000000000000000000000000000000000000000000000000000  nav-pilot-linux-amd64\n")
-	}))
-	defer srv.Close()
-
-	err := verifyChecksum(data, "nav-pilot-linux-amd64", srv.URL+"/SHA256SUMS")
-	if err == nil {
-		t.Fatal("expected checksum mismatch error")
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var err error
+			captureStdout(func() { err = checkChecksum(data, "nav-pilot-linux-amd64", []byte(tt.sums)) })
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("expected no error, got: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.HasPrefix(err.Error(), tt.wantErr) {
+				t.Errorf("err = %v, want %q", err, tt.wantErr)
+			}
+		})
 	}
 }
 
-func TestVerifyChecksum_NoSumsFile(t *testing.T) {
-	data := []byte("binary-data")
-
-	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		w.WriteHeader(http.StatusNotFound)
-	}))
-	defer srv.Close()
+func TestInstallRelease_RefusesBadSignatureAndMissingSums(t *testing.T) {
+	const current, next = "2026.04.01-120000-aaa1111", "2026.04.13-170138-bbb2222"
+	fakeReleases(t, next)
+	self := fakeSelf(t, current)
 
-	// Should error — checksum verification is mandatory
-	err := verifyChecksum(data, "nav-pilot-linux-amd64", srv.URL+"/SHA256SUMS")
-	if err == nil {
-		t.Fatal("expected error when checksums unavailable")
+	// The .minisig is reachable but made with another key than this build's:
+	// --allow-unsigned does not cover it.
+	newTestSigner(t)
+	var err error
+	captureStderr(func() {
+		captureStdout(func() { err = installRelease(releaseTagPrefix+next, true) })
+	})
+	if err == nil || errors.Is(err, errUnsigned) {
+		t.Fatalf("bad signature err = %v, want a verification error", err)
+	}
+	if data, _ := os.ReadFile(self); string(data) != current {
+		t.Fatal("release with a bad signature was installed")
 	}
-}
-
-func TestVerifyChecksum_NoEntry(t *testing.T) {
-	data := []byte("binary-data")
-
-	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		fmt.Fprintf(w, "abcdef1234567890  nav-pilot-linux-arm64\n") // different asset
-	}))
-	defer srv.Close()
 
-	err := verifyChecksum(data, "nav-pilot-linux-amd64", srv.URL+"/SHA256SUMS")
+	// Checksums are mandatory: a release without SHA256SUMS is refused.
+	captureStdout(func() { err = installRelease(releaseTagPrefix+"2026.05.01-000000-ccc3333", true) })
 	if err == nil {
-		t.Fatal("expected error when asset entry is missing")
+		t.Fatal("expected error when the release has no checksums")
 	}
 }
 
@@ -137,3 +140,139 @@ func TestRun_UpdateCommand(t *testing.T) {
 	}
 	// Will get a network error or version mismatch, that's fine
 }
+
+// fakeReleases serves signed releases for the given versions, with the asset
+// body being the version string, and trusts the signing key.
+func fakeReleases(t *testing.T, versions ...string) {
+	t.Helper()
+	asset := fmt.Sprintf("nav-pilot-%s-%s", runtime.GOOS, runtime.GOARCH)
+	signer := newTestSigner(t)
+	mux := http.NewServeMux()
+	for _, v := range versions {
+		sums := fmt.Sprintf("%s  %s\n", sha256sum([]byte(v)), asset)
+		mux.HandleFunc("/nav-pilot/"+v+"/"+asset, func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprint(w, v)
+		})
+		mux.HandleFunc("/nav-pilot/"+v+"/SHA256SUMS", func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprint(w, sums)
+		})
+		mux.HandleFunc("/nav-pilot/"+v+"/SHA256SUMS.minisig", func(w http.ResponseWriter, r *http.Request) {
+			w.Write(signer.sign([]byte(sums), "nav-pilot/"+v))
+		})
+	}
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+
+	origURL, origClient := downloadURL, httpClient
+	downloadURL, httpClient = srv.URL, srv.Client()
+	t.Cleanup(func() { downloadURL, httpClient = origURL, origClient })
+}
+
+// fakeSelf points executablePath at a temp file standing in for the binary.
+func fakeSelf(t *testing.T, version string) string {
+	t.Helper()
+	self := filepath.Join(t.TempDir(), "nav-pilot")
+	if err := os.WriteFile(self, []byte(version), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	origPath, origVersion := executablePath, Version
+	executablePath = func() (string, error) { return self, nil }
+	Version = version
+	t.Cleanup(func() { executablePath, Version = origPath, origVersion })
+	return self
+}
+
+func TestUpdate_VersionPinsAndRollbackRestores(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("USERPROFILE", home)
+	const good, bad = "2026.04.01-120000-aaa1111", "2026.04.13-170138-bbb2222"
+	fakeReleases(t, bad)
+	self := fakeSelf(t, good)
+
+	captureStdout(func() {
+		if err := cmdUpdate("nav-pilot/"+bad, false, false); err != nil {
+			t.Fatalf("upgrade --version: %v", err)
+		}
+	})
+	if data, _ := os.ReadFile(self); string(data) != bad {
+		t.Fatalf("binary = %q, want %s", data, bad)
+	}
+	if got := readPinnedVersion(); got != bad {
+		t.Errorf("pinned = %q, want %s", got, bad)
+	}
+
+	// The running binary is now the bad release.
+	Version = bad
+	if !versionPinned() {
+		t.Error("pinned version should suppress staleness nagging")
+	}
+	captureStdout(func() {
+		if err := cmdUpdate("", true, false); err != nil {
+			t.Fatalf("upgrade --rollback: %v", err)
+		}
+	})
+	if data, _ := os.ReadFile(self); string(data) != good {
+		t.Fatalf("binary after rollback = %q, want %s", data, good)
+	}
+	if got := readPinnedVersion(); got != good {
+		t.Errorf("pinned after rollback = %q, want %s", got, good)
+	}
+	kept, _ := listKeptVersions()
+	if len(kept) != 2 || kept[0].Version != bad {
+		t.Errorf("kept = %+v, want the rolled-back release first", kept)
+	}
+
+	if err := cmdUpdate(bad, true, false); err == nil {
+		t.Error("--version and --rollback together should be rejected")
+	}
+}
+
+func TestUpdate_RollbackWithoutKeptVersion(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("USERPROFILE", home)
+	fakeSelf(t, "2026.04.01-120000-aaa1111")
+
+	err := cmdUpdate("", true, false)
+	if err == nil || !strings.Contains(err.Error(), "no previous nav-pilot version") {
+		t.Errorf("err = %v", err)
+	}
+}
+
+func TestKeepBinary_PrunesOldest(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("USERPROFILE", home)
+	self := filepath.Join(t.TempDir(), "nav-pilot")
+	os.WriteFile(self, []byte("bin"), 0o755)
+
+	base := time.Now().Add(-time.Hour)
+	for i := range keepVersions + 2 {
+		v := fmt.Sprintf("2026.04.0%d-120000-abc1234", i+1)
+		if err := keepBinary(self, v); err != nil {
+			t.Fatal(err)
+		}
+		dir, _ := versionsDir()
+		at := base.Add(time.Duration(i) * time.Minute)
+		os.Chtimes(filepath.Join(dir, v, keptBinaryName), at, at)
+	}
+	if err := keepBinary(self, "dev"); err != nil {
+		t.Fatal(err)
+	}
+
+	kept, err := listKeptVersions()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(kept) != keepVersions || kept[0].Version != "2026.04.05-120000-abc1234" {
+		t.Errorf("kept = %+v, want the %d newest", kept, keepVersions)
+	}
+}
+
+func TestRun_VersionFlagOnlyForUpgrade(t *testing.T) {
+	err := run([]string{"list", "--version", "x"})
+	if err == nil || !strings.Contains(err.Error(), "only supported for upgrade") {
+		t.Errorf("err = %v", err)
+	}
+}
@@ -0,0 +1,179 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// keepVersions is how many replaced binaries `nav-pilot upgrade` keeps in
+// ~/.nav-pilot/versions for --rollback.
+const keepVersions = 3
+
+const (
+	keptBinaryName = "nav-pilot"
+	pinnedFileName = "pinned"
+)
+
+// keptVersion is a binary kept from an earlier install.
+type keptVersion struct {
+	Version string
+	Path    string
+	KeptAt  time.Time
+}
+
+// versionsDir returns ~/.nav-pilot/versions (not created).
+func versionsDir() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".nav-pilot", "versions"), nil
+}
+
+// keepBinary copies the binary at self to versions/<version>/nav-pilot and
+// prunes the store to keepVersions. Development builds are not kept: they
+// have no version to roll back to.
+func keepBinary(self, version string) error {
+	if version == "" || version == "dev" {
+		return nil
+	}
+	dir, err := versionsDir()
+	if err != nil {
+		return err
+	}
+	data, err := os.ReadFile(self)
+	if err != nil {
+		return err
+	}
+	target := filepath.Join(dir, version)
+	if err := os.MkdirAll(target, 0o755); err != nil {
+		return fmt.Errorf("creating %s: %w", target, err)
+	}
+	path := filepath.Join(target, keptBinaryName)
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o755); err != nil {
+		return fmt.Errorf("writing %s: %w", tmp, err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		os.Remove(tmp)
+		return fmt.Errorf("writing %s: %w", path, err)
+	}
+	// The mtime orders the store: the newest kept binary is the one that was
+	// replaced last, even when the same version is kept again.
+	now := time.Now()
+	if err := os.Chtimes(path, now, now); err != nil {
+		return err
+	}
+	return pruneKeptVersions(dir)
+}
+
+// listKeptVersions returns the kept binaries, most recently kept first.
+func listKeptVersions() ([]keptVersion, error) {
+	dir, err := versionsDir()
+	if err != nil {
+		return nil, err
+	}
+	return listKeptVersionsIn(dir)
+}
+
+func listKeptVersionsIn(dir string) ([]keptVersion, error) {
+	entries, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading %s: %w", dir, err)
+	}
+	var kept []keptVersion
+	for _, e := range entries {
+		if !e.IsDir() {
+			continue
+		}
+		path := filepath.Join(dir, e.Name(), keptBinaryName)
+		info, err := os.Stat(path)
+		if err != nil {
+			continue
+		}
+		kept = append(kept, keptVersion{Version: e.Name(), Path: path, KeptAt: info.ModTime()})
+	}
+	sort.SliceStable(kept, func(i, j int) bool { return kept[i].KeptAt.After(kept[j].KeptAt) })
+	return kept, nil
+}
+
+func pruneKeptVersions(dir string) error {
+	kept, err := listKeptVersionsIn(dir)
+	if err != nil {
+		return err
+	}
+	if len(kept) <= keepVersions {
+		return nil
+	}
+	for _, k := range kept[keepVersions:] {
+		if err := os.RemoveAll(filepath.Dir(k.Path)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// previousKeptVersion returns the most recently kept binary that is not
+// current, or nil when there is nothing to roll back to.
+func previousKeptVersion(current string) (*keptVersion, error) {
+	kept, err := listKeptVersions()
+	if err != nil {
+		return nil, err
+	}
+	for _, k := range kept {
+		if k.Version != current {
+			return &k, nil
+		}
+	}
+	return nil, nil
+}
+
+// readPinnedVersion returns the version pinned by `upgrade --version` or
+// `upgrade --rollback`, or "" when nothing is pinned.
+func readPinnedVersion() string {
+	dir, err := versionsDir()
+	if err != nil {
+		return ""
+	}
+	data, err := os.ReadFile(filepath.Join(dir, pinnedFileName))
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(data))
+}
+
+func writePinnedVersion(version string) error {
+	dir, err := versionsDir()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return fmt.Errorf("creating %s: %w", dir, err)
+	}
+	path := filepath.Join(dir, pinnedFileName)
+	if err := os.WriteFile(path, []byte(version+"\n"), 0o644); err != nil {
+		return fmt.Errorf("writing %s: %w", path, err)
+	}
+	return nil
+}
+
+func clearPinnedVersion() {
+	if dir, err := versionsDir(); err == nil {
+		os.Remove(filepath.Join(dir, pinnedFileName))
+	}
+}
+
+// versionPinned reports whether the running binary is the pinned version.
+// A pin for another version (the binary was replaced by other means) is
+// ignored, so the user is not left on an old release silently.
+func versionPinned() bool {
+	pinned := readPinnedVersion()
+	return pinned != "" && pinned == Version
+}
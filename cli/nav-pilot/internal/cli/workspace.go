@@ -0,0 +1,366 @@
+package cli
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// workspaceJobs is how many repos workspace sync checks or applies at once.
+const workspaceJobs = 8
+
+// Per-repo outcome of a workspace sync.
+const (
+	workspaceUpToDate  = "up_to_date"
+	workspaceUpdates   = "updates_available"
+	workspaceUpdated   = "updated"
+	workspaceConflicts = "conflicts"
+	workspaceFailed    = "failed"
+)
+
+// workspaceRepo is one repo's row in the workspace report.
+type workspaceRepo struct {
+	Path      string      `json:"path"` // relative to the workspace root
+	Mode      string      `json:"mode"` // "state" (nav-pilot install) or "detected"
+	Status    string      `json:"status"`
+	Source    string      `json:"source,omitempty"`
+	Files     int         `json:"files"`
+	Updates   int         `json:"updates"`
+	Deletions int         `json:"deletions"`
+	Conflicts int         `json:"conflicts"`
+	Error     string      `json:"error,omitempty"`
+	Result    *syncResult `json:"result,omitempty"`
+}
+
+type workspaceReport struct {
+	Root  string          `json:"root"`
+	Apply bool            `json:"apply"`
+	Repos []workspaceRepo `json:"repos"`
+	// Skipped counts repos without a state file where auto-detect found no
+	// files that exist in source.
+	Skipped int `json:"skipped"`
+}
+
+// cmdWorkspace dispatches `nav-pilot workspace <subcommand>`.
+func cmdWorkspace(args []string, ref, sourceRepo string, apply, jsonOutput bool) error {
+	if len(args) == 0 {
+		return fmt.Errorf("workspace requires a subcommand.\n\nUsage: nav-pilot workspace sync [dir] [--apply] [--json]\n\nSubcommands:\n  sync    Check or apply updates in every nav-pilot repo under dir")
+	}
+	switch args[0] {
+	case "sync":
+		if len(args) > 2 {
+			return fmt.Errorf("usage: nav-pilot workspace sync [dir] [--apply] [--json]")
+		}
+		dir := "."
+		if len(args) == 2 {
+			dir = args[1]
+		}
+		return cmdWorkspaceSync(dir, ref, sourceRepo, apply, jsonOutput)
+	default:
+		return fmt.Errorf("unknown workspace subcommand: %q\n\nSubcommands: sync", args[0])
+	}
+}
+
+// cmdWorkspaceSync runs sync in every git repo under dir that has a state
+// file or customization files, workspaceJobs at a time. Each distinct source
+// is resolved once and shared by all repos that use it.
+func cmdWorkspaceSync(dir, ref, sourceRepo string, apply, jsonOutput bool) error {
+	root, err := filepath.Abs(dir)
+	if err != nil {
+		return err
+	}
+	if info, err := os.Stat(root); err != nil || !info.IsDir() {
+		return fmt.Errorf("%s is not a directory", dir)
+	}
+	repos, err := findWorkspaceRepos(root)
+	if err != nil {
+		return err
+	}
+
+	sources := &workspaceSources{srcs: map[string]*workspaceSource{}}
+	defer sources.cleanup()
+
+	if !jsonOutput {
+		verb := "Checking"
+		if apply {
+			verb = "Syncing"
+		}
+		fmt.Printf("%s %s %d repo(s) under %s...\n\n", dim("→"), verb, len(repos), root)
+	}
+
+	results := make([]*workspaceRepo, len(repos))
+	sem := make(chan struct{}, workspaceJobs)
+	var wg sync.WaitGroup
+	for i, repo := range repos {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+			results[i] = syncWorkspaceRepo(root, repo, ref, sourceRepo, apply, sources)
+		}()
+	}
+	wg.Wait()
+
+	report := workspaceReport{Root: root, Apply: apply, Repos: []workspaceRepo{}}
+	for _, r := range results {
+		if r == nil {
+			report.Skipped++
+			continue
+		}
+		report.Repos = append(report.Repos, *r)
+	}
+
+	var failed, pending int
+	for _, r := range report.Repos {
+		switch r.Status {
+		case workspaceFailed:
+			failed++
+		case workspaceUpdates, workspaceConflicts:
+			pending++
+		}
+	}
+
+	if jsonOutput {
+		if err := outputJSON(report); err != nil {
+			return err
+		}
+	} else {
+		printWorkspaceReport(report, dir)
+	}
+
+	switch {
+	case failed > 0:
+		return errSyncFailed
+	case pending > 0:
+		return errUpdatesAvailable
+	}
+	return nil
+}
+
+// syncWorkspaceRepo syncs one repo into buffers and summarizes the outcome.
+// It returns nil for a repo without state where nothing was detected.
+func syncWorkspaceRepo(root, repo, ref, sourceRepo string, apply bool, sources *workspaceSources) *workspaceRepo {
+	scope := ScopeRepo(repo)
+	mode := "detected"
+	if _, err := os.Stat(scope.StatePath()); err == nil {
+		mode = "state"
+	}
+
+	var out, errOut bytes.Buffer
+	run := &syncRun{out: &out, errOut: &errOut, resolve: sources.resolve}
+	err := run.sync(scope, ref, sourceRepo, lockFollow, apply, false)
+
+	if mode == "detected" && err == nil && run.files == 0 {
+		return nil
+	}
+
+	rel, relErr := filepath.Rel(root, repo)
+	if relErr != nil {
+		rel = repo
+	}
+	res := &workspaceRepo{Path: filepath.ToSlash(rel), Mode: mode, Files: run.files, Result: run.result}
+	if r := run.result; r != nil {
+		res.Source = r.Source
+		res.Updates = len(r.Updates)
+		res.Deletions = len(r.Deletions)
+		res.Conflicts = len(r.Conflicts)
+	}
+
+	switch {
+	case err == nil && apply && res.Updates+res.Deletions > 0:
+		res.Status = workspaceUpdated
+	case err == nil:
+		res.Status = workspaceUpToDate
+	case errors.Is(err, errUpdatesAvailable) && !apply && res.Updates+res.Deletions > 0:
+		res.Status = workspaceUpdates
+	case errors.Is(err, errUpdatesAvailable):
+		res.Status = workspaceConflicts
+	default:
+		res.Status = workspaceFailed
+		res.Error = err.Error()
+		if run.result != nil && len(run.result.Errors) > 0 {
+			res.Error = strings.Join(run.result.Errors, "; ")
+		} else if line := lastLine(errOut.String()); errors.Is(err, errSyncFailed) && line != "" {
+			res.Error = line
+		}
+	}
+	return res
+}
+
+func printWorkspaceReport(report workspaceReport, dir string) {
+	width := 0
+	for _, r := range report.Repos {
+		width = max(width, len(r.Path))
+	}
+	counts := map[string]int{}
+	for _, r := range report.Repos {
+		counts[r.Status]++
+		var glyph, label, details string
+		switch r.Status {
+		case workspaceUpToDate:
+			glyph, label = green("✓"), "up to date"
+			details = fmt.Sprintf("%d files", r.Files)
+		case workspaceUpdated:
+			glyph, label = green("✓"), "updated"
+			details = workspaceChanges(r)
+		case workspaceUpdates:
+			glyph, label = yellow("~"), "updates"
+			details = workspaceChanges(r)
+		case workspaceConflicts:
+			glyph, label = yellow("⚠"), "conflicts"
+			details = fmt.Sprintf("%d file(s) in conflict", r.Conflicts)
+		default:
+			glyph, label = red("×"), "failed"
+			details = r.Error
+		}
+		if r.Source != "" {
+			details += dim(" (source: " + r.Source + ")")
+		}
+		fmt.Printf("  %-*s  %s %-10s  %s\n", width, r.Path, glyph, label, details)
+	}
+	if len(report.Repos) == 0 {
+		fmt.Println("No nav-pilot repos found.")
+	}
+	fmt.Println()
+
+	var parts []string
+	for _, c := range []struct{ status, label string }{
+		{workspaceUpToDate, "up to date"},
+		{workspaceUpdated, "updated"},
+		{workspaceUpdates, "with updates"},
+		{workspaceConflicts, "with conflicts"},
+		{workspaceFailed, "failed"},
+	} {
+		if counts[c.status] > 0 {
+			parts = append(parts, fmt.Sprintf("%d %s", counts[c.status], c.label))
+		}
+	}
+	if len(parts) > 0 {
+		fmt.Printf("%s repo(s): %s\n", bold(fmt.Sprint(len(report.Repos))), strings.Join(parts, ", "))
+	}
+	if report.Skipped > 0 {
+		fmt.Printf("%s %d git repo(s) skipped: no state file and no files that exist in source\n", dim("→"), report.Skipped)
+	}
+	if counts[workspaceUpdates] > 0 {
+		fmt.Printf("Run %s to apply updates.\n", bold("nav-pilot workspace sync "+dir+" --apply"))
+	}
+}
+
+func workspaceChanges(r workspaceRepo) string {
+	var parts []string
+	if r.Updates > 0 {
+		parts = append(parts, fmt.Sprintf("%d update(s)", r.Updates))
+	}
+	if r.Deletions > 0 {
+		parts = append(parts, fmt.Sprintf("%d deletion(s)", r.Deletions))
+	}
+	if r.Conflicts > 0 {
+		parts = append(parts, fmt.Sprintf("%d conflict(s)", r.Conflicts))
+	}
+	return strings.Join(parts, ", ")
+}
+
+func lastLine(s string) string {
+	lines := strings.Split(strings.TrimSpace(s), "\n")
+	return strings.TrimSpace(lines[len(lines)-1])
+}
+
+// findWorkspaceRepos returns the git repos under root that have a state file
+// or customization files under .github. It does not descend into repos,
+// hidden directories or node_modules.
+func findWorkspaceRepos(root string) ([]string, error) {
+	var repos []string
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if path == root {
+				return err
+			}
+			return nil
+		}
+		if !d.IsDir() {
+			return nil
+		}
+		if path != root && (strings.HasPrefix(d.Name(), ".") || d.Name() == "node_modules") {
+			return filepath.SkipDir
+		}
+		if _, err := os.Stat(filepath.Join(path, ".git")); err != nil {
+			return nil
+		}
+		if hasWorkspaceArtifacts(path) {
+			repos = append(repos, path)
+		}
+		return filepath.SkipDir
+	})
+	return repos, err
+}
+
+func hasWorkspaceArtifacts(repo string) bool {
+	if _, err := os.Stat(ScopeRepo(repo).StatePath()); err == nil {
+		return true
+	}
+	for _, kind := range AllKinds {
+		entries, err := os.ReadDir(filepath.Join(repo, ".github", kind.Dir))
+		if err == nil && len(entries) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// workspaceSources resolves each distinct ref and source stack once. Repos
+// get a borrowed copy whose Cleanup is a no-op; the workspace cleans up the
+// originals when every repo is done.
+type workspaceSources struct {
+	mu   sync.Mutex
+	srcs map[string]*workspaceSource
+}
+
+// workspaceSource is one key's resolution. mu only guards the map, so a
+// clone or fetch for one key does not hold up repos waiting on another;
+// repos with the same key wait on once.
+type workspaceSource struct {
+	once sync.Once
+	src  *Source
+	err  error
+}
+
+func (w *workspaceSources) resolve(ref, sourceRepo string) (*Source, error) {
+	key := ref + "\x00" + sourceRepo
+	w.mu.Lock()
+	e, ok := w.srcs[key]
+	if !ok {
+		e = &workspaceSource{}
+		w.srcs[key] = e
+	}
+	w.mu.Unlock()
+
+	e.once.Do(func() { e.src, e.err = resolveSourceForSync(ref, sourceRepo) })
+	if e.err != nil {
+		return nil, e.err
+	}
+	return borrowedSource(e.src), nil
+}
+
+func (w *workspaceSources) cleanup() {
+	for _, e := range w.srcs {
+		if e.src != nil {
+			e.src.Cleanup()
+		}
+	}
+}
+
+func borrowedSource(src *Source) *Source {
+	cp := *src
+	cp.TempDir = ""
+	cp.Overlays = make([]*Source, len(src.Overlays))
+	for i, o := range src.Overlays {
+		cp.Overlays[i] = borrowedSource(o)
+	}
+	return &cp
+}
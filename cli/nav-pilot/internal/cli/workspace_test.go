@@ -0,0 +1,170 @@
+package cli
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/navikt/copilot/cli/nav-pilot/internal/source"
+)
+
+// workspaceFixture lays out a workspace with two installed repos, one repo
+// whose customizations are not in source, and directories that must be
+// ignored. It returns the workspace root and a counter of source resolutions.
+func workspaceFixture(t *testing.T) (root string, resolves *atomic.Int32) {
+	t.Helper()
+	root = t.TempDir()
+	srcDir := t.TempDir()
+	os.MkdirAll(filepath.Join(srcDir, "collections", "backend"), 0o755)
+	os.WriteFile(filepath.Join(srcDir, "collections", "backend", "manifest.json"),
+		[]byte(`{"name":"backend","agents":["nais"],"skills":["api-design"]}`), 0o644)
+	os.MkdirAll(filepath.Join(srcDir, "collections", "skills"), 0o755)
+	os.WriteFile(filepath.Join(srcDir, "collections", "skills", "manifest.json"),
+		[]byte(`{"name":"skills","skills":["api-design"]}`), 0o644)
+	os.MkdirAll(filepath.Join(srcDir, "agents"), 0o755)
+	os.WriteFile(filepath.Join(srcDir, "agents", "nais.agent.md"), []byte("# Nais\n\nv1\n"), 0o644)
+	os.MkdirAll(filepath.Join(srcDir, "skills", "api-design"), 0o755)
+	os.WriteFile(filepath.Join(srcDir, "skills", "api-design", "SKILL.md"), []byte("# API\n\nv1\n"), 0o644)
+
+	src := &source.Source{Dir: srcDir, SHA: "v1", Version: "dev"}
+	for repo, collection := range map[string]string{"team/app-a": "backend", "app-b": "skills"} {
+		dir := filepath.Join(root, repo)
+		os.MkdirAll(filepath.Join(dir, ".git"), 0o755)
+		if err := cmdInstallFromSource(collection, src, ScopeRepo(dir), false, false, false); err != nil {
+			t.Fatalf("install %s: %v", repo, err)
+		}
+	}
+	// Only local customizations: found by the walk, skipped after detection.
+	os.MkdirAll(filepath.Join(root, "local-only", ".git"), 0o755)
+	os.MkdirAll(filepath.Join(root, "local-only", ".github", "agents"), 0o755)
+	os.WriteFile(filepath.Join(root, "local-only", ".github", "agents", "mine.agent.md"), []byte("# Mine\n"), 0o644)
+	// Never visited: dependencies, hidden dirs and plain folders.
+	os.MkdirAll(filepath.Join(root, "app-b", "node_modules", "dep", ".git"), 0o755)
+	os.MkdirAll(filepath.Join(root, ".cache", "x", ".git"), 0o755)
+	os.MkdirAll(filepath.Join(root, "notes"), 0o755)
+
+	// The source changes after install. Each resolution is a temp clone, so a
+	// repo that cleaned up the shared source would break the others.
+	os.WriteFile(filepath.Join(srcDir, "agents", "nais.agent.md"), []byte("# Nais\n\nv2\n"), 0o644)
+	resolves = &atomic.Int32{}
+	orig := resolveSourceForSync
+	t.Cleanup(func() { resolveSourceForSync = orig })
+	resolveSourceForSync = func(ref, sourceRepo string) (*source.Source, error) {
+		resolves.Add(1)
+		clone := filepath.Join(t.TempDir(), "clone")
+		if err := os.CopyFS(clone, os.DirFS(srcDir)); err != nil {
+			return nil, err
+		}
+		return &source.Source{Dir: clone, TempDir: clone, SHA: "v2", Version: "dev"}, nil
+	}
+	return root, resolves
+}
+
+func TestWorkspaceSync_CheckReportsPerRepo(t *testing.T) {
+	root, resolves := workspaceFixture(t)
+
+	var err error
+	out := captureStdout(func() { err = run([]string{"workspace", "sync", root, "--json"}) })
+	if err != errUpdatesAvailable {
+		t.Fatalf("err = %v, want errUpdatesAvailable", err)
+	}
+	var report workspaceReport
+	if err := json.Unmarshal([]byte(out), &report); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, out)
+	}
+	got := map[string]string{}
+	for _, r := range report.Repos {
+		got[r.Path] = r.Status
+	}
+	want := map[string]string{"team/app-a": workspaceUpdates, "app-b": workspaceUpToDate}
+	if len(got) != len(want) || got["team/app-a"] != want["team/app-a"] || got["app-b"] != want["app-b"] {
+		t.Errorf("statuses = %v, want %v", got, want)
+	}
+	if report.Skipped != 1 {
+		t.Errorf("skipped = %d, want 1 (local-only)", report.Skipped)
+	}
+	if n := resolves.Load(); n != 1 {
+		t.Errorf("source resolved %d times, want once for the whole workspace", n)
+	}
+}
+
+func TestWorkspaceSync_Apply(t *testing.T) {
+	root, _ := workspaceFixture(t)
+
+	out := captureStdout(func() {
+		if err := run([]string{"workspace", "sync", root, "--apply"}); err != nil {
+			t.Fatalf("workspace sync --apply: %v", err)
+		}
+	})
+	if !strings.Contains(out, "team/app-a") || !strings.Contains(out, "1 up to date, 1 updated") {
+		t.Errorf("output = %q", out)
+	}
+	data, _ := os.ReadFile(filepath.Join(root, "team", "app-a", ".github", "agents", "nais.agent.md"))
+	if string(data) != "# Nais\n\nv2\n" {
+		t.Errorf("agent not updated: %q", data)
+	}
+
+	captureStdout(func() {
+		if err := run([]string{"workspace", "sync", root}); err != nil {
+			t.Errorf("second check: %v", err)
+		}
+	})
+}
+
+func TestWorkspaceSources_OnlySameKeyWaits(t *testing.T) {
+	// The slow ref's clone only finishes once the fast ref has resolved, so a
+	// lock held across resolution would deadlock.
+	fastDone := make(chan struct{})
+	var resolves atomic.Int32
+	orig := resolveSourceForSync
+	t.Cleanup(func() { resolveSourceForSync = orig })
+	resolveSourceForSync = func(ref, sourceRepo string) (*source.Source, error) {
+		resolves.Add(1)
+		if ref == "slow" {
+			select {
+			case <-fastDone:
+			case <-time.After(5 * time.Second):
+				t.Error("fast ref waited on the slow one")
+			}
+		}
+		return &source.Source{Dir: ref, SHA: ref}, nil
+	}
+
+	w := &workspaceSources{srcs: map[string]*workspaceSource{}}
+	var wg sync.WaitGroup
+	for i := 0; i < 3; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if src, err := w.resolve("slow", ""); err != nil || src.SHA != "slow" {
+				t.Errorf("slow = %v, %v", src, err)
+			}
+		}()
+	}
+	time.Sleep(10 * time.Millisecond)
+	if src, err := w.resolve("fast", ""); err != nil || src.SHA != "fast" {
+		t.Errorf("fast = %v, %v", src, err)
+	}
+	close(fastDone)
+	wg.Wait()
+	if n := resolves.Load(); n != 2 {
+		t.Errorf("resolved %d times, want once per key", n)
+	}
+}
+
+func TestWorkspace_Usage(t *testing.T) {
+	if err := cmdWorkspace(nil, "", "", false, false); err == nil {
+		t.Error("expected error without subcommand")
+	}
+	if err := cmdWorkspace([]string{"status"}, "", "", false, false); err == nil || !strings.Contains(err.Error(), "unknown workspace subcommand") {
+		t.Errorf("err = %v", err)
+	}
+	if err := run([]string{"workspace", "sync", "--target", t.TempDir()}); err == nil {
+		t.Error("--target should be rejected for workspace")
+	}
+}
@@ -27,11 +27,48 @@ type Config struct {
 	RtkPromptedClient *string `toml:"rtk_prompted_client"`
 	RtkPromptedAt     *string `toml:"rtk_prompted_at"`
 	AutoUpdate        *bool   `toml:"auto_update"`
+	Telemetry         *bool   `toml:"telemetry"`
+
+	Profiles map[string]*Profile `toml:"profiles"`
+}
+
+// Profile is a named set of launch settings from a [profiles.<name>] table.
+// Fields that are set override the top-level values when the profile is
+// selected with --profile or NAV_PILOT_PROFILE.
+type Profile struct {
+	Client          *string `toml:"client"`
+	Model           *string `toml:"model"`
+	Mode            *string `toml:"mode"`
+	ReasoningEffort *string `toml:"reasoning_effort"`
+	ContextTier     *string `toml:"context_tier"`
+	AllowAllTools   *bool   `toml:"allow_all_tools"`
+	AskUser         *bool   `toml:"ask_user"`
+	AutoLaunch      *bool   `toml:"auto_launch"`
+	LogLevel        *string `toml:"log_level"`
+	OtelLogLevel    *string `toml:"otel_log_level"`
+}
+
+// RepoConfig is the team-shared .nav-pilot.toml at a repository's git root.
+// Its values are defaults below the user's config; keys listed in Locked are
+// enforced over every other layer, CLI flags included. It deliberately has no
+// permission keys (allow_all_tools, ask_user): a cloned repo must not be able
+// to grant itself tool access.
+type RepoConfig struct {
+	Version         int      `toml:"version"`
+	Client          *string  `toml:"client"`
+	Model           *string  `toml:"model"`
+	Mode            *string  `toml:"mode"`
+	ReasoningEffort *string  `toml:"reasoning_effort"`
+	ContextTier     *string  `toml:"context_tier"`
+	Locked          []string `toml:"locked"`
 }
 
 // ResolvedConfig holds the final configuration after applying precedence:
-// CLI flag > file value > built-in default.
+// locked repo value > CLI flag > profile value > file value > repo value >
+// built-in default.
 type ResolvedConfig struct {
+	Profile           string            // selected profile; empty = none
+	Sources           map[string]string // config key → layer its value came from
 	Client            string
 	Model             string // empty = use agent default
 	Mode              string
@@ -45,11 +82,13 @@ type ResolvedConfig struct {
 	RtkPromptedClient string   // comma-separated list of clients where the RTK setup was prompted
 	RtkPromptedAt     string   // RFC3339 timestamp of when the user was last prompted
 	AutoUpdate        bool     // true to bypass upgrade prompt
+	Telemetry         bool     // false opts out of usage metrics permanently
 	ExtraArgs         []string // pass-through arguments for the client
 }
 
 // CLIOverrides holds optional CLI flag values. Empty string means "not provided via CLI".
 type CLIOverrides struct {
+	Profile         string
 	Client          string
 	Model           string
 	Mode            string
@@ -127,6 +166,7 @@ type InstallScope struct {
 	StateFile      string   // path relative to RootDir
 	PathPrefix     string   // ".github/" (repo) or "" (user)
 	SupportedTypes []string // artifact types that can be installed
+	Subproject     string   // repo-relative directory instructions are scoped to; "" = whole repo
 }
 
 // ScopeRepo creates a scope for repo-level installs (.github/).
@@ -140,6 +180,15 @@ func ScopeRepo(targetDir string) *InstallScope {
 	}
 }
 
+// ScopeSubproject creates a repo scope for a monorepo subproject. Files still
+// install under the git root's .github/, but instructions go to
+// .github/instructions/<subproject>/ with applyTo rooted at the subproject.
+func ScopeSubproject(targetDir, subproject string) *InstallScope {
+	s := ScopeRepo(targetDir)
+	s.Subproject = subproject
+	return s
+}
+
 // ScopeUser creates a scope for user-level installs (~/.copilot/).
 func ScopeUser() (*InstallScope, error) {
 	home, err := os.UserHomeDir()
@@ -237,7 +286,9 @@ func (s *InstallScope) ValidateStatePath(p string) error {
 // CleanupDirs removes empty artifact directories after uninstall.
 func (s *InstallScope) CleanupDirs() {
 	if s.Name == "repo" {
-		for _, sub := range []string{"agents", "skills", "instructions", "prompts"} {
+		// Subproject instructions live in nested directories.
+		removeEmptyDirs(filepath.Join(s.RootDir, ".github", "instructions"))
+		for _, sub := range []string{"agents", "skills", "prompts"} {
 			dir := filepath.Join(s.RootDir, ".github", sub)
 			entries, err := os.ReadDir(dir)
 			if err == nil && len(entries) == 0 {
@@ -265,11 +316,31 @@ func (s *InstallScope) CleanupDirs() {
 	}
 }
 
+// removeEmptyDirs removes dir and every directory below it that is, or
+// becomes, empty.
+func removeEmptyDirs(dir string) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return
+	}
+	for _, e := range entries {
+		if e.IsDir() {
+			removeEmptyDirs(filepath.Join(dir, e.Name()))
+		}
+	}
+	if entries, err := os.ReadDir(dir); err == nil && len(entries) == 0 {
+		os.Remove(dir)
+	}
+}
+
 // Label returns a display label for UI output.
 func (s *InstallScope) Label() string {
 	if s.Name == "user" {
 		return "~/.copilot (user-wide)"
 	}
+	if s.Subproject != "" {
+		return filepath.Join(s.RootDir, filepath.FromSlash(s.Subproject))
+	}
 	return s.RootDir
 }
 
@@ -285,8 +356,27 @@ type StateFile struct {
 	Scope       string          `json:"scope,omitempty"`       // "repo" or "user"; empty means "repo" (backwards compat)
 	SourceRepo  string          `json:"source_repo,omitempty"` // git repository owner/name (e.g. "navikt/copilot")
 	SourceSHA   string          `json:"source_sha"`
+	Sources     []SourceLayer   `json:"sources,omitempty"` // layered installs only, base first
 	InstalledAt string          `json:"installed_at"`
 	Files       []InstalledFile `json:"files"`
+	// MCPServers are the registry servers `nav-pilot mcp add` wrote into
+	// client configs, so sync can update or deprecate them.
+	MCPServers []InstalledMCPServer `json:"mcp_servers,omitempty"`
+}
+
+// SourceLayer records one layer of a layered (multi-source) install.
+type SourceLayer struct {
+	Repo string `json:"repo"`
+	SHA  string `json:"sha"`
+}
+
+// InstalledMCPServer records an MCP server installed from the registry.
+type InstalledMCPServer struct {
+	Name    string   `json:"name"`          // registry name, e.g. "io.github.navikt/github-mcp"
+	Key     string   `json:"key"`           // entry key in the client configs
+	Version string   `json:"version"`       // registry version the entries were written from
+	Clients []string `json:"clients"`       // "vscode", "copilot", "opencode"
+	Env     []string `json:"env,omitempty"` // environment variable names; values stay in the client config
 }
 
 // InstalledFile records a single installed file with its content hash.
@@ -294,8 +384,46 @@ type InstalledFile struct {
 	Path   string `json:"path"`
 	Hash   string `json:"hash"`
 	Status string `json:"status,omitempty"` // "" = active, FileStatusIgnored = intentionally excluded, FileStatusConflict = exists with local modifications
+	Source string `json:"source,omitempty"` // repo of the source layer that provided the file
+	// Subproject is set for instructions installed with --subproject: the file
+	// lives under .github/instructions/<subproject>/ and its applyTo is
+	// rooted there, so sync re-renders the source the same way.
+	Subproject string `json:"subproject,omitempty"`
+}
+
+// LockFile pins every installed artifact to an exact source revision and
+// content hash. Unlike StateFile it is meant to be committed, so a team and
+// CI reproduce the same install.
+type LockFile struct {
+	LockVersion int              `json:"lock_version"`
+	Collection  string           `json:"collection"`
+	Scope       string           `json:"scope,omitempty"`
+	SourceRepo  string           `json:"source_repo"` // as in StateFile; layered installs join every layer
+	Sources     []LockedSource   `json:"sources"`     // base first
+	Artifacts   []LockedArtifact `json:"artifacts"`
 }
 
+// LockedSource pins one source layer to a full commit SHA.
+type LockedSource struct {
+	Repo   string `json:"repo"`
+	Commit string `json:"commit"`
+}
+
+// LockedArtifact pins one installed file or directory to the commit it came
+// from and the hash of its pristine source content.
+type LockedArtifact struct {
+	Path   string `json:"path"`
+	Source string `json:"source"`
+	Commit string `json:"commit"`
+	Hash   string `json:"hash"`
+	// Subproject mirrors InstalledFile.Subproject; Hash is still the hash of
+	// the pristine source.
+	Subproject string `json:"subproject,omitempty"`
+}
+
+// LockFileVersion is the current nav-pilot.lock format version.
+const LockFileVersion = 1
+
 // FileStatusIgnored marks a file as intentionally excluded by the user.
 // Sync and status skip files with this status.
 const FileStatusIgnored = "ignored"
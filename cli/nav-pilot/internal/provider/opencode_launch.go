@@ -154,6 +154,24 @@ func openCodeLogLevel(level string) string {
 	}
 }
 
+// OpenCodeOTelEnabled reports whether opencode.json already has
+// experimental.openTelemetry=true. A missing file counts as not enabled.
+func OpenCodeOTelEnabled() (bool, error) {
+	data, err := os.ReadFile(openCodeConfigPath())
+	if err != nil {
+		if os.IsNotExist(err) {
+			return false, nil
+		}
+		return false, fmt.Errorf("reading opencode config: %w", err)
+	}
+	var cfg map[string]any
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return false, fmt.Errorf("opencode config is not valid JSON (%s): %w", openCodeConfigPath(), err)
+	}
+	experimental, _ := cfg["experimental"].(map[string]any)
+	return experimental["openTelemetry"] == true, nil
+}
+
 // EnsureOpenCodeOTelConfig reads ~/.config/opencode/opencode.json (or creates it),
 // sets experimental.openTelemetry=true without clobbering other keys, and writes back.
 func EnsureOpenCodeOTelConfig() error {
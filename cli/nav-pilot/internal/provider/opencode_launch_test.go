@@ -499,3 +499,26 @@ func TestLaunchPi_RoutesThroughCplt(t *testing.T) {
 		t.Errorf("cplt argv = %q, want %q", string(got), "cplt --agent pi --")
 	}
 }
+
+func TestOpenCodeOTelEnabled(t *testing.T) {
+	ConfigPathOverride = filepath.Join(t.TempDir(), "opencode.json")
+	defer func() { ConfigPathOverride = "" }()
+
+	if on, err := OpenCodeOTelEnabled(); err != nil || on {
+		t.Errorf("missing file = %v, %v; want false, nil", on, err)
+	}
+	_ = os.WriteFile(ConfigPathOverride, []byte(`{"experimental": {"openTelemetry": false}}`), 0o600)
+	if on, err := OpenCodeOTelEnabled(); err != nil || on {
+		t.Errorf("disabled = %v, %v; want false, nil", on, err)
+	}
+	if err := EnsureOpenCodeOTelConfig(); err != nil {
+		t.Fatal(err)
+	}
+	if on, err := OpenCodeOTelEnabled(); err != nil || !on {
+		t.Errorf("after ensure = %v, %v; want true, nil", on, err)
+	}
+	_ = os.WriteFile(ConfigPathOverride, []byte(`{`), 0o600)
+	if _, err := OpenCodeOTelEnabled(); err == nil {
+		t.Error("expected error for invalid JSON")
+	}
+}
@@ -0,0 +1,385 @@
+package provider
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/navikt/copilot/cli/nav-pilot/internal/domain"
+)
+
+// PluginPrefix is the executable name prefix for external providers. An
+// executable named nav-pilot-provider-<id> on PATH becomes client <id>.
+const PluginPrefix = "nav-pilot-provider-"
+
+// PluginProtocolVersion is sent with every request so plugins can reject
+// versions they don't understand.
+const PluginProtocolVersion = 1
+
+// Plugin calls are bounded so a hung executable can't hang nav-pilot.
+// sync-context gets longer since plugins typically fetch content.
+var (
+	pluginCallTimeout = 10 * time.Second
+	pluginSyncTimeout = 2 * time.Minute
+)
+
+// pluginModel is the wire form of domain.ModelChoice.
+type pluginModel struct {
+	ID    string `json:"id"`
+	Label string `json:"label"`
+}
+
+// pluginInfo is the response to "info".
+type pluginInfo struct {
+	ID           string        `json:"id"`
+	DisplayName  string        `json:"display_name"`
+	Available    bool          `json:"available"`
+	DefaultModel string        `json:"default_model"`
+	Models       []pluginModel `json:"models"`
+}
+
+// pluginValidation is the response to "validate-model".
+type pluginValidation struct {
+	Error    string `json:"error"`
+	Advisory string `json:"advisory"`
+}
+
+// pluginLaunch is the response to "launch": what nav-pilot should exec.
+type pluginLaunch struct {
+	Command  string            `json:"command"`
+	Args     []string          `json:"args"`
+	Env      map[string]string `json:"env"`
+	Warnings []string          `json:"warnings"`
+}
+
+// pluginSync is the response to "sync-context".
+type pluginSync struct {
+	Managed bool   `json:"managed"`
+	Message string `json:"message"`
+	Error   string `json:"error"`
+}
+
+// pluginProvider adapts a nav-pilot-provider-<id> executable to Provider.
+// Each method runs "<exe> <method>" with a JSON request on stdin and reads
+// a JSON response from stdout; a non-zero exit is an error and stderr is
+// surfaced to the user. The info response is fetched once per process.
+type pluginProvider struct {
+	id   string
+	path string
+
+	once    sync.Once
+	info    pluginInfo
+	infoErr error
+}
+
+// PluginPath returns the executable backing p, or "" for built-in providers.
+func PluginPath(p Provider) string {
+	if pp, ok := p.(*pluginProvider); ok {
+		return pp.path
+	}
+	return ""
+}
+
+// PluginError returns why a plugin's info call failed, or nil.
+func PluginError(p Provider) error {
+	if pp, ok := p.(*pluginProvider); ok {
+		pp.load()
+		return pp.infoErr
+	}
+	return nil
+}
+
+func (p *pluginProvider) call(method string, timeout time.Duration, req, resp interface{}) error {
+	payload := map[string]interface{}{"protocol": PluginProtocolVersion}
+	if req != nil {
+		payload["params"] = req
+	}
+	in, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	cmd := exec.CommandContext(ctx, p.path, method)
+	cmd.Stdin = bytes.NewReader(in)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if ctx.Err() == context.DeadlineExceeded {
+			return fmt.Errorf("provider plugin %s %s timed out after %s", p.id, method, timeout)
+		}
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return fmt.Errorf("provider plugin %s %s: %s", p.id, method, msg)
+		}
+		return fmt.Errorf("provider plugin %s %s: %w", p.id, method, err)
+	}
+	if resp == nil {
+		return nil
+	}
+	if err := json.Unmarshal(stdout.Bytes(), resp); err != nil {
+		return fmt.Errorf("provider plugin %s %s: invalid JSON response: %w", p.id, method, err)
+	}
+	return nil
+}
+
+func (p *pluginProvider) load() {
+	p.once.Do(func() {
+		p.infoErr = p.call("info", pluginCallTimeout, nil, &p.info)
+		if p.infoErr == nil && p.info.ID != "" && p.info.ID != p.id {
+			p.infoErr = fmt.Errorf("provider plugin %s reports id %q", p.path, p.info.ID)
+		}
+	})
+}
+
+func (p *pluginProvider) ID() string { return p.id }
+
+func (p *pluginProvider) DisplayName() string {
+	p.load()
+	if p.info.DisplayName != "" {
+		return p.info.DisplayName
+	}
+	return p.id
+}
+
+func (p *pluginProvider) Available() bool {
+	p.load()
+	return p.infoErr == nil && p.info.Available
+}
+
+func (p *pluginProvider) DefaultModel() string {
+	p.load()
+	return p.info.DefaultModel
+}
+
+func (p *pluginProvider) KnownModels() []domain.ModelChoice {
+	p.load()
+	if len(p.info.Models) == 0 {
+		return nil
+	}
+	models := make([]domain.ModelChoice, len(p.info.Models))
+	for i, m := range p.info.Models {
+		models[i] = domain.ModelChoice{ID: m.ID, Label: m.Label}
+	}
+	return models
+}
+
+func (p *pluginProvider) validate(model string) (pluginValidation, error) {
+	var v pluginValidation
+	err := p.call("validate-model", pluginCallTimeout, map[string]string{"model": model}, &v)
+	return v, err
+}
+
+// ValidateModel applies the shared shape check before asking the plugin, so
+// a plugin can only narrow what nav-pilot accepts, never widen it.
+func (p *pluginProvider) ValidateModel(model string) error {
+	if err := domain.ValidateModelValue(model); err != nil {
+		return err
+	}
+	v, err := p.validate(model)
+	if err != nil {
+		return err
+	}
+	if v.Error != "" {
+		return errors.New(v.Error)
+	}
+	return nil
+}
+
+func (p *pluginProvider) ModelAdvisory(model string) string {
+	if domain.ValidateModelValue(model) != nil {
+		return ""
+	}
+	v, err := p.validate(model)
+	if err != nil || v.Error != "" {
+		return ""
+	}
+	return v.Advisory
+}
+
+func (p *pluginProvider) UnsupportedConfigWarnings(_ domain.ResolvedConfig) []string { return nil }
+func (p *pluginProvider) Bootstrap() (string, error)                                 { return "", nil }
+func (p *pluginProvider) ContextStatus() *ProviderContextStatus                      { return nil }
+func (p *pluginProvider) PrintContextStatus()                                        {}
+func (p *pluginProvider) PrintSystemDiagnostics()                                    {}
+
+// launchRequest is the resolved config as sent to "launch".
+func launchRequest(r domain.ResolvedConfig) map[string]interface{} {
+	return map[string]interface{}{
+		"model":            r.Model,
+		"mode":             r.Mode,
+		"reasoning_effort": r.ReasoningEffort,
+		"context_tier":     r.ContextTier,
+		"allow_all_tools":  r.AllowAllTools,
+		"ask_user":         r.AskUser,
+		"log_level":        r.LogLevel,
+		"extra_args":       r.ExtraArgs,
+	}
+}
+
+// Launch asks the plugin how to start its client and runs that command with
+// stdio attached. The plugin only describes the launch; nav-pilot owns the
+// process so the client behaves like the built-in ones.
+func (p *pluginProvider) Launch(r domain.ResolvedConfig) error {
+	var spec pluginLaunch
+	if err := p.call("launch", pluginCallTimeout, launchRequest(r), &spec); err != nil {
+		telemetryRecorder.RecordLaunchError("other", "launch_failed")
+		return err
+	}
+	if spec.Command == "" {
+		telemetryRecorder.RecordLaunchError("other", "launch_failed")
+		return fmt.Errorf("provider plugin %s returned no command to launch", p.id)
+	}
+	for _, msg := range spec.Warnings {
+		fmt.Fprintf(os.Stderr, "%s %s\n", domain.Yellow("⚠"), msg)
+	}
+
+	fmt.Printf("Launching %s via %s...\n\n", domain.Bold(p.DisplayName()), domain.Bold("provider plugin"))
+
+	cmd := exec.Command(spec.Command, spec.Args...)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	cmd.Env = os.Environ()
+	keys := make([]string, 0, len(spec.Env))
+	for k := range spec.Env {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		cmd.Env = append(cmd.Env, k+"="+spec.Env[k])
+	}
+	if err := cmd.Run(); err != nil {
+		var exitErr *exec.ExitError
+		if !errors.As(err, &exitErr) {
+			fmt.Fprintf(os.Stderr, "%s Could not launch %s: %v\n", domain.Yellow("⚠"), p.DisplayName(), err)
+		}
+		telemetryRecorder.RecordLaunchError("other", classifyLaunchError(err))
+		return err
+	}
+	return nil
+}
+
+// SyncContext lets the plugin refresh whatever context it manages. Plugins
+// that manage nothing answer {"managed": false} and stay silent.
+func (p *pluginProvider) SyncContext(ref, sourceRepo string, jsonOutput, hasPrevOutput bool) ProviderSyncResult {
+	req := map[string]string{"ref": ref, "source_repo": sourceRepo}
+	var res pluginSync
+	err := p.call("sync-context", pluginSyncTimeout, req, &res)
+	if err == nil && res.Error != "" {
+		err = errors.New(res.Error)
+		res.Managed = true
+	}
+	if err != nil {
+		if !jsonOutput {
+			fmt.Fprintf(os.Stderr, "%s %s sync failed: %v\n", domain.Yellow("⚠"), p.id, err)
+		}
+		return ProviderSyncResult{Managed: res.Managed, Err: err}
+	}
+	if !res.Managed {
+		return ProviderSyncResult{}
+	}
+	if !jsonOutput {
+		if hasPrevOutput {
+			fmt.Println()
+		}
+		msg := res.Message
+		if msg == "" {
+			msg = "context synced."
+		}
+		fmt.Printf("%s %s %s\n", domain.Green("✓"), domain.Bold(p.id), msg)
+	}
+	return ProviderSyncResult{Managed: true}
+}
+
+// pluginCache memoizes discovery per PATH value, so repeated registry
+// lookups in one run don't rescan PATH or re-run "info".
+var pluginCache struct {
+	sync.Mutex
+	path    string
+	plugins []Provider
+}
+
+// DiscoverPlugins returns the provider plugins found on PATH, sorted by id.
+// The first executable for an id wins, like command lookup; ids that clash
+// with a built-in provider are ignored.
+func DiscoverPlugins() []Provider {
+	path := os.Getenv("PATH")
+	pluginCache.Lock()
+	defer pluginCache.Unlock()
+	if pluginCache.plugins != nil && pluginCache.path == path {
+		return pluginCache.plugins
+	}
+
+	seen := map[string]bool{}
+	for _, p := range providerRegistry {
+		seen[p.ID()] = true
+	}
+	plugins := []Provider{}
+	for _, dir := range filepath.SplitList(path) {
+		if dir == "" {
+			continue
+		}
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			continue
+		}
+		for _, e := range entries {
+			id, ok := pluginID(e.Name())
+			if !ok || seen[id] {
+				continue
+			}
+			full := filepath.Join(dir, e.Name())
+			if !isExecutable(full) {
+				continue
+			}
+			seen[id] = true
+			plugins = append(plugins, &pluginProvider{id: id, path: full})
+		}
+	}
+	sort.Slice(plugins, func(i, j int) bool { return plugins[i].ID() < plugins[j].ID() })
+
+	pluginCache.path = path
+	pluginCache.plugins = plugins
+	return plugins
+}
+
+// pluginID extracts <id> from nav-pilot-provider-<id>[.exe]. Ids use the
+// same character set as client names elsewhere: lowercase, digits, '-'.
+func pluginID(name string) (string, bool) {
+	if runtime.GOOS == "windows" {
+		name = strings.TrimSuffix(strings.ToLower(name), ".exe")
+	}
+	id, ok := strings.CutPrefix(name, PluginPrefix)
+	if !ok || id == "" {
+		return "", false
+	}
+	for _, r := range id {
+		if (r < 'a' || r > 'z') && (r < '0' || r > '9') && r != '-' {
+			return "", false
+		}
+	}
+	return id, true
+}
+
+func isExecutable(path string) bool {
+	info, err := os.Stat(path)
+	if err != nil || info.IsDir() {
+		return false
+	}
+	if runtime.GOOS == "windows" {
+		return true
+	}
+	return info.Mode()&0o111 != 0
+}
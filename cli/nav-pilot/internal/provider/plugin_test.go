@@ -0,0 +1,136 @@
+package provider
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/navikt/copilot/cli/nav-pilot/internal/domain"
+)
+
+// fakePlugin is a shell implementation of the plugin protocol. launch writes
+// a script that records its args and env so the test can check the exec.
+const fakePlugin = `#!/bin/sh
+req=$(cat)
+case "$1" in
+info)
+  echo '{"id":"acme","display_name":"Acme Agent","available":true,"default_model":"acme-large","models":[{"id":"acme-large","label":"Acme Large"},{"id":"acme-small","label":"Acme Small"}]}' ;;
+validate-model)
+  case "$req" in
+  *'"model":"acme-'*) echo '{}' ;;
+  *'"model":"exp-'*) echo '{"advisory":"experimental model"}' ;;
+  *) echo '{"error":"acme only serves acme-* models"}' ;;
+  esac ;;
+launch)
+  printf '{"command":"%s","args":["--model","acme-large"],"env":{"ACME_TOKEN":"t"}}\n' "$LAUNCH_TARGET" ;;
+sync-context)
+  case "$req" in
+  *'"ref":"broken"'*) echo "upstream unreachable" >&2; exit 3 ;;
+  *'"ref":"main"'*) echo '{"managed":true,"message":"rules refreshed"}' ;;
+  *) echo '{"managed":false}' ;;
+  esac ;;
+*) exit 64 ;;
+esac
+`
+
+func installFakePlugin(t *testing.T) string {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("shell plugin fixture requires a POSIX shell")
+	}
+	bin := t.TempDir()
+	mustWrite(t, filepath.Join(bin, PluginPrefix+"acme"), fakePlugin)
+	os.Chmod(filepath.Join(bin, PluginPrefix+"acme"), 0o755)
+	// Not executable, and an id clashing with a built-in: both ignored.
+	mustWrite(t, filepath.Join(bin, PluginPrefix+"noexec"), fakePlugin)
+	mustWrite(t, filepath.Join(bin, PluginPrefix+"copilot"), fakePlugin)
+	os.Chmod(filepath.Join(bin, PluginPrefix+"copilot"), 0o755)
+	t.Setenv("PATH", bin+string(os.PathListSeparator)+os.Getenv("PATH"))
+	return bin
+}
+
+func TestDiscoverPlugins(t *testing.T) {
+	bin := installFakePlugin(t)
+
+	plugins := DiscoverPlugins()
+	if len(plugins) != 1 || plugins[0].ID() != "acme" {
+		t.Fatalf("DiscoverPlugins() = %v, want only acme", plugins)
+	}
+	if got := PluginPath(plugins[0]); got != filepath.Join(bin, PluginPrefix+"acme") {
+		t.Errorf("PluginPath = %q", got)
+	}
+
+	p, err := ProviderFor("acme")
+	if err != nil {
+		t.Fatalf("ProviderFor(acme): %v", err)
+	}
+	if p.DisplayName() != "Acme Agent" || !p.Available() || p.DefaultModel() != "acme-large" {
+		t.Errorf("info = %q available=%v default=%q", p.DisplayName(), p.Available(), p.DefaultModel())
+	}
+	if models := p.KnownModels(); len(models) != 2 || models[1] != (domain.ModelChoice{ID: "acme-small", Label: "Acme Small"}) {
+		t.Errorf("KnownModels = %v", models)
+	}
+	if ids := ProviderIDs(); strings.Join(ids, ",") != "copilot,opencode,pi,acme" {
+		t.Errorf("ProviderIDs = %v", ids)
+	}
+	if c, _ := ProviderFor("copilot"); PluginPath(c) != "" {
+		t.Error("a plugin must not shadow the built-in copilot provider")
+	}
+}
+
+func TestPluginValidateModel(t *testing.T) {
+	installFakePlugin(t)
+	p, _ := ProviderFor("acme")
+
+	if err := p.ValidateModel("acme-small"); err != nil {
+		t.Errorf("ValidateModel(acme-small) = %v", err)
+	}
+	if err := p.ValidateModel("gpt-5.5"); err == nil || !strings.Contains(err.Error(), "acme only serves") {
+		t.Errorf("ValidateModel(gpt-5.5) = %v, want plugin error", err)
+	}
+	if err := p.ValidateModel("bad model"); err == nil {
+		t.Error("shape validation must run before the plugin")
+	}
+	if got := p.ModelAdvisory("exp-1"); got != "experimental model" {
+		t.Errorf("ModelAdvisory = %q", got)
+	}
+}
+
+func TestPluginLaunch(t *testing.T) {
+	installFakePlugin(t)
+	out := filepath.Join(t.TempDir(), "launched")
+	target := filepath.Join(t.TempDir(), "acme")
+	mustWrite(t, target, "#!/bin/sh\necho \"$* $ACME_TOKEN\" > "+out+"\n")
+	os.Chmod(target, 0o755)
+	t.Setenv("LAUNCH_TARGET", target)
+
+	p, _ := ProviderFor("acme")
+	if err := p.Launch(domain.ResolvedConfig{Client: "acme"}); err != nil {
+		t.Fatalf("Launch: %v", err)
+	}
+	data, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatalf("launched command did not run: %v", err)
+	}
+	if got := strings.TrimSpace(string(data)); got != "--model acme-large t" {
+		t.Errorf("launched with %q", got)
+	}
+}
+
+func TestPluginSyncContext(t *testing.T) {
+	installFakePlugin(t)
+	p, _ := ProviderFor("acme")
+
+	if res := p.SyncContext("", "", true, false); res.Managed || res.Err != nil {
+		t.Errorf("unmanaged sync = %+v", res)
+	}
+	if res := p.SyncContext("main", "", true, false); !res.Managed || res.Err != nil {
+		t.Errorf("managed sync = %+v", res)
+	}
+	res := p.SyncContext("broken", "", true, false)
+	if res.Err == nil || !strings.Contains(res.Err.Error(), "upstream unreachable") {
+		t.Errorf("failing sync = %+v, want stderr in error", res)
+	}
+}
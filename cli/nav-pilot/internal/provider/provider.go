@@ -346,9 +346,10 @@ var providerRegistry = []Provider{
 	piProvider{},
 }
 
-// ProviderFor returns the Provider implementation for the given id.
+// ProviderFor returns the Provider implementation for the given id, looking
+// at built-in providers first and then at plugins discovered on PATH.
 func ProviderFor(id string) (Provider, error) {
-	for _, p := range providerRegistry {
+	for _, p := range AllProviders() {
 		if p.ID() == id {
 			return p, nil
 		}
@@ -356,12 +357,26 @@ func ProviderFor(id string) (Provider, error) {
 	return nil, fmt.Errorf("unknown client %q", id)
 }
 
-// AllProviders returns all registered providers in registry order.
+// AllProviders returns the built-in providers in registry order followed by
+// any provider plugins on PATH.
 func AllProviders() []Provider {
+	plugins := DiscoverPlugins()
+	if len(plugins) == 0 {
+		return providerRegistry
+	}
+	all := make([]Provider, 0, len(providerRegistry)+len(plugins))
+	all = append(all, providerRegistry...)
+	return append(all, plugins...)
+}
+
+// BuiltinProviders returns only the in-tree providers. Use it where running
+// plugin executables would be too costly, e.g. on every startup.
+func BuiltinProviders() []Provider {
 	return providerRegistry
 }
 
 // ValidProviderIDs is derived from the registry and used for validation/help text.
+// It lists built-in providers only; ProviderIDs includes plugins.
 var ValidProviderIDs = func() []string {
 	ids := make([]string, len(providerRegistry))
 	for i, p := range providerRegistry {
@@ -370,6 +385,16 @@ var ValidProviderIDs = func() []string {
 	return ids
 }()
 
+// ProviderIDs returns the ids of all providers, built-in and plugin.
+func ProviderIDs() []string {
+	all := AllProviders()
+	ids := make([]string, len(all))
+	for i, p := range all {
+		ids[i] = p.ID()
+	}
+	return ids
+}
+
 func assessStaleness(installedVersion string) artifacts.StalenessAssessment {
 	fetchFn := FetchLatestVersion
 	if fetchFn == nil {
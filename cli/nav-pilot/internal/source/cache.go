@@ -0,0 +1,306 @@
+package source
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/navikt/copilot/cli/nav-pilot/internal/domain"
+)
+
+// Offline makes remote sources resolve from the cache only: the ref's last
+// fetched commit is used and nothing touches the network. Set by --offline.
+var Offline bool
+
+// Cache is the persistent source cache under ~/.nav-pilot/cache:
+//
+//	repos/<hash>.git   bare repo per remote, fetched incrementally (depth 1)
+//	trees/<commit>/    checked-out snapshot, content-addressed by commit
+//	refs.json          "repo@ref" → commit last fetched, for --offline
+//
+// Snapshots are immutable, so a Source backed by the cache has no TempDir
+// and Cleanup leaves it alone.
+type Cache struct {
+	Root string
+}
+
+// cachedRef records where a ref pointed when it was last fetched.
+type cachedRef struct {
+	Commit    string `json:"commit"`
+	FetchedAt string `json:"fetched_at"`
+}
+
+// CacheRoot returns the cache directory, ~/.nav-pilot/cache.
+func CacheRoot() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".nav-pilot", "cache"), nil
+}
+
+// OpenCache returns the cache, creating its directory if needed.
+func OpenCache() (*Cache, error) {
+	root, err := CacheRoot()
+	if err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(root, 0o700); err != nil {
+		return nil, fmt.Errorf("creating cache dir: %w", err)
+	}
+	return &Cache{Root: root}, nil
+}
+
+func (c *Cache) treeDir(commit string) string {
+	return filepath.Join(c.Root, "trees", commit)
+}
+
+func (c *Cache) mirrorDir(repo string) string {
+	sum := sha256.Sum256([]byte(RemoteURL(repo)))
+	return filepath.Join(c.Root, "repos", hex.EncodeToString(sum[:8])+".git")
+}
+
+func (c *Cache) refsPath() string {
+	return filepath.Join(c.Root, "refs.json")
+}
+
+func refKey(repo, ref string) string {
+	if ref == "" {
+		ref = "HEAD"
+	}
+	return repo + "@" + ref
+}
+
+// Fetch resolves ref of sourceRepo to a cached snapshot. A commit SHA that
+// is already cached needs no network; any other ref is fetched into the
+// repo's bare mirror so only new objects are downloaded. With Offline set,
+// the ref's last fetched commit is used instead.
+func (c *Cache) Fetch(ref, sourceRepo string) (*Source, error) {
+	repo := repoName(sourceRepo)
+	key := refKey(repo, ref)
+
+	if IsCommitSHA(ref) && c.hasTree(ref) {
+		return c.snapshot(ref, repo), nil
+	}
+
+	if Offline {
+		refs, err := c.readRefs()
+		if err != nil {
+			return nil, err
+		}
+		r, ok := refs[key]
+		if !ok || IsCommitSHA(ref) || !c.hasTree(r.Commit) {
+			return nil, fmt.Errorf("%s is not in the source cache. Run once without --offline to fetch it", key)
+		}
+		fmt.Fprintf(os.Stderr, "%s Using cached %s (%s, fetched %s)\n",
+			domain.Dim("→"), key, r.Commit[:7], domain.Dim(r.FetchedAt))
+		return c.snapshot(r.Commit, repo), nil
+	}
+
+	mirror := c.mirrorDir(repo)
+	var steps [][]string
+	if _, err := os.Stat(mirror); os.IsNotExist(err) {
+		steps = append(steps, []string{"init", "--bare", "--quiet", mirror})
+	}
+	want := ref
+	if want == "" {
+		want = "HEAD"
+	}
+	steps = append(steps, []string{"--git-dir", mirror, "fetch", "--depth", "1", "--quiet", RemoteURL(repo), want})
+
+	stop := startSpinner(fetchMessage(repo, ref))
+	stderr, err := runGitSteps(nil, steps)
+	stop()
+	if err != nil {
+		return nil, cloneError(repo, ref, stderr)
+	}
+
+	out, err := exec.Command("git", "--git-dir", mirror, "rev-parse", "FETCH_HEAD^{commit}").Output()
+	if err != nil {
+		return nil, fmt.Errorf("reading fetched commit for %s: %w", key, err)
+	}
+	commit := strings.TrimSpace(string(out))
+
+	// Keep the commit reachable from a ref so gc in Prune doesn't drop it and
+	// the next fetch only downloads what changed.
+	sum := sha256.Sum256([]byte(key))
+	exec.Command("git", "--git-dir", mirror, "update-ref", "refs/nav-pilot/"+hex.EncodeToString(sum[:8]), commit).Run()
+
+	if !c.hasTree(commit) {
+		if err := c.extract(mirror, commit); err != nil {
+			return nil, fmt.Errorf("unpacking %s: %w", key, err)
+		}
+	}
+	if err := c.recordRef(key, commit); err != nil {
+		fmt.Fprintf(os.Stderr, "%s Could not update source cache index: %v\n", domain.Yellow("⚠"), err)
+	}
+	return c.snapshot(commit, repo), nil
+}
+
+func (c *Cache) hasTree(commit string) bool {
+	info, err := os.Stat(c.treeDir(commit))
+	return err == nil && info.IsDir()
+}
+
+func (c *Cache) snapshot(commit, repo string) *Source {
+	return &Source{Dir: c.treeDir(commit), SHA: commit[:7], Commit: commit, Repo: repo}
+}
+
+// extract checks commit out of mirror into trees/<commit>. It writes to a
+// temp dir with a private index and renames into place, so concurrent runs
+// never see a half-written snapshot.
+func (c *Cache) extract(mirror, commit string) error {
+	trees := filepath.Join(c.Root, "trees")
+	if err := os.MkdirAll(trees, 0o700); err != nil {
+		return err
+	}
+	tmp, err := os.MkdirTemp(trees, ".tmp-")
+	if err != nil {
+		return err
+	}
+	index := tmp + ".index"
+	defer os.Remove(index)
+
+	stderr, err := runGitSteps([]string{"GIT_INDEX_FILE=" + index}, [][]string{
+		{"--git-dir", mirror, "--work-tree", tmp, "read-tree", commit},
+		{"--git-dir", mirror, "--work-tree", tmp, "checkout-index", "--all", "--force"},
+	})
+	if err != nil {
+		os.RemoveAll(tmp)
+		return fmt.Errorf("%v: %s", err, strings.TrimSpace(stderr))
+	}
+	if err := os.Rename(tmp, c.treeDir(commit)); err != nil {
+		os.RemoveAll(tmp)
+		if c.hasTree(commit) {
+			return nil // another run got there first
+		}
+		return err
+	}
+	return nil
+}
+
+func (c *Cache) readRefs() (map[string]cachedRef, error) {
+	refs := map[string]cachedRef{}
+	data, err := os.ReadFile(c.refsPath())
+	if os.IsNotExist(err) {
+		return refs, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &refs); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", c.refsPath(), err)
+	}
+	return refs, nil
+}
+
+func (c *Cache) recordRef(key, commit string) error {
+	refs, err := c.readRefs()
+	if err != nil {
+		refs = map[string]cachedRef{}
+	}
+	refs[key] = cachedRef{Commit: commit, FetchedAt: time.Now().UTC().Format(time.RFC3339)}
+	data, err := json.MarshalIndent(refs, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(c.Root, ".refs-*.json")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(append(data, '\n')); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), c.refsPath())
+}
+
+// PruneResult reports what Prune removed (or would remove).
+type PruneResult struct {
+	Removed []string `json:"removed"`
+	Bytes   int64    `json:"bytes"`
+}
+
+// Prune evicts snapshots no ref points to any more and compacts the
+// mirrors. With all set, the whole cache is removed, including what
+// --offline relies on.
+func (c *Cache) Prune(all, dryRun bool) (*PruneResult, error) {
+	result := &PruneResult{}
+	if all {
+		entries, err := os.ReadDir(c.Root)
+		if err != nil {
+			return nil, err
+		}
+		for _, e := range entries {
+			p := filepath.Join(c.Root, e.Name())
+			result.Bytes += diskUsage(p)
+			result.Removed = append(result.Removed, e.Name())
+			if !dryRun {
+				if err := os.RemoveAll(p); err != nil {
+					return result, err
+				}
+			}
+		}
+		return result, nil
+	}
+
+	refs, err := c.readRefs()
+	if err != nil {
+		return nil, err
+	}
+	keep := make(map[string]bool, len(refs))
+	for _, r := range refs {
+		keep[r.Commit] = true
+	}
+
+	trees, err := os.ReadDir(filepath.Join(c.Root, "trees"))
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	for _, e := range trees {
+		if keep[e.Name()] {
+			continue
+		}
+		p := filepath.Join(c.Root, "trees", e.Name())
+		result.Bytes += diskUsage(p)
+		result.Removed = append(result.Removed, filepath.Join("trees", e.Name()))
+		if !dryRun {
+			if err := os.RemoveAll(p); err != nil {
+				return result, err
+			}
+		}
+	}
+	sort.Strings(result.Removed)
+
+	if !dryRun {
+		mirrors, _ := filepath.Glob(filepath.Join(c.Root, "repos", "*.git"))
+		for _, m := range mirrors {
+			// Best effort: a failed gc only means the mirror stays larger.
+			exec.Command("git", "--git-dir", m, "gc", "--quiet", "--prune=now").Run()
+		}
+	}
+	return result, nil
+}
+
+// diskUsage returns the total size of regular files under path.
+func diskUsage(path string) int64 {
+	var n int64
+	filepath.Walk(path, func(_ string, info os.FileInfo, err error) error {
+		if err == nil && info.Mode().IsRegular() {
+			n += info.Size()
+		}
+		return nil
+	})
+	return n
+}
@@ -0,0 +1,183 @@
+package source
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// cacheRemote creates a local git repo to stand in for GitHub and points
+// RemoteURL and HOME at temp dirs. commit writes agent content and commits.
+func cacheRemote(t *testing.T) (commit func(content string) string) {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("USERPROFILE", os.Getenv("HOME"))
+	remote := t.TempDir()
+
+	origURL, origOffline := RemoteURL, Offline
+	t.Cleanup(func() { RemoteURL, Offline = origURL, origOffline })
+	RemoteURL = func(string) string { return remote }
+
+	git := func(args ...string) string {
+		t.Helper()
+		cmd := exec.Command("git", args...)
+		cmd.Dir = remote
+		cmd.Env = append(os.Environ(), "GIT_AUTHOR_NAME=t", "GIT_AUTHOR_EMAIL=t@t", "GIT_COMMITTER_NAME=t", "GIT_COMMITTER_EMAIL=t@t")
+		out, err := cmd.CombinedOutput()
+		if err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+		return strings.TrimSpace(string(out))
+	}
+	git("init", "--quiet", "--initial-branch", "main")
+	os.MkdirAll(filepath.Join(remote, "agents"), 0o755)
+	return func(content string) string {
+		os.WriteFile(filepath.Join(remote, "agents", "nais.agent.md"), []byte(content), 0o644)
+		git("add", "-A")
+		git("commit", "--quiet", "-m", content)
+		return git("rev-parse", "HEAD")
+	}
+}
+
+func readAgent(t *testing.T, src *Source) string {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join(src.Dir, "agents", "nais.agent.md"))
+	if err != nil {
+		t.Fatalf("reading snapshot: %v", err)
+	}
+	return string(data)
+}
+
+func TestCacheFetch_SnapshotAndOffline(t *testing.T) {
+	commit := cacheRemote(t)
+	c1 := commit("v1\n")
+
+	src, err := cloneRemote("main", "team/agents")
+	if err != nil {
+		t.Fatalf("fetch: %v", err)
+	}
+	if src.Commit != c1 || src.SHA != c1[:7] || src.Repo != "team/agents" || src.TempDir != "" {
+		t.Errorf("source = %+v", src)
+	}
+	if got := readAgent(t, src); got != "v1\n" {
+		t.Errorf("snapshot content = %q", got)
+	}
+	if _, err := os.Stat(filepath.Join(src.Dir, ".git")); !os.IsNotExist(err) {
+		t.Errorf("snapshot should not contain .git, stat err = %v", err)
+	}
+	src.Cleanup()
+	if _, err := os.Stat(src.Dir); err != nil {
+		t.Errorf("Cleanup must not remove a cached snapshot: %v", err)
+	}
+
+	// Upstream moves on; offline keeps using the last fetched commit.
+	c2 := commit("v2\n")
+	Offline = true
+	src, err = cloneRemote("main", "team/agents")
+	if err != nil || src.Commit != c1 {
+		t.Fatalf("offline fetch = %+v, %v; want %s", src, err, c1)
+	}
+	if _, err := cloneRemote("other", "team/agents"); err == nil {
+		t.Error("offline fetch of an unfetched ref should fail")
+	}
+
+	Offline = false
+	src, err = cloneRemote("main", "team/agents")
+	if err != nil || src.Commit != c2 || readAgent(t, src) != "v2\n" {
+		t.Fatalf("refetch = %+v, %v; want %s", src, err, c2)
+	}
+}
+
+func TestCacheFetch_CommitSHAIsReused(t *testing.T) {
+	commit := cacheRemote(t)
+	c1 := commit("v1\n")
+	if _, err := cloneRemote("main", ""); err != nil {
+		t.Fatalf("fetch: %v", err)
+	}
+
+	// A cached commit resolves without touching the remote, even offline.
+	Offline = true
+	src, err := cloneRemote(c1, "")
+	if err != nil || src.Commit != c1 || src.Repo != "navikt/copilot" {
+		t.Fatalf("cached commit = %+v, %v", src, err)
+	}
+}
+
+func TestCachePrune(t *testing.T) {
+	commit := cacheRemote(t)
+	c1 := commit("v1\n")
+	if _, err := cloneRemote("main", ""); err != nil {
+		t.Fatalf("fetch: %v", err)
+	}
+	commit("v2\n")
+	src, err := cloneRemote("main", "")
+	if err != nil {
+		t.Fatalf("refetch: %v", err)
+	}
+
+	cache, err := OpenCache()
+	if err != nil {
+		t.Fatal(err)
+	}
+	res, err := cache.Prune(false, true)
+	if err != nil || len(res.Removed) != 1 || res.Removed[0] != filepath.Join("trees", c1) || res.Bytes == 0 {
+		t.Fatalf("dry-run prune = %+v, %v", res, err)
+	}
+	if !cache.hasTree(c1) {
+		t.Error("dry run removed the snapshot")
+	}
+
+	if _, err := cache.Prune(false, false); err != nil {
+		t.Fatalf("prune: %v", err)
+	}
+	if cache.hasTree(c1) || !cache.hasTree(src.Commit) {
+		t.Error("prune should drop only the stale snapshot")
+	}
+
+	if _, err := cache.Prune(true, false); err != nil {
+		t.Fatalf("prune --all: %v", err)
+	}
+	if entries, _ := os.ReadDir(cache.Root); len(entries) != 0 {
+		t.Errorf("cache not empty after prune --all: %v", entries)
+	}
+}
+
+func TestLayerChangelog_DeepensShallowMirror(t *testing.T) {
+	commit := cacheRemote(t)
+	c1 := commit("v1\n")
+	if _, err := cloneRemote("main", "team/agents"); err != nil {
+		t.Fatal(err)
+	}
+	c2 := commit("v2\n")
+	c3 := commit("v3\n")
+	src, err := cloneRemote("main", "team/agents")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	entries, err := src.Layers()[0].Changelog(c1[:7], "agents/nais.agent.md")
+	if err != nil {
+		t.Fatalf("Changelog: %v", err)
+	}
+	var got []string
+	for _, e := range entries {
+		got = append(got, e.Commit+" "+e.Subject+" "+e.Author)
+	}
+	if want := c3 + " v3 t|" + c2 + " v2 t"; strings.Join(got, "|") != want {
+		t.Errorf("changelog = %q, want %q", strings.Join(got, "|"), want)
+	}
+	if len(entries) > 0 && len(entries[0].Date) != len("2006-01-02") {
+		t.Errorf("date = %q", entries[0].Date)
+	}
+
+	// Offline, a commit outside the fetched history is an error.
+	Offline = true
+	if _, err := src.Layers()[0].Changelog("0000000", "agents/nais.agent.md"); err == nil {
+		t.Error("Changelog(unknown commit) offline: expected error")
+	}
+}
@@ -0,0 +1,85 @@
+package source
+
+import (
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// LogEntry is one commit in an artifact's source history.
+type LogEntry struct {
+	Commit  string `json:"commit"`
+	Subject string `json:"subject"`
+	Author  string `json:"author"`
+	Date    string `json:"date"` // author date, YYYY-MM-DD
+}
+
+// changelogDeepen is how many commits each deepening fetch adds to a
+// shallow source, and changelogMaxDeepen how many times to try before
+// giving up on finding the installed commit.
+const (
+	changelogDeepen    = 100
+	changelogMaxDeepen = 5
+)
+
+// Changelog lists the commits that touched path (relative to the layer
+// root) after from and up to the layer's commit, newest first. from is the
+// SHA recorded in state and may be short.
+//
+// Cached sources are shallow, so the bare mirror is deepened until it has
+// from; with Offline set nothing is fetched and a missing commit is an
+// error.
+func (l Layer) Changelog(from, path string) ([]LogEntry, error) {
+	if l.Commit == "" {
+		return nil, fmt.Errorf("%s is not a git checkout", l.Dir)
+	}
+	git := l.gitArgs()
+	if err := l.ensureCommit(git, from); err != nil {
+		return nil, err
+	}
+	args := append(git, "log", "--format=%H%x1f%s%x1f%an%x1f%as", from+".."+l.Commit, "--", filepath.ToSlash(path))
+	out, err := exec.Command("git", args...).Output()
+	if err != nil {
+		return nil, fmt.Errorf("reading history of %s: %w", path, err)
+	}
+	var entries []LogEntry
+	for _, line := range strings.Split(strings.TrimSpace(string(out)), "\n") {
+		f := strings.Split(line, "\x1f")
+		if len(f) != 4 {
+			continue
+		}
+		entries = append(entries, LogEntry{Commit: f[0], Subject: f[1], Author: f[2], Date: f[3]})
+	}
+	return entries, nil
+}
+
+// gitArgs returns the arguments that point git at the layer's history: the
+// cache mirror for a cached snapshot, otherwise the checkout itself.
+func (l Layer) gitArgs() []string {
+	if root, err := CacheRoot(); err == nil && filepath.Dir(l.Dir) == filepath.Join(root, "trees") {
+		c := &Cache{Root: root}
+		return []string{"--git-dir", c.mirrorDir(repoName(l.Repo))}
+	}
+	return []string{"-C", l.Dir}
+}
+
+// ensureCommit deepens a shallow history until commit is an ancestor of the
+// layer's commit. Having commit is not enough: an earlier depth-1 fetch
+// leaves it in the mirror without the commits in between.
+func (l Layer) ensureCommit(git []string, commit string) error {
+	for i := 0; ; i++ {
+		if exec.Command("git", append(git, "merge-base", "--is-ancestor", commit, l.Commit)...).Run() == nil {
+			return nil
+		}
+		out, _ := exec.Command("git", append(git, "rev-parse", "--is-shallow-repository")...).Output()
+		if strings.TrimSpace(string(out)) != "true" || Offline || i == changelogMaxDeepen {
+			return fmt.Errorf("commit %s not found in the history of %s", commit, l.Repo)
+		}
+		stderr, err := runGitSteps(nil, [][]string{append(git, "fetch", "--quiet",
+			fmt.Sprintf("--deepen=%d", changelogDeepen), RemoteURL(repoName(l.Repo)), l.Commit)})
+		if err != nil {
+			return fmt.Errorf("fetching history of %s: %v: %s", l.Repo, err, strings.TrimSpace(stderr))
+		}
+	}
+}
@@ -0,0 +1,137 @@
+package source
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// DependencyKey is the frontmatter key artifacts use to declare what they
+// need installed alongside them, as "kind:name" entries:
+//
+//	requires:
+//	  - skill:api-design
+//	  - instruction:kotlin-ktor
+const DependencyKey = "requires"
+
+// Dependency identifies one artifact by kind and name.
+type Dependency struct {
+	Kind *ArtifactKind
+	Name string
+}
+
+// String returns the "kind:name" form used in frontmatter.
+func (d Dependency) String() string {
+	return d.Kind.Name + ":" + d.Name
+}
+
+// ParseDependency parses a "kind:name" entry. "kind: name" and "kind/name"
+// are accepted as well.
+func ParseDependency(entry string) (Dependency, error) {
+	sep := strings.IndexAny(entry, ":/")
+	if sep <= 0 {
+		return Dependency{}, fmt.Errorf("dependency %q must be kind:name", entry)
+	}
+	kind, ok := KindByName[strings.TrimSpace(entry[:sep])]
+	if !ok {
+		return Dependency{}, fmt.Errorf("dependency %q: unknown kind (valid: agent, skill, instruction, prompt)", entry)
+	}
+	name := strings.TrimSpace(entry[sep+1:])
+	if err := ValidateName(name); err != nil {
+		return Dependency{}, fmt.Errorf("dependency %q: %w", entry, err)
+	}
+	return Dependency{Kind: kind, Name: name}, nil
+}
+
+// DescriptorPath returns the file holding an artifact's frontmatter: the file
+// itself, the marker inside a directory artifact (SKILL.md), or
+// <name><suffix> inside a directory prompt.
+func (k *ArtifactKind) DescriptorPath(absPath, name string, isDir bool) string {
+	switch {
+	case !isDir:
+		return absPath
+	case k.Marker != "":
+		return filepath.Join(absPath, k.Marker)
+	default:
+		return filepath.Join(absPath, name+k.Suffix)
+	}
+}
+
+// ReadDependencies returns the dependencies declared in the frontmatter of
+// the artifact at absPath. A missing descriptor or frontmatter means none.
+func ReadDependencies(kind *ArtifactKind, name, absPath string, isDir bool) ([]Dependency, error) {
+	data, err := os.ReadFile(kind.DescriptorPath(absPath, name, isDir))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	fm, _, ok := SplitFrontmatter(data)
+	if !ok {
+		return nil, nil
+	}
+	entries, _, err := frontmatterStrings(fm, DependencyKey)
+	if err != nil {
+		return nil, fmt.Errorf("%s %s: %w", kind.Name, name, err)
+	}
+	var deps []Dependency
+	for _, e := range entries {
+		d, err := ParseDependency(e)
+		if err != nil {
+			return nil, fmt.Errorf("%s %s: %w", kind.Name, name, err)
+		}
+		deps = append(deps, d)
+	}
+	return deps, nil
+}
+
+// Dependencies returns the dependencies declared by a resolved artifact.
+func (r *SourceResolver) Dependencies(art Resolved) ([]Dependency, error) {
+	return ReadDependencies(art.Kind, art.Name, art.AbsPath, art.IsDir)
+}
+
+// ResolvedDependency is one artifact pulled in by dependency resolution.
+type ResolvedDependency struct {
+	Dependency
+	RequiredBy Dependency // the artifact that first declared it
+	Found      bool       // false when no layer provides it
+}
+
+// ResolveClosure returns every artifact transitively required by roots that
+// is not itself a root, in breadth-first order. Cycles are tolerated.
+// Missing dependencies are reported with Found=false rather than as errors,
+// so callers can warn and continue like for a missing manifest entry.
+func (r *SourceResolver) ResolveClosure(roots []Dependency) ([]ResolvedDependency, error) {
+	seen := make(map[string]bool, len(roots))
+	for _, d := range roots {
+		seen[d.String()] = true
+	}
+	queue := append([]Dependency(nil), roots...)
+	var out []ResolvedDependency
+	for len(queue) > 0 {
+		cur := queue[0]
+		queue = queue[1:]
+		art, ok := r.Get(cur.Kind, cur.Name)
+		if !ok {
+			continue
+		}
+		deps, err := r.Dependencies(art)
+		if err != nil {
+			return nil, err
+		}
+		for _, d := range deps {
+			if seen[d.String()] {
+				continue
+			}
+			seen[d.String()] = true
+			_, found := r.Get(d.Kind, d.Name)
+			out = append(out, ResolvedDependency{Dependency: d, RequiredBy: cur, Found: found})
+			if found {
+				queue = append(queue, d)
+			}
+		}
+	}
+	return out, nil
+}
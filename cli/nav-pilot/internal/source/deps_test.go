@@ -0,0 +1,72 @@
+package source
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestParseDependency(t *testing.T) {
+	for _, entry := range []string{"skill:api-design", "skill: api-design", "skill/api-design"} {
+		d, err := ParseDependency(entry)
+		if err != nil || d.Kind != KindSkill || d.Name != "api-design" {
+			t.Errorf("ParseDependency(%q) = %+v, %v", entry, d, err)
+		}
+	}
+	for _, entry := range []string{"api-design", "widget:x", "skill:../x", ":x"} {
+		if _, err := ParseDependency(entry); err == nil {
+			t.Errorf("ParseDependency(%q) should fail", entry)
+		}
+	}
+}
+
+func TestResolveClosure(t *testing.T) {
+	tmp := t.TempDir()
+	os.MkdirAll(filepath.Join(tmp, "agents"), 0o755)
+	os.WriteFile(filepath.Join(tmp, "agents", "nais.agent.md"),
+		[]byte("---\nname: nais\nrequires:\n  - skill:api-design\n  - prompt:missing\n---\n# Nais\n"), 0o644)
+	os.MkdirAll(filepath.Join(tmp, "skills", "api-design"), 0o755)
+	os.WriteFile(filepath.Join(tmp, "skills", "api-design", "SKILL.md"),
+		[]byte("---\nrequires: [instruction:kotlin, agent:nais]\n---\n# API\n"), 0o644)
+	os.MkdirAll(filepath.Join(tmp, "instructions"), 0o755)
+	os.WriteFile(filepath.Join(tmp, "instructions", "kotlin.instructions.md"), []byte("# Kotlin\n"), 0o644)
+
+	r := NewSourceResolver(tmp)
+	got, err := r.ResolveClosure([]Dependency{{Kind: KindAgent, Name: "nais"}})
+	if err != nil {
+		t.Fatalf("ResolveClosure: %v", err)
+	}
+	want := []struct {
+		dep, by string
+		found   bool
+	}{
+		{"skill:api-design", "agent:nais", true},
+		{"prompt:missing", "agent:nais", false},
+		{"instruction:kotlin", "skill:api-design", true},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("closure = %+v, want %d entries (the agent cycle must not repeat the root)", got, len(want))
+	}
+	for i, w := range want {
+		if got[i].String() != w.dep || got[i].RequiredBy.String() != w.by || got[i].Found != w.found {
+			t.Errorf("closure[%d] = %s (by %s, found %v), want %s (by %s, found %v)",
+				i, got[i], got[i].RequiredBy, got[i].Found, w.dep, w.by, w.found)
+		}
+	}
+}
+
+func TestReadDependencies_InvalidEntry(t *testing.T) {
+	tmp := t.TempDir()
+	p := filepath.Join(tmp, "x.agent.md")
+	os.WriteFile(p, []byte("---\nrequires:\n  - bogus\n---\n"), 0o644)
+	if _, err := ReadDependencies(KindAgent, "x", p, false); err == nil {
+		t.Error("expected error for malformed entry")
+	}
+
+	// Frontmatter that lint rejects is rejected here too, not half-read.
+	os.WriteFile(p, []byte("---\ndescription: Ekspert: Aksel\nrequires: [skill:a\n---\n"), 0o644)
+	if _, err := ReadDependencies(KindAgent, "x", p, false); err == nil || !strings.Contains(err.Error(), "invalid frontmatter") {
+		t.Errorf("err = %v, want invalid frontmatter", err)
+	}
+}
@@ -0,0 +1,154 @@
+package source
+
+import (
+	"fmt"
+	"strings"
+)
+
+// DiffContext is the number of unchanged lines shown around each change,
+// matching diff -u and git diff.
+const DiffContext = 3
+
+// noNewlineMarker follows a diff line whose source line had no trailing newline.
+const noNewlineMarker = `\ No newline at end of file`
+
+// DiffHunk is one hunk of a unified diff. Lines carry their ' ', '-' or '+'
+// prefix and no trailing newline; OldStart/NewStart are 1-based as in the
+// "@@ -l,s +l,s @@" header (0 for an empty side).
+type DiffHunk struct {
+	OldStart int      `json:"old_start"`
+	OldLines int      `json:"old_lines"`
+	NewStart int      `json:"new_start"`
+	NewLines int      `json:"new_lines"`
+	Lines    []string `json:"lines"`
+}
+
+// Header returns the hunk's "@@ -l,s +l,s @@" line.
+func (h DiffHunk) Header() string {
+	return fmt.Sprintf("@@ -%s +%s @@", hunkRange(h.OldStart, h.OldLines), hunkRange(h.NewStart, h.NewLines))
+}
+
+func hunkRange(start, n int) string {
+	if n == 1 {
+		return fmt.Sprint(start)
+	}
+	return fmt.Sprintf("%d,%d", start, n)
+}
+
+// diffOp is one line of the edit script: ' ' keep, '-' delete, '+' insert.
+type diffOp struct {
+	kind byte
+	line string
+}
+
+// Diff returns the unified-diff hunks turning old into new, with context
+// unchanged lines around each change. Identical input yields no hunks.
+// Lines are matched with the same Myers diff Merge3 uses.
+func Diff(old, new []byte, context int) []DiffHunk {
+	a := splitLines(old)
+	b := splitLines(new)
+	match := matchLines(a, b)
+
+	var ops []diffOp
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		switch {
+		case i < len(a) && match[i] == j:
+			ops = append(ops, diffOp{' ', a[i]})
+			i++
+			j++
+		case i < len(a) && match[i] < 0:
+			ops = append(ops, diffOp{'-', a[i]})
+			i++
+		default:
+			ops = append(ops, diffOp{'+', b[j]})
+			j++
+		}
+	}
+
+	var hunks []DiffHunk
+	oldLine, newLine := 0, 0 // lines consumed before ops[k]
+	for k := 0; k < len(ops); {
+		if ops[k].kind == ' ' {
+			oldLine++
+			newLine++
+			k++
+			continue
+		}
+		// Start the hunk up to context lines before the first change.
+		start := k
+		for start > 0 && k-start < context && ops[start-1].kind == ' ' {
+			start--
+		}
+		h := DiffHunk{OldStart: oldLine - (k - start) + 1, NewStart: newLine - (k - start) + 1}
+
+		// Extend until a run of more than 2*context unchanged lines (or the
+		// end), so nearby changes share a hunk, then keep context lines.
+		end := k
+		for end < len(ops) {
+			if ops[end].kind != ' ' {
+				end++
+				continue
+			}
+			run := end
+			for run < len(ops) && ops[run].kind == ' ' {
+				run++
+			}
+			if run == len(ops) || run-end > 2*context {
+				end += min(context, run-end)
+				break
+			}
+			end = run
+		}
+
+		for _, op := range ops[start:end] {
+			switch op.kind {
+			case ' ':
+				h.OldLines++
+				h.NewLines++
+			case '-':
+				h.OldLines++
+			case '+':
+				h.NewLines++
+			}
+			h.Lines = append(h.Lines, string(op.kind)+strings.TrimSuffix(op.line, "\n"))
+			if !strings.HasSuffix(op.line, "\n") {
+				h.Lines = append(h.Lines, noNewlineMarker)
+			}
+		}
+		if h.OldLines == 0 {
+			h.OldStart--
+		}
+		if h.NewLines == 0 {
+			h.NewStart--
+		}
+		hunks = append(hunks, h)
+
+		for _, op := range ops[k:end] {
+			if op.kind != '+' {
+				oldLine++
+			}
+			if op.kind != '-' {
+				newLine++
+			}
+		}
+		k = end
+	}
+	return hunks
+}
+
+// DiffStat counts the inserted and deleted lines in hunks.
+func DiffStat(hunks []DiffHunk) (added, deleted int) {
+	for _, h := range hunks {
+		for _, l := range h.Lines {
+			switch {
+			case l == noNewlineMarker:
+			case strings.HasPrefix(l, "+"):
+				added++
+			case strings.HasPrefix(l, "-"):
+				deleted++
+			}
+		}
+	}
+	return added, deleted
+}
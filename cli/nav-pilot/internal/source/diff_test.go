@@ -0,0 +1,83 @@
+package source
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func numbered(from, to int, edit map[int]string) []byte {
+	var b strings.Builder
+	for i := from; i <= to; i++ {
+		if l, ok := edit[i]; ok {
+			if l != "" {
+				b.WriteString(l + "\n")
+			}
+			continue
+		}
+		fmt.Fprintf(&b, "line %d\n", i)
+	}
+	return []byte(b.String())
+}
+
+func TestDiff_Identical(t *testing.T) {
+	if h := Diff([]byte("a\nb\n"), []byte("a\nb\n"), DiffContext); len(h) != 0 {
+		t.Errorf("Diff of identical input = %v", h)
+	}
+}
+
+func TestDiff_SingleChange(t *testing.T) {
+	old := numbered(1, 10, nil)
+	new := numbered(1, 10, map[int]string{5: "line five"})
+	hunks := Diff(old, new, DiffContext)
+	if len(hunks) != 1 {
+		t.Fatalf("got %d hunks, want 1: %v", len(hunks), hunks)
+	}
+	h := hunks[0]
+	if h.Header() != "@@ -2,7 +2,7 @@" {
+		t.Errorf("header = %q", h.Header())
+	}
+	want := []string{" line 2", " line 3", " line 4", "-line 5", "+line five", " line 6", " line 7", " line 8"}
+	if !reflect.DeepEqual(h.Lines, want) {
+		t.Errorf("lines = %q", h.Lines)
+	}
+	if a, d := DiffStat(hunks); a != 1 || d != 1 {
+		t.Errorf("DiffStat = +%d -%d", a, d)
+	}
+}
+
+func TestDiff_SeparateAndMergedHunks(t *testing.T) {
+	old := numbered(1, 30, nil)
+	// Changes 6 lines apart share a hunk; one 20 lines later gets its own.
+	new := numbered(1, 30, map[int]string{3: "", 9: "nine", 29: "29!"})
+	hunks := Diff(old, new, DiffContext)
+	if len(hunks) != 2 {
+		t.Fatalf("got %d hunks, want 2: %v", len(hunks), hunks)
+	}
+	if hunks[0].Header() != "@@ -1,12 +1,11 @@" || hunks[1].Header() != "@@ -26,5 +25,5 @@" {
+		t.Errorf("headers = %q, %q", hunks[0].Header(), hunks[1].Header())
+	}
+}
+
+func TestDiff_EmptySidesAndMissingNewline(t *testing.T) {
+	hunks := Diff(nil, []byte("a\nb"), DiffContext)
+	if len(hunks) != 1 || hunks[0].Header() != "@@ -0,0 +1,2 @@" {
+		t.Fatalf("add-all hunks = %v", hunks)
+	}
+	want := []string{"+a", "+b", `\ No newline at end of file`}
+	if !reflect.DeepEqual(hunks[0].Lines, want) {
+		t.Errorf("lines = %q", hunks[0].Lines)
+	}
+	if a, d := DiffStat(hunks); a != 2 || d != 0 {
+		t.Errorf("DiffStat = +%d -%d", a, d)
+	}
+
+	hunks = Diff([]byte("x\n"), nil, DiffContext)
+	if len(hunks) != 1 || hunks[0].Header() != "@@ -1 +0,0 @@" {
+		t.Errorf("delete-all hunks = %v", hunks)
+	}
+	if hunks := Diff([]byte{}, nil, DiffContext); len(hunks) != 0 {
+		t.Errorf("Diff of two empty inputs = %v", hunks)
+	}
+}
@@ -59,10 +59,15 @@ func NormalizedFileHash(path string) (string, error) {
 	if err != nil {
 		return "", err
 	}
-	normalized := NormalizeMarkdown(data)
+	return NormalizedDataHash(data), nil
+}
+
+// NormalizedDataHash hashes markdown content that is already in memory, the
+// same way NormalizedFileHash hashes a .md file.
+func NormalizedDataHash(data []byte) string {
 	h := sha256.New()
-	h.Write(normalized)
-	return hex.EncodeToString(h.Sum(nil))[:16], nil
+	h.Write(NormalizeMarkdown(data))
+	return hex.EncodeToString(h.Sum(nil))[:16]
 }
 
 // DirHash hashes all files in a directory recursively.
@@ -139,6 +144,36 @@ func CopyFile(src, dst, boundary string) error {
 	return os.Rename(tmpPath, dst)
 }
 
+// WriteFile writes data to dst atomically, with the same symlink protection
+// as CopyFile.
+func WriteFile(dst string, data []byte, boundary string) error {
+	if err := CheckSymlink(dst, boundary); err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(dst), 0o755); err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(dst), ".nav-pilot-*")
+	if err != nil {
+		return err
+	}
+	tmpPath := tmp.Name()
+	defer os.Remove(tmpPath)
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	tmp.Close()
+
+	return os.Rename(tmpPath, dst)
+}
+
 // CheckSymlink detects symlinks in the path chain between path and boundary.
 // Walks up from the file's parent directory, checking each component with Lstat.
 // Stops at boundary (the trusted root) to avoid false positives from system
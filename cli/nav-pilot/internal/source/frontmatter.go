@@ -2,7 +2,10 @@ package source
 
 import (
 	"bytes"
+	"fmt"
 	"strings"
+
+	"gopkg.in/yaml.v3"
 )
 
 // SplitFrontmatter splits a markdown file into YAML frontmatter and body.
@@ -165,6 +168,67 @@ func ExtractFrontmatterValue(fm []byte, key string) (string, bool) {
 	return "", false
 }
 
+// frontmatterStrings returns the values of a top-level key in frontmatter,
+// parsed as lint does: a scalar is one item, a list its items. found is
+// false when the key is absent or null.
+func frontmatterStrings(fm []byte, key string) (items []string, found bool, err error) {
+	doc, err := parseYAML(fm)
+	if err != nil {
+		return nil, false, fmt.Errorf("invalid frontmatter: %w", err)
+	}
+	n := yamlGet(doc, key)
+	if yamlIsNull(n) {
+		return nil, false, nil
+	}
+	switch n.Kind {
+	case yaml.ScalarNode:
+		return []string{n.Value}, true, nil
+	case yaml.SequenceNode:
+		for _, item := range n.Content {
+			if item = yamlResolve(item); item.Kind != yaml.ScalarNode {
+				return nil, true, fmt.Errorf("%q entries must be strings", key)
+			}
+			items = append(items, item.Value)
+		}
+		return items, true, nil
+	}
+	return nil, true, fmt.Errorf("%q must be a string or a list of strings, got a %s", key, yamlKindName(n))
+}
+
+// ScopeApplyTo roots an instruction's applyTo globs at dir, a slash-separated
+// path relative to the repository root: "**/*.kt" becomes "backend/**/*.kt".
+// An instruction without applyTo applies to everything under dir.
+func ScopeApplyTo(data []byte, dir string) ([]byte, error) {
+	dir = strings.Trim(dir, "/")
+	fm, body, ok := SplitFrontmatter(data)
+	var globs []string
+	if ok {
+		items, _, err := frontmatterStrings(fm, "applyTo")
+		if err != nil {
+			return nil, err
+		}
+		for _, item := range items {
+			for _, g := range splitGlobList(item) {
+				if g != "" {
+					globs = append(globs, g)
+				}
+			}
+		}
+		fm = StripFrontmatterKeys(fm, []string{"applyTo"})
+	}
+	if len(globs) == 0 {
+		globs = []string{"**"}
+	}
+
+	scoped := make([]string, len(globs))
+	for i, g := range globs {
+		g = strings.TrimPrefix(strings.TrimPrefix(g, "./"), "/")
+		scoped[i] = dir + "/" + g
+	}
+	fm = append(fm, "applyTo: \""+strings.Join(scoped, ", ")+"\"\n"...)
+	return Reassemble(fm, bytes.TrimLeft(body, "\n")), nil
+}
+
 // openCodePrimaryAgents are the materialized agent names that opencode should
 // treat as primary agents — i.e. selectable in the Tab/switch_agent picker and
 // launchable via `opencode --agent <name>`. Every other Nav agent is exported
@@ -197,6 +261,14 @@ func BuildAgentFrontmatter(description, mode string) []byte {
 	return buf.Bytes()
 }
 
+// BuildClaudeAgentFrontmatter generates Claude Code subagent frontmatter.
+func BuildClaudeAgentFrontmatter(name, description string) []byte {
+	var buf bytes.Buffer
+	buf.WriteString("name: " + name + "\n")
+	buf.WriteString("description: " + yamlQuoteIfNeeded(description) + "\n")
+	return buf.Bytes()
+}
+
 // yamlQuoteIfNeeded wraps a string in double quotes if it contains characters
 // that are special in YAML.
 func yamlQuoteIfNeeded(s string) string {
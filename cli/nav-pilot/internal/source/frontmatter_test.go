@@ -1,6 +1,7 @@
 package source
 
 import (
+	"strings"
 	"testing"
 )
 
@@ -336,3 +337,77 @@ func TestReassemble(t *testing.T) {
 		})
 	}
 }
+
+func TestFrontmatterStrings(t *testing.T) {
+	tests := []struct {
+		name   string
+		fm     string
+		want   []string
+		wantOK bool
+	}{
+		{"block list", "name: x\nrequires:\n  - skill:a\n  - 'prompt:b'\ndescription: y\n", []string{"skill:a", "prompt:b"}, true},
+		{"unindented block list", "requires:\n- skill:a\n- skill:b\n", []string{"skill:a", "skill:b"}, true},
+		{"flow list", "requires: [skill:a, \"agent:b\"]\n", []string{"skill:a", "agent:b"}, true},
+		{"scalar", "requires: skill:a\n", []string{"skill:a"}, true},
+		{"missing", "name: x\n", nil, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok, err := frontmatterStrings([]byte(tt.fm), "requires")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if ok != tt.wantOK {
+				t.Fatalf("found = %v, want %v", ok, tt.wantOK)
+			}
+			if strings.Join(got, "|") != strings.Join(tt.want, "|") {
+				t.Errorf("items = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestScopeApplyTo(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{
+			"single glob",
+			"---\ndescription: Kotlin\napplyTo: \"**/*.kt\"\n---\n\n# Kotlin\n",
+			"---\ndescription: Kotlin\napplyTo: \"backend/**/*.kt\"\n---\n\n# Kotlin\n",
+		},
+		{
+			"comma list keeps braces",
+			"---\napplyTo: '**/*.ts, src/**/*.{tsx,jsx}'\n---\n# TS\n",
+			"---\napplyTo: \"backend/**/*.ts, backend/src/**/*.{tsx,jsx}\"\n---\n\n# TS\n",
+		},
+		{
+			"yaml list",
+			"---\napplyTo:\n  - \"**/*.kt\"\n  - ./build.gradle.kts\nname: k\n---\n\n# K\n",
+			"---\nname: k\napplyTo: \"backend/**/*.kt, backend/build.gradle.kts\"\n---\n\n# K\n",
+		},
+		{
+			"no applyTo",
+			"---\nname: k\n---\n\n# K\n",
+			"---\nname: k\napplyTo: \"backend/**\"\n---\n\n# K\n",
+		},
+		{
+			"no frontmatter",
+			"# K\n",
+			"---\napplyTo: \"backend/**\"\n---\n\n# K\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ScopeApplyTo([]byte(tt.in), "backend/")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("ScopeApplyTo:\ngot:  %q\nwant: %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,224 @@
+package source
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// InitTemplatesDir holds the templates `nav-pilot init` renders, relative to
+// a source root.
+const InitTemplatesDir = "templates/init"
+
+// InitStack is a stack declared by an init templates manifest, on top of the
+// ones init detects itself (go, node, next, kotlin, nais).
+type InitStack struct {
+	Name  string   `json:"name"`  // key used by templates: {{if .Has "python"}}
+	Label string   `json:"label"` // shown in the stack summary: "Python"
+	Files []string `json:"files"` // glob patterns; any match in a directory detects the stack
+}
+
+// Detect reports whether dir has any of the stack's marker files.
+func (s InitStack) Detect(dir string) bool {
+	for _, pattern := range s.Files {
+		if matches, _ := filepath.Glob(filepath.Join(dir, pattern)); len(matches) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// InitTemplate is one file init writes.
+type InitTemplate struct {
+	// Path is the target path relative to the repo root. It is itself a
+	// template, so per-subproject files can use {{.Path}}.
+	Path     string   `json:"path"`
+	Template string   `json:"template"`            // file in templates/init
+	Stacks   []string `json:"stacks,omitempty"`    // render only if one of these is detected
+	MaxChars int      `json:"max_chars,omitempty"` // truncate longer output (Copilot Code Review reads 4000)
+	// Subprojects renders the template once per monorepo subproject instead
+	// of once for the repo.
+	Subprojects bool `json:"subprojects,omitempty"`
+
+	// Body is the template text, read from the layer that provided it so
+	// it outlives the source's temp dir.
+	Body string `json:"-"`
+}
+
+// InitTemplates is the merged templates/init/manifest.json of a source.
+type InitTemplates struct {
+	Stacks    []InitStack    `json:"stacks"`
+	Templates []InitTemplate `json:"templates"`
+	// PartialFiles are parsed into every template, so {{define}} blocks like
+	// build commands can be shared.
+	PartialFiles []string `json:"partials,omitempty"`
+
+	// Partials maps each partial file to its text.
+	Partials map[string]string `json:"-"`
+}
+
+// LoadInitTemplates reads templates/init/manifest.json from every layer. A
+// higher layer replaces a stack with the same name and a template with the
+// same path, and adds the rest, so an overlay can add a stack without
+// copying the base templates. It returns nil when no layer has templates.
+func LoadInitTemplates(layers []Layer) (*InitTemplates, error) {
+	var merged *InitTemplates
+	for _, layer := range layers {
+		t, err := loadInitTemplatesIn(layer.Dir)
+		if err != nil {
+			if layer.Repo != "" {
+				return nil, fmt.Errorf("%s: %w", layer.Repo, err)
+			}
+			return nil, err
+		}
+		if t == nil {
+			continue
+		}
+		if merged == nil {
+			merged = &InitTemplates{Partials: map[string]string{}}
+		}
+		merged.merge(t)
+	}
+	return merged, nil
+}
+
+func (m *InitTemplates) merge(t *InitTemplates) {
+	for name, body := range t.Partials {
+		m.Partials[name] = body
+	}
+	for _, s := range t.Stacks {
+		replaced := false
+		for i := range m.Stacks {
+			if m.Stacks[i].Name == s.Name {
+				m.Stacks[i], replaced = s, true
+			}
+		}
+		if !replaced {
+			m.Stacks = append(m.Stacks, s)
+		}
+	}
+	for _, tmpl := range t.Templates {
+		replaced := false
+		for i := range m.Templates {
+			if m.Templates[i].Path == tmpl.Path {
+				m.Templates[i], replaced = tmpl, true
+			}
+		}
+		if !replaced {
+			m.Templates = append(m.Templates, tmpl)
+		}
+	}
+}
+
+func loadInitTemplatesIn(root string) (*InitTemplates, error) {
+	dir := filepath.Join(root, filepath.FromSlash(InitTemplatesDir))
+	manifestPath := filepath.Join(dir, "manifest.json")
+	if _, err := checkSafePath(root, manifestPath); err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	data, err := os.ReadFile(manifestPath)
+	if err != nil {
+		return nil, err
+	}
+	var t InitTemplates
+	if err := json.Unmarshal(data, &t); err != nil {
+		return nil, fmt.Errorf("parsing %s/manifest.json: %w", InitTemplatesDir, err)
+	}
+	if err := ValidateInitTemplates(&t); err != nil {
+		return nil, fmt.Errorf("%s/manifest.json: %w", InitTemplatesDir, err)
+	}
+	for i := range t.Templates {
+		body, err := readInitTemplateFile(dir, t.Templates[i].Template)
+		if err != nil {
+			return nil, err
+		}
+		t.Templates[i].Body = body
+	}
+	t.Partials = map[string]string{}
+	for _, name := range t.PartialFiles {
+		body, err := readInitTemplateFile(dir, name)
+		if err != nil {
+			return nil, err
+		}
+		t.Partials[name] = body
+	}
+	return &t, nil
+}
+
+func readInitTemplateFile(dir, name string) (string, error) {
+	abs := filepath.Join(dir, filepath.FromSlash(name))
+	if _, err := checkSafePath(dir, abs); err != nil {
+		return "", fmt.Errorf("%s/manifest.json: template %q: %w", InitTemplatesDir, name, err)
+	}
+	data, err := os.ReadFile(abs)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+// ValidateInitTemplates checks an init templates manifest. Target paths are
+// checked again after rendering (ValidateInitPath).
+func ValidateInitTemplates(t *InitTemplates) error {
+	stacks := map[string]bool{}
+	for _, s := range t.Stacks {
+		if err := ValidateName(s.Name); err != nil {
+			return fmt.Errorf("invalid stack: %w", err)
+		}
+		if stacks[s.Name] {
+			return fmt.Errorf("duplicate stack: %q", s.Name)
+		}
+		stacks[s.Name] = true
+		if s.Label == "" || len(s.Files) == 0 {
+			return fmt.Errorf("stack %q needs a label and at least one file", s.Name)
+		}
+		for _, f := range s.Files {
+			if _, err := filepath.Match(f, ""); err != nil || ValidateInitPath(f) != nil {
+				return fmt.Errorf("stack %q: invalid file pattern %q", s.Name, f)
+			}
+		}
+	}
+	paths := map[string]bool{}
+	for _, tmpl := range t.Templates {
+		if tmpl.Path == "" || tmpl.Template == "" {
+			return fmt.Errorf("template needs a path and a template file")
+		}
+		if paths[tmpl.Path] {
+			return fmt.Errorf("duplicate template path: %q", tmpl.Path)
+		}
+		paths[tmpl.Path] = true
+		if err := ValidateInitPath(tmpl.Template); err != nil {
+			return fmt.Errorf("template file: %w", err)
+		}
+		if tmpl.MaxChars < 0 {
+			return fmt.Errorf("template %q: negative max_chars", tmpl.Path)
+		}
+	}
+	for _, p := range t.PartialFiles {
+		if err := ValidateInitPath(p); err != nil {
+			return fmt.Errorf("partial: %w", err)
+		}
+	}
+	return nil
+}
+
+// ValidateInitPath checks a slash-separated path that must stay inside the
+// directory it is relative to.
+func ValidateInitPath(p string) error {
+	if p == "" {
+		return fmt.Errorf("empty path")
+	}
+	if strings.HasPrefix(p, "/") || filepath.IsAbs(p) || strings.Contains(p, "\\") {
+		return fmt.Errorf("path %q is not relative", p)
+	}
+	if path.Clean(p) != p || p == "." || p == ".." || strings.HasPrefix(p, "../") {
+		return fmt.Errorf("path %q is not clean or leaves its directory", p)
+	}
+	return nil
+}
@@ -0,0 +1,92 @@
+package source
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeInitTemplates(t *testing.T, root, manifest string, files map[string]string) {
+	t.Helper()
+	dir := filepath.Join(root, "templates", "init")
+	os.MkdirAll(dir, 0o755)
+	os.WriteFile(filepath.Join(dir, "manifest.json"), []byte(manifest), 0o644)
+	for name, body := range files {
+		os.WriteFile(filepath.Join(dir, name), []byte(body), 0o644)
+	}
+}
+
+func TestLoadInitTemplates(t *testing.T) {
+	base := t.TempDir()
+	writeInitTemplates(t, base, `{
+		"partials": ["common.tmpl"],
+		"templates": [
+			{"path": "AGENTS.md", "template": "agents.tmpl"},
+			{"path": ".github/copilot-instructions.md", "template": "copilot.tmpl"}
+		]
+	}`, map[string]string{"common.tmpl": "base", "agents.tmpl": "base agents", "copilot.tmpl": "base copilot"})
+
+	overlay := t.TempDir()
+	writeInitTemplates(t, overlay, `{
+		"stacks": [{"name": "python", "label": "Python", "files": ["pyproject.toml"]}],
+		"templates": [
+			{"path": "AGENTS.md", "template": "agents.tmpl"},
+			{"path": ".github/instructions/python.instructions.md", "template": "python.tmpl", "stacks": ["python"]}
+		]
+	}`, map[string]string{"agents.tmpl": "team agents", "python.tmpl": "python"})
+
+	got, err := LoadInitTemplates([]Layer{{Dir: base}, {Dir: t.TempDir()}, {Dir: overlay}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var paths []string
+	for _, tmpl := range got.Templates {
+		paths = append(paths, tmpl.Path+"="+tmpl.Body)
+	}
+	want := "AGENTS.md=team agents|.github/copilot-instructions.md=base copilot|.github/instructions/python.instructions.md=python"
+	if strings.Join(paths, "|") != want {
+		t.Errorf("templates = %q, want %q", strings.Join(paths, "|"), want)
+	}
+	if len(got.Stacks) != 1 || got.Stacks[0].Name != "python" {
+		t.Errorf("stacks = %+v", got.Stacks)
+	}
+	if got.Partials["common.tmpl"] != "base" {
+		t.Errorf("partials = %v", got.Partials)
+	}
+
+	if none, err := LoadInitTemplates([]Layer{{Dir: t.TempDir()}}); none != nil || err != nil {
+		t.Errorf("source without templates: %v, %v", none, err)
+	}
+}
+
+func TestLoadInitTemplates_Invalid(t *testing.T) {
+	for name, manifest := range map[string]string{
+		"template outside dir": `{"templates": [{"path": "AGENTS.md", "template": "../../secret"}]}`,
+		"missing template":     `{"templates": [{"path": "AGENTS.md", "template": "missing.tmpl"}]}`,
+		"duplicate path":       `{"templates": [{"path": "a", "template": "a.tmpl"}, {"path": "a", "template": "a.tmpl"}]}`,
+		"stack without files":  `{"stacks": [{"name": "python", "label": "Python"}]}`,
+		"bad stack name":       `{"stacks": [{"name": "../x", "label": "X", "files": ["x"]}]}`,
+		"invalid json":         `{`,
+	} {
+		t.Run(name, func(t *testing.T) {
+			root := t.TempDir()
+			writeInitTemplates(t, root, manifest, map[string]string{"a.tmpl": ""})
+			if _, err := LoadInitTemplates([]Layer{{Dir: root, Repo: "team/copilot"}}); err == nil {
+				t.Error("expected error")
+			}
+		})
+	}
+}
+
+func TestInitStackDetect(t *testing.T) {
+	dir := t.TempDir()
+	os.WriteFile(filepath.Join(dir, "App.csproj"), nil, 0o644)
+	dotnet := InitStack{Name: "dotnet", Label: ".NET", Files: []string{"*.csproj", "*.sln"}}
+	if !dotnet.Detect(dir) {
+		t.Error("expected *.csproj to detect .NET")
+	}
+	if (InitStack{Name: "python", Files: []string{"pyproject.toml"}}).Detect(dir) {
+		t.Error("python detected without pyproject.toml")
+	}
+}
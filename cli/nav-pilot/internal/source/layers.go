@@ -0,0 +1,93 @@
+package source
+
+import (
+	"fmt"
+	"strings"
+)
+
+// LayerSeparator joins layered sources in --source values and state files
+// (e.g. "navikt/copilot,myteam/copilot").
+const LayerSeparator = ","
+
+// Layer is one source directory in an ordered multi-source stack.
+// Layers are ordered by increasing precedence: the first layer is the base
+// (usually navikt/copilot) and later layers are overlays whose artifacts win
+// on name collisions.
+type Layer struct {
+	Repo   string // repository label recorded in state (e.g. "myteam/copilot")
+	Dir    string
+	SHA    string
+	Commit string // full commit SHA; empty when the layer is not a git checkout
+}
+
+// SplitSourceRepos splits a --source value into its ordered layer specs.
+// Empty entries are dropped, so "a,,b" and "a,b" are equivalent.
+func SplitSourceRepos(sourceRepo string) []string {
+	var repos []string
+	for _, r := range strings.Split(sourceRepo, LayerSeparator) {
+		if r = strings.TrimSpace(r); r != "" {
+			repos = append(repos, r)
+		}
+	}
+	return repos
+}
+
+// splitRepoRef splits a layer spec of the form "owner/name@ref".
+// Only an "@" after the last path separator is treated as a ref, so
+// local paths containing "@" are left alone.
+func splitRepoRef(spec string) (repo, ref string) {
+	at := strings.LastIndex(spec, "@")
+	if at <= 0 || at < strings.LastIndex(spec, "/") {
+		return spec, ""
+	}
+	return spec[:at], spec[at+1:]
+}
+
+// resolveLayered resolves every layer in repos and stacks them on the first.
+// The global ref applies to the base layer only; overlays may pin their own
+// ref with "owner/name@ref".
+func resolveLayered(ref string, repos []string, cliVersion string) (*Source, error) {
+	var base *Source
+	for i, spec := range repos {
+		repo, layerRef := splitRepoRef(spec)
+		if i == 0 && layerRef == "" {
+			layerRef = ref
+		}
+		src, err := ResolveSource(layerRef, repo, cliVersion)
+		if err != nil {
+			if base != nil {
+				base.Cleanup()
+			}
+			return nil, fmt.Errorf("source layer %d (%s): %w", i+1, repo, err)
+		}
+		if base == nil {
+			base = src
+			continue
+		}
+		base.Overlays = append(base.Overlays, src)
+	}
+	base.Repo = strings.Join(repos, LayerSeparator)
+	return base, nil
+}
+
+// Layers returns the source's layers in increasing precedence order.
+// A single (non-layered) source returns one layer.
+func (s *Source) Layers() []Layer {
+	if len(s.Overlays) == 0 {
+		return []Layer{{Repo: s.Repo, Dir: s.Dir, SHA: s.SHA, Commit: s.Commit}}
+	}
+	baseRepo := s.Repo
+	if repos := SplitSourceRepos(s.Repo); len(repos) > 0 {
+		baseRepo, _ = splitRepoRef(repos[0])
+	}
+	layers := []Layer{{Repo: baseRepo, Dir: s.Dir, SHA: s.SHA, Commit: s.Commit}}
+	for _, o := range s.Overlays {
+		layers = append(layers, Layer{Repo: o.Repo, Dir: o.Dir, SHA: o.SHA, Commit: o.Commit})
+	}
+	return layers
+}
+
+// Resolver returns a SourceResolver spanning all of the source's layers.
+func (s *Source) Resolver() *SourceResolver {
+	return NewLayeredSourceResolver(s.Layers())
+}
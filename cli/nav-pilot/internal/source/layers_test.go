@@ -0,0 +1,239 @@
+package source
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestSplitSourceRepos(t *testing.T) {
+	tests := []struct {
+		in   string
+		want []string
+	}{
+		{"", nil},
+		{"navikt/copilot", []string{"navikt/copilot"}},
+		{"navikt/copilot,team/agents", []string{"navikt/copilot", "team/agents"}},
+		{" navikt/copilot ,, team/agents@v2 ", []string{"navikt/copilot", "team/agents@v2"}},
+	}
+	for _, tt := range tests {
+		if got := SplitSourceRepos(tt.in); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("SplitSourceRepos(%q) = %v, want %v", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestSplitRepoRef(t *testing.T) {
+	tests := []struct {
+		in, repo, ref string
+	}{
+		{"team/agents", "team/agents", ""},
+		{"team/agents@v2", "team/agents", "v2"},
+		{"/home/me@work/agents", "/home/me@work/agents", ""},
+	}
+	for _, tt := range tests {
+		repo, ref := splitRepoRef(tt.in)
+		if repo != tt.repo || ref != tt.ref {
+			t.Errorf("splitRepoRef(%q) = (%q, %q), want (%q, %q)", tt.in, repo, ref, tt.repo, tt.ref)
+		}
+	}
+}
+
+func layeredFixture(t *testing.T) (*SourceResolver, string, string) {
+	t.Helper()
+	base := t.TempDir()
+	team := t.TempDir()
+	mkFile(t, base, "agents", "nais.agent.md")
+	mkFile(t, base, "agents", "shared.agent.md")
+	mkFile(t, base, "skills", "api-design", "SKILL.md")
+	mkFile(t, team, "agents", "shared.agent.md")
+	mkFile(t, team, "agents", "team-only.agent.md")
+	mkFile(t, team, "skills", "api-design", "SKILL.md")
+
+	r := NewLayeredSourceResolver([]Layer{
+		{Repo: "navikt/copilot", Dir: base},
+		{Repo: "team/agents", Dir: team},
+	})
+	return r, base, team
+}
+
+func TestLayeredResolver_OverlayWinsCollision(t *testing.T) {
+	r, base, team := layeredFixture(t)
+
+	tests := []struct {
+		kind     *ArtifactKind
+		name     string
+		wantRepo string
+		wantRoot string
+	}{
+		{KindAgent, "nais", "navikt/copilot", base},
+		{KindAgent, "shared", "team/agents", team},
+		{KindAgent, "team-only", "team/agents", team},
+		{KindSkill, "api-design", "team/agents", team},
+	}
+	for _, tt := range tests {
+		art, ok := r.Get(tt.kind, tt.name)
+		if !ok {
+			t.Fatalf("Get(%s, %s) not found", tt.kind.Name, tt.name)
+		}
+		if art.Source != tt.wantRepo || art.Root != tt.wantRoot {
+			t.Errorf("Get(%s, %s) = %s (%s), want %s (%s)", tt.kind.Name, tt.name, art.Source, art.Root, tt.wantRepo, tt.wantRoot)
+		}
+		if !strings.HasPrefix(art.AbsPath, tt.wantRoot+string(filepath.Separator)) {
+			t.Errorf("AbsPath %q not under %q", art.AbsPath, tt.wantRoot)
+		}
+	}
+}
+
+func TestLayeredResolver_ListUnionsLayers(t *testing.T) {
+	r, _, _ := layeredFixture(t)
+
+	var names []string
+	for _, art := range r.List(KindAgent) {
+		names = append(names, art.Name)
+	}
+	want := []string{"nais", "shared", "team-only"}
+	if !reflect.DeepEqual(names, want) {
+		t.Errorf("List(agents) = %v, want %v", names, want)
+	}
+}
+
+func TestLayeredResolver_Shadowed(t *testing.T) {
+	r, _, _ := layeredFixture(t)
+
+	shadowed := r.Shadowed(KindAgent, "shared")
+	if len(shadowed) != 1 || shadowed[0].Repo != "navikt/copilot" {
+		t.Errorf("Shadowed(shared) = %v, want [navikt/copilot]", shadowed)
+	}
+	if got := r.Shadowed(KindAgent, "nais"); len(got) != 0 {
+		t.Errorf("Shadowed(nais) = %v, want none", got)
+	}
+}
+
+func TestLayeredResolver_MapLocalPathLayer(t *testing.T) {
+	r, base, team := layeredFixture(t)
+
+	rel, layer := r.MapLocalPathLayer(".github/agents/shared.agent.md", false)
+	if rel != filepath.Join("agents", "shared.agent.md") || layer.Dir != team {
+		t.Errorf("shared → (%q, %q), want overlay", rel, layer.Dir)
+	}
+	rel, layer = r.MapLocalPathLayer(".github/agents/nais.agent.md", false)
+	if rel != filepath.Join("agents", "nais.agent.md") || layer.Dir != base {
+		t.Errorf("nais → (%q, %q), want base", rel, layer.Dir)
+	}
+	if _, layer = r.MapLocalPathLayer("README.md", false); layer.Dir != base {
+		t.Errorf("unmapped path layer = %q, want base", layer.Dir)
+	}
+}
+
+func TestLayeredResolver_Collections(t *testing.T) {
+	base := t.TempDir()
+	team := t.TempDir()
+	writeManifest := func(dir, name, desc string) {
+		t.Helper()
+		path := filepath.Join(dir, "collections", name, "manifest.json")
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		data := `{"name":"` + name + `","description":"` + desc + `","agents":["nais"]}`
+		if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	writeManifest(base, "kotlin-backend", "base")
+	writeManifest(base, "frontend", "base")
+	writeManifest(team, "kotlin-backend", "team")
+	writeManifest(team, "team-stack", "team")
+
+	r := NewLayeredSourceResolver([]Layer{{Repo: "navikt/copilot", Dir: base}, {Repo: "team/agents", Dir: team}})
+
+	names, err := r.Collections()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"frontend", "kotlin-backend", "team-stack"}; !reflect.DeepEqual(names, want) {
+		t.Errorf("Collections() = %v, want %v", names, want)
+	}
+
+	m, err := r.LoadManifest("kotlin-backend")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m.Description != "team" {
+		t.Errorf("LoadManifest(kotlin-backend) description = %q, want overlay's", m.Description)
+	}
+	m, err = r.LoadManifest("frontend")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m.Description != "base" {
+		t.Errorf("LoadManifest(frontend) description = %q, want base's", m.Description)
+	}
+}
+
+func TestLayeredResolver_ExtendsAcrossLayers(t *testing.T) {
+	base := t.TempDir()
+	team := t.TempDir()
+	writeCollections(t, base, map[string]string{
+		"base":   `{"name":"base","agents":["nav-pilot"],"skills":["kafka"]}`,
+		"kotlin": `{"name":"kotlin","extends":["base"],"instructions":["kotlin"]}`,
+	})
+	// The overlay's base replaces the base layer's for every collection
+	// that extends it.
+	writeCollections(t, team, map[string]string{
+		"base":      `{"name":"base","agents":["nav-pilot","team-agent"]}`,
+		"team-only": `{"name":"team-only","extends":["kotlin"],"exclude":{"agents":["nav-pilot"]}}`,
+	})
+	r := NewLayeredSourceResolver([]Layer{{Repo: "navikt/copilot", Dir: base}, {Repo: "team/agents", Dir: team}})
+
+	m, err := r.LoadManifest("team-only")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := strings.Join(m.Agents, "|") + " " + strings.Join(m.Skills, "|") + " " + strings.Join(m.Instructions, "|"); got != "team-agent  kotlin" {
+		t.Errorf("team-only = %q", got)
+	}
+	if from := m.Origin("agent", "team-agent"); from != "base" {
+		t.Errorf("Origin(team-agent) = %q, want base", from)
+	}
+}
+
+func TestResolveSource_Layered(t *testing.T) {
+	origClone := CloneRemoteFn
+	t.Cleanup(func() { CloneRemoteFn = origClone })
+
+	dirs := map[string]string{"navikt/copilot": t.TempDir(), "team/agents": t.TempDir()}
+	var calls []string
+	CloneRemoteFn = func(ref, sourceRepo string) (*Source, error) {
+		calls = append(calls, sourceRepo+"@"+ref)
+		return &Source{Dir: dirs[sourceRepo], SHA: "sha-" + sourceRepo, Repo: sourceRepo}, nil
+	}
+
+	src, err := ResolveSource("main", "navikt/copilot,team/agents@v2", "dev")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer src.Cleanup()
+
+	if want := []string{"navikt/copilot@main", "team/agents@v2"}; !reflect.DeepEqual(calls, want) {
+		t.Errorf("clone calls = %v, want %v", calls, want)
+	}
+	if src.Repo != "navikt/copilot,team/agents@v2" {
+		t.Errorf("Repo = %q, want full layer list", src.Repo)
+	}
+	layers := src.Layers()
+	if len(layers) != 2 {
+		t.Fatalf("Layers() = %d, want 2", len(layers))
+	}
+	if layers[0].Repo != "navikt/copilot" || layers[0].SHA != "sha-navikt/copilot" {
+		t.Errorf("base layer = %+v", layers[0])
+	}
+	if layers[1].Repo != "team/agents" || layers[1].Dir != dirs["team/agents"] {
+		t.Errorf("overlay layer = %+v", layers[1])
+	}
+	if !src.Resolver().Layered() {
+		t.Error("Resolver() should be layered")
+	}
+}
@@ -0,0 +1,454 @@
+package source
+
+import (
+	"bytes"
+	"fmt"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Lint severities, named after SARIF result levels.
+const (
+	LintError   = "error"
+	LintWarning = "warning"
+)
+
+// LintRule describes one check; IDs are stable so SARIF consumers can
+// suppress or track them.
+type LintRule struct {
+	ID          string
+	Severity    string // default severity
+	Description string
+}
+
+// LintRules lists every rule Lint can report, in documentation order.
+var LintRules = []LintRule{
+	{"frontmatter/missing", LintError, "Artifact has no YAML frontmatter block"},
+	{"frontmatter/yaml", LintError, "Frontmatter is not valid YAML"},
+	{"frontmatter/required", LintError, "A required frontmatter key is missing or empty"},
+	{"frontmatter/type", LintError, "A frontmatter value has the wrong type"},
+	{"frontmatter/glob", LintError, "applyTo contains an invalid glob"},
+	{"skill/marker", LintError, "Skill directory has no SKILL.md"},
+	{"skill/name", LintError, "Skill name does not match its directory or the naming rules"},
+	{"naming/suffix", LintWarning, "Markdown file in an artifact directory lacks the kind's suffix and is ignored"},
+	{"size/limit", LintError, "Artifact exceeds a size limit"},
+	{"links/broken", LintError, "Relative link points to a file that does not exist"},
+	{"collection/exclude", LintWarning, "Collection excludes an item none of its parents provide"},
+}
+
+// Size limits. Copilot truncates or rejects agent profiles above 30,000
+// characters; the SKILL.md line budget and description limits follow the
+// Agent Skills spec (and scripts/lint-skills.sh).
+const (
+	LintMaxFileBytes        = 100 << 10
+	LintMaxAgentChars       = 30000
+	LintMaxSkillLines       = 500
+	LintMaxSkillDescription = 1024
+	LintMaxSkillName        = 64
+)
+
+// LintFinding is one problem. Path is slash-separated and relative to the
+// linted root; Line is 1-based, or 0 when the finding is about the whole file.
+type LintFinding struct {
+	Rule     string `json:"rule"`
+	Severity string `json:"severity"`
+	Path     string `json:"path"`
+	Line     int    `json:"line,omitempty"`
+	Message  string `json:"message"`
+}
+
+// LintResult is the outcome of linting a tree.
+type LintResult struct {
+	Files    int           `json:"files"`
+	Findings []LintFinding `json:"findings"`
+}
+
+// Counts returns the number of error and warning findings.
+func (r *LintResult) Counts() (errors, warnings int) {
+	for _, f := range r.Findings {
+		if f.Severity == LintError {
+			errors++
+		} else {
+			warnings++
+		}
+	}
+	return errors, warnings
+}
+
+var skillNameRe = regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)*$`)
+
+// Lint validates every artifact under root against its kind's schema. It
+// looks in both the source-repo layout (<root>/agents) and the installed
+// layout (<root>/.github/agents), so it works for the source repo, for
+// consuming repos and for ~/.copilot.
+func Lint(root string) (*LintResult, error) {
+	l := &linter{root: root, result: &LintResult{Findings: []LintFinding{}}}
+	for _, base := range []string{root, filepath.Join(root, ".github")} {
+		for _, kind := range AllKinds {
+			if err := l.lintKindDir(base, kind); err != nil {
+				return nil, err
+			}
+		}
+	}
+	l.lintCollections()
+	sort.SliceStable(l.result.Findings, func(i, j int) bool {
+		a, b := l.result.Findings[i], l.result.Findings[j]
+		if a.Path != b.Path {
+			return a.Path < b.Path
+		}
+		return a.Line < b.Line
+	})
+	return l.result, nil
+}
+
+type linter struct {
+	root   string
+	result *LintResult
+}
+
+func (l *linter) add(rule, severity, absPath string, line int, format string, args ...interface{}) {
+	rel, err := filepath.Rel(l.root, absPath)
+	if err != nil {
+		rel = absPath
+	}
+	l.result.Findings = append(l.result.Findings, LintFinding{
+		Rule: rule, Severity: severity, Path: filepath.ToSlash(rel), Line: line,
+		Message: fmt.Sprintf(format, args...),
+	})
+}
+
+// lintCollections warns about stale excludes in the source layout's
+// collections. Manifests that fail to load are left to install and list,
+// which report them.
+func (l *linter) lintCollections() {
+	names, err := ListCollectionDirs(l.root)
+	if err != nil {
+		return
+	}
+	for _, name := range names {
+		m, err := LoadManifest(l.root, name)
+		if err != nil {
+			continue
+		}
+		p := filepath.Join(l.root, "collections", name, "manifest.json")
+		for _, key := range m.StaleExcludes {
+			kind, item, _ := strings.Cut(key, ":")
+			l.add("collection/exclude", LintWarning, p, 0, "excluded %s %q is not inherited from %s; remove it from exclude", kind, item, strings.Join(m.Extends, ", "))
+		}
+	}
+}
+
+func (l *linter) lintKindDir(base string, kind *ArtifactKind) error {
+	dir := filepath.Join(base, kind.Dir)
+	entries, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	for _, e := range entries {
+		p := filepath.Join(dir, e.Name())
+		switch {
+		case e.IsDir() && kind.IsDir:
+			marker := filepath.Join(p, kind.Marker)
+			if _, err := os.Stat(marker); err != nil {
+				l.add("skill/marker", LintError, p, 0, "%s directory %q has no %s and will not be loaded", kind.Name, e.Name(), kind.Marker)
+				continue
+			}
+			l.lintDir(p, kind, e.Name(), marker)
+		case e.IsDir() && kind.CanBeDir:
+			l.lintDir(p, kind, e.Name(), "")
+		case e.IsDir() || e.Type()&os.ModeSymlink != 0:
+		case kind.Suffix != "" && strings.HasSuffix(e.Name(), kind.Suffix):
+			l.lintArtifact(p, kind, strings.TrimSuffix(e.Name(), kind.Suffix))
+		case kind.Suffix != "" && strings.HasSuffix(e.Name(), ".md") && !strings.EqualFold(e.Name(), "README.md"):
+			l.add("naming/suffix", LintWarning, p, 0, "%s is not named *%s and will be ignored", e.Name(), kind.Suffix)
+		}
+	}
+	return nil
+}
+
+// lintDir lints a directory artifact: the marker file (SKILL.md), or every
+// *.prompt.md in a prompt directory. Supporting files such as references/
+// are not loaded by Copilot on their own and are often templates, so only
+// their existence is checked (through links from the artifact).
+func (l *linter) lintDir(dir string, kind *ArtifactKind, name, marker string) {
+	if marker != "" {
+		l.lintArtifact(marker, kind, name)
+		return
+	}
+	filepath.WalkDir(dir, func(p string, d os.DirEntry, err error) error {
+		if err == nil && !d.IsDir() && strings.HasSuffix(p, kind.Suffix) {
+			l.lintArtifact(p, kind, name)
+		}
+		return nil
+	})
+}
+
+func (l *linter) lintArtifact(p string, kind *ArtifactKind, name string) {
+	data, err := os.ReadFile(p)
+	if err != nil {
+		l.add("frontmatter/missing", LintError, p, 0, "cannot read file: %v", err)
+		return
+	}
+	l.result.Files++
+
+	if len(data) > LintMaxFileBytes {
+		l.add("size/limit", LintError, p, 0, "file is %d KiB; the limit is %d KiB", len(data)>>10, LintMaxFileBytes>>10)
+	}
+	if kind == KindAgent {
+		if n := utf8.RuneCount(data); n > LintMaxAgentChars {
+			l.add("size/limit", LintError, p, 0, "agent profile is %d characters; Copilot allows %d", n, LintMaxAgentChars)
+		}
+	}
+	if kind == KindSkill {
+		if n := bytes.Count(data, []byte("\n")); n > LintMaxSkillLines {
+			l.add("size/limit", LintWarning, p, 0, "SKILL.md is %d lines; keep it under %d and move detail into referenced files", n, LintMaxSkillLines)
+		}
+	}
+
+	l.lintFrontmatter(p, data, kind, name)
+	l.lintLinks(p, data)
+}
+
+func (l *linter) lintFrontmatter(p string, data []byte, kind *ArtifactKind, name string) {
+	fm, _, ok := SplitFrontmatter(data)
+	if !ok {
+		l.add("frontmatter/missing", LintError, p, 1, "%s has no frontmatter block (---)", kind.Name)
+		return
+	}
+	// YAML line numbers are relative to the line after the opening "---".
+	normalized := bytes.ReplaceAll(data, []byte("\r\n"), []byte("\n"))
+	offset := bytes.Count(normalized[:len(normalized)-len(bytes.TrimLeft(normalized, " \t\r\n"))], []byte("\n")) + 1
+
+	doc, err := parseYAML(fm)
+	if err != nil {
+		if yerr, ok := err.(*YAMLError); ok {
+			l.add("frontmatter/yaml", LintError, p, offset+yerr.Line, "invalid frontmatter: %s", yerr.Msg)
+		} else {
+			l.add("frontmatter/yaml", LintError, p, offset+1, "invalid frontmatter: %v", err)
+		}
+		return
+	}
+	if yamlIsNull(doc) {
+		doc = &yaml.Node{Kind: yaml.MappingNode}
+	}
+	if doc.Kind != yaml.MappingNode {
+		l.add("frontmatter/type", LintError, p, offset+doc.Line, "frontmatter must be a mapping of keys, got a %s", yamlKindName(doc))
+		return
+	}
+	line := func(n *yaml.Node) int { return offset + n.Line }
+
+	str := func(key string, required bool) (string, bool) {
+		n := yamlGet(doc, key)
+		if yamlIsNull(n) || (n.Kind == yaml.ScalarNode && strings.TrimSpace(n.Value) == "") {
+			if required {
+				l.add("frontmatter/required", LintError, p, offset, "%s requires a non-empty %q", kind.Name, key)
+			}
+			return "", false
+		}
+		if n.Kind != yaml.ScalarNode {
+			l.add("frontmatter/type", LintError, p, line(n), "%q must be a string, got a %s", key, yamlKindName(n))
+			return "", false
+		}
+		return n.Value, true
+	}
+
+	switch kind {
+	case KindAgent, KindPrompt:
+		str("description", true)
+		str("name", false)
+		str("model", false)
+		l.lintTools(p, yamlGet(doc, "tools"), line)
+	case KindSkill:
+		if desc, ok := str("description", true); ok && utf8.RuneCountInString(desc) > LintMaxSkillDescription {
+			l.add("size/limit", LintError, p, line(yamlGet(doc, "description")), "description is %d characters; the limit is %d", utf8.RuneCountInString(desc), LintMaxSkillDescription)
+		}
+		if n, ok := str("name", true); ok {
+			switch {
+			case n != name:
+				l.add("skill/name", LintError, p, line(yamlGet(doc, "name")), "name %q must match the directory name %q", n, name)
+			case len(n) > LintMaxSkillName || !skillNameRe.MatchString(n):
+				l.add("skill/name", LintError, p, line(yamlGet(doc, "name")), "name %q must be lowercase letters, digits and single hyphens, at most %d characters", n, LintMaxSkillName)
+			}
+		}
+	case KindInstruction:
+		str("description", false)
+		l.lintApplyTo(p, yamlGet(doc, "applyTo"), offset, line)
+	}
+}
+
+func (l *linter) lintTools(p string, n *yaml.Node, line func(*yaml.Node) int) {
+	if yamlIsNull(n) {
+		return
+	}
+	if n.Kind != yaml.SequenceNode {
+		l.add("frontmatter/type", LintError, p, line(n), "\"tools\" must be a list, e.g. [read, edit]")
+		return
+	}
+	for _, item := range n.Content {
+		if item = yamlResolve(item); item.Kind != yaml.ScalarNode || yamlIsNull(item) || strings.TrimSpace(item.Value) == "" {
+			l.add("frontmatter/type", LintError, p, line(item), "\"tools\" entries must be non-empty strings")
+		}
+	}
+}
+
+func (l *linter) lintApplyTo(p string, n *yaml.Node, offset int, line func(*yaml.Node) int) {
+	if yamlIsNull(n) {
+		l.add("frontmatter/required", LintError, p, offset, "instruction requires \"applyTo\" or it is never applied")
+		return
+	}
+	var globs []*yaml.Node
+	switch n.Kind {
+	case yaml.ScalarNode:
+		// Copilot accepts a comma-separated list in one string.
+		for _, g := range splitGlobList(n.Value) {
+			globs = append(globs, &yaml.Node{Kind: yaml.ScalarNode, Value: g, Line: n.Line})
+		}
+		if len(globs) == 0 {
+			globs = append(globs, n)
+		}
+	case yaml.SequenceNode:
+		for _, g := range n.Content {
+			globs = append(globs, yamlResolve(g))
+		}
+	default:
+		l.add("frontmatter/type", LintError, p, line(n), "\"applyTo\" must be a glob string or a list of globs")
+		return
+	}
+	for _, g := range globs {
+		if g.Kind != yaml.ScalarNode {
+			l.add("frontmatter/type", LintError, p, line(g), "\"applyTo\" entries must be strings")
+			continue
+		}
+		if err := ValidateGlob(g.Value); err != nil {
+			l.add("frontmatter/glob", LintError, p, line(g), "invalid applyTo glob %q: %v", g.Value, err)
+		}
+	}
+}
+
+// splitGlobList splits a comma-separated applyTo value into trimmed globs.
+// Commas inside {} alternatives do not split; a stray } is left for
+// ValidateGlob to report. Empty entries are kept so lint can flag them.
+func splitGlobList(s string) []string {
+	var out []string
+	depth, start := 0, 0
+	for i, c := range s {
+		switch c {
+		case '{':
+			depth++
+		case '}':
+			if depth > 0 {
+				depth--
+			}
+		case ',':
+			if depth == 0 {
+				out = append(out, strings.TrimSpace(s[start:i]))
+				start = i + 1
+			}
+		}
+	}
+	return append(out, strings.TrimSpace(s[start:]))
+}
+
+// ValidateGlob checks an applyTo glob: non-empty, balanced {} and [], and
+// otherwise valid path.Match syntax with ** allowed as a path segment.
+func ValidateGlob(g string) error {
+	if strings.TrimSpace(g) == "" {
+		return fmt.Errorf("empty pattern")
+	}
+	depth := 0
+	for _, c := range g {
+		switch c {
+		case '{':
+			depth++
+		case '}':
+			depth--
+			if depth < 0 {
+				return fmt.Errorf("unbalanced '}'")
+			}
+		}
+	}
+	if depth != 0 {
+		return fmt.Errorf("unbalanced '{'")
+	}
+	flat := strings.NewReplacer("**", "*", "{", "", "}", "", ",", "").Replace(g)
+	if _, err := path.Match(flat, ""); err != nil {
+		return err
+	}
+	return nil
+}
+
+var (
+	inlineLinkRe = regexp.MustCompile(`!?\[[^\]]*\]\(\s*<?([^)\s>]+)>?(?:\s+["'(][^)]*)?\)`)
+	refLinkRe    = regexp.MustCompile(`^\s{0,3}\[[^\]]+\]:\s*<?([^\s>]+)>?`)
+	inlineCodeRe = regexp.MustCompile("`[^`]*`")
+)
+
+// lintLinks reports relative links and images whose target does not exist.
+// Links inside code fences and inline code are skipped, as are URLs,
+// anchors and absolute paths.
+func (l *linter) lintLinks(p string, data []byte) {
+	inFence := false
+	fence := ""
+	for i, line := range strings.Split(string(data), "\n") {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~") {
+			switch {
+			case !inFence:
+				inFence, fence = true, trimmed[:3]
+			case strings.HasPrefix(trimmed, fence):
+				inFence = false
+			}
+			continue
+		}
+		if inFence {
+			continue
+		}
+		line = inlineCodeRe.ReplaceAllString(line, "")
+		var targets []string
+		for _, m := range inlineLinkRe.FindAllStringSubmatch(line, -1) {
+			targets = append(targets, m[1])
+		}
+		if m := refLinkRe.FindStringSubmatch(line); m != nil {
+			targets = append(targets, m[1])
+		}
+		for _, t := range targets {
+			if target, ok := localLinkTarget(t); ok {
+				if _, err := os.Stat(filepath.Join(filepath.Dir(p), target)); err != nil {
+					l.add("links/broken", LintError, p, i+1, "link target %q does not exist", t)
+				}
+			}
+		}
+	}
+}
+
+// localLinkTarget returns the file path a link refers to, or false for
+// URLs, pure anchors, absolute paths and {placeholder} templates.
+func localLinkTarget(t string) (string, bool) {
+	if strings.HasPrefix(t, "#") || strings.HasPrefix(t, "/") || strings.Contains(t, "://") || strings.ContainsAny(t, "{}") {
+		return "", false
+	}
+	if i := strings.IndexByte(t, ':'); i > 0 && !strings.ContainsAny(t[:i], "/.") {
+		return "", false // mailto:, vscode:, etc.
+	}
+	if i := strings.IndexAny(t, "#?"); i >= 0 {
+		t = t[:i]
+	}
+	if t == "" {
+		return "", false
+	}
+	if u, err := url.PathUnescape(t); err == nil {
+		t = u
+	}
+	return filepath.FromSlash(t), true
+}
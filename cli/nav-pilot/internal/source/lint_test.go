@@ -0,0 +1,135 @@
+package source
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func writeLintFile(t *testing.T, root, rel, content string) {
+	t.Helper()
+	p := filepath.Join(root, filepath.FromSlash(rel))
+	if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(p, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestLint_CleanTree(t *testing.T) {
+	root := t.TempDir()
+	writeLintFile(t, root, ".github/agents/nais.agent.md", "---\nname: nais\ndescription: Nais expert\ntools:\n  - read\n  - edit\n---\n# Nais\n\nSee [api](../skills/api-design/SKILL.md) and [docs](https://docs.nais.io).\n")
+	writeLintFile(t, root, ".github/skills/api-design/SKILL.md", "---\nname: api-design\ndescription: Design REST APIs\n---\n# API\n\n[ref](references/guide.md#intro)\n")
+	writeLintFile(t, root, ".github/skills/api-design/references/guide.md", "[placeholder]({slack-url}) [missing](nope.md)\n")
+	writeLintFile(t, root, ".github/instructions/kotlin.instructions.md", "---\napplyTo: \"**/*.kt,**/*.{kts,gradle}\"\n---\nUse Kotlin.\n")
+	writeLintFile(t, root, ".github/prompts/plan.prompt.md", "---\ndescription: Plan a feature\n---\nPlan it.\n")
+	writeLintFile(t, root, ".github/agents/README.md", "# Agents\n")
+
+	res, err := Lint(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res.Findings) != 0 {
+		t.Errorf("findings = %+v", res.Findings)
+	}
+	if res.Files != 4 {
+		t.Errorf("files = %d, want 4", res.Files)
+	}
+}
+
+func TestLint_Findings(t *testing.T) {
+	root := t.TempDir()
+	writeLintFile(t, root, "agents/no-desc.agent.md", "---\nname: no-desc\ntools: read\n---\n# Agent\n")
+	writeLintFile(t, root, "agents/bad-yaml.agent.md", "\n---\nname: x\ndescription: Expert: Aksel\n---\n")
+	writeLintFile(t, root, "agents/no-fm.agent.md", "# No frontmatter\n")
+	writeLintFile(t, root, "agents/big.agent.md", "---\ndescription: big\n---\n"+strings.Repeat("x", LintMaxAgentChars))
+	writeLintFile(t, root, "agents/helper.md", "---\ndescription: ignored\n---\n")
+	writeLintFile(t, root, "skills/wrong/SKILL.md", "---\nname: other\ndescription: d\n---\n```\n[in fence](missing.md)\n```\n`[inline](missing.md)`\n[broken](missing.md)\n\n[ref]: ./gone.md\n")
+	writeLintFile(t, root, "skills/empty/notes.md", "no marker\n")
+	writeLintFile(t, root, "instructions/none.instructions.md", "---\ndescription: no applyTo\n---\n")
+	writeLintFile(t, root, "instructions/badglob.instructions.md", "---\napplyTo:\n  - \"**/*.{ts,tsx\"\n  - \"[a-\"\n  - src/**/*.go\n---\n")
+	writeLintFile(t, root, "prompts/p/main.prompt.md", "---\ndescription: [a, b]\n---\n")
+	writeLintFile(t, root, "collections/base/manifest.json", `{"name":"base","agents":["no-desc"]}`)
+	writeLintFile(t, root, "collections/team/manifest.json", `{"name":"team","extends":["base"],"exclude":{"agents":["gone"]}}`)
+
+	res, err := Lint(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := make(map[string]bool)
+	for _, f := range res.Findings {
+		got[f.Path+":"+strconv.Itoa(f.Line)+" "+f.Rule] = true
+	}
+	for _, want := range []string{
+		"agents/bad-yaml.agent.md:4 frontmatter/yaml",
+		"agents/big.agent.md:0 size/limit",
+		"agents/helper.md:0 naming/suffix",
+		"agents/no-desc.agent.md:1 frontmatter/required",
+		"agents/no-desc.agent.md:3 frontmatter/type",
+		"agents/no-fm.agent.md:1 frontmatter/missing",
+		"collections/team/manifest.json:0 collection/exclude",
+		"instructions/badglob.instructions.md:3 frontmatter/glob",
+		"instructions/badglob.instructions.md:4 frontmatter/glob",
+		"instructions/none.instructions.md:1 frontmatter/required",
+		"prompts/p/main.prompt.md:2 frontmatter/type",
+		"skills/empty:0 skill/marker",
+		"skills/wrong/SKILL.md:2 skill/name",
+		"skills/wrong/SKILL.md:9 links/broken",
+		"skills/wrong/SKILL.md:11 links/broken",
+	} {
+		if !got[want] {
+			t.Errorf("missing finding %q", want)
+		}
+	}
+	if len(res.Findings) != 15 {
+		t.Errorf("got %d findings, want 15: %+v", len(res.Findings), res.Findings)
+	}
+	if errs, warns := res.Counts(); errs != 13 || warns != 2 {
+		t.Errorf("counts = %d errors, %d warnings", errs, warns)
+	}
+}
+
+func TestLint_FrontmatterYAML(t *testing.T) {
+	root := t.TempDir()
+	// Anchors, aliases, tags and folded scalars are valid YAML and read as such.
+	writeLintFile(t, root, "agents/anchors.agent.md", "---\ndescription: &d !!str >-\n  Nais\n  expert\nname: *d\ntools: [read, edit]\n---\n")
+	writeLintFile(t, root, "agents/dup.agent.md", "---\ndescription: a\ndescription: b\n---\n")
+	writeLintFile(t, root, "agents/null.agent.md", "---\ndescription: ~\n---\n")
+
+	res, err := Lint(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := make(map[string]bool)
+	for _, f := range res.Findings {
+		got[f.Path+":"+strconv.Itoa(f.Line)+" "+f.Rule] = true
+	}
+	want := []string{
+		"agents/dup.agent.md:3 frontmatter/yaml",
+		"agents/null.agent.md:1 frontmatter/required",
+	}
+	for _, w := range want {
+		if !got[w] {
+			t.Errorf("missing finding %q", w)
+		}
+	}
+	if len(res.Findings) != len(want) {
+		t.Errorf("findings = %+v", res.Findings)
+	}
+}
+
+func TestValidateGlob(t *testing.T) {
+	for _, g := range []string{"**/*.kt", "src/**/{a,b}/*.ts", "*.[ch]", "**"} {
+		if err := ValidateGlob(g); err != nil {
+			t.Errorf("ValidateGlob(%q) = %v", g, err)
+		}
+	}
+	for _, g := range []string{"", "{a,b", "a}", "[a-", "x\\"} {
+		if err := ValidateGlob(g); err == nil {
+			t.Errorf("ValidateGlob(%q) = nil, want error", g)
+		}
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
 	"sort"
 	"strings"
 )
@@ -18,6 +19,69 @@ type Manifest struct {
 	Skills       []string `json:"skills"`
 	Instructions []string `json:"instructions"`
 	Prompts      []string `json:"prompts"`
+
+	// Extends lists collections whose items this one inherits, in order.
+	// Exclude drops inherited items. LoadManifest resolves both, so the
+	// lists above hold the full contents once loaded.
+	Extends []string         `json:"extends,omitempty"`
+	Exclude *ManifestExclude `json:"exclude,omitempty"`
+
+	// Origins maps "kind:name" to the collection that contributed the item,
+	// for items inherited through Extends. Items the manifest lists itself
+	// have no entry.
+	Origins map[string]string `json:"-"`
+
+	// StaleExcludes lists, as "kind:name", exclude entries that no parent
+	// provides any more. Loading ignores them; lint warns about them.
+	StaleExcludes []string `json:"-"`
+}
+
+// ManifestExclude lists inherited items a collection leaves out.
+type ManifestExclude struct {
+	Agents       []string `json:"agents,omitempty"`
+	Skills       []string `json:"skills,omitempty"`
+	Instructions []string `json:"instructions,omitempty"`
+	Prompts      []string `json:"prompts,omitempty"`
+}
+
+// manifestList is one kind's item list in a manifest.
+type manifestList struct {
+	kind  string
+	names *[]string
+}
+
+func (m *Manifest) lists() []manifestList {
+	return []manifestList{
+		{"agent", &m.Agents},
+		{"skill", &m.Skills},
+		{"instruction", &m.Instructions},
+		{"prompt", &m.Prompts},
+	}
+}
+
+func (e *ManifestExclude) lists() []manifestList {
+	return []manifestList{
+		{"agent", &e.Agents},
+		{"skill", &e.Skills},
+		{"instruction", &e.Instructions},
+		{"prompt", &e.Prompts},
+	}
+}
+
+// Items returns the manifest's list for kind.
+func (m *Manifest) Items(kind *ArtifactKind) []string {
+	for _, list := range m.lists() {
+		if list.kind == kind.Name {
+			return *list.names
+		}
+	}
+	return nil
+}
+
+// Origin returns the collection an item was inherited from, or "" for an
+// item the manifest lists itself.
+func (m *Manifest) Origin(kind, name string) string {
+	return m.Origins[kind+":"+name]
 }
 
 // CollectionAll is the collection name used in state files for "install everything".
@@ -29,16 +93,8 @@ func ValidateManifest(m *Manifest) error {
 		return fmt.Errorf("manifest has empty name")
 	}
 	seen := make(map[string]bool)
-	for _, list := range []struct {
-		kind  string
-		names []string
-	}{
-		{"agent", m.Agents},
-		{"skill", m.Skills},
-		{"instruction", m.Instructions},
-		{"prompt", m.Prompts},
-	} {
-		for _, name := range list.names {
+	for _, list := range m.lists() {
+		for _, name := range *list.names {
 			if err := ValidateName(name); err != nil {
 				return fmt.Errorf("invalid %s in manifest: %w", list.kind, err)
 			}
@@ -49,11 +105,55 @@ func ValidateManifest(m *Manifest) error {
 			seen[key] = true
 		}
 	}
+	extends := make(map[string]bool)
+	for _, parent := range m.Extends {
+		if err := ValidateName(parent); err != nil {
+			return fmt.Errorf("invalid extends in manifest: %w", err)
+		}
+		if parent == m.Name {
+			return fmt.Errorf("manifest extends itself")
+		}
+		if extends[parent] {
+			return fmt.Errorf("duplicate extends in manifest: %q", parent)
+		}
+		extends[parent] = true
+	}
+	if m.Exclude == nil {
+		return nil
+	}
+	if len(m.Extends) == 0 {
+		return fmt.Errorf("exclude requires extends")
+	}
+	excluded := make(map[string]bool)
+	for _, list := range m.Exclude.lists() {
+		for _, name := range *list.names {
+			if err := ValidateName(name); err != nil {
+				return fmt.Errorf("invalid %s in exclude: %w", list.kind, err)
+			}
+			key := list.kind + ":" + name
+			if seen[key] {
+				return fmt.Errorf("%s %q is both listed and excluded", list.kind, name)
+			}
+			if excluded[key] {
+				return fmt.Errorf("duplicate %s in exclude: %q", list.kind, name)
+			}
+			excluded[key] = true
+		}
+	}
 	return nil
 }
 
-// LoadManifest loads and validates a collection manifest from the source directory.
+// LoadManifest loads and validates a collection manifest from the source
+// directory, resolving the collections it extends.
 func LoadManifest(sourceDir, collection string) (*Manifest, error) {
+	return resolveManifest(collection, func(name string) (*Manifest, error) {
+		return readManifest(sourceDir, name)
+	})
+}
+
+// readManifest loads and validates one manifest.json as written, without
+// resolving extends.
+func readManifest(sourceDir, collection string) (*Manifest, error) {
 	path := filepath.Join(sourceDir, "collections", collection, "manifest.json")
 	data, err := os.ReadFile(path)
 	if err != nil {
@@ -69,6 +169,99 @@ func LoadManifest(sourceDir, collection string) (*Manifest, error) {
 	return &m, nil
 }
 
+// resolveManifest loads collection with read and merges in the collections
+// it extends, depth first. Parents contribute items in Extends order, and an
+// item keeps the origin of the first collection that provides it; the
+// collection's own items come last. Exclude then removes inherited items; an
+// exclude that matches nothing is recorded in StaleExcludes, not an error, so
+// a parent dropping an item does not break its children.
+func resolveManifest(collection string, read func(string) (*Manifest, error)) (*Manifest, error) {
+	resolved := map[string]*Manifest{}
+	var resolve func(name string, chain []string) (*Manifest, error)
+	resolve = func(name string, chain []string) (*Manifest, error) {
+		for i, c := range chain {
+			if c == name {
+				return nil, fmt.Errorf("collection %q: extends cycle: %s", chain[0], strings.Join(append(chain[i:], name), " → "))
+			}
+		}
+		if m, ok := resolved[name]; ok {
+			return m, nil
+		}
+		m, err := read(name)
+		if err != nil {
+			return nil, err
+		}
+		if len(m.Extends) == 0 {
+			resolved[name] = m
+			return m, nil
+		}
+		chain = append(chain, name)
+		parents := make([]*Manifest, 0, len(m.Extends))
+		for _, p := range m.Extends {
+			parent, err := resolve(p, chain)
+			if err != nil {
+				return nil, err
+			}
+			parents = append(parents, parent)
+		}
+		merged, err := mergeManifest(m, parents)
+		if err != nil {
+			return nil, fmt.Errorf("collection %q: %w", name, err)
+		}
+		resolved[name] = merged
+		return merged, nil
+	}
+	return resolve(collection, nil)
+}
+
+// mergeManifest returns m with the items of its resolved parents, which are
+// in m.Extends order.
+func mergeManifest(m *Manifest, parents []*Manifest) (*Manifest, error) {
+	out := *m
+	out.Origins = map[string]string{}
+	for i, list := range out.lists() {
+		var names []string
+		seen := map[string]bool{}
+		for j, parent := range parents {
+			for _, name := range *parent.lists()[i].names {
+				if seen[name] {
+					continue
+				}
+				seen[name] = true
+				names = append(names, name)
+				from := parent.Origin(list.kind, name)
+				if from == "" {
+					from = m.Extends[j]
+				}
+				out.Origins[list.kind+":"+name] = from
+			}
+		}
+		for _, name := range *m.lists()[i].names {
+			if !seen[name] {
+				seen[name] = true
+				names = append(names, name)
+			}
+			// Listing an inherited item makes it the collection's own.
+			delete(out.Origins, list.kind+":"+name)
+		}
+		if m.Exclude != nil {
+			for _, name := range *m.Exclude.lists()[i].names {
+				if !seen[name] {
+					out.StaleExcludes = append(out.StaleExcludes, list.kind+":"+name)
+					continue
+				}
+				names = slices.DeleteFunc(names, func(n string) bool { return n == name })
+				delete(out.Origins, list.kind+":"+name)
+			}
+		}
+		*list.names = names
+	}
+	if err := ValidateManifest(&out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
 // ListCollectionDirs returns the names of all collections in the source directory.
 func ListCollectionDirs(sourceDir string) ([]string, error) {
 	collectionsDir := filepath.Join(sourceDir, "collections")
@@ -92,21 +285,7 @@ func ListCollectionDirs(sourceDir string) ([]string, error) {
 // CollectAllItems scans the source directory for all agents, skills, and instructions,
 // returning a synthetic manifest. Used for user-scope "install everything".
 func CollectAllItems(sourceDir string) (*Manifest, error) {
-	resolver := NewSourceResolver(sourceDir)
-	m := &Manifest{
-		Name:        "(all)",
-		Description: "All agents, skills, and instructions",
-	}
-	for _, a := range resolver.List(KindAgent) {
-		m.Agents = append(m.Agents, a.Name)
-	}
-	for _, s := range resolver.List(KindSkill) {
-		m.Skills = append(m.Skills, s.Name)
-	}
-	for _, i := range resolver.List(KindInstruction) {
-		m.Instructions = append(m.Instructions, i.Name)
-	}
-	return m, nil
+	return NewSourceResolver(sourceDir).CollectAll(), nil
 }
 
 // ValidateName checks that a manifest entry name is safe for use in file paths.
@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/navikt/copilot/cli/nav-pilot/internal/domain"
@@ -99,6 +100,110 @@ func TestLoadManifest_NotFound(t *testing.T) {
 	}
 }
 
+func writeCollections(t *testing.T, dir string, manifests map[string]string) {
+	t.Helper()
+	for name, data := range manifests {
+		path := filepath.Join(dir, "collections", name, "manifest.json")
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestLoadManifest_Extends(t *testing.T) {
+	tmp := t.TempDir()
+	writeCollections(t, tmp, map[string]string{
+		"base":   `{"name":"base","agents":["nav-pilot","forfatter"],"instructions":["testing"]}`,
+		"kotlin": `{"name":"kotlin","extends":["base"],"skills":["kafka"],"instructions":["kotlin"]}`,
+		"team": `{"name":"team","extends":["kotlin","base"],"agents":["team-agent","forfatter"],
+			"exclude":{"skills":["kafka"],"instructions":["testing"]}}`,
+	})
+
+	m, err := LoadManifest(tmp, "team")
+	if err != nil {
+		t.Fatalf("LoadManifest = %v", err)
+	}
+	if got := strings.Join(m.Agents, "|"); got != "nav-pilot|forfatter|team-agent" {
+		t.Errorf("agents = %s", got)
+	}
+	if len(m.Skills) != 0 {
+		t.Errorf("skills = %v, want kafka excluded", m.Skills)
+	}
+	if got := strings.Join(m.Instructions, "|"); got != "kotlin" {
+		t.Errorf("instructions = %s", got)
+	}
+
+	origins := []struct{ kind, name, want string }{
+		{"agent", "nav-pilot", "base"},
+		{"agent", "forfatter", ""}, // inherited, but listed by team too
+		{"agent", "team-agent", ""},
+		{"instruction", "kotlin", "kotlin"},
+	}
+	for _, o := range origins {
+		if got := m.Origin(o.kind, o.name); got != o.want {
+			t.Errorf("Origin(%s, %s) = %q, want %q", o.kind, o.name, got, o.want)
+		}
+	}
+}
+
+func TestLoadManifest_StaleExclude(t *testing.T) {
+	tmp := t.TempDir()
+	writeCollections(t, tmp, map[string]string{
+		"base": `{"name":"base","agents":["nav-pilot"],"skills":["kafka"]}`,
+		"team": `{"name":"team","extends":["base"],"exclude":{"agents":["forfatter"],"skills":["kafka"]}}`,
+	})
+
+	// base no longer provides forfatter: team still loads.
+	m, err := LoadManifest(tmp, "team")
+	if err != nil {
+		t.Fatalf("LoadManifest = %v", err)
+	}
+	if got := strings.Join(m.Agents, "|"); got != "nav-pilot" {
+		t.Errorf("agents = %s", got)
+	}
+	if len(m.Skills) != 0 {
+		t.Errorf("skills = %v, want kafka excluded", m.Skills)
+	}
+	if got := strings.Join(m.StaleExcludes, "|"); got != "agent:forfatter" {
+		t.Errorf("StaleExcludes = %s", got)
+	}
+}
+
+func TestLoadManifest_ExtendsErrors(t *testing.T) {
+	tmp := t.TempDir()
+	writeCollections(t, tmp, map[string]string{
+		"base":       `{"name":"base","agents":["nav-pilot"]}`,
+		"a":          `{"name":"a","extends":["b"]}`,
+		"b":          `{"name":"b","extends":["base","a"]}`,
+		"missing":    `{"name":"missing","extends":["nope"]}`,
+		"no-extends": `{"name":"no-extends","exclude":{"agents":["nav-pilot"]}}`,
+		"self":       `{"name":"self","extends":["self"]}`,
+		"listed":     `{"name":"listed","extends":["base"],"agents":["x"],"exclude":{"agents":["x"]}}`,
+	})
+
+	tests := []struct {
+		collection string
+		wantErr    string
+	}{
+		{"a", "extends cycle: a → b → a"},
+		{"missing", `collection "nope" not found`},
+		{"no-extends", "exclude requires extends"},
+		{"self", "extends itself"},
+		{"listed", "both listed and excluded"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.collection, func(t *testing.T) {
+			_, err := LoadManifest(tmp, tt.collection)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("LoadManifest(%s) = %v, want %q", tt.collection, err, tt.wantErr)
+			}
+		})
+	}
+}
+
 // --- ListCollectionDirs ---
 
 func TestListCollectionDirs_WithCollections(t *testing.T) {
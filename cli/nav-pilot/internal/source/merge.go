@@ -0,0 +1,194 @@
+package source
+
+import (
+	"bytes"
+	"strings"
+)
+
+// Conflict marker lines written by Merge3. They match git's default
+// "merge" conflict style so editors and tooling recognise them.
+const (
+	ConflictMarkerLocal  = "<<<<<<< "
+	ConflictMarkerSep    = "======="
+	ConflictMarkerSource = ">>>>>>> "
+)
+
+// Merge3 performs a line-based three-way merge of local and remote changes
+// relative to base (the diff3 algorithm). Hunks changed on only one side are
+// taken from that side; hunks changed identically on both sides are taken once.
+// Overlapping changes are written with standard conflict markers labelled
+// localLabel and remoteLabel, and conflict is reported as true.
+func Merge3(base, local, remote []byte, localLabel, remoteLabel string) (merged []byte, conflict bool) {
+	o := splitLines(base)
+	a := splitLines(local)
+	b := splitLines(remote)
+	ma := matchLines(o, a)
+	mb := matchLines(o, b)
+
+	var out bytes.Buffer
+	emit := func(lines []string) {
+		for _, l := range lines {
+			out.WriteString(l)
+		}
+	}
+	chunk := func(co, ca, cb []string) {
+		switch {
+		case equalLines(ca, co):
+			emit(cb)
+		case equalLines(cb, co):
+			emit(ca)
+		case equalLines(ca, cb):
+			emit(ca)
+		default:
+			conflict = true
+			out.WriteString(ConflictMarkerLocal + localLabel + "\n")
+			emit(ca)
+			ensureNewline(&out)
+			out.WriteString(ConflictMarkerSep + "\n")
+			emit(cb)
+			ensureNewline(&out)
+			out.WriteString(ConflictMarkerSource + remoteLabel + "\n")
+		}
+	}
+
+	io, ia, ib := 0, 0, 0
+	for io < len(o) || ia < len(a) || ib < len(b) {
+		// Stable run: base lines matched at the current position on both sides.
+		n := 0
+		for io+n < len(o) && ma[io+n] == ia+n && mb[io+n] == ib+n {
+			n++
+		}
+		if n > 0 {
+			emit(o[io : io+n])
+			io, ia, ib = io+n, ia+n, ib+n
+			continue
+		}
+
+		// Unstable chunk: runs until the next base line matched on both sides.
+		j := io
+		for j < len(o) && (ma[j] < 0 || mb[j] < 0) {
+			j++
+		}
+		if j == len(o) {
+			chunk(o[io:], a[ia:], b[ib:])
+			break
+		}
+		chunk(o[io:j], a[ia:ma[j]], b[ib:mb[j]])
+		io, ia, ib = j, ma[j], mb[j]
+	}
+	return out.Bytes(), conflict
+}
+
+// HasConflictMarkers reports whether data contains unresolved conflict markers.
+func HasConflictMarkers(data []byte) bool {
+	for _, l := range splitLines(data) {
+		if strings.HasPrefix(l, ConflictMarkerLocal) || strings.HasPrefix(l, ConflictMarkerSource) {
+			return true
+		}
+	}
+	return false
+}
+
+// IsBinary reports whether data looks like binary content (contains a NUL byte
+// in the first 8 KiB), in which case line merging is not attempted.
+func IsBinary(data []byte) bool {
+	if len(data) > 8192 {
+		data = data[:8192]
+	}
+	return bytes.IndexByte(data, 0) >= 0
+}
+
+func ensureNewline(b *bytes.Buffer) {
+	if b.Len() > 0 && b.Bytes()[b.Len()-1] != '\n' {
+		b.WriteByte('\n')
+	}
+}
+
+func splitLines(data []byte) []string {
+	if len(data) == 0 {
+		return nil
+	}
+	lines := strings.SplitAfter(string(data), "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
+
+func equalLines(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// matchLines returns, for each line in a, the index of the line in b it is
+// matched to by a longest common subsequence, or -1. Uses Myers' O(ND) diff.
+func matchLines(a, b []string) []int {
+	n, m := len(a), len(b)
+	match := make([]int, n)
+	for i := range match {
+		match[i] = -1
+	}
+	if n == 0 || m == 0 {
+		return match
+	}
+	limit := n + m
+	off := limit + 1
+	v := make([]int, 2*limit+2)
+	var trace [][]int
+
+	final := -1
+	for d := 0; d <= limit && final < 0; d++ {
+		trace = append(trace, append([]int(nil), v...))
+		for k := -d; k <= d; k += 2 {
+			var x int
+			if k == -d || (k != d && v[off+k-1] < v[off+k+1]) {
+				x = v[off+k+1]
+			} else {
+				x = v[off+k-1] + 1
+			}
+			y := x - k
+			for x < n && y < m && a[x] == b[y] {
+				x++
+				y++
+			}
+			v[off+k] = x
+			if x >= n && y >= m {
+				final = d
+				break
+			}
+		}
+	}
+
+	x, y := n, m
+	for d := final; d > 0; d-- {
+		pv := trace[d]
+		k := x - y
+		var prevK int
+		if k == -d || (k != d && pv[off+k-1] < pv[off+k+1]) {
+			prevK = k + 1
+		} else {
+			prevK = k - 1
+		}
+		prevX := pv[off+prevK]
+		prevY := prevX - prevK
+		for x > prevX && y > prevY {
+			x--
+			y--
+			match[x] = y
+		}
+		x, y = prevX, prevY
+	}
+	for x > 0 && y > 0 {
+		x--
+		y--
+		match[x] = y
+	}
+	return match
+}
@@ -0,0 +1,118 @@
+package source
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestMerge3(t *testing.T) {
+	base := "# Agent\n\nintro\n\n## Rules\n- one\n- two\n\n## Footer\nend\n"
+	tests := []struct {
+		name         string
+		base         string // defaults to the shared base above
+		local        string
+		remote       string
+		want         string
+		wantConflict bool
+	}{
+		{
+			name:   "unchanged both sides",
+			local:  base,
+			remote: base,
+			want:   base,
+		},
+		{
+			name:   "remote only",
+			local:  base,
+			remote: "# Agent\n\nintro v2\n\n## Rules\n- one\n- two\n\n## Footer\nend\n",
+			want:   "# Agent\n\nintro v2\n\n## Rules\n- one\n- two\n\n## Footer\nend\n",
+		},
+		{
+			name:   "local only",
+			local:  "# Agent\n\nintro\n\n## Rules\n- one\n- two\n- team rule\n\n## Footer\nend\n",
+			remote: base,
+			want:   "# Agent\n\nintro\n\n## Rules\n- one\n- two\n- team rule\n\n## Footer\nend\n",
+		},
+		{
+			name:   "disjoint changes merge cleanly",
+			local:  "# Agent\n\nintro\n\n## Rules\n- one\n- two\n- team rule\n\n## Footer\nend\n",
+			remote: "# Agent\n\nintro v2\n\n## Rules\n- one\n- two\n\n## Footer\nend v2\n",
+			want:   "# Agent\n\nintro v2\n\n## Rules\n- one\n- two\n- team rule\n\n## Footer\nend v2\n",
+		},
+		{
+			name:   "identical change on both sides",
+			local:  "# Agent\n\nintro v2\n\n## Rules\n- one\n- two\n\n## Footer\nend\n",
+			remote: "# Agent\n\nintro v2\n\n## Rules\n- one\n- two\n\n## Footer\nend\n",
+			want:   "# Agent\n\nintro v2\n\n## Rules\n- one\n- two\n\n## Footer\nend\n",
+		},
+		{
+			name:         "overlapping change conflicts",
+			local:        "# Agent\n\nintro (local)\n\n## Rules\n- one\n- two\n\n## Footer\nend\n",
+			remote:       "# Agent\n\nintro (upstream)\n\n## Rules\n- one\n- two\n\n## Footer\nend\n",
+			want:         "# Agent\n\n<<<<<<< local\nintro (local)\n=======\nintro (upstream)\n>>>>>>> source\n\n## Rules\n- one\n- two\n\n## Footer\nend\n",
+			wantConflict: true,
+		},
+		{
+			name:         "missing trailing newline inside conflict",
+			base:         "a\nbase",
+			local:        "a\nlocal",
+			remote:       "a\nremote",
+			want:         "a\n<<<<<<< local\nlocal\n=======\nremote\n>>>>>>> source\n",
+			wantConflict: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := base
+			if tt.base != "" {
+				b = tt.base
+			}
+			got, conflict := Merge3([]byte(b), []byte(tt.local), []byte(tt.remote), "local", "source")
+			if string(got) != tt.want {
+				t.Errorf("merged =\n%s\nwant\n%s", got, tt.want)
+			}
+			if conflict != tt.wantConflict {
+				t.Errorf("conflict = %v, want %v", conflict, tt.wantConflict)
+			}
+		})
+	}
+}
+
+func TestMatchLines(t *testing.T) {
+	a := []string{"a", "b", "c", "d"}
+	b := []string{"a", "x", "c", "d", "e"}
+	want := []int{0, -1, 2, 3}
+	if got := matchLines(a, b); !reflect.DeepEqual(got, want) {
+		t.Errorf("matchLines = %v, want %v", got, want)
+	}
+	if got := matchLines(nil, b); len(got) != 0 {
+		t.Errorf("matchLines(nil) = %v, want empty", got)
+	}
+	if got := matchLines(a, nil); !reflect.DeepEqual(got, []int{-1, -1, -1, -1}) {
+		t.Errorf("matchLines(a, nil) = %v, want all -1", got)
+	}
+	if got := matchLines(nil, nil); len(got) != 0 {
+		t.Errorf("matchLines(nil, nil) = %v, want empty", got)
+	}
+}
+
+func TestMerge3_EmptyFiles(t *testing.T) {
+	// An empty file (a .gitkeep in a skill directory) on all three sides.
+	got, conflict := Merge3(nil, []byte{}, nil, "local", "source")
+	if len(got) != 0 || conflict {
+		t.Errorf("Merge3(empty) = %q, %v; want empty, no conflict", got, conflict)
+	}
+	got, conflict = Merge3(nil, []byte("local\n"), nil, "local", "source")
+	if string(got) != "local\n" || conflict {
+		t.Errorf("Merge3(local only) = %q, %v", got, conflict)
+	}
+}
+
+func TestHasConflictMarkers(t *testing.T) {
+	if HasConflictMarkers([]byte("# Clean\n=======\n")) {
+		t.Error("separator alone should not count as a conflict")
+	}
+	if !HasConflictMarkers([]byte("x\n<<<<<<< local\ny\n=======\nz\n>>>>>>> source\n")) {
+		t.Error("expected conflict markers to be detected")
+	}
+}
@@ -42,8 +42,10 @@ type Resolved struct {
 	Kind    *ArtifactKind
 	Name    string // bare name without suffix
 	AbsPath string // full filesystem path
-	RelPath string // relative to source root (e.g. "agents/foo.agent.md")
+	RelPath string // relative to the layer root (e.g. "agents/foo.agent.md")
 	IsDir   bool   // actual shape on disk
+	Source  string // repo label of the layer that provided the artifact
+	Root    string // root directory of that layer
 }
 
 // FileName returns the name used for destination paths.
@@ -69,28 +71,84 @@ func (k *ArtifactKind) RelPathForName(scope *domain.InstallScope, name string) s
 }
 
 // SourceResolver centralizes all source-repo path resolution.
+//
+// A resolver spans one or more layers ordered by increasing precedence.
+// Name-collision rules:
+//   - an artifact is identified by kind + name; the highest layer that has it wins
+//   - the winning artifact is used whole — a skill directory is never merged
+//     file-by-file with a lower layer's copy
+//   - collections follow the same rule by collection name
 type SourceResolver struct {
-	sourceDir string
+	layers []Layer
 }
 
 // NewSourceResolver creates a resolver for the given source directory.
 func NewSourceResolver(sourceDir string) *SourceResolver {
-	return &SourceResolver{sourceDir: sourceDir}
+	return &SourceResolver{layers: []Layer{{Dir: sourceDir}}}
 }
 
-// Get finds a single named artifact. Checks root first, then .github/.
+// NewLayeredSourceResolver creates a resolver over layers in increasing precedence order.
+func NewLayeredSourceResolver(layers []Layer) *SourceResolver {
+	return &SourceResolver{layers: layers}
+}
+
+// Layers returns the resolver's layers in increasing precedence order.
+func (r *SourceResolver) Layers() []Layer {
+	return r.layers
+}
+
+// Layered reports whether the resolver spans more than one source.
+func (r *SourceResolver) Layered() bool {
+	return len(r.layers) > 1
+}
+
+// Get finds a single named artifact, searching layers from highest precedence down.
 func (r *SourceResolver) Get(kind *ArtifactKind, name string) (Resolved, bool) {
-	if kind.IsDir {
-		return r.getDir(kind, name)
+	for i := len(r.layers) - 1; i >= 0; i-- {
+		if art, ok := r.getInLayer(r.layers[i], kind, name); ok {
+			return art, true
+		}
 	}
-	if kind.CanBeDir {
-		return r.getCanBeDir(kind, name)
+	return Resolved{}, false
+}
+
+// Shadowed returns the lower-precedence layers that also provide kind/name
+// but lost the collision to the layer returned by Get.
+func (r *SourceResolver) Shadowed(kind *ArtifactKind, name string) []Layer {
+	var shadowed []Layer
+	found := false
+	for i := len(r.layers) - 1; i >= 0; i-- {
+		if _, ok := r.getInLayer(r.layers[i], kind, name); !ok {
+			continue
+		}
+		if found {
+			shadowed = append(shadowed, r.layers[i])
+		}
+		found = true
+	}
+	return shadowed
+}
+
+func (r *SourceResolver) getInLayer(layer Layer, kind *ArtifactKind, name string) (Resolved, bool) {
+	var art Resolved
+	var ok bool
+	switch {
+	case kind.IsDir:
+		art, ok = getDir(layer.Dir, kind, name)
+	case kind.CanBeDir:
+		art, ok = getCanBeDir(layer.Dir, kind, name)
+	default:
+		art, ok = getSimpleFile(layer.Dir, kind, name)
+	}
+	if ok {
+		art.Source = layer.Repo
+		art.Root = layer.Dir
 	}
-	return r.getSimpleFile(kind, name)
+	return art, ok
 }
 
-func (r *SourceResolver) checkSafePath(abs string) (os.FileInfo, error) {
-	rel, err := filepath.Rel(r.sourceDir, abs)
+func checkSafePath(root, abs string) (os.FileInfo, error) {
+	rel, err := filepath.Rel(root, abs)
 	if err != nil || strings.HasPrefix(rel, "..") || rel == ".." {
 		return nil, os.ErrNotExist
 	}
@@ -104,45 +162,45 @@ func (r *SourceResolver) checkSafePath(abs string) (os.FileInfo, error) {
 	return info, nil
 }
 
-func (r *SourceResolver) getSimpleFile(kind *ArtifactKind, name string) (Resolved, bool) {
+func getSimpleFile(root string, kind *ArtifactKind, name string) (Resolved, bool) {
 	fileName := name + kind.Suffix
 	rel := filepath.Join(kind.Dir, fileName)
-	abs := filepath.Join(r.sourceDir, rel)
-	if _, err := r.checkSafePath(abs); err == nil {
+	abs := filepath.Join(root, rel)
+	if _, err := checkSafePath(root, abs); err == nil {
 		return Resolved{Kind: kind, Name: name, AbsPath: abs, RelPath: rel, IsDir: false}, true
 	}
 	return Resolved{}, false
 }
 
-func (r *SourceResolver) getDir(kind *ArtifactKind, name string) (Resolved, bool) {
+func getDir(root string, kind *ArtifactKind, name string) (Resolved, bool) {
 	rel := filepath.Join(kind.Dir, name)
-	abs := filepath.Join(r.sourceDir, rel)
+	abs := filepath.Join(root, rel)
 
-	if _, err := r.checkSafePath(abs); err != nil {
+	if _, err := checkSafePath(root, abs); err != nil {
 		return Resolved{}, false
 	}
 
 	if kind.Marker != "" {
-		if _, err := r.checkSafePath(filepath.Join(abs, kind.Marker)); err == nil {
+		if _, err := checkSafePath(root, filepath.Join(abs, kind.Marker)); err == nil {
 			return Resolved{Kind: kind, Name: name, AbsPath: abs, RelPath: rel, IsDir: true}, true
 		}
 	} else {
-		if info, err := r.checkSafePath(abs); err == nil && info.IsDir() {
+		if info, err := checkSafePath(root, abs); err == nil && info.IsDir() {
 			return Resolved{Kind: kind, Name: name, AbsPath: abs, RelPath: rel, IsDir: true}, true
 		}
 	}
 	return Resolved{}, false
 }
 
-func (r *SourceResolver) getCanBeDir(kind *ArtifactKind, name string) (Resolved, bool) {
+func getCanBeDir(root string, kind *ArtifactKind, name string) (Resolved, bool) {
 	dirRel := filepath.Join(kind.Dir, name)
-	dirAbs := filepath.Join(r.sourceDir, dirRel)
-	if info, err := r.checkSafePath(dirAbs); err == nil && info.IsDir() {
+	dirAbs := filepath.Join(root, dirRel)
+	if info, err := checkSafePath(root, dirAbs); err == nil && info.IsDir() {
 		return Resolved{Kind: kind, Name: name, AbsPath: dirAbs, RelPath: dirRel, IsDir: true}, true
 	}
 	fileRel := filepath.Join(kind.Dir, name+kind.Suffix)
-	fileAbs := filepath.Join(r.sourceDir, fileRel)
-	if _, err := r.checkSafePath(fileAbs); err == nil {
+	fileAbs := filepath.Join(root, fileRel)
+	if _, err := checkSafePath(root, fileAbs); err == nil {
 		return Resolved{Kind: kind, Name: name, AbsPath: fileAbs, RelPath: fileRel, IsDir: false}, true
 	}
 	return Resolved{}, false
@@ -150,15 +208,23 @@ func (r *SourceResolver) getCanBeDir(kind *ArtifactKind, name string) (Resolved,
 
 // GetFile resolves a specific file by typeDir + fileName.
 func (r *SourceResolver) GetFile(typeDir, fileName string) (absPath, relPath string, ok bool) {
+	absPath, relPath, _, ok = r.LocateFile(typeDir, fileName)
+	return absPath, relPath, ok
+}
+
+// LocateFile is GetFile that also reports which layer provided the file.
+func (r *SourceResolver) LocateFile(typeDir, fileName string) (absPath, relPath string, layer Layer, ok bool) {
 	rel := filepath.Join(typeDir, fileName)
-	abs := filepath.Join(r.sourceDir, rel)
-	if _, err := r.checkSafePath(abs); err == nil {
-		return abs, rel, true
+	for i := len(r.layers) - 1; i >= 0; i-- {
+		abs := filepath.Join(r.layers[i].Dir, rel)
+		if _, err := checkSafePath(r.layers[i].Dir, abs); err == nil {
+			return abs, rel, r.layers[i], true
+		}
 	}
-	return "", "", false
+	return "", "", Layer{}, false
 }
 
-// List discovers all artifacts of a kind.
+// List discovers all artifacts of a kind across all layers.
 func (r *SourceResolver) List(kind *ArtifactKind) []Resolved {
 	names := r.discoverNames(kind)
 	var results []Resolved
@@ -174,10 +240,8 @@ func (r *SourceResolver) discoverNames(kind *ArtifactKind) []string {
 	seen := make(map[string]bool)
 	var names []string
 
-	for _, base := range [1]string{
-		filepath.Join(r.sourceDir, kind.Dir),
-	} {
-		entries, err := os.ReadDir(base)
+	for _, layer := range r.layers {
+		entries, err := os.ReadDir(filepath.Join(layer.Dir, kind.Dir))
 		if err != nil {
 			continue
 		}
@@ -217,8 +281,79 @@ func (r *SourceResolver) discoverNames(kind *ArtifactKind) []string {
 	return names
 }
 
+// Collections returns the union of collection names across all layers.
+func (r *SourceResolver) Collections() ([]string, error) {
+	seen := make(map[string]bool)
+	var names []string
+	var firstErr error
+	ok := false
+	for _, layer := range r.layers {
+		layerNames, err := ListCollectionDirs(layer.Dir)
+		if err != nil {
+			if firstErr == nil {
+				firstErr = err
+			}
+			continue
+		}
+		ok = true
+		for _, n := range layerNames {
+			if !seen[n] {
+				seen[n] = true
+				names = append(names, n)
+			}
+		}
+	}
+	if !ok {
+		return nil, firstErr
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// LoadManifest loads a collection from the highest-precedence layer that
+// defines it. Each collection it extends is looked up the same way, so an
+// overlay collection can extend one from the base.
+func (r *SourceResolver) LoadManifest(collection string) (*Manifest, error) {
+	return resolveManifest(collection, func(name string) (*Manifest, error) {
+		for i := len(r.layers) - 1; i > 0; i-- {
+			manifest := filepath.Join(r.layers[i].Dir, "collections", name, "manifest.json")
+			if _, err := os.Stat(manifest); err == nil {
+				return readManifest(r.layers[i].Dir, name)
+			}
+		}
+		return readManifest(r.layers[0].Dir, name)
+	})
+}
+
+// CollectAll returns a synthetic manifest of every agent, skill, and
+// instruction across all layers.
+func (r *SourceResolver) CollectAll() *Manifest {
+	m := &Manifest{
+		Name:        "(all)",
+		Description: "All agents, skills, and instructions",
+	}
+	for _, a := range r.List(KindAgent) {
+		m.Agents = append(m.Agents, a.Name)
+	}
+	for _, s := range r.List(KindSkill) {
+		m.Skills = append(m.Skills, s.Name)
+	}
+	for _, i := range r.List(KindInstruction) {
+		m.Instructions = append(m.Instructions, i.Name)
+	}
+	return m
+}
+
 // MapLocalPath maps an installed/state path back to the source path.
 func (r *SourceResolver) MapLocalPath(localPath string, isUserScope bool) string {
+	rel, _ := r.MapLocalPathLayer(localPath, isUserScope)
+	return rel
+}
+
+// MapLocalPathLayer is MapLocalPath that also reports which layer the source
+// path belongs to. Paths that do not resolve map to the base layer.
+func (r *SourceResolver) MapLocalPathLayer(localPath string, isUserScope bool) (string, Layer) {
+	base := r.layers[0]
 	sp := filepath.ToSlash(localPath)
 	hasSuffix := strings.HasSuffix(sp, "/")
 
@@ -230,7 +365,7 @@ func (r *SourceResolver) MapLocalPath(localPath string, isUserScope bool) string
 	} else if isUserScope {
 		rest = sp
 	} else {
-		return sp
+		return sp, base
 	}
 
 	for _, kind := range AllKinds {
@@ -242,17 +377,18 @@ func (r *SourceResolver) MapLocalPath(localPath string, isUserScope bool) string
 		if kind.IsDir {
 			name := strings.TrimSuffix(remainder, "/")
 			if art, ok := r.Get(kind, name); ok {
+				layer := Layer{Repo: art.Source, Dir: art.Root}
 				if hasSuffix {
-					return art.RelPath + "/"
+					return art.RelPath + "/", layer
 				}
-				return art.RelPath
+				return art.RelPath, layer
 			}
 		} else {
-			if _, relPath, ok := r.GetFile(kind.Dir, remainder); ok {
+			if _, relPath, layer, ok := r.LocateFile(kind.Dir, remainder); ok {
 				if hasSuffix && !strings.HasSuffix(relPath, "/") {
-					return relPath + "/"
+					return relPath + "/", layer
 				}
-				return relPath
+				return relPath, layer
 			}
 		}
 		break
@@ -263,8 +399,8 @@ func (r *SourceResolver) MapLocalPath(localPath string, isUserScope bool) string
 		if hasSuffix && !strings.HasSuffix(result, "/") {
 			result += "/"
 		}
-		return result
+		return result, base
 	}
 
-	return sp
+	return sp, base
 }
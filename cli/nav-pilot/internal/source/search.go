@@ -0,0 +1,282 @@
+package source
+
+import (
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// BM25 parameters: k1 saturates term frequency, b normalizes for length.
+const (
+	bm25K1 = 1.2
+	bm25B  = 0.75
+)
+
+// Field weights: a term in the name counts as much as several in the body.
+const (
+	searchNameWeight        = 4
+	searchDescriptionWeight = 2
+	searchBodyWeight        = 1
+)
+
+// SearchDoc is one artifact in the search index.
+type SearchDoc struct {
+	Kind        *ArtifactKind
+	Name        string
+	Description string
+	Body        string   // markdown without frontmatter
+	Collections []string // collections that include the artifact, sorted
+	Source      string   // repo label of the layer that provided it
+
+	terms  map[string]float64 // weighted term frequency
+	length float64            // weighted term count
+}
+
+// SearchHit is a ranked match.
+type SearchHit struct {
+	Doc     *SearchDoc
+	Score   float64
+	Snippet string // the body (or description) line that best matches the query
+}
+
+// SearchIndex ranks artifacts with BM25 over name, description and body.
+type SearchIndex struct {
+	Docs      []*SearchDoc
+	docFreq   map[string]int
+	avgLength float64
+}
+
+// SearchIndex indexes every artifact the resolver can see, with the
+// collections that include it.
+func (r *SourceResolver) SearchIndex() *SearchIndex {
+	inCollections := map[string][]string{}
+	if names, err := r.Collections(); err == nil {
+		for _, name := range names {
+			m, err := r.LoadManifest(name)
+			if err != nil {
+				continue
+			}
+			for _, list := range []struct {
+				kind  *ArtifactKind
+				names []string
+			}{
+				{KindAgent, m.Agents},
+				{KindSkill, m.Skills},
+				{KindInstruction, m.Instructions},
+				{KindPrompt, m.Prompts},
+			} {
+				for _, item := range list.names {
+					key := list.kind.Name + ":" + item
+					inCollections[key] = append(inCollections[key], name)
+				}
+			}
+		}
+	}
+
+	var docs []*SearchDoc
+	for _, kind := range AllKinds {
+		for _, art := range r.List(kind) {
+			doc := &SearchDoc{Kind: kind, Name: art.Name, Source: art.Source}
+			if data, err := os.ReadFile(artifactMarkdown(art)); err == nil {
+				fm, body, _ := SplitFrontmatter(data)
+				doc.Description, _ = ExtractFrontmatterValue(fm, "description")
+				doc.Body = string(body)
+			}
+			doc.Collections = inCollections[kind.Name+":"+art.Name]
+			sort.Strings(doc.Collections)
+			docs = append(docs, doc)
+		}
+	}
+	return NewSearchIndex(docs)
+}
+
+// artifactMarkdown returns the file that describes an artifact: the file
+// itself, or the marker or same-named file inside a directory artifact.
+func artifactMarkdown(art Resolved) string {
+	if !art.IsDir {
+		return art.AbsPath
+	}
+	if art.Kind.Marker != "" {
+		return filepath.Join(art.AbsPath, art.Kind.Marker)
+	}
+	return filepath.Join(art.AbsPath, art.Name+art.Kind.Suffix)
+}
+
+// NewSearchIndex builds an index over docs.
+func NewSearchIndex(docs []*SearchDoc) *SearchIndex {
+	idx := &SearchIndex{Docs: docs, docFreq: map[string]int{}}
+	var total float64
+	for _, d := range docs {
+		d.terms = map[string]float64{}
+		d.length = 0
+		for _, f := range []struct {
+			text   string
+			weight float64
+		}{
+			{d.Name, searchNameWeight},
+			{d.Description, searchDescriptionWeight},
+			{d.Body, searchBodyWeight},
+		} {
+			for _, t := range SearchTerms(f.text) {
+				d.terms[t] += f.weight
+				d.length += f.weight
+			}
+		}
+		for t := range d.terms {
+			idx.docFreq[t]++
+		}
+		total += d.length
+	}
+	if len(docs) > 0 {
+		idx.avgLength = total / float64(len(docs))
+	}
+	return idx
+}
+
+// Search returns the docs matching any query term, best first. keep filters
+// docs before ranking; nil keeps all.
+func (idx *SearchIndex) Search(query string, keep func(*SearchDoc) bool) []SearchHit {
+	terms := uniqueTerms(SearchTerms(query))
+	if len(terms) == 0 {
+		return nil
+	}
+	n := float64(len(idx.Docs))
+	var hits []SearchHit
+	for _, d := range idx.Docs {
+		if keep != nil && !keep(d) {
+			continue
+		}
+		var score float64
+		for _, t := range terms {
+			tf := d.terms[t]
+			if tf == 0 {
+				continue
+			}
+			df := float64(idx.docFreq[t])
+			idf := math.Log(1 + (n-df+0.5)/(df+0.5))
+			norm := 1 - bm25B + bm25B*d.length/idx.avgLength
+			score += idf * tf * (bm25K1 + 1) / (tf + bm25K1*norm)
+		}
+		if score > 0 {
+			hits = append(hits, SearchHit{Doc: d, Score: score, Snippet: searchSnippet(d, terms)})
+		}
+	}
+	sort.SliceStable(hits, func(i, j int) bool {
+		if hits[i].Score != hits[j].Score {
+			return hits[i].Score > hits[j].Score
+		}
+		if hits[i].Doc.Kind.Name != hits[j].Doc.Kind.Name {
+			return hits[i].Doc.Kind.Name < hits[j].Doc.Kind.Name
+		}
+		return hits[i].Doc.Name < hits[j].Doc.Name
+	})
+	return hits
+}
+
+// SearchTerms lowercases text and splits it into letter and digit runs,
+// dropping single characters and common English words. "nav-kafka" yields
+// "nav" and "kafka".
+func SearchTerms(text string) []string {
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	terms := words[:0]
+	for _, w := range words {
+		if utf8.RuneCountInString(w) > 1 && !searchStopwords[w] {
+			terms = append(terms, w)
+		}
+	}
+	return terms
+}
+
+var searchStopwords = map[string]bool{
+	"a": true, "an": true, "and": true, "are": true, "as": true, "at": true, "be": true,
+	"by": true, "for": true, "from": true, "in": true, "is": true, "it": true, "of": true,
+	"on": true, "or": true, "the": true, "to": true, "with": true,
+}
+
+func uniqueTerms(terms []string) []string {
+	seen := map[string]bool{}
+	var out []string
+	for _, t := range terms {
+		if !seen[t] {
+			seen[t] = true
+			out = append(out, t)
+		}
+	}
+	return out
+}
+
+// searchSnippetWidth is the most characters a snippet shows.
+const searchSnippetWidth = 100
+
+// searchSnippet returns the body line with the most distinct query terms,
+// cut around the first match. It falls back to the description.
+func searchSnippet(d *SearchDoc, terms []string) string {
+	best, bestCount := "", 0
+	inFence := false
+	for _, line := range strings.Split(d.Body, "\n") {
+		line = strings.TrimSpace(line)
+		if strings.HasPrefix(line, "```") {
+			inFence = !inFence
+			continue
+		}
+		if line == "" || inFence {
+			continue
+		}
+		lineTerms := map[string]bool{}
+		for _, t := range SearchTerms(line) {
+			lineTerms[t] = true
+		}
+		count := 0
+		for _, t := range terms {
+			if lineTerms[t] {
+				count++
+			}
+		}
+		if count > bestCount {
+			best, bestCount = line, count
+		}
+	}
+	if best == "" {
+		return d.Description
+	}
+	best = snippetMarkup.Replace(strings.TrimLeft(best, "#->*|0123456789. "))
+	return cutSnippet(best, terms)
+}
+
+// snippetMarkup strips inline markdown that reads as noise out of context.
+var snippetMarkup = strings.NewReplacer("**", "", "__", "", "`", "")
+
+// cutSnippet shortens line to searchSnippetWidth runes, keeping the first
+// query term in view.
+func cutSnippet(line string, terms []string) string {
+	runes := []rune(line)
+	if len(runes) <= searchSnippetWidth {
+		return line
+	}
+	lower := strings.ToLower(line)
+	first := len(runes)
+	for _, t := range terms {
+		if i := strings.Index(lower, t); i >= 0 {
+			first = min(first, utf8.RuneCountInString(lower[:i]))
+		}
+	}
+	start := 0
+	if first != len(runes) && first > searchSnippetWidth/3 {
+		start = first - searchSnippetWidth/3
+	}
+	end := min(start+searchSnippetWidth, len(runes))
+	out := strings.TrimSpace(string(runes[start:end]))
+	if start > 0 {
+		out = "…" + out
+	}
+	if end < len(runes) {
+		out += "…"
+	}
+	return out
+}
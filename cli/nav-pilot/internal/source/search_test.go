@@ -0,0 +1,92 @@
+package source
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestSearchTerms(t *testing.T) {
+	got := strings.Join(SearchTerms("Nav-Kafka: the Rapids & Rivers 2.0 — bruk på én"), "|")
+	want := "nav|kafka|rapids|rivers|bruk|på|én"
+	if got != want {
+		t.Errorf("SearchTerms = %q, want %q", got, want)
+	}
+}
+
+func TestSearchIndex_Ranking(t *testing.T) {
+	idx := NewSearchIndex([]*SearchDoc{
+		{Kind: KindAgent, Name: "observability", Description: "Metrics and tracing", Body: "See @kafka-agent for consumer lag.\n"},
+		{Kind: KindSkill, Name: "kafka", Description: "Rapids & Rivers and Kafka patterns", Body: "# Kafka\n\nUse Kafka topics.\n"},
+		{Kind: KindInstruction, Name: "testing", Description: "Test conventions", Body: "Write tests.\n"},
+		{Kind: KindPrompt, Name: "kafka-topic", Description: "Add a Kafka topic", Body: "Configure the topic in the Nais manifest.\n"},
+	})
+
+	hits := idx.Search("kafka", nil)
+	var names []string
+	for _, h := range hits {
+		names = append(names, h.Doc.Name)
+	}
+	if strings.Join(names, "|") != "kafka|kafka-topic|observability" {
+		t.Errorf("ranking = %v", names)
+	}
+
+	// Every query term adds to the score: "topic" lifts the prompt.
+	if hits := idx.Search("kafka topic", nil); hits[0].Doc.Name != "kafka-topic" {
+		t.Errorf("kafka topic: top hit %s", hits[0].Doc.Name)
+	}
+
+	hits = idx.Search("kafka", func(d *SearchDoc) bool { return d.Kind == KindAgent })
+	if len(hits) != 1 || hits[0].Snippet != "See @kafka-agent for consumer lag." {
+		t.Errorf("filtered hits = %+v", hits)
+	}
+
+	if hits := idx.Search("the", nil); hits != nil {
+		t.Errorf("stopword-only query matched %d docs", len(hits))
+	}
+}
+
+func TestSearchSnippet(t *testing.T) {
+	d := &SearchDoc{
+		Description: "fallback",
+		Body:        "# Title\n\n```\nkafka in code\n```\n\n- **Kafka** consumers and the Kafka lag\n- only kafka\n",
+	}
+	if got := searchSnippet(d, []string{"kafka", "lag"}); got != "Kafka consumers and the Kafka lag" {
+		t.Errorf("snippet = %q", got)
+	}
+	if got := searchSnippet(d, []string{"missing"}); got != "fallback" {
+		t.Errorf("snippet without match = %q", got)
+	}
+
+	long := strings.Repeat("word ", 40) + "kafka " + strings.Repeat("tail ", 40)
+	got := cutSnippet(long, []string{"kafka"})
+	if !strings.HasPrefix(got, "…") || !strings.HasSuffix(got, "…") || !strings.Contains(got, "kafka") {
+		t.Errorf("cutSnippet = %q", got)
+	}
+}
+
+func TestResolverSearchIndex(t *testing.T) {
+	dir := t.TempDir()
+	os.MkdirAll(filepath.Join(dir, "collections", "backend"), 0o755)
+	os.WriteFile(filepath.Join(dir, "collections", "backend", "manifest.json"),
+		[]byte(`{"name":"backend","skills":["kafka"]}`), 0o644)
+	os.MkdirAll(filepath.Join(dir, "skills", "kafka"), 0o755)
+	os.WriteFile(filepath.Join(dir, "skills", "kafka", "SKILL.md"),
+		[]byte("---\nname: kafka\ndescription: \"Kafka-mønstre\"\n---\n\n# Kafka\n"), 0o644)
+	os.MkdirAll(filepath.Join(dir, "agents"), 0o755)
+	os.WriteFile(filepath.Join(dir, "agents", "nais.agent.md"), []byte("# Nais\n"), 0o644)
+
+	idx := NewSourceResolver(dir).SearchIndex()
+	if len(idx.Docs) != 2 {
+		t.Fatalf("indexed %d docs, want 2", len(idx.Docs))
+	}
+	hits := idx.Search("kafka", nil)
+	if len(hits) != 1 {
+		t.Fatalf("hits = %+v", hits)
+	}
+	d := hits[0].Doc
+	if d.Kind != KindSkill || d.Description != "Kafka-mønstre" || strings.Join(d.Collections, ",") != "backend" {
+		t.Errorf("doc = %+v", d)
+	}
+}
@@ -16,9 +16,14 @@ import (
 type Source struct {
 	Dir     string
 	TempDir string
-	SHA     string
+	SHA     string // short commit SHA, for display and state
+	Commit  string // full commit SHA, used to pin lockfiles; empty when unknown
 	Version string // release version (e.g. "2026.04.14-..."), empty for local dev
-	Repo    string // git repository owner/name (e.g. "navikt/copilot")
+	Repo    string // git repository owner/name (e.g. "navikt/copilot"); layered sources join all layers with LayerSeparator
+
+	// Overlays are higher-precedence layers stacked on top of Dir, in order.
+	// Empty for a single-source install.
+	Overlays []*Source
 }
 
 // CloneRemoteFn is overridable in tests.
@@ -28,19 +33,29 @@ func (s *Source) Cleanup() {
 	if s.TempDir != "" {
 		os.RemoveAll(s.TempDir)
 	}
+	for _, o := range s.Overlays {
+		o.Cleanup()
+	}
 }
 
 // ResolveSource finds the navikt/copilot source. Priority:
 //  1. Explicit --ref flag
 //  2. Local repo (walk up from CWD to git root — dev mode)
 //  3. Clone HEAD of main (always gets latest content)
+//
+// A comma-separated sourceRepo ("navikt/copilot,myteam/copilot") resolves
+// every layer and stacks them; see Source.Layers for precedence.
 func ResolveSource(ref, sourceRepo, cliVersion string) (*Source, error) {
+	if repos := SplitSourceRepos(sourceRepo); len(repos) > 1 {
+		return resolveLayered(ref, repos, cliVersion)
+	}
+
 	// If a custom source repo is specified, always clone remote
 	if sourceRepo != "" {
 		if filepath.IsAbs(sourceRepo) {
 			if info, err := os.Stat(sourceRepo); err == nil && info.IsDir() {
 				sha := getGitSHA(sourceRepo)
-				return &Source{Dir: sourceRepo, SHA: sha, Version: cliVersion, Repo: sourceRepo}, nil
+				return &Source{Dir: sourceRepo, SHA: sha, Commit: getGitCommit(sourceRepo), Version: cliVersion, Repo: sourceRepo}, nil
 			}
 		}
 		src, err := CloneRemoteFn(ref, sourceRepo)
@@ -74,7 +89,7 @@ func ResolveSource(ref, sourceRepo, cliVersion string) (*Source, error) {
 			if info, err := os.Stat(candidate); err == nil && info.IsDir() {
 				sha := getGitSHA(gitRoot)
 				fmt.Fprintf(os.Stderr, "%s Using local source (%s)\n", domain.Dim("→"), domain.Dim(gitRoot))
-				return &Source{Dir: gitRoot, SHA: sha, Version: cliVersion, Repo: gitRoot}, nil
+				return &Source{Dir: gitRoot, SHA: sha, Commit: getGitCommit(gitRoot), Version: cliVersion, Repo: gitRoot}, nil
 			}
 		}
 	}
@@ -120,26 +135,80 @@ func FindGitRoot(dir string) string {
 	}
 }
 
+// cloneRemote resolves a remote ref through the persistent source cache
+// (see cache.go). When the cache cannot be opened it falls back to a
+// throwaway clone in a temp dir, removed again by Source.Cleanup.
 func cloneRemote(ref, sourceRepo string) (*Source, error) {
+	cache, err := OpenCache()
+	if err != nil {
+		if Offline {
+			return nil, fmt.Errorf("--offline needs the source cache: %w", err)
+		}
+		return cloneTemp(ref, sourceRepo)
+	}
+	return cache.Fetch(ref, sourceRepo)
+}
+
+// cloneTemp shallow-clones ref into a fresh temp dir.
+func cloneTemp(ref, sourceRepo string) (*Source, error) {
 	tmpDir, err := os.MkdirTemp("", "nav-pilot-*")
 	if err != nil {
 		return nil, fmt.Errorf("creating temp dir: %w", err)
 	}
 
-	repoURL := "https://github.com/navikt/copilot.git"
-	if sourceRepo != "" {
-		repoURL = "https://github.com/" + sourceRepo + ".git"
+	repo := repoName(sourceRepo)
+	repoURL := RemoteURL(repo)
+
+	// `clone --branch` only takes branches and tags. A full commit SHA (as
+	// pinned by nav-pilot.lock) is fetched directly instead.
+	var steps [][]string
+	if IsCommitSHA(ref) {
+		steps = [][]string{
+			{"init", "--quiet", tmpDir},
+			{"-C", tmpDir, "fetch", "--depth", "1", "--quiet", repoURL, ref},
+			{"-C", tmpDir, "-c", "advice.detachedHead=false", "checkout", "--quiet", "FETCH_HEAD"},
+		}
+	} else {
+		args := []string{"-c", "advice.detachedHead=false", "clone", "--depth", "1", "--quiet"}
+		if ref != "" {
+			args = append(args, "--branch", ref)
+		}
+		steps = [][]string{append(args, repoURL, tmpDir)}
 	}
 
-	label := "navikt/copilot"
-	if sourceRepo != "" {
-		label = sourceRepo
+	stop := startSpinner(fetchMessage(repo, ref))
+	stderr, err := runGitSteps(nil, steps)
+	stop()
+	if err != nil {
+		os.RemoveAll(tmpDir)
+		return nil, cloneError(repo, ref, stderr)
 	}
-	msg := fmt.Sprintf("Fetching %s...", label)
+
+	return &Source{Dir: tmpDir, TempDir: tmpDir, SHA: getGitSHA(tmpDir), Commit: getGitCommit(tmpDir), Repo: repo}, nil
+}
+
+// RemoteURL returns the clone URL for an owner/name repo. Overridable in tests.
+var RemoteURL = func(repo string) string {
+	return "https://github.com/" + repo + ".git"
+}
+
+// repoName returns sourceRepo, defaulting to navikt/copilot.
+func repoName(sourceRepo string) string {
+	if sourceRepo == "" {
+		return "navikt/copilot"
+	}
+	return sourceRepo
+}
+
+func fetchMessage(repo, ref string) string {
 	if ref != "" {
-		msg = fmt.Sprintf("Fetching %s@%s...", label, ref)
+		return fmt.Sprintf("Fetching %s@%s...", repo, ref)
 	}
+	return fmt.Sprintf("Fetching %s...", repo)
+}
 
+// startSpinner animates msg on stderr until the returned stop func is called.
+func startSpinner(msg string) (stop func()) {
 	done := make(chan struct{})
 	go func() {
 		frames := []string{"⠋", "⠙", "⠹", "⠸", "⠼", "⠴", "⠦", "⠧", "⠇", "⠏"}
@@ -156,46 +225,67 @@ func cloneRemote(ref, sourceRepo string) (*Source, error) {
 			}
 		}
 	}()
-
-	args := []string{"-c", "advice.detachedHead=false", "clone", "--depth", "1", "--quiet"}
-	if ref != "" {
-		args = append(args, "--branch", ref)
+	return func() {
+		close(done)
+		fmt.Fprintf(os.Stderr, "\r\033[K")
 	}
-	args = append(args, repoURL, tmpDir)
+}
 
-	cmd := exec.Command("git", args...)
-	cmd.Env = append(os.Environ(), "GIT_TERMINAL_PROMPT=0")
+// runGitSteps runs git commands in order, stopping at the first failure.
+// stderr is captured rather than shown so it doesn't overwrite the spinner.
+func runGitSteps(env []string, steps [][]string) (string, error) {
 	var stderr bytes.Buffer
-	cmd.Stderr = &stderr
-	// Suppress stderr during clone so it doesn't overwrite the spinner unless there's an error
-	err = cmd.Run()
-
-	close(done)
-	fmt.Fprintf(os.Stderr, "\r\033[K")
-	if err != nil {
-		os.RemoveAll(tmpDir)
-		gitErr := strings.TrimSpace(stderr.String())
-		if gitErr != "" {
-			gitErr = "\n\n  " + strings.ReplaceAll(gitErr, "\n", "\n  ")
-		}
-		isAuthFailure := strings.Contains(gitErr, "Authentication failed") ||
-			strings.Contains(gitErr, "could not read Username") ||
-			strings.Contains(gitErr, "Permission denied")
-		if isAuthFailure {
-			return nil, fmt.Errorf("could not clone %s: authentication failed. Check your SSH keys/git credentials or set GITHUB_TOKEN.%s", label, gitErr)
+	for _, args := range steps {
+		cmd := exec.Command("git", args...)
+		cmd.Env = append(append(os.Environ(), "GIT_TERMINAL_PROMPT=0"), env...)
+		cmd.Stderr = &stderr
+		if err := cmd.Run(); err != nil {
+			return stderr.String(), err
 		}
-		if ref != "" {
-			return nil, fmt.Errorf("could not clone %s@%s — check that the ref exists and you have network access%s", label, ref, gitErr)
+	}
+	return stderr.String(), nil
+}
+
+// cloneError turns git's stderr into an actionable error.
+func cloneError(label, ref, stderr string) error {
+	gitErr := strings.TrimSpace(stderr)
+	if gitErr != "" {
+		gitErr = "\n\n  " + strings.ReplaceAll(gitErr, "\n", "\n  ")
+	}
+	isAuthFailure := strings.Contains(gitErr, "Authentication failed") ||
+		strings.Contains(gitErr, "could not read Username") ||
+		strings.Contains(gitErr, "Permission denied")
+	if isAuthFailure {
+		return fmt.Errorf("could not clone %s: authentication failed. Check your SSH keys/git credentials or set GITHUB_TOKEN.%s", label, gitErr)
+	}
+	if ref != "" {
+		return fmt.Errorf("could not clone %s@%s — check that the ref exists and you have network access%s", label, ref, gitErr)
+	}
+	return fmt.Errorf("could not clone %s — check your network connection%s", label, gitErr)
+}
+
+// IsCommitSHA reports whether ref is a full 40-character hex commit SHA.
+func IsCommitSHA(ref string) bool {
+	if len(ref) != 40 {
+		return false
+	}
+	for _, c := range ref {
+		if !strings.ContainsRune("0123456789abcdef", c) {
+			return false
 		}
-		return nil, fmt.Errorf("could not clone %s — check your network connection%s", label, gitErr)
 	}
+	return true
+}
 
-	sha := getGitSHA(tmpDir)
-	repo := "navikt/copilot"
-	if sourceRepo != "" {
-		repo = sourceRepo
+// getGitCommit returns the full HEAD commit SHA of dir, or "" outside git.
+func getGitCommit(dir string) string {
+	cmd := exec.Command("git", "rev-parse", "HEAD")
+	cmd.Dir = dir
+	out, err := cmd.Output()
+	if err != nil {
+		return ""
 	}
-	return &Source{Dir: tmpDir, TempDir: tmpDir, SHA: sha, Repo: repo}, nil
+	return strings.TrimSpace(string(out))
 }
 
 func getGitSHA(dir string) string {
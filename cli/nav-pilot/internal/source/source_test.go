@@ -81,3 +81,21 @@ func TestResolveSourceForSync_SkipsLocalRepoAutoDetection(t *testing.T) {
 		t.Fatalf("resolveSourceForSync dir = %q, want %q", src.Dir, "/tmp/remote")
 	}
 }
+
+func TestIsCommitSHA(t *testing.T) {
+	tests := []struct {
+		ref  string
+		want bool
+	}{
+		{"0123456789abcdef0123456789abcdef01234567", true},
+		{"main", false},
+		{"a25f6c3", false},
+		{"0123456789ABCDEF0123456789ABCDEF01234567", false},
+		{"nav-pilot/2026.04.14-202800-a25f6c3", false},
+	}
+	for _, tt := range tests {
+		if got := IsCommitSHA(tt.ref); got != tt.want {
+			t.Errorf("IsCommitSHA(%q) = %v, want %v", tt.ref, got, tt.want)
+		}
+	}
+}
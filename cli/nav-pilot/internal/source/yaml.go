@@ -0,0 +1,102 @@
+package source
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+
+	"gopkg.in/yaml.v3"
+)
+
+// YAMLError is a frontmatter parse error at a 1-based line of the input.
+type YAMLError struct {
+	Line int
+	Msg  string
+}
+
+func (e *YAMLError) Error() string { return fmt.Sprintf("line %d: %s", e.Line, e.Msg) }
+
+var yamlErrorRe = regexp.MustCompile(`^yaml: line (\d+): (.*)$`)
+
+// parseYAML parses frontmatter with yaml.v3 and returns the document's root
+// node, or nil when the input holds no document. Syntax errors that carry a
+// line number, and duplicate keys (which decoding into a Node lets through),
+// are returned as *YAMLError.
+func parseYAML(data []byte) (*yaml.Node, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		if m := yamlErrorRe.FindStringSubmatch(err.Error()); m != nil {
+			line, _ := strconv.Atoi(m[1])
+			return nil, &YAMLError{Line: line, Msg: m[2]}
+		}
+		return nil, err
+	}
+	if doc.Kind != yaml.DocumentNode || len(doc.Content) == 0 {
+		return nil, nil
+	}
+	if err := yamlCheckKeys(doc.Content[0]); err != nil {
+		return nil, err
+	}
+	return yamlResolve(doc.Content[0]), nil
+}
+
+// yamlCheckKeys rejects a mapping that defines the same key twice.
+func yamlCheckKeys(n *yaml.Node) error {
+	if n.Kind == yaml.MappingNode {
+		seen := map[string]int{}
+		for i := 0; i+1 < len(n.Content); i += 2 {
+			k := n.Content[i]
+			if line, ok := seen[k.Value]; ok {
+				return &YAMLError{Line: k.Line, Msg: fmt.Sprintf("mapping key %q already defined at line %d", k.Value, line)}
+			}
+			seen[k.Value] = k.Line
+		}
+	}
+	for _, c := range n.Content {
+		if err := yamlCheckKeys(c); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// yamlResolve follows an alias to the node it refers to.
+func yamlResolve(n *yaml.Node) *yaml.Node {
+	for n != nil && n.Kind == yaml.AliasNode {
+		n = n.Alias
+	}
+	return n
+}
+
+// yamlGet returns the value for key in a mapping node, or nil.
+func yamlGet(n *yaml.Node, key string) *yaml.Node {
+	if n == nil || n.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(n.Content); i += 2 {
+		if n.Content[i].Value == key {
+			return yamlResolve(n.Content[i+1])
+		}
+	}
+	return nil
+}
+
+// yamlIsNull reports whether n is absent, empty or an explicit null.
+func yamlIsNull(n *yaml.Node) bool {
+	return n == nil || (n.Kind == yaml.ScalarNode && n.Tag == "!!null")
+}
+
+// yamlKindName names a node's type for error messages.
+func yamlKindName(n *yaml.Node) string {
+	switch {
+	case yamlIsNull(n):
+		return "empty"
+	case n.Kind == yaml.ScalarNode:
+		return "string"
+	case n.Kind == yaml.SequenceNode:
+		return "list"
+	case n.Kind == yaml.MappingNode:
+		return "mapping"
+	}
+	return "node"
+}
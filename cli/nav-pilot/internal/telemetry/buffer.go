@@ -0,0 +1,284 @@
+package telemetry
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Exports that fail (offline, broken VPN, collector down) are written to a
+// disk buffer as the raw OTLP request body and replayed, oldest first, once a
+// later run reaches the collector. The caps keep the buffer from growing
+// without bound on machines that are never online.
+const (
+	BufferDirName  = "telemetry-buffer"
+	BufferMaxBytes = 2 << 20 // 2 MiB
+	BufferMaxAge   = 7 * 24 * time.Hour
+
+	bufferExt         = ".pb"
+	bufferContentType = "application/x-protobuf"
+)
+
+// BufferDir returns ~/.nav-pilot/telemetry-buffer (not created).
+func BufferDir() (string, error) {
+	configDir, err := GetConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(configDir, BufferDirName), nil
+}
+
+// Endpoint returns the OTLP metrics endpoint: NAV_PILOT_TELEMETRY_ENDPOINT,
+// then OTEL_EXPORTER_OTLP_ENDPOINT, then the NAV collector.
+func Endpoint() string {
+	if v := strings.TrimSpace(os.Getenv("NAV_PILOT_TELEMETRY_ENDPOINT")); v != "" {
+		return v
+	}
+	if v := strings.TrimSpace(os.Getenv("OTEL_EXPORTER_OTLP_ENDPOINT")); v != "" {
+		return v
+	}
+	return defaultTelemetryEndpoint
+}
+
+// BufferedExport is one queued export on disk.
+type BufferedExport struct {
+	Path    string    `json:"path"`
+	Bytes   int64     `json:"bytes"`
+	QueueAt time.Time `json:"queued_at"`
+}
+
+// BufferStatus describes the disk buffer and where it is replayed to.
+type BufferStatus struct {
+	Enabled  bool             `json:"enabled"`
+	Endpoint string           `json:"endpoint"`
+	Dir      string           `json:"dir"`
+	Queued   []BufferedExport `json:"queued"`
+	Bytes    int64            `json:"bytes"`
+	MaxBytes int64            `json:"max_bytes"`
+	MaxAge   string           `json:"max_age"`
+}
+
+// ReadBufferStatus lists the queued exports, oldest first.
+func ReadBufferStatus() (*BufferStatus, error) {
+	dir, err := BufferDir()
+	if err != nil {
+		return nil, err
+	}
+	queued, err := listBuffer(dir)
+	if err != nil {
+		return nil, err
+	}
+	status := &BufferStatus{
+		Enabled:  TelemetryEnabled(),
+		Endpoint: Endpoint(),
+		Dir:      dir,
+		Queued:   queued,
+		MaxBytes: BufferMaxBytes,
+		MaxAge:   BufferMaxAge.String(),
+	}
+	for _, q := range queued {
+		status.Bytes += q.Bytes
+	}
+	return status, nil
+}
+
+// listBuffer returns the queued exports in dir, oldest first. The queue time
+// is encoded in the file name, so it survives copies and clock-skewed mtimes.
+func listBuffer(dir string) ([]BufferedExport, error) {
+	entries, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return []BufferedExport{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading telemetry buffer: %w", err)
+	}
+	queued := []BufferedExport{}
+	for _, e := range entries {
+		name := e.Name()
+		if e.IsDir() || !strings.HasSuffix(name, bufferExt) {
+			continue
+		}
+		nanos, err := strconv.ParseInt(strings.TrimSuffix(name, bufferExt), 10, 64)
+		if err != nil {
+			continue
+		}
+		info, err := e.Info()
+		if err != nil {
+			continue
+		}
+		queued = append(queued, BufferedExport{
+			Path:    filepath.Join(dir, name),
+			Bytes:   info.Size(),
+			QueueAt: time.Unix(0, nanos).UTC(),
+		})
+	}
+	sort.Slice(queued, func(i, j int) bool { return queued[i].QueueAt.Before(queued[j].QueueAt) })
+	return queued, nil
+}
+
+// bufferingTransport sends OTLP requests and queues the body of any request
+// that did not reach the collector. Retries are disabled on the exporter, so
+// each export is attempted (and queued) exactly once.
+type bufferingTransport struct {
+	base http.RoundTripper
+	dir  string
+	now  func() time.Time
+
+	mu        sync.Mutex
+	delivered bool
+}
+
+func newBufferingTransport(dir string) *bufferingTransport {
+	return &bufferingTransport{base: http.DefaultTransport, dir: dir, now: time.Now}
+}
+
+func (t *bufferingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	var body []byte
+	if req.Body != nil {
+		data, err := io.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		body = data
+		req.Body = io.NopCloser(bytes.NewReader(body))
+		req.GetBody = func() (io.ReadCloser, error) { return io.NopCloser(bytes.NewReader(body)), nil }
+	}
+
+	resp, err := t.base.RoundTrip(req)
+	switch {
+	case err == nil && resp.StatusCode < 300:
+		t.mu.Lock()
+		t.delivered = true
+		t.mu.Unlock()
+	case err != nil || resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500:
+		// Transient: keep it for the next run. Other 4xx mean the payload
+		// itself was rejected, and replaying it would fail the same way.
+		if qErr := enqueueExport(t.dir, body, t.now()); qErr != nil {
+			DebugLog("telemetry buffer: %v", qErr)
+		}
+	}
+	return resp, err
+}
+
+// Delivered reports whether any export in this run reached the collector.
+func (t *bufferingTransport) Delivered() bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.delivered
+}
+
+// enqueueExport writes body to the buffer and enforces the caps.
+func enqueueExport(dir string, body []byte, now time.Time) error {
+	if len(body) == 0 || int64(len(body)) > BufferMaxBytes {
+		return nil
+	}
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return fmt.Errorf("creating %s: %w", dir, err)
+	}
+	nanos := now.UnixNano()
+	path := filepath.Join(dir, strconv.FormatInt(nanos, 10)+bufferExt)
+	for {
+		if _, err := os.Stat(path); os.IsNotExist(err) {
+			break
+		}
+		nanos++
+		path = filepath.Join(dir, strconv.FormatInt(nanos, 10)+bufferExt)
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, body, 0o600); err != nil {
+		return fmt.Errorf("writing %s: %w", tmp, err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		os.Remove(tmp)
+		return fmt.Errorf("writing %s: %w", path, err)
+	}
+	return pruneBuffer(dir, now)
+}
+
+// pruneBuffer drops exports older than BufferMaxAge, then the oldest ones
+// until the buffer fits in BufferMaxBytes.
+func pruneBuffer(dir string, now time.Time) error {
+	queued, err := listBuffer(dir)
+	if err != nil {
+		return err
+	}
+	var total int64
+	var kept []BufferedExport
+	for _, q := range queued {
+		if now.Sub(q.QueueAt) > BufferMaxAge {
+			os.Remove(q.Path)
+			continue
+		}
+		kept = append(kept, q)
+		total += q.Bytes
+	}
+	for len(kept) > 0 && total > BufferMaxBytes {
+		os.Remove(kept[0].Path)
+		total -= kept[0].Bytes
+		kept = kept[1:]
+	}
+	return nil
+}
+
+// replayBuffer posts queued exports to endpoint, oldest first, removing each
+// one the collector accepts. It stops at the first failure or when ctx ends;
+// what is left is replayed by a later run.
+func replayBuffer(ctx context.Context, client *http.Client, endpoint, dir string, now time.Time) (int, error) {
+	if err := pruneBuffer(dir, now); err != nil {
+		return 0, err
+	}
+	queued, err := listBuffer(dir)
+	if err != nil {
+		return 0, err
+	}
+	sent := 0
+	for _, q := range queued {
+		body, err := os.ReadFile(q.Path)
+		if err != nil {
+			return sent, err
+		}
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(body))
+		if err != nil {
+			return sent, err
+		}
+		req.Header.Set("Content-Type", bufferContentType)
+		resp, err := client.Do(req)
+		if err != nil {
+			return sent, err
+		}
+		io.Copy(io.Discard, resp.Body)
+		resp.Body.Close()
+		switch {
+		case resp.StatusCode < 300:
+			sent++
+		case resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500:
+			return sent, fmt.Errorf("collector returned %s", resp.Status)
+		default:
+			DebugLog("telemetry buffer: dropping %s rejected with %s", filepath.Base(q.Path), resp.Status)
+		}
+		if err := os.Remove(q.Path); err != nil {
+			return sent, err
+		}
+	}
+	return sent, nil
+}
+
+// DiscardBuffer deletes every queued export. Used when the user has opted
+// out, so data recorded before the opt-out is never sent.
+func DiscardBuffer() error {
+	dir, err := BufferDir()
+	if err != nil {
+		return err
+	}
+	return os.RemoveAll(dir)
+}
@@ -0,0 +1,150 @@
+package telemetry
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// collector is a fake OTLP endpoint whose availability can be toggled.
+type collector struct {
+	up     atomic.Bool
+	mu     sync.Mutex
+	bodies []string
+}
+
+func (c *collector) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if !c.up.Load() {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		return
+	}
+	body, _ := io.ReadAll(r.Body)
+	c.mu.Lock()
+	c.bodies = append(c.bodies, string(body))
+	c.mu.Unlock()
+	w.WriteHeader(http.StatusOK)
+}
+
+func (c *collector) received() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.bodies)
+}
+
+func TestTelemetry_BuffersFailedExportsAndReplays(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("NAV_PILOT_TELEMETRY_ENABLED", "true")
+	c := &collector{}
+	srv := httptest.NewServer(c)
+	defer srv.Close()
+	t.Setenv("NAV_PILOT_TELEMETRY_ENDPOINT", srv.URL+"/v1/metrics")
+
+	runOnce := func() {
+		rec, err := InitTelemetry(context.Background(), "1.0.0", "false")
+		if err != nil {
+			t.Fatal(err)
+		}
+		rec.RecordCommand("sync", "non_interactive", "repo", "success", "", time.Second)
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		_ = rec.Shutdown(ctx)
+	}
+
+	// Collector down: the export is queued on disk.
+	runOnce()
+	status, err := ReadBufferStatus()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(status.Queued) != 1 || status.Bytes == 0 {
+		t.Fatalf("queued = %+v, want one buffered export", status.Queued)
+	}
+	if status.Endpoint != srv.URL+"/v1/metrics" || !strings.HasSuffix(status.Dir, BufferDirName) {
+		t.Errorf("status = %+v", status)
+	}
+
+	// Collector back: the new export and the queued one are both delivered.
+	c.up.Store(true)
+	runOnce()
+	if got := c.received(); got != 2 {
+		t.Errorf("collector received %d exports, want 2 (current + replayed)", got)
+	}
+	if status, _ := ReadBufferStatus(); len(status.Queued) != 0 {
+		t.Errorf("buffer not drained: %+v", status.Queued)
+	}
+}
+
+func TestEnqueueExport_EnforcesCaps(t *testing.T) {
+	dir := t.TempDir()
+	now := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+
+	if err := enqueueExport(dir, []byte("old"), now.Add(-BufferMaxAge-time.Hour)); err != nil {
+		t.Fatal(err)
+	}
+	big := []byte(strings.Repeat("x", BufferMaxBytes/2+1))
+	if err := enqueueExport(dir, big, now.Add(-time.Minute)); err != nil {
+		t.Fatal(err)
+	}
+	if err := enqueueExport(dir, big, now); err != nil {
+		t.Fatal(err)
+	}
+
+	queued, err := listBuffer(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(queued) != 1 || !queued[0].QueueAt.Equal(now) {
+		t.Fatalf("queued = %+v, want only the newest export", queued)
+	}
+
+	// Payloads larger than the whole buffer are never written.
+	if err := enqueueExport(dir, []byte(strings.Repeat("x", BufferMaxBytes+1)), now.Add(time.Second)); err != nil {
+		t.Fatal(err)
+	}
+	if queued, _ := listBuffer(dir); len(queued) != 1 {
+		t.Errorf("oversized export was queued: %+v", queued)
+	}
+}
+
+func TestReplayBuffer_StopsAtFirstFailureAndDropsRejected(t *testing.T) {
+	dir := t.TempDir()
+	now := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+	for i, body := range []string{"bad", "ok", "later"} {
+		if err := enqueueExport(dir, []byte(body), now.Add(time.Duration(i)*time.Second)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		switch string(body) {
+		case "bad":
+			w.WriteHeader(http.StatusBadRequest)
+		case "later":
+			w.WriteHeader(http.StatusBadGateway)
+		}
+	}))
+	defer srv.Close()
+
+	sent, err := replayBuffer(context.Background(), srv.Client(), srv.URL, dir, now)
+	if sent != 1 || err == nil {
+		t.Fatalf("replayBuffer = %d, %v; want 1 sent and an error", sent, err)
+	}
+	queued, _ := listBuffer(dir)
+	if len(queued) != 1 {
+		t.Fatalf("queued = %+v", queued)
+	}
+	if data, _ := os.ReadFile(queued[0].Path); string(data) != "later" {
+		t.Errorf("kept %q, want the export that failed transiently", data)
+	}
+	if matches, _ := filepath.Glob(filepath.Join(dir, "*.tmp")); len(matches) != 0 {
+		t.Errorf("temp files left behind: %v", matches)
+	}
+}
@@ -0,0 +1,110 @@
+package telemetry
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"go.opentelemetry.io/otel/attribute"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+)
+
+// Inspector is a Recorder that keeps metrics in memory instead of exporting
+// them. `nav-pilot telemetry inspect` runs a command against it and prints
+// what would have been sent.
+type Inspector struct {
+	*otelTelemetry
+	reader *sdkmetric.ManualReader
+}
+
+// InspectedPoint is one data point as it would be exported.
+type InspectedPoint struct {
+	Name       string            `json:"name"`
+	Kind       string            `json:"kind"` // counter, gauge or histogram
+	Value      int64             `json:"value"`
+	Count      uint64            `json:"count,omitempty"` // histograms only
+	Attributes map[string]string `json:"attributes"`
+}
+
+// Inspection is everything a run recorded.
+type Inspection struct {
+	Endpoint string            `json:"endpoint"`
+	Resource map[string]string `json:"resource"`
+	Points   []InspectedPoint  `json:"metrics"`
+}
+
+// NewInspector builds a local recorder with the same resource and
+// instruments as InitTelemetry. It ignores TelemetryEnabled: nothing leaves
+// the machine.
+func NewInspector(ctx context.Context, cliVersion, rtkInstalled string) (*Inspector, error) {
+	reader := sdkmetric.NewManualReader(sdkmetric.WithTemporalitySelector(deltaCounters))
+	tel, err := newOtelTelemetry(ctx, reader, cliVersion, rtkInstalled)
+	if err != nil {
+		return nil, err
+	}
+	tel.recordInfo()
+	return &Inspector{otelTelemetry: tel, reader: reader}, nil
+}
+
+// Collect returns the recorded points, sorted by metric name and attributes.
+func (i *Inspector) Collect(ctx context.Context) (*Inspection, error) {
+	var rm metricdata.ResourceMetrics
+	if err := i.reader.Collect(ctx, &rm); err != nil {
+		return nil, fmt.Errorf("collecting metrics: %w", err)
+	}
+	out := &Inspection{Endpoint: Endpoint(), Resource: map[string]string{}, Points: []InspectedPoint{}}
+	if rm.Resource != nil {
+		out.Resource = attrMap(*rm.Resource.Set())
+	}
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			out.Points = append(out.Points, inspectPoints(m)...)
+		}
+	}
+	sort.SliceStable(out.Points, func(a, b int) bool {
+		pa, pb := out.Points[a], out.Points[b]
+		if pa.Name != pb.Name {
+			return pa.Name < pb.Name
+		}
+		return fmt.Sprint(SortedAttributes(pa.Attributes)) < fmt.Sprint(SortedAttributes(pb.Attributes))
+	})
+	return out, nil
+}
+
+func inspectPoints(m metricdata.Metrics) []InspectedPoint {
+	var out []InspectedPoint
+	switch data := m.Data.(type) {
+	case metricdata.Sum[int64]:
+		for _, dp := range data.DataPoints {
+			out = append(out, InspectedPoint{Name: m.Name, Kind: "counter", Value: dp.Value, Attributes: attrMap(dp.Attributes)})
+		}
+	case metricdata.Gauge[int64]:
+		for _, dp := range data.DataPoints {
+			out = append(out, InspectedPoint{Name: m.Name, Kind: "gauge", Value: dp.Value, Attributes: attrMap(dp.Attributes)})
+		}
+	case metricdata.Histogram[int64]:
+		for _, dp := range data.DataPoints {
+			out = append(out, InspectedPoint{Name: m.Name, Kind: "histogram", Value: dp.Sum, Count: dp.Count, Attributes: attrMap(dp.Attributes)})
+		}
+	}
+	return out
+}
+
+func attrMap(set attribute.Set) map[string]string {
+	out := make(map[string]string, set.Len())
+	for _, kv := range set.ToSlice() {
+		out[string(kv.Key)] = kv.Value.Emit()
+	}
+	return out
+}
+
+// SortedAttributes renders attributes as sorted key=value pairs.
+func SortedAttributes(attrs map[string]string) []string {
+	out := make([]string, 0, len(attrs))
+	for k, v := range attrs {
+		out = append(out, k+"="+v)
+	}
+	sort.Strings(out)
+	return out
+}
@@ -0,0 +1,50 @@
+package telemetry
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestInspector_CollectsWhatWouldBeSent(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	// Inspect is local, so it works even when exporting is disabled.
+	t.Setenv("NAV_PILOT_TELEMETRY_ENABLED", "false")
+
+	insp, err := NewInspector(context.Background(), "1.2.3", "true")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var rec Recorder = insp
+	rec.RecordCommand("sync", "non_interactive", "repo", "success", "", 40*time.Millisecond)
+	rec.RecordSyncUpdates("repo", "non_interactive", 3)
+
+	got, err := insp.Collect(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Resource["service.version"] != "1.2.3" || got.Resource["service.name"] != "nav-pilot" {
+		t.Errorf("resource = %v", got.Resource)
+	}
+	byName := map[string]InspectedPoint{}
+	for _, p := range got.Points {
+		byName[p.Name] = p
+	}
+	if p := byName["nav_pilot_command_total"]; p.Kind != "counter" || p.Value != 1 || p.Attributes["command"] != "sync" || p.Attributes["scope"] != "repo" {
+		t.Errorf("command_total = %+v", p)
+	}
+	if p := byName["nav_pilot_sync_updates_total"]; p.Value != 3 {
+		t.Errorf("sync_updates_total = %+v", p)
+	}
+	if p := byName["nav_pilot_command_duration_ms"]; p.Kind != "histogram" || p.Count != 1 || p.Value != 40 {
+		t.Errorf("command_duration_ms = %+v", p)
+	}
+	if p := byName["nav_pilot_info"]; p.Kind != "gauge" || p.Attributes["rtk_installed"] != "true" {
+		t.Errorf("info = %+v", p)
+	}
+	for i := 1; i < len(got.Points); i++ {
+		if got.Points[i-1].Name > got.Points[i].Name {
+			t.Fatalf("points not sorted by name: %s before %s", got.Points[i-1].Name, got.Points[i].Name)
+		}
+	}
+}
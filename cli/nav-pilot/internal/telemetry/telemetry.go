@@ -3,6 +3,7 @@ package telemetry
 import (
 	"context"
 	"fmt"
+	"net/http"
 	"os"
 	"path/filepath"
 	"runtime"
@@ -76,6 +77,8 @@ func (NoopRecorder) Shutdown(context.Context) error        { return nil }
 
 type otelTelemetry struct {
 	provider *sdkmetric.MeterProvider
+	buffer   *bufferingTransport
+	endpoint string
 
 	commandTotal       metric.Int64Counter
 	commandDurationMS  metric.Int64Histogram
@@ -110,6 +113,63 @@ func InitTelemetry(ctx context.Context, cliVersion string, rtkInstalled string)
 		return NoopRecorder{}, nil
 	}
 
+	endpoint := Endpoint()
+
+	opts := []otlpmetrichttp.Option{
+		otlpmetrichttp.WithTemporalitySelector(deltaCounters),
+		otlpmetrichttp.WithEndpointURL(endpoint),
+	}
+	var buffer *bufferingTransport
+	if dir, err := BufferDir(); err == nil {
+		// The disk buffer stands in for in-process retries, which would only
+		// delay exit while offline and queue the same export several times.
+		buffer = newBufferingTransport(dir)
+		opts = append(opts,
+			otlpmetrichttp.WithHTTPClient(&http.Client{Transport: buffer}),
+			otlpmetrichttp.WithRetry(otlpmetrichttp.RetryConfig{Enabled: false}),
+		)
+	} else {
+		DebugLog("telemetry buffer disabled: %v", err)
+	}
+
+	exporter, err := otlpmetrichttp.New(ctx, opts...)
+	if err != nil {
+		return NoopRecorder{}, fmt.Errorf("create OTLP metrics exporter: %w", err)
+	}
+
+	reader := sdkmetric.NewPeriodicReader(exporter,
+		sdkmetric.WithInterval(10*time.Second),
+		sdkmetric.WithTimeout(2*time.Second),
+	)
+	tel, err := newOtelTelemetry(ctx, reader, cliVersion, rtkInstalled)
+	if err != nil {
+		return NoopRecorder{}, err
+	}
+	tel.buffer = buffer
+	tel.endpoint = endpoint
+	tel.recordInfo()
+
+	return tel, nil
+}
+
+// deltaCounters exports counters as deltas, so each run reports only its own
+// increments, and gauges and histograms cumulatively.
+func deltaCounters(kind sdkmetric.InstrumentKind) metricdata.Temporality {
+	switch kind {
+	case sdkmetric.InstrumentKindCounter,
+		sdkmetric.InstrumentKindUpDownCounter,
+		sdkmetric.InstrumentKindObservableCounter,
+		sdkmetric.InstrumentKindObservableUpDownCounter:
+		return metricdata.DeltaTemporality
+	default:
+		return metricdata.CumulativeTemporality
+	}
+}
+
+// newOtelTelemetry builds the resource and every instrument on reader. The
+// exporting recorder and `telemetry inspect` share it, so inspect shows
+// exactly the metrics and attributes that would be sent.
+func newOtelTelemetry(ctx context.Context, reader sdkmetric.Reader, cliVersion, rtkInstalled string) (*otelTelemetry, error) {
 	deviceID, err := GetOrCreateDeviceID()
 	if err != nil {
 		DebugLog("failed to get device ID: %v; continuing without it", err)
@@ -123,36 +183,6 @@ func InitTelemetry(ctx context.Context, cliVersion string, rtkInstalled string)
 	execCtx := normalizeTelemetryDimension(executionContext, "unknown")
 	projType := normalizeTelemetryDimension(detectProjectType(), "na")
 
-	endpoint := strings.TrimSpace(os.Getenv("NAV_PILOT_TELEMETRY_ENDPOINT"))
-	if endpoint == "" {
-		endpoint = strings.TrimSpace(os.Getenv("OTEL_EXPORTER_OTLP_ENDPOINT"))
-	}
-	if endpoint == "" {
-		endpoint = defaultTelemetryEndpoint
-	}
-
-	opts := []otlpmetrichttp.Option{
-		otlpmetrichttp.WithTemporalitySelector(func(kind sdkmetric.InstrumentKind) metricdata.Temporality {
-			switch kind {
-			case sdkmetric.InstrumentKindCounter,
-				sdkmetric.InstrumentKindUpDownCounter,
-				sdkmetric.InstrumentKindObservableCounter,
-				sdkmetric.InstrumentKindObservableUpDownCounter:
-				return metricdata.DeltaTemporality
-			default:
-				return metricdata.CumulativeTemporality
-			}
-		}),
-	}
-	if endpoint != "" {
-		opts = append(opts, otlpmetrichttp.WithEndpointURL(endpoint))
-	}
-
-	exporter, err := otlpmetrichttp.New(ctx, opts...)
-	if err != nil {
-		return NoopRecorder{}, fmt.Errorf("create OTLP metrics exporter: %w", err)
-	}
-
 	res, err := resource.New(ctx, resource.WithAttributes(
 		attribute.String("service.name", "nav-pilot"),
 		attribute.String("service.version", version),
@@ -162,13 +192,9 @@ func InitTelemetry(ctx context.Context, cliVersion string, rtkInstalled string)
 		attribute.String("execution_context", execCtx),
 	))
 	if err != nil {
-		return NoopRecorder{}, fmt.Errorf("create telemetry resource: %w", err)
+		return nil, fmt.Errorf("create telemetry resource: %w", err)
 	}
 
-	reader := sdkmetric.NewPeriodicReader(exporter,
-		sdkmetric.WithInterval(10*time.Second),
-		sdkmetric.WithTimeout(2*time.Second),
-	)
 	provider := sdkmetric.NewMeterProvider(
 		sdkmetric.WithReader(reader),
 		sdkmetric.WithResource(res),
@@ -177,69 +203,69 @@ func InitTelemetry(ctx context.Context, cliVersion string, rtkInstalled string)
 	meter := provider.Meter("github.com/navikt/copilot/cli/nav-pilot")
 	commandTotal, err := meter.Int64Counter("nav_pilot_command_total")
 	if err != nil {
-		return NoopRecorder{}, fmt.Errorf("create command total counter: %w", err)
+		return nil, fmt.Errorf("create command total counter: %w", err)
 	}
 	commandDurationMS, err := meter.Int64Histogram("nav_pilot_command_duration_ms")
 	if err != nil {
-		return NoopRecorder{}, fmt.Errorf("create command duration histogram: %w", err)
+		return nil, fmt.Errorf("create command duration histogram: %w", err)
 	}
 	commandErrorTotal, err := meter.Int64Counter("nav_pilot_command_error_total")
 	if err != nil {
-		return NoopRecorder{}, fmt.Errorf("create command error counter: %w", err)
+		return nil, fmt.Errorf("create command error counter: %w", err)
 	}
 	launchErrorTotal, err := meter.Int64Counter("nav_pilot_launch_error_total",
 		metric.WithDescription("Counts client launch failures by client and error type."))
 	if err != nil {
-		return NoopRecorder{}, fmt.Errorf("create launch error counter: %w", err)
+		return nil, fmt.Errorf("create launch error counter: %w", err)
 	}
 	installItemsTotal, err := meter.Int64Counter("nav_pilot_install_items_total")
 	if err != nil {
-		return NoopRecorder{}, fmt.Errorf("create install items counter: %w", err)
+		return nil, fmt.Errorf("create install items counter: %w", err)
 	}
 	syncUpdatesTotal, err := meter.Int64Counter("nav_pilot_sync_updates_total")
 	if err != nil {
-		return NoopRecorder{}, fmt.Errorf("create sync updates counter: %w", err)
+		return nil, fmt.Errorf("create sync updates counter: %w", err)
 	}
 	syncConflictsTotal, err := meter.Int64Counter("nav_pilot_sync_conflicts_total")
 	if err != nil {
-		return NoopRecorder{}, fmt.Errorf("create sync conflicts counter: %w", err)
+		return nil, fmt.Errorf("create sync conflicts counter: %w", err)
 	}
 	infoGauge, err := meter.Int64Gauge("nav_pilot_info")
 	if err != nil {
-		return NoopRecorder{}, fmt.Errorf("create info gauge: %w", err)
+		return nil, fmt.Errorf("create info gauge: %w", err)
 	}
 	installPresent, err := meter.Int64Gauge("nav_pilot_install_present")
 	if err != nil {
-		return NoopRecorder{}, fmt.Errorf("create install present gauge: %w", err)
+		return nil, fmt.Errorf("create install present gauge: %w", err)
 	}
 	installedItems, err := meter.Int64Gauge("nav_pilot_installed_items")
 	if err != nil {
-		return NoopRecorder{}, fmt.Errorf("create installed items gauge: %w", err)
+		return nil, fmt.Errorf("create installed items gauge: %w", err)
 	}
 	configInfo, err := meter.Int64Gauge("nav_pilot_config_info")
 	if err != nil {
-		return NoopRecorder{}, fmt.Errorf("create config info gauge: %w", err)
+		return nil, fmt.Errorf("create config info gauge: %w", err)
 	}
 	clientAvailable, err := meter.Int64Gauge("nav_pilot_client_available")
 	if err != nil {
-		return NoopRecorder{}, fmt.Errorf("create client available gauge: %w", err)
+		return nil, fmt.Errorf("create client available gauge: %w", err)
 	}
 	stalenessCheck, err := meter.Int64Counter("nav_pilot_staleness_check_total")
 	if err != nil {
-		return NoopRecorder{}, fmt.Errorf("create staleness check counter: %w", err)
+		return nil, fmt.Errorf("create staleness check counter: %w", err)
 	}
 	upToDate, err := meter.Int64Gauge("nav_pilot_up_to_date")
 	if err != nil {
-		return NoopRecorder{}, fmt.Errorf("create up to date gauge: %w", err)
+		return nil, fmt.Errorf("create up to date gauge: %w", err)
 	}
 	versionSkewDays, err := meter.Int64Histogram("nav_pilot_version_skew_days")
 	if err != nil {
-		return NoopRecorder{}, fmt.Errorf("create version skew days histogram: %w", err)
+		return nil, fmt.Errorf("create version skew days histogram: %w", err)
 	}
 	rtkSetupTotal, err := meter.Int64Counter("nav_pilot_rtk_setup_total",
 		metric.WithDescription("Counts the result of the interactive RTK setup prompt."))
 	if err != nil {
-		return NoopRecorder{}, fmt.Errorf("create rtk setup counter: %w", err)
+		return nil, fmt.Errorf("create rtk setup counter: %w", err)
 	}
 
 	tel := &otelTelemetry{
@@ -268,7 +294,6 @@ func InitTelemetry(ctx context.Context, cliVersion string, rtkInstalled string)
 		rtkInstalled:       rtkInstalled,
 		projectType:        projType,
 	}
-	tel.recordInfo()
 
 	return tel, nil
 }
@@ -495,8 +520,20 @@ func (t *otelTelemetry) RecordVersionSkewDays(component, scope string, days int6
 	))
 }
 
+// Shutdown flushes pending metrics. When this run reached the collector,
+// exports buffered by earlier offline runs are replayed in the time left.
 func (t *otelTelemetry) Shutdown(ctx context.Context) error {
-	return t.provider.Shutdown(ctx)
+	err := t.provider.Shutdown(ctx)
+	if t.buffer != nil && t.buffer.Delivered() {
+		client := &http.Client{Transport: t.buffer.base}
+		sent, rErr := replayBuffer(ctx, client, t.endpoint, t.buffer.dir, t.buffer.now())
+		if rErr != nil {
+			DebugLog("telemetry buffer: replayed %d export(s), stopped: %v", sent, rErr)
+		} else if sent > 0 {
+			DebugLog("telemetry buffer: replayed %d export(s)", sent)
+		}
+	}
+	return err
 }
 
 // RecordLaunchError records a client launch failure with a normalized error type.
@@ -529,7 +566,7 @@ func normalizeTelemetryDimension(v, fallback string) string {
 	}
 	switch v {
 	case "install", "sync", "upgrade", "list", "startup", "launch", "doctor",
-		"init", "export", "uninstall", "config", "env", "feedback", "models", "ignore", "add",
+		"init", "export", "uninstall", "rollback", "config", "cache", "telemetry", "workspace", "diff", "lint", "env", "feedback", "models", "ignore", "add", "search", "mcp", "mcp-serve",
 		"interactive", "non_interactive",
 		"repo", "user", "auto", "none", "unknown",
 		"go", "node", "jvm", "python", "na",
@@ -17,12 +17,16 @@ var (
 	version   = "dev"
 	commit    = "unknown"
 	buildDate = "unknown"
+	// releaseKey is the minisign public key that `nav-pilot upgrade` verifies
+	// releases against. Set by the release workflow.
+	releaseKey = ""
 )
 
 func main() {
 	cli.Main(cli.BuildInfo{
-		Version:   version,
-		Commit:    commit,
-		BuildDate: buildDate,
+		Version:    version,
+		Commit:     commit,
+		BuildDate:  buildDate,
+		ReleaseKey: releaseKey,
 	})
 }